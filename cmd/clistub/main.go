@@ -0,0 +1,22 @@
+// Command clistub impersonates zbox and zwallet from scripted fixtures, for testing the harness without a network.
+// Copied or linked as zbox or zwallet, it answers from the fixtures file named by CLI_STUB_FIXTURES.
+//
+//	go build -o tests/cli_tests/zbox ./cmd/clistub
+//	CLI_STUB_FIXTURES=fixtures.yaml tests/cli_tests/zbox list-all
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/0chain/system_test/internal/cli/stub"
+)
+
+func main() {
+	fixtures := os.Getenv(stub.FixturesEnv)
+	if fixtures == "" {
+		fmt.Fprintf(os.Stderr, "clistub: %s is not set\n", stub.FixturesEnv)
+		os.Exit(stub.NoFixtureExitCode)
+	}
+	os.Exit(stub.Run(fixtures, stub.ProgramName(os.Args[0]), os.Args[1:], os.Stdout, os.Stderr))
+}
@@ -0,0 +1,55 @@
+// Command keystore converts the wallet and config files of the test suites to and from encrypted keystore files.
+//
+//	WALLET_KEYSTORE_PASSPHRASE=... go run ./cmd/keystore tests/api_tests/config/wallets.json tests/cli_tests/config/wallets/*.json
+//	WALLET_KEYSTORE_PASSPHRASE=... go run ./cmd/keystore -decrypt tests/api_tests/config/wallets.json.enc
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/0chain/system_test/internal/api/util/keystore"
+)
+
+func main() {
+	decrypt := flag.Bool("decrypt", false, "decrypt the given "+keystore.Extension+" files instead of encrypting")
+	keep := flag.Bool("keep", false, "keep the source files after conversion")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [-decrypt] [-keep] files...\n\nThe passphrase is read from %s.\n", os.Args[0], keystore.PassphraseEnv)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	passphrase := os.Getenv(keystore.PassphraseEnv)
+	if passphrase == "" {
+		log.Fatalln(keystore.ErrNoPassphrase)
+	}
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	for _, path := range flag.Args() {
+		var (
+			converted string
+			err       error
+		)
+		if *decrypt {
+			converted, err = keystore.DecryptFile(path, passphrase)
+		} else {
+			converted, err = keystore.EncryptFile(path, passphrase)
+		}
+		if err != nil {
+			log.Fatalf("converting %s: %v", path, err)
+		}
+
+		if !*keep {
+			if err := os.Remove(path); err != nil {
+				log.Fatalf("removing %s: %v", path, err)
+			}
+		}
+		log.Printf("%s -> %s", path, converted)
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/0chain/gosdk/core/zcncrypto"
 	climodel "github.com/0chain/system_test/internal/cli/model"
+	"github.com/0chain/system_test/internal/currency"
 	"github.com/herumi/bls-go-binary/bls"
 	"gorm.io/gorm"
 )
@@ -276,7 +277,7 @@ type InternalTransactionPutRequest struct {
 	TransactionData
 	ToClientID string
 	Wallet     *Wallet
-	Value      *int64
+	Value      *currency.Coin
 	TxnType    int
 }
 
@@ -973,6 +974,18 @@ type SCRestGetStakePoolStatResponse struct {
 	Settings    StakePoolSettings           `json:"settings"`
 }
 
+type SCRestGetChallengePoolStatRequest struct {
+	AllocationID string
+}
+
+type SCRestGetChallengePoolStatResponse struct {
+	ID         string `json:"id"`
+	Balance    int64  `json:"balance"`
+	StartTime  int64  `json:"start_time"`
+	Expiration int64  `json:"expiration"`
+	Finalized  bool   `json:"finalized"`
+}
+
 type SCRestGetUserStakePoolStatRequest struct {
 	ClientId string
 }
@@ -10,16 +10,18 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/0chain/system_test/internal/api/util/test"
 
 	"github.com/0chain/system_test/internal/api/model"
 	"github.com/0chain/system_test/internal/api/util/crypto"
+	"github.com/0chain/system_test/internal/api/util/paginate"
 	"github.com/0chain/system_test/internal/api/util/wait"
 	"github.com/stretchr/testify/require"
 
-	"github.com/0chain/system_test/internal/api/util/tokenomics"
+	"github.com/0chain/system_test/internal/currency"
 
 	resty "github.com/go-resty/resty/v2"
 )
@@ -33,6 +35,7 @@ const (
 	GetValidators                      = "/v1/screst/:sc_address/validators"
 	GetStakePoolStat                   = "/v1/screst/:sc_address/getStakePoolStat"
 	getUserStakePoolStat               = "/v1/screst/:sc_address/getUserStakePoolStat"
+	GetChallengePoolStat               = "/v1/screst/:sc_address/getChallengePoolStat"
 	GetAllocationBlobbers              = "/v1/screst/:sc_address/alloc_blobbers"
 	GetFreeAllocationBlobbers          = "/v1/screst/:sc_address/free_alloc_blobbers"
 	SCRestGetOpenChallenges            = "/v1/screst/:sc_address/openchallenges"
@@ -93,17 +96,30 @@ const (
 )
 
 var (
-	TxValue = tokenomics.IntToZCN(1)
+	TxValue = TokenValue(1)
 )
 
+// TokenValue returns the value of a transaction sending tokens ZCN
+func TokenValue(tokens float64) *currency.Coin {
+	value := currency.Coin(currency.ZCNToSAS(tokens))
+	return &value
+}
+
+// TransactionObserver is notified of every transaction accepted by the miners
+type TransactionObserver func(request model.TransactionPutRequest)
+
 type APIClient struct {
 	BaseHttpClient
 	model.HealthyServiceProviders
+
+	observersMu  sync.Mutex
+	observers    map[int]TransactionObserver
+	nextObserver int
 }
 
 func NewAPIClient(networkEntrypoint string) *APIClient {
 	apiClient := &APIClient{}
-	apiClient.HttpClient = resty.New()
+	apiClient.HttpClient = newRestyClient()
 
 	if err := apiClient.selectHealthyServiceProviders(networkEntrypoint); err != nil {
 		log.Fatalln(err)
@@ -135,7 +151,7 @@ func (c *APIClient) getHealthyNodes(nodes []string, serviceProviderType int) ([]
 
 		healthResponse, err := r.Get(formattedURL)
 		if err == nil && healthResponse.IsSuccess() {
-			log.Printf("%s is UP!", node)
+			logger.Printf("%s is UP!", node)
 			result = append(result, node)
 			continue
 		}
@@ -143,11 +159,11 @@ func (c *APIClient) getHealthyNodes(nodes []string, serviceProviderType int) ([]
 		status := healthResponse.StatusCode()
 		response := healthResponse.Body()
 		if err != nil {
-			log.Printf("Read error %s for blobber %s.", err.Error(), node)
+			logger.Printf("Read error %s for blobber %s.", err.Error(), node)
 			continue
 		}
 
-		log.Printf("%s is DOWN! Status: %d, Message: %s", node, status, string(response))
+		logger.Printf("%s is DOWN! Status: %d, Message: %s", node, status, string(response))
 	}
 	return result, nil
 }
@@ -203,33 +219,22 @@ func (c *APIClient) selectHealthyServiceProviders(networkEntrypoint string) erro
 
 	c.HealthyServiceProviders.Sharders = healthySharders
 
-	offset := 0
-	limit := 20
-	var nodes model.StorageNodes
-
-	for {
-		if err := urlBuilder.MustShiftParse(networkServiceProviders.Sharders[0]); err != nil {
-			return err
-		}
-		urlBuilder = urlBuilder.SetPath(GetBlobbers).SetPathVariable("sc_address", StorageSmartContractAddress)
-		formattedURL = urlBuilder.AddParams("offset", fmt.Sprint(offset)).AddParams("limit", fmt.Sprint(limit)).String()
-		resp, err = c.HttpClient.R().Get(formattedURL)
-		if err != nil {
-			return ErrNoBlobbersHealthy
-		}
-		err = json.Unmarshal(resp.Body(), &nodes)
-		if err != nil {
-			return errors.New(ErrNetworkHealthy.Error() + "failed to unmarshall network service providers. Body: " + string(resp.Body()))
-		}
-
-		if len(nodes.Nodes) == 0 {
-			break
-		}
-
-		for _, node := range nodes.Nodes {
-			networkServiceProviders.Blobbers = append(networkServiceProviders.Blobbers, node.BaseURL)
-		}
-		offset += limit
+	if err := urlBuilder.MustShiftParse(networkServiceProviders.Sharders[0]); err != nil {
+		return err
+	}
+	formattedURL = urlBuilder.SetPath(GetBlobbers).SetPathVariable("sc_address", StorageSmartContractAddress).String()
+	blobbers := paginate.Offset[*model.StorageNode](formattedURL, nil).
+		WithClient(c.HttpClient.GetClient()).
+		WithDecoder(func(body []byte) ([]*model.StorageNode, error) {
+			var nodes model.StorageNodes
+			err := json.Unmarshal(body, &nodes)
+			return nodes.Nodes, err
+		})
+	for blobbers.Next() {
+		networkServiceProviders.Blobbers = append(networkServiceProviders.Blobbers, blobbers.Item().BaseURL)
+	}
+	if err := blobbers.Err(); err != nil {
+		return errors.New(ErrNoBlobbersHealthy.Error() + ": " + err.Error())
 	}
 
 	healthyBlobbers, err := c.getHealthyBlobbers(networkServiceProviders.Blobbers)
@@ -360,7 +365,38 @@ func (c *APIClient) V1TransactionPutWithNonceAndServiceProviders(
 	internalTransactionPutRequest model.InternalTransactionPutRequest,
 	requiredStatusCode, withNonce int, withProviders []string, options ...float64,
 ) (*model.TransactionPutResponse, *resty.Response, error) { //nolint
+	return c.v1TransactionPut(t, internalTransactionPutRequest, requiredStatusCode, withNonce, withProviders,
+		func(transactionPutRequest *model.TransactionPutRequest) error {
+			crypto.SignTransaction(t, transactionPutRequest, internalTransactionPutRequest.Wallet.Keys)
+			return nil
+		}, options...)
+}
 
+// V1TransactionPutWithThresholdShares submits the transaction signed by the given threshold key shares instead of the wallet key
+func (c *APIClient) V1TransactionPutWithThresholdShares(
+	t *test.SystemTest,
+	internalTransactionPutRequest model.InternalTransactionPutRequest,
+	shares []crypto.SignatureScheme,
+	requiredStatusCode int, options ...float64,
+) (*model.TransactionPutResponse, *resty.Response, error) { //nolint
+	return c.v1TransactionPut(t, internalTransactionPutRequest, requiredStatusCode, 0, nil,
+		func(transactionPutRequest *model.TransactionPutRequest) error {
+			signature, err := crypto.SignWithThresholdShares(transactionPutRequest.Hash, shares)
+			if err != nil {
+				return err
+			}
+			transactionPutRequest.Signature = signature
+			return nil
+		}, options...)
+}
+
+func (c *APIClient) v1TransactionPut(
+	t *test.SystemTest,
+	internalTransactionPutRequest model.InternalTransactionPutRequest,
+	requiredStatusCode, withNonce int, withProviders []string,
+	sign func(transactionPutRequest *model.TransactionPutRequest) error,
+	options ...float64,
+) (*model.TransactionPutResponse, *resty.Response, error) { //nolint
 	var transactionPutResponse *model.TransactionPutResponse
 
 	data, err := json.Marshal(internalTransactionPutRequest.TransactionData)
@@ -374,7 +410,7 @@ func (c *APIClient) V1TransactionPutWithNonceAndServiceProviders(
 		ToClientId:       internalTransactionPutRequest.ToClientID,
 		TransactionNonce: internalTransactionPutRequest.Wallet.Nonce + 1,
 		TxnOutputHash:    TxOutput,
-		TransactionValue: *TxValue,
+		TransactionValue: int64(*TxValue),
 		TransactionType:  internalTransactionPutRequest.TxnType,
 		TransactionFee:   int64(TxFee),
 		TransactionData:  string(data),
@@ -398,7 +434,7 @@ func (c *APIClient) V1TransactionPutWithNonceAndServiceProviders(
 	}
 
 	if internalTransactionPutRequest.Value != nil {
-		transactionPutRequest.TransactionValue = *internalTransactionPutRequest.Value
+		transactionPutRequest.TransactionValue = int64(*internalTransactionPutRequest.Value)
 	}
 
 	transactionPutRequest.Hash = crypto.Sha3256([]byte(fmt.Sprintf("%d:%d:%s:%s:%d:%s",
@@ -409,7 +445,10 @@ func (c *APIClient) V1TransactionPutWithNonceAndServiceProviders(
 		transactionPutRequest.TransactionValue,
 		crypto.Sha3256([]byte(transactionPutRequest.TransactionData)))))
 
-	crypto.SignTransaction(t, &transactionPutRequest, internalTransactionPutRequest.Wallet.Keys)
+	err = sign(&transactionPutRequest)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	serviceProviders := c.HealthyServiceProviders.Miners
 	if withProviders != nil {
@@ -429,9 +468,45 @@ func (c *APIClient) V1TransactionPutWithNonceAndServiceProviders(
 
 	transactionPutResponse.Request = transactionPutRequest
 
+	if err == nil && resp != nil && resp.StatusCode() == HttpOkStatus {
+		c.notifyObservers(transactionPutRequest)
+	}
+
 	return transactionPutResponse, resp, err
 }
 
+// ObserveTransactions registers observer for the transactions submitted from now on, until the returned function is called
+func (c *APIClient) ObserveTransactions(observer TransactionObserver) func() {
+	c.observersMu.Lock()
+	defer c.observersMu.Unlock()
+
+	if c.observers == nil {
+		c.observers = make(map[int]TransactionObserver)
+	}
+	id := c.nextObserver
+	c.nextObserver++
+	c.observers[id] = observer
+
+	return func() {
+		c.observersMu.Lock()
+		defer c.observersMu.Unlock()
+		delete(c.observers, id)
+	}
+}
+
+func (c *APIClient) notifyObservers(request model.TransactionPutRequest) {
+	c.observersMu.Lock()
+	observers := make([]TransactionObserver, 0, len(c.observers))
+	for _, observer := range c.observers {
+		observers = append(observers, observer)
+	}
+	c.observersMu.Unlock()
+
+	for _, observer := range observers {
+		observer(request)
+	}
+}
+
 func estimateTxnFee(t *test.SystemTest, c *APIClient, transactionPutRequest *model.TransactionPutRequest) int64 {
 	urlBuilder := NewURLBuilder().SetPath(TransactionFeeGet)
 	resp, err := c.executeForAllServiceProviders(
@@ -876,7 +951,7 @@ func (c *APIClient) CreateAllocationWithLockValue(t *test.SystemTest,
 			Wallet:          wallet,
 			ToClientID:      StorageSmartContractAddress,
 			TransactionData: model.NewCreateAllocationTransactionData(scRestGetAllocationBlobbersResponse),
-			Value:           tokenomics.IntToZCN(lockValue),
+			Value:           TokenValue(lockValue),
 			TxnType:         SCTxType,
 		},
 		HttpOkStatus)
@@ -928,7 +1003,7 @@ func (c *APIClient) RegisterBlobber(t *test.SystemTest,
 			Wallet:          wallet,
 			ToClientID:      StorageSmartContractAddress,
 			TransactionData: model.NewRegisterBlobberTransactionData(storageNode),
-			Value:           tokenomics.IntToZCN(0),
+			Value:           TokenValue(0),
 			TxnType:         SCTxType,
 		},
 		HttpOkStatus)
@@ -979,7 +1054,7 @@ func (c *APIClient) CreateFreeAllocation(t *test.SystemTest,
 			Wallet:          wallet,
 			ToClientID:      StorageSmartContractAddress,
 			TransactionData: model.NewCreateFreeAllocationTransactionData(scRestGetFreeAllocationBlobbersResponse),
-			Value:           tokenomics.IntToZCN(0.1),
+			Value:           TokenValue(0.1),
 			TxnType:         SCTxType,
 		},
 		HttpOkStatus)
@@ -1032,7 +1107,7 @@ func (c *APIClient) UpdateAllocation(
 			Wallet:          wallet,
 			ToClientID:      StorageSmartContractAddress,
 			TransactionData: model.NewUpdateAllocationTransactionData(uar),
-			Value:           tokenomics.IntToZCN(lock),
+			Value:           TokenValue(lock),
 			TxnType:         SCTxType,
 		},
 		HttpOkStatus)
@@ -1084,7 +1159,7 @@ func (c *APIClient) AddFreeStorageAssigner(
 				IndividualLimit: 10.0,
 				TotalLimit:      100.0,
 			}),
-			Value:   tokenomics.IntToZCN(0.1),
+			Value:   TokenValue(0.1),
 			TxnType: SCTxType,
 		},
 		HttpOkStatus)
@@ -1133,7 +1208,7 @@ func (c *APIClient) UpdateAllocationBlobbers(t *test.SystemTest, wallet *model.W
 				AddBlobberId:    newBlobberID,
 				RemoveBlobberId: oldBlobberID,
 			}),
-			Value:   tokenomics.IntToZCN(0.1),
+			Value:   TokenValue(0.1),
 			TxnType: SCTxType,
 		},
 		HttpOkStatus)
@@ -1440,7 +1515,7 @@ func (c *APIClient) UpdateBlobber(t *test.SystemTest, wallet *model.Wallet, scRe
 			Wallet:          wallet,
 			ToClientID:      StorageSmartContractAddress,
 			TransactionData: model.NewUpdateBlobberTransactionData(scRestGetBlobberResponse),
-			Value:           tokenomics.IntToZCN(0.1),
+			Value:           TokenValue(0.1),
 			TxnType:         SCTxType,
 		},
 		HttpOkStatus)
@@ -1494,7 +1569,7 @@ func (c *APIClient) CreateStakePool(t *test.SystemTest, wallet *model.Wallet, pr
 					ProviderType: providerType,
 					ProviderID:   providerID,
 				}),
-			Value:   tokenomics.IntToZCN(tokens),
+			Value:   TokenValue(tokens),
 			TxnType: SCTxType,
 		},
 		HttpOkStatus)
@@ -1544,7 +1619,7 @@ func (c *APIClient) UnlockStakePool(t *test.SystemTest, wallet *model.Wallet, pr
 					ProviderType: providerType,
 					ProviderID:   providerID,
 				}),
-			Value:   tokenomics.IntToZCN(0.1),
+			Value:   TokenValue(0.1),
 			TxnType: SCTxType,
 		},
 		HttpOkStatus)
@@ -1595,7 +1670,7 @@ func (c *APIClient) CreateMinerStakePool(t *test.SystemTest, wallet *model.Walle
 					ProviderType: providerType,
 					ProviderID:   providerID,
 				}),
-			Value:   tokenomics.IntToZCN(tokens),
+			Value:   TokenValue(tokens),
 			TxnType: SCTxType,
 		},
 		HttpOkStatus)
@@ -1645,7 +1720,7 @@ func (c *APIClient) UnlockMinerStakePool(t *test.SystemTest, wallet *model.Walle
 					ProviderType: providerType,
 					ProviderID:   providerID,
 				}),
-			Value:   tokenomics.IntToZCN(0.1),
+			Value:   TokenValue(0.1),
 			TxnType: SCTxType,
 		},
 		HttpOkStatus)
@@ -1695,7 +1770,7 @@ func (c *APIClient) CreateWritePool(t *test.SystemTest, wallet *model.Wallet, al
 				model.CreateWritePoolRequest{
 					AllocationID: allocationId,
 				}),
-			Value:   tokenomics.IntToZCN(tokens),
+			Value:   TokenValue(tokens),
 			TxnType: SCTxType,
 		},
 		HttpOkStatus)
@@ -1742,7 +1817,7 @@ func (c *APIClient) CreateReadPool(t *test.SystemTest, wallet *model.Wallet, tok
 			Wallet:          wallet,
 			ToClientID:      StorageSmartContractAddress,
 			TransactionData: model.NewCreateReadPoolTransactionData(),
-			Value:           tokenomics.IntToZCN(tokens),
+			Value:           TokenValue(tokens),
 			TxnType:         SCTxType,
 		},
 		HttpOkStatus)
@@ -1788,7 +1863,7 @@ func (c *APIClient) UnlockReadPool(t *test.SystemTest, wallet *model.Wallet, req
 			Wallet:          wallet,
 			ToClientID:      StorageSmartContractAddress,
 			TransactionData: model.NewUnlockReadPoolTransactionData(),
-			Value:           tokenomics.IntToZCN(0.1),
+			Value:           TokenValue(0.1),
 			TxnType:         SCTxType,
 		},
 		HttpOkStatus)
@@ -1825,6 +1900,53 @@ func (c *APIClient) UnlockReadPool(t *test.SystemTest, wallet *model.Wallet, req
 	return unlockReadPoolTransactionGetConfirmationResponse.Hash
 }
 
+// ExecuteFaucetWithTokens pours the given amount of tokens from the faucet to the wallet
+func (c *APIClient) ExecuteFaucetWithTokens(t *test.SystemTest, wallet *model.Wallet, tokens float64, requiredTransactionStatus int) string {
+	t.Log("Execute faucet...")
+
+	faucetTransactionPutResponse, resp, err := c.V1TransactionPut(
+		t,
+		model.InternalTransactionPutRequest{
+			Wallet:          wallet,
+			ToClientID:      FaucetSmartContractAddress,
+			TransactionData: model.NewFaucetTransactionData(),
+			Value:           TokenValue(tokens),
+			TxnType:         SCTxType,
+		},
+		HttpOkStatus)
+	require.Nil(t, err)
+	require.NotNil(t, resp)
+	require.NotNil(t, faucetTransactionPutResponse)
+
+	var faucetTransactionGetConfirmationResponse *model.TransactionGetConfirmationResponse
+
+	wait.PoolImmediately(t, time.Minute*2, func() bool {
+		faucetTransactionGetConfirmationResponse, resp, err = c.V1TransactionGetConfirmation(
+			t,
+			model.TransactionGetConfirmationRequest{
+				Hash: faucetTransactionPutResponse.Entity.Hash,
+			},
+			HttpOkStatus)
+		if err != nil {
+			return false
+		}
+
+		if resp == nil {
+			return false
+		}
+
+		if faucetTransactionGetConfirmationResponse == nil {
+			return false
+		}
+
+		return faucetTransactionGetConfirmationResponse.Status == requiredTransactionStatus
+	})
+
+	wallet.IncNonce()
+
+	return faucetTransactionGetConfirmationResponse.Hash
+}
+
 func (c *APIClient) V1SCRestGetStakePoolStat(t *test.SystemTest, scRestGetStakePoolStatRequest model.SCRestGetStakePoolStatRequest, requiredStatusCode int) (*model.SCRestGetStakePoolStatResponse, *resty.Response, error) { //nolint
 	var scRestGetStakePoolStatResponse *model.SCRestGetStakePoolStatResponse
 
@@ -1847,6 +1969,27 @@ func (c *APIClient) V1SCRestGetStakePoolStat(t *test.SystemTest, scRestGetStakeP
 	return scRestGetStakePoolStatResponse, resp, err
 }
 
+func (c *APIClient) V1SCRestGetChallengePoolStat(t *test.SystemTest, scRestGetChallengePoolStatRequest model.SCRestGetChallengePoolStatRequest, requiredStatusCode int) (*model.SCRestGetChallengePoolStatResponse, *resty.Response, error) { //nolint
+	var scRestGetChallengePoolStatResponse *model.SCRestGetChallengePoolStatResponse
+
+	urlBuilder := NewURLBuilder().
+		SetPath(GetChallengePoolStat).
+		SetPathVariable("sc_address", StorageSmartContractAddress).
+		AddParams("allocation_id", scRestGetChallengePoolStatRequest.AllocationID)
+
+	resp, err := c.executeForAllServiceProviders(
+		t,
+		urlBuilder,
+		&model.ExecutionRequest{
+			Dst:                &scRestGetChallengePoolStatResponse,
+			RequiredStatusCode: requiredStatusCode,
+		},
+		HttpGETMethod,
+		SharderServiceProvider)
+
+	return scRestGetChallengePoolStatResponse, resp, err
+}
+
 func (c *APIClient) V1SCRestGetUserStakePoolStat(t *test.SystemTest, scRestGetUserStakePoolStatRequest model.SCRestGetUserStakePoolStatRequest, requiredStatusCode int) (*model.SCRestGetUserStakePoolStatResponse, *resty.Response, error) { //nolint
 	var scRestGetUserStakePoolStatResponse *model.SCRestGetUserStakePoolStatResponse
 
@@ -1891,7 +2034,7 @@ func (c *APIClient) CollectRewards(t *test.SystemTest, wallet *model.Wallet, pro
 			Wallet:          wallet,
 			ToClientID:      StorageSmartContractAddress,
 			TransactionData: model.NewCollectRewardTransactionData(providerID, providerType),
-			Value:           tokenomics.IntToZCN(0),
+			Value:           TokenValue(0),
 			TxnType:         SCTxType,
 		},
 		HttpOkStatus)
@@ -2196,7 +2339,7 @@ func (c *APIClient) BurnZcn(t *test.SystemTest, wallet *model.Wallet, address st
 			TransactionData: model.NewBurnZcnTransactionData(&model.SCRestBurnZcnRequest{
 				EthereumAddress: address,
 			}),
-			Value:   tokenomics.IntToZCN(amount),
+			Value:   TokenValue(amount),
 			TxnType: SCTxType,
 		},
 		requiredTransactionStatus)
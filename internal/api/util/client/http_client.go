@@ -3,6 +3,10 @@ package client
 import (
 	"encoding/json"
 	"fmt"
+	"log"
+	"os"
+
+	"github.com/0chain/system_test/internal/api/util/redact"
 
 	"github.com/0chain/system_test/internal/api/util/test"
 
@@ -31,6 +35,29 @@ type BaseHttpClient struct {
 	HttpClient *resty.Client //nolint
 }
 
+// logger logs what the clients print outside of tests, with secrets such as 0box tokens redacted
+var logger = log.New(redact.NewWriter(os.Stderr), "", log.LstdFlags)
+
+// restyLogger routes the debug and error logs of resty through logger
+type restyLogger struct{}
+
+func (restyLogger) Errorf(format string, v ...interface{}) {
+	logger.Printf("ERROR RESTY "+format, v...)
+}
+
+func (restyLogger) Warnf(format string, v ...interface{}) {
+	logger.Printf("WARN RESTY "+format, v...)
+}
+
+func (restyLogger) Debugf(format string, v ...interface{}) {
+	logger.Printf("DEBUG RESTY "+format, v...)
+}
+
+// newRestyClient returns a resty client logging with secrets redacted
+func newRestyClient() *resty.Client {
+	return resty.New().SetLogger(restyLogger{})
+}
+
 func (c *BaseHttpClient) executeForServiceProvider(t *test.SystemTest, url string, executionRequest model.ExecutionRequest, method int) (*resty.Response, error) { //nolint
 	var (
 		resp *resty.Response
@@ -38,7 +38,7 @@ func NewZboxClient(zboxEntrypoint string) *ZboxClient {
 	zboxClient := &ZboxClient{
 		zboxEntrypoint: zboxEntrypoint,
 	}
-	zboxClient.HttpClient = resty.New()
+	zboxClient.HttpClient = newRestyClient()
 
 	return zboxClient
 }
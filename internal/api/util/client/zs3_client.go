@@ -8,26 +8,50 @@ import (
 	"strings"
 
 	"github.com/0chain/system_test/internal/api/util/test"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
 	resty "github.com/go-resty/resty/v2"
 )
 
-const (
-	AccessKey       = "rootroot"
-	SecretAccessKey = "rootroot"
-)
+// ZS3Region is the region requests to zs3server are signed for, zs3server accepts any
+const ZS3Region = "us-east-1"
 
+// ZS3Client talks to zs3server, through its S3 API signed with SigV4 or through its legacy action API,
+// which takes the credentials as query parameters
 type ZS3Client struct {
 	BaseHttpClient
 	zs3ServerUrl string
+
+	AccessKey string
+	SecretKey string
+	s3        *s3.S3
 }
 
-func NewZS3Client(zs3ServerUrl string) *ZS3Client {
+func NewZS3Client(zs3ServerUrl, accessKey, secretKey string) *ZS3Client {
 	zs3Client := &ZS3Client{}
-	zs3Client.HttpClient = resty.New()
+	zs3Client.HttpClient = newRestyClient()
 	zs3Client.zs3ServerUrl = zs3ServerUrl
+	zs3Client.AccessKey = accessKey
+	zs3Client.SecretKey = secretKey
+	zs3Client.s3 = zs3Client.WithCredentials(accessKey, secretKey)
 	return zs3Client
 }
 
+// WithCredentials returns an S3 client of zs3server signing its requests with other credentials
+func (c *ZS3Client) WithCredentials(accessKey, secretKey string) *s3.S3 {
+	sess := session.Must(session.NewSession(&aws.Config{
+		Endpoint:         aws.String(c.zs3ServerUrl),
+		Region:           aws.String(ZS3Region),
+		Credentials:      credentials.NewStaticCredentials(accessKey, secretKey, ""),
+		S3ForcePathStyle: aws.Bool(true),
+		HTTPClient:       c.HttpClient.GetClient(),
+		MaxRetries:       aws.Int(0),
+	}))
+	return s3.New(sess)
+}
+
 func (c *ZS3Client) BucketOperation(t *test.SystemTest, queryParams, formData map[string]string) (*resty.Response, error) {
 	resp, err := c.BaseHttpClient.HttpClient.R().SetFiles(formData).SetQueryParams(queryParams).Post(c.zs3ServerUrl)
 	if err != nil {
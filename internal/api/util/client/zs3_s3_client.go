@@ -0,0 +1,165 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/0chain/system_test/internal/api/util/test"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// S3Error returns the S3 error code and HTTP status of a failed request to the S3 API, as "NoSuchKey" and 404
+func S3Error(err error) (code string, status int) {
+	var failure awserr.RequestFailure
+	if errors.As(err, &failure) {
+		return failure.Code(), failure.StatusCode()
+	}
+	var aerr awserr.Error
+	if errors.As(err, &aerr) {
+		return aerr.Code(), 0
+	}
+	return "", 0
+}
+
+func (c *ZS3Client) CreateBucket(t *test.SystemTest, bucket string) error {
+	t.Logf("creating bucket [%s] using zs3server...", bucket)
+	_, err := c.s3.CreateBucket(&s3.CreateBucketInput{Bucket: aws.String(bucket)})
+	return err
+}
+
+func (c *ZS3Client) DeleteBucket(t *test.SystemTest, bucket string) error {
+	t.Logf("deleting bucket [%s] using zs3server...", bucket)
+	_, err := c.s3.DeleteBucket(&s3.DeleteBucketInput{Bucket: aws.String(bucket)})
+	return err
+}
+
+func (c *ZS3Client) PutObject(t *test.SystemTest, bucket, key string, content []byte, metadata map[string]string) (*s3.PutObjectOutput, error) {
+	t.Logf("putting object [%s/%s] of %d bytes using zs3server...", bucket, key, len(content))
+	return c.s3.PutObject(&s3.PutObjectInput{
+		Bucket:   aws.String(bucket),
+		Key:      aws.String(key),
+		Body:     bytes.NewReader(content),
+		Metadata: aws.StringMap(metadata),
+	})
+}
+
+// GetObject reads an object, or the byte range of it given as "bytes=0-99" unless byteRange is empty
+func (c *ZS3Client) GetObject(t *test.SystemTest, bucket, key, byteRange string) ([]byte, *s3.GetObjectOutput, error) {
+	t.Logf("getting object [%s/%s] %s using zs3server...", bucket, key, byteRange)
+	input := &s3.GetObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)}
+	if byteRange != "" {
+		input.Range = aws.String(byteRange)
+	}
+	output, err := c.s3.GetObject(input)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer output.Body.Close()
+	content, err := io.ReadAll(output.Body)
+	return content, output, err
+}
+
+func (c *ZS3Client) HeadObject(t *test.SystemTest, bucket, key string) (*s3.HeadObjectOutput, error) {
+	t.Logf("getting metadata of object [%s/%s] using zs3server...", bucket, key)
+	return c.s3.HeadObject(&s3.HeadObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+}
+
+// ListObjects lists the objects with the prefix with ListObjectsV2, pageSize at a time, and returns them with the
+// number of pages read
+func (c *ZS3Client) ListObjects(t *test.SystemTest, bucket, prefix string, pageSize int64) ([]*s3.Object, int, error) {
+	t.Logf("listing objects of [%s/%s] %d at a time using zs3server...", bucket, prefix, pageSize)
+	var (
+		objects []*s3.Object
+		pages   int
+	)
+	err := c.s3.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket:  aws.String(bucket),
+		Prefix:  aws.String(prefix),
+		MaxKeys: aws.Int64(pageSize),
+	}, func(page *s3.ListObjectsV2Output, last bool) bool {
+		pages++
+		objects = append(objects, page.Contents...)
+		return true
+	})
+	return objects, pages, err
+}
+
+func (c *ZS3Client) CopyObject(t *test.SystemTest, srcBucket, srcKey, dstBucket, dstKey string) (*s3.CopyObjectOutput, error) {
+	t.Logf("copying object [%s/%s] to [%s/%s] using zs3server...", srcBucket, srcKey, dstBucket, dstKey)
+	return c.s3.CopyObject(&s3.CopyObjectInput{
+		Bucket:     aws.String(dstBucket),
+		Key:        aws.String(dstKey),
+		CopySource: aws.String(url.PathEscape(srcBucket) + "/" + (&url.URL{Path: srcKey}).EscapedPath()),
+	})
+}
+
+// DeleteObjects deletes the objects in a single request, the objects that could not be deleted are in the Errors
+// of the output
+func (c *ZS3Client) DeleteObjects(t *test.SystemTest, bucket string, keys ...string) (*s3.DeleteObjectsOutput, error) {
+	t.Logf("deleting %d objects of [%s] using zs3server...", len(keys), bucket)
+	objects := make([]*s3.ObjectIdentifier, 0, len(keys))
+	for _, key := range keys {
+		objects = append(objects, &s3.ObjectIdentifier{Key: aws.String(key)})
+	}
+	return c.s3.DeleteObjects(&s3.DeleteObjectsInput{
+		Bucket: aws.String(bucket),
+		Delete: &s3.Delete{Objects: objects},
+	})
+}
+
+// MultipartUpload uploads an object in parts, all but the last one must be at least 5 MiB. The upload is aborted if
+// a part fails.
+func (c *ZS3Client) MultipartUpload(t *test.SystemTest, bucket, key string, parts ...[]byte) (*s3.CompleteMultipartUploadOutput, error) {
+	t.Logf("uploading object [%s/%s] in %d parts using zs3server...", bucket, key, len(parts))
+	upload, err := c.s3.CreateMultipartUpload(&s3.CreateMultipartUploadInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+	if err != nil {
+		return nil, err
+	}
+
+	completed := make([]*s3.CompletedPart, 0, len(parts))
+	for i, part := range parts {
+		number := aws.Int64(int64(i + 1))
+		output, err := c.s3.UploadPart(&s3.UploadPartInput{
+			Bucket:     aws.String(bucket),
+			Key:        aws.String(key),
+			UploadId:   upload.UploadId,
+			PartNumber: number,
+			Body:       bytes.NewReader(part),
+		})
+		if err != nil {
+			_, abortErr := c.s3.AbortMultipartUpload(&s3.AbortMultipartUploadInput{Bucket: aws.String(bucket), Key: aws.String(key), UploadId: upload.UploadId})
+			if abortErr != nil {
+				t.Logf("aborting upload [%s] of [%s/%s]: %v", aws.StringValue(upload.UploadId), bucket, key, abortErr)
+			}
+			return nil, fmt.Errorf("uploading part %d: %w", i+1, err)
+		}
+		completed = append(completed, &s3.CompletedPart{ETag: output.ETag, PartNumber: number})
+	}
+
+	return c.s3.CompleteMultipartUpload(&s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(bucket),
+		Key:             aws.String(key),
+		UploadId:        upload.UploadId,
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: completed},
+	})
+}
+
+// PresignGetObject returns a URL anyone can read the object from until it expires
+func (c *ZS3Client) PresignGetObject(t *test.SystemTest, bucket, key string, expires time.Duration) (string, error) {
+	t.Logf("presigning download of object [%s/%s] for %v using zs3server...", bucket, key, expires)
+	req, _ := c.s3.GetObjectRequest(&s3.GetObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+	return req.Presign(expires)
+}
+
+// PresignPutObject returns a URL anyone can upload the object to with a PUT request until it expires
+func (c *ZS3Client) PresignPutObject(t *test.SystemTest, bucket, key string, expires time.Duration) (string, error) {
+	t.Logf("presigning upload of object [%s/%s] for %v using zs3server...", bucket, key, expires)
+	req, _ := c.s3.PutObjectRequest(&s3.PutObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+	return req.Presign(expires)
+}
@@ -4,7 +4,6 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"log"
 	"os"
 	"time"
 
@@ -12,7 +11,6 @@ import (
 	"github.com/0chain/system_test/internal/api/util/crypto"
 	"github.com/0chain/system_test/internal/api/util/test"
 	"github.com/stretchr/testify/require"
-	"gopkg.in/yaml.v3" //nolint
 )
 
 // ConfigPathEnv contains name of env variable
@@ -21,34 +19,46 @@ const ConfigPathEnv = "CONFIG_PATH"
 // DefaultConfigPath contains default value of ConfigPathEnv
 const DefaultConfigPath = "./config/api_tests_config.yaml"
 
+// Config is the configuration of the suites, see Load for how it is built
 type Config struct {
-	BlockWorker                 string `yaml:"block_worker"`
-	ZboxUrl                     string `yaml:"0box_url"`
+	BlockWorker                 string `yaml:"block_worker" required:"api,cli,tokenomics"`
+	ZboxUrl                     string `yaml:"0box_url" required:"api"`
+	ZboxPhoneNumber             string `yaml:"0box_phone_number"`
 	DefaultTestCaseTimeout      string `yaml:"default_test_case_timeout"`
-	ZS3ServerUrl                string `yaml:"zs3_server_url"`
-	ChimneyTestNetwork          string `yaml:"chimney_test_network"`
-	S3SecretKey                 string `yaml:"s3_secret_key"`
-	S3AccessKey                 string `yaml:"s3_access_key"`
+	SmokeTestMode               bool   `yaml:"smoke_test_mode" env:"SMOKE_TEST_MODE"`
+	ZS3ServerUrl                string `yaml:"zs3_server_url" required:"api"`
+	ZS3AccessKey                string `yaml:"zs3_access_key" required:"api" secret:"true"`
+	ZS3SecretKey                string `yaml:"zs3_secret_key" required:"api" secret:"true"`
+	ChimneyTestNetwork          string `yaml:"chimney_test_network" required:"api"`
+	S3SecretKey                 string `yaml:"s3_secret_key" secret:"true"`
+	S3AccessKey                 string `yaml:"s3_access_key" secret:"true"`
 	EthereumAddress             string `yaml:"ethereum_address"`
+	EthereumNodeURL             string `yaml:"ethereum_node_url" required:"cli"`
 	S3BucketName                string `yaml:"s3_bucket_name"`
 	S3BucketNameAlternate       string `yaml:"s3_bucket_name_alternate"`
-	BlobberOwnerWalletMnemonics string `yaml:"blobber_owner_wallet_mnemonics"`
-	OwnerWalletMnemonics        string `yaml:"owner_wallet_mnemonics"`
-}
+	S3Stub                      bool   `yaml:"s3_stub"`
+	BlobberOwnerWalletMnemonics string `yaml:"blobber_owner_wallet_mnemonics" required:"api" secret:"true"`
+	OwnerWalletMnemonics        string `yaml:"owner_wallet_mnemonics" required:"api" secret:"true"`
 
-func Parse(configPath string) *Config {
-	var result *Config
+	Bridge BridgeConfig `yaml:"bridge"`
 
-	file, err := os.ReadFile(configPath)
-	if err != nil {
-		log.Fatalln("Failed to read config file! due to error: " + err.Error())
-	}
-	err = yaml.Unmarshal(file, &result) //nolint
-	if err != nil {
-		log.Fatalln("failed to deserialise config file due to error: " + err.Error())
-	}
+	// Sources are the layers the configuration was built from, in order
+	Sources []string `yaml:"-"`
+}
+
+// BridgeConfig holds the Ethereum side of the bridge
+type BridgeConfig struct {
+	BridgeAddress      string `yaml:"bridge_address"`
+	TokenAddress       string `yaml:"token_address" required:"cli"`
+	AuthorizersAddress string `yaml:"authorizers_address"`
+	UniswapAddress     string `yaml:"uniswap_address"`
+	EthereumAddress    string `yaml:"ethereum_address" required:"cli"`
+	Password           string `yaml:"password" secret:"true"`
+}
 
-	return result
+// Defaults is the first layer of the configuration
+func Defaults() Config {
+	return Config{}
 }
 
 func GetHomeDir() (string, error) {
@@ -0,0 +1,223 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0chain/system_test/internal/api/util/keystore"
+	"github.com/0chain/system_test/internal/api/util/redact"
+	"github.com/0chain/system_test/internal/api/util/test"
+	"gopkg.in/yaml.v3" //nolint
+)
+
+// Suite names a test suite, whose required fields are listed in the required tag of Config
+type Suite string
+
+const (
+	API        Suite = "api"
+	CLI        Suite = "cli"
+	Tokenomics Suite = "tokenomics"
+)
+
+// ProfileEnv names the profile applied on top of the files, as "local", "devnet" or "ci", or the path of a profile file
+const ProfileEnv = "TEST_PROFILE"
+
+// EnvPrefix prefixes the environment variables overriding fields, as SYSTEM_TEST_BLOCK_WORKER for block_worker or
+// SYSTEM_TEST_BRIDGE_TOKEN_ADDRESS for bridge.token_address. Fields with an env tag are also read from that variable.
+const EnvPrefix = "SYSTEM_TEST_"
+
+// ProfileDir holds the profiles shared by the suites, relative to the suite directory the tests run in
+var ProfileDir = filepath.Join("..", "profiles")
+
+// Load builds the configuration of a suite from layers, each overriding the fields the previous ones set: the
+// defaults, the files in order, the profile named by ProfileEnv and the environment. It fails if a file cannot be
+// read or a field the suite requires is missing or invalid. Secrets are registered with the redact package.
+func Load(suite Suite, files ...string) (*Config, error) {
+	cfg := Defaults()
+	cfg.Sources = []string{"defaults"}
+
+	for _, file := range files {
+		if err := cfg.merge(file); err != nil {
+			return nil, err
+		}
+	}
+
+	if profile := os.Getenv(ProfileEnv); profile != "" {
+		path := profile
+		if filepath.Ext(path) == "" {
+			path = filepath.Join(ProfileDir, profile+".yaml")
+		}
+		if err := cfg.merge(path); err != nil {
+			return nil, fmt.Errorf("profile %s: %w", profile, err)
+		}
+	}
+
+	overridden, err := cfg.applyEnv()
+	if err != nil {
+		return nil, err
+	}
+	if len(overridden) > 0 {
+		cfg.Sources = append(cfg.Sources, "env "+strings.Join(overridden, ","))
+	}
+
+	var secrets []string
+	for _, f := range fields(&cfg) {
+		if f.secret && f.value.Kind() == reflect.String {
+			secrets = append(secrets, f.value.String())
+		}
+	}
+	redact.AddSecret(secrets...)
+
+	if err := cfg.Validate(suite); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// merge overrides the fields set in the YAML file at path, which may be encrypted
+func (c *Config) merge(path string) error {
+	content, err := keystore.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+	if err := yaml.Unmarshal(content, c); err != nil {
+		return fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+	c.Sources = append(c.Sources, path)
+	return nil
+}
+
+// applyEnv overrides the fields set in the environment and returns their variables
+func (c *Config) applyEnv() ([]string, error) {
+	var overridden []string
+	for _, f := range fields(c) {
+		for _, name := range f.envNames() {
+			value, ok := os.LookupEnv(name)
+			if !ok {
+				continue
+			}
+			if err := f.set(value); err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			overridden = append(overridden, name)
+		}
+	}
+	return overridden, nil
+}
+
+// Validate checks that the fields the suite requires are set and the set fields are valid
+func (c *Config) Validate(suite Suite) error {
+	var errs []error
+	for _, f := range fields(c) {
+		if f.requiredBy(suite) && f.value.IsZero() {
+			errs = append(errs, fmt.Errorf("%s is required by the %s tests, set it in the config files or %s", f.key, suite, f.envNames()[0]))
+		}
+	}
+	if c.DefaultTestCaseTimeout != "" {
+		if _, err := time.ParseDuration(c.DefaultTestCaseTimeout); err != nil {
+			errs = append(errs, fmt.Errorf("default_test_case_timeout: %w", err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("invalid configuration from %s:\n%w", strings.Join(c.Sources, ", "), err)
+	}
+	return nil
+}
+
+// Apply sets the test case timeout and the smoke test mode of the suite
+func (c *Config) Apply() {
+	if timeout, err := time.ParseDuration(c.DefaultTestCaseTimeout); err == nil {
+		test.DefaultTestTimeout = timeout
+	}
+	test.SmokeTestMode = c.SmokeTestMode
+}
+
+// Redacted returns the configuration as YAML with the secrets masked, to be logged
+func (c *Config) Redacted() string {
+	masked := *c
+	for _, f := range fields(&masked) {
+		if f.secret && !f.value.IsZero() {
+			_ = f.set(redact.Mask)
+		}
+	}
+	out, err := yaml.Marshal(masked)
+	if err != nil {
+		return err.Error()
+	}
+	return string(out)
+}
+
+// field is a leaf of Config, with its dotted YAML key
+type field struct {
+	key      string
+	value    reflect.Value
+	env      string
+	required []string
+	secret   bool
+}
+
+// fields returns the leaves of the configuration, depth first
+func fields(c *Config) []field {
+	return walk(reflect.ValueOf(c).Elem(), "")
+}
+
+func walk(v reflect.Value, prefix string) []field {
+	var leaves []field
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		name := strings.Split(sf.Tag.Get("yaml"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		key := prefix + name
+		if sf.Type.Kind() == reflect.Struct {
+			leaves = append(leaves, walk(v.Field(i), key+".")...)
+			continue
+		}
+		f := field{key: key, value: v.Field(i), env: sf.Tag.Get("env"), secret: sf.Tag.Get("secret") == "true"}
+		if required := sf.Tag.Get("required"); required != "" {
+			f.required = strings.Split(required, ",")
+		}
+		leaves = append(leaves, f)
+	}
+	return leaves
+}
+
+func (f field) envNames() []string {
+	names := []string{EnvPrefix + strings.ToUpper(strings.ReplaceAll(f.key, ".", "_"))}
+	if f.env != "" {
+		names = append(names, f.env)
+	}
+	return names
+}
+
+func (f field) requiredBy(suite Suite) bool {
+	for _, s := range f.required {
+		if Suite(s) == suite {
+			return true
+		}
+	}
+	return false
+}
+
+func (f field) set(value string) error {
+	switch f.value.Kind() {
+	case reflect.String:
+		f.value.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		f.value.SetBool(b)
+	default:
+		return fmt.Errorf("cannot set %s of kind %s", f.key, f.value.Kind())
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/0chain/system_test/internal/api/util/redact"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestLoadLayers(t *testing.T) {
+	dir := t.TempDir()
+	bridge := writeFile(t, dir, "config.yaml", "block_worker: https://zbox/dns\nbridge:\n  token_address: 0xtoken\n  ethereum_address: 0xeth\n")
+	suite := writeFile(t, dir, "suite.yaml", "block_worker: https://file/dns\ndefault_test_case_timeout: 3m\nethereum_node_url: https://eth\nbridge:\n  token_address: 0xsuite\n")
+	writeFile(t, dir, "profiles/local.yaml", "block_worker: http://localhost:9091\n")
+
+	ProfileDir = filepath.Join(dir, "profiles")
+	t.Cleanup(func() { ProfileDir = filepath.Join("..", "profiles") })
+	t.Setenv(ProfileEnv, "local")
+	t.Setenv("SYSTEM_TEST_BRIDGE_ETHEREUM_ADDRESS", "0xenv")
+	t.Setenv("SMOKE_TEST_MODE", "true")
+
+	cfg, err := Load(CLI, bridge, suite)
+	require.NoError(t, err)
+
+	require.Equal(t, "0xsuite", cfg.Bridge.TokenAddress)
+	require.Equal(t, "0xenv", cfg.Bridge.EthereumAddress)
+	require.Equal(t, "http://localhost:9091", cfg.BlockWorker)
+	require.Equal(t, "3m", cfg.DefaultTestCaseTimeout)
+	require.True(t, cfg.SmokeTestMode)
+	require.Equal(t, []string{"defaults", bridge, suite, filepath.Join(dir, "profiles", "local.yaml"), "env SMOKE_TEST_MODE,SYSTEM_TEST_BRIDGE_ETHEREUM_ADDRESS"}, cfg.Sources)
+}
+
+func TestLoadValidates(t *testing.T) {
+	dir := t.TempDir()
+	suite := writeFile(t, dir, "suite.yaml", "block_worker: https://file/dns\ndefault_test_case_timeout: soon\n")
+
+	_, err := Load(API, suite)
+	require.Error(t, err)
+	for _, problem := range []string{"0box_url is required by the api tests", "SYSTEM_TEST_0BOX_URL", "owner_wallet_mnemonics is required", "default_test_case_timeout"} {
+		require.Contains(t, err.Error(), problem)
+	}
+	require.NotContains(t, err.Error(), "block_worker")
+
+	_, err = Load(Tokenomics, suite+".missing")
+	require.ErrorContains(t, err, "reading config file")
+}
+
+func TestRedacted(t *testing.T) {
+	dir := t.TempDir()
+	suite := writeFile(t, dir, "suite.yaml", "block_worker: https://file/dns\ns3_secret_key: s3cr3tvalue\nbridge:\n  password: passw0rd-value\n")
+
+	cfg, err := Load(Tokenomics, suite)
+	require.NoError(t, err)
+
+	out := cfg.Redacted()
+	require.Contains(t, out, "block_worker: https://file/dns")
+	require.Contains(t, out, "s3_secret_key: '"+redact.Mask+"'")
+	require.NotContains(t, out, "s3cr3tvalue")
+	require.NotContains(t, out, "passw0rd-value")
+	require.True(t, strings.Contains(out, "s3_access_key: \"\""), out)
+	require.Equal(t, "s3cr3tvalue", cfg.S3SecretKey)
+	require.Equal(t, "output "+redact.Mask, redact.String("output s3cr3tvalue"))
+}
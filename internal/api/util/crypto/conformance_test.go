@@ -0,0 +1,149 @@
+package crypto
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/0chain/gosdk/core/zcncrypto"
+	"github.com/0chain/system_test/internal/api/model"
+	"github.com/0chain/system_test/internal/api/util/test"
+	climodel "github.com/0chain/system_test/internal/cli/model"
+	"github.com/stretchr/testify/require"
+)
+
+// signatureVector is a fixed mnemonic -> keys -> client id -> signatures test vector
+type signatureVector struct {
+	Scheme     string `json:"scheme"`
+	Mnemonic   string `json:"mnemonic"`
+	PublicKey  string `json:"public_key"`
+	PrivateKey string `json:"private_key"`
+	ClientID   string `json:"client_id"`
+	Signatures []struct {
+		Hash      string `json:"hash"`
+		Signature string `json:"signature"`
+	} `json:"signatures"`
+}
+
+func loadSignatureVectors(t *testing.T) []signatureVector {
+	content, err := os.ReadFile("testdata/signature_vectors.json")
+	require.NoError(t, err)
+
+	var vectors []signatureVector
+	require.NoError(t, json.Unmarshal(content, &vectors))
+	require.NotEmpty(t, vectors)
+
+	return vectors
+}
+
+func TestSignatureVectors(t *testing.T) {
+	for _, vector := range loadSignatureVectors(t) {
+		vector := vector
+		t.Run(vector.Scheme+"/"+vector.ClientID, func(t *testing.T) {
+			recovery, err := NewSignatureScheme(vector.Scheme)
+			require.NoError(t, err)
+			wallet, err := recovery.RecoverKeys(vector.Mnemonic)
+			require.NoError(t, err)
+			require.Equal(t, vector.PublicKey, wallet.Keys[0].PublicKey)
+			require.Equal(t, vector.PrivateKey, wallet.Keys[0].PrivateKey)
+			require.Equal(t, vector.ClientID, wallet.ClientID)
+
+			gosdkRecovery := zcncrypto.NewSignatureScheme(vector.Scheme)
+			gosdkWallet, err := gosdkRecovery.RecoverKeys(vector.Mnemonic)
+			require.NoError(t, err)
+			require.Equal(t, vector.PublicKey, gosdkWallet.Keys[0].PublicKey, "gosdk public key")
+			require.Equal(t, vector.PrivateKey, gosdkWallet.Keys[0].PrivateKey, "gosdk private key")
+			require.Equal(t, vector.ClientID, gosdkWallet.ClientID, "gosdk client id")
+
+			signer, err := NewSignatureScheme(vector.Scheme)
+			require.NoError(t, err)
+			require.NoError(t, signer.SetPrivateKey(vector.PrivateKey))
+
+			gosdkSigner := zcncrypto.NewSignatureScheme(vector.Scheme)
+			require.NoError(t, gosdkSigner.SetPrivateKey(vector.PrivateKey))
+
+			for _, expected := range vector.Signatures {
+				signature, err := signer.Sign(expected.Hash)
+				require.NoError(t, err)
+				require.Equal(t, expected.Signature, signature, "signature of %s", expected.Hash)
+
+				gosdkSignature, err := gosdkSigner.Sign(expected.Hash)
+				require.NoError(t, err)
+				require.Equal(t, expected.Signature, gosdkSignature, "gosdk signature of %s", expected.Hash)
+			}
+		})
+	}
+}
+
+func TestSignatureCrossVerification(t *testing.T) {
+	for _, vector := range loadSignatureVectors(t) {
+		vector := vector
+		t.Run(vector.Scheme+"/"+vector.ClientID, func(t *testing.T) {
+			signer, err := NewSignatureScheme(vector.Scheme)
+			require.NoError(t, err)
+			require.NoError(t, signer.SetPrivateKey(vector.PrivateKey))
+
+			gosdkSigner := zcncrypto.NewSignatureScheme(vector.Scheme)
+			require.NoError(t, gosdkSigner.SetPrivateKey(vector.PrivateKey))
+
+			verifier, err := NewSignatureScheme(vector.Scheme)
+			require.NoError(t, err)
+			require.NoError(t, verifier.SetPublicKey(vector.PublicKey))
+
+			gosdkVerifier := zcncrypto.NewSignatureScheme(vector.Scheme)
+			require.NoError(t, gosdkVerifier.SetPublicKey(vector.PublicKey))
+
+			for i, expected := range vector.Signatures {
+				signature, err := signer.Sign(expected.Hash)
+				require.NoError(t, err)
+				ok, err := gosdkVerifier.Verify(signature, expected.Hash)
+				require.NoError(t, err)
+				require.True(t, ok, "gosdk must verify local signature of %s", expected.Hash)
+
+				gosdkSignature, err := gosdkSigner.Sign(expected.Hash)
+				require.NoError(t, err)
+				ok, err = verifier.Verify(gosdkSignature, expected.Hash)
+				require.NoError(t, err)
+				require.True(t, ok, "local scheme must verify gosdk signature of %s", expected.Hash)
+
+				otherHash := vector.Signatures[(i+1)%len(vector.Signatures)].Hash
+				ok, _ = verifier.Verify(signature, otherHash)
+				require.False(t, ok, "signature of %s must not verify %s", expected.Hash, otherHash)
+				ok, _ = gosdkVerifier.Verify(signature, otherHash)
+				require.False(t, ok, "gosdk: signature of %s must not verify %s", expected.Hash, otherHash)
+			}
+		})
+	}
+}
+
+// TestSignHelpersVectors checks the key pair based helpers used by the api tests against the bls0chain vectors
+func TestSignHelpersVectors(t *testing.T) {
+	for _, vector := range loadSignatureVectors(t) {
+		if vector.Scheme != BLS0Chain {
+			continue
+		}
+
+		vector := vector
+		t.Run(vector.ClientID, func(testSetup *testing.T) {
+			t := test.NewSystemTest(testSetup)
+
+			keys := GenerateKeys(t, vector.Mnemonic)
+			require.Equal(t, vector.PublicKey, keys.PublicKey.SerializeToHexStr())
+			require.Equal(t, vector.PrivateKey, keys.PrivateKey.SerializeToHexStr())
+
+			secretKey := ToSecretKey(t, &climodel.WalletFile{Keys: []climodel.KeyPair{{PrivateKey: vector.PrivateKey}}})
+
+			for _, expected := range vector.Signatures {
+				signature, err := SignHashUsingSignatureScheme(expected.Hash, BLS0Chain, []*model.KeyPair{keys})
+				require.NoError(t, err)
+				require.Equal(t, expected.Signature, signature, "SignHashUsingSignatureScheme of %s", expected.Hash)
+
+				request := &model.TransactionPutRequest{Hash: expected.Hash}
+				SignTransaction(t, request, keys)
+				require.Equal(t, expected.Signature, request.Signature, "SignTransaction of %s", expected.Hash)
+
+				require.Equal(t, expected.Signature, SignHexString(t, expected.Hash, secretKey), "SignHexString of %s", expected.Hash)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package crypto
+
+import (
+	"fmt"
+
+	"github.com/0chain/errors"
+	"github.com/0chain/system_test/internal/api/model"
+	"github.com/0chain/system_test/internal/api/util/test"
+	"github.com/herumi/bls-go-binary/bls"
+	"github.com/stretchr/testify/require"
+)
+
+// GenerateWalletThresholdShares splits the wallet private key into n BLS shares, any threshold of which can sign for the wallet
+func GenerateWalletThresholdShares(t *test.SystemTest, keys *model.KeyPair, threshold, n int) []SignatureScheme {
+	require.True(t, threshold > 0 && threshold <= n, "threshold %d must be within [1, %d]", threshold, n)
+
+	scheme := NewHerumiScheme()
+	err := scheme.SetPrivateKey(keys.PrivateKey.SerializeToHexStr())
+	require.NoError(t, err)
+
+	blsLock.Lock()
+	shares, err := GenerateThresholdKeyShares(threshold, n, scheme)
+	blsLock.Unlock()
+	require.NoError(t, err, "failed to generate %d-of-%d key shares", threshold, n)
+	require.Len(t, shares, n)
+
+	return shares
+}
+
+// SignWithThresholdShares signs hash with every given share and combines the partial signatures with Add.
+// Each share key is weighted by its Lagrange coefficient over the given share ids, so the combined signature
+// equals the signature of the original key only when at least threshold shares are provided.
+func SignWithThresholdShares(hash string, shares []SignatureScheme) (string, error) {
+	if len(shares) == 0 {
+		return "", errors.New("threshold_sign", "no key shares provided")
+	}
+
+	blsLock.Lock()
+	defer blsLock.Unlock()
+
+	ids := make([]bls.Fr, len(shares))
+	for i, share := range shares {
+		hs, ok := share.(*HerumiScheme)
+		if !ok {
+			return "", errors.New("threshold_sign", "invalid encryption scheme")
+		}
+
+		var id bls.ID
+		if err := id.SetHexString(hs.Ids); err != nil {
+			return "", err
+		}
+		if err := ids[i].Deserialize(id.Serialize()); err != nil {
+			return "", err
+		}
+	}
+
+	var signature string
+	for i, share := range shares {
+		weightedKey, err := lagrangeWeightedKey(share.(*HerumiScheme), ids, i)
+		if err != nil {
+			return "", err
+		}
+
+		partial := NewHerumiScheme()
+		if err = partial.SetPrivateKey(weightedKey); err != nil {
+			return "", err
+		}
+
+		if signature == "" {
+			signature, err = partial.Sign(hash)
+		} else {
+			signature, err = partial.Add(signature, hash)
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return signature, nil
+}
+
+// lagrangeWeightedKey returns the share private key multiplied by its Lagrange coefficient at zero
+func lagrangeWeightedKey(share *HerumiScheme, ids []bls.Fr, index int) (string, error) {
+	privateKey, err := share.GetPrivateKeyAsByteArray()
+	if err != nil {
+		return "", err
+	}
+
+	var sk bls.SecretKey
+	if err = sk.SetLittleEndian(privateKey); err != nil {
+		return "", err
+	}
+
+	var coefficient, difference bls.Fr
+	coefficient.SetInt64(1)
+	for j := range ids {
+		if j == index {
+			continue
+		}
+		if ids[j].IsEqual(&ids[index]) {
+			return "", errors.New("threshold_sign", fmt.Sprintf("duplicate key share id %s", share.Ids))
+		}
+		bls.FrSub(&difference, &ids[j], &ids[index])
+		bls.FrMul(&coefficient, &coefficient, &ids[j])
+		bls.FrDiv(&coefficient, &coefficient, &difference)
+	}
+
+	bls.FrMul(bls.CastFromSecretKey(&sk), bls.CastFromSecretKey(&sk), &coefficient)
+
+	return sk.SerializeToHexStr(), nil
+}
@@ -0,0 +1,71 @@
+package crypto
+
+import (
+	"testing"
+
+	"github.com/0chain/system_test/internal/api/model"
+	"github.com/0chain/system_test/internal/api/util/test"
+	"github.com/stretchr/testify/require"
+)
+
+const thresholdMnemonic = "critic intact hurdle cabbage bench cotton exact fix enlist glue cable vehicle recycle dwarf cherry shove fun urge wrap ensure marriage dynamic pave invest"
+
+// subsets returns every combination of k of the shares
+func subsets(shares []SignatureScheme, k int) [][]SignatureScheme {
+	if k == 0 {
+		return [][]SignatureScheme{nil}
+	}
+	var combinations [][]SignatureScheme
+	for i := 0; i <= len(shares)-k; i++ {
+		for _, rest := range subsets(shares[i+1:], k-1) {
+			combinations = append(combinations, append([]SignatureScheme{shares[i]}, rest...))
+		}
+	}
+	return combinations
+}
+
+func TestSignWithThresholdShares(testSetup *testing.T) {
+	t := test.NewSystemTest(testSetup)
+
+	const (
+		threshold = 3
+		numShares = 5
+		hash      = "3b4c9d8e2f1a0b7c6d5e4f3a2b1c0d9e8f7a6b5c4d3e2f1a0b9c8d7e6f5a4b3c"
+	)
+
+	keys := GenerateKeys(t, thresholdMnemonic)
+	expected, err := SignHashUsingSignatureScheme(hash, BLS0Chain, []*model.KeyPair{keys})
+	require.NoError(t, err)
+
+	verifier := NewHerumiScheme()
+	require.NoError(t, verifier.SetPublicKey(keys.PublicKey.SerializeToHexStr()))
+
+	shares := GenerateWalletThresholdShares(t, keys, threshold, numShares)
+	for k := threshold; k <= numShares; k++ {
+		for _, signers := range subsets(shares, k) {
+			signature, err := SignWithThresholdShares(hash, signers)
+			require.NoError(t, err)
+			require.Equal(t, expected, signature, "%d of %d shares must sign as the original key", k, numShares)
+		}
+	}
+
+	for _, signers := range subsets(shares, threshold-1) {
+		signature, err := SignWithThresholdShares(hash, signers)
+		require.NoError(t, err)
+		require.NotEqual(t, expected, signature, "%d shares must not sign as the original key", threshold-1)
+		ok, _ := verifier.Verify(signature, hash)
+		require.False(t, ok, "signature of %d shares must not verify with the original public key", threshold-1)
+	}
+}
+
+func TestSignWithThresholdSharesRejectsInvalidShares(testSetup *testing.T) {
+	t := test.NewSystemTest(testSetup)
+
+	shares := GenerateWalletThresholdShares(t, GenerateKeys(t, thresholdMnemonic), 2, 3)
+
+	_, err := SignWithThresholdShares("hash", nil)
+	require.ErrorContains(t, err, "no key shares")
+
+	_, err = SignWithThresholdShares("hash", []SignatureScheme{shares[0], shares[0]})
+	require.ErrorContains(t, err, "duplicate key share id")
+}
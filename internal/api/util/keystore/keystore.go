@@ -0,0 +1,243 @@
+package keystore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// PassphraseEnv contains name of env variable holding the keystore passphrase
+const PassphraseEnv = "WALLET_KEYSTORE_PASSPHRASE"
+
+// Extension is appended to the name of an encrypted file
+const Extension = ".enc"
+
+const (
+	version     = 1
+	kdfScrypt   = "scrypt"
+	cipherAES   = "aes-256-gcm"
+	scryptN     = 1 << 15
+	scryptR     = 8
+	scryptP     = 1
+	keyLength   = 32
+	saltLength  = 32
+	defaultMode = 0600
+)
+
+// ErrNoPassphrase is returned when an encrypted file is read while PassphraseEnv is not set
+var ErrNoPassphrase = errors.New("keystore passphrase is not set, export " + PassphraseEnv)
+
+type kdfParams struct {
+	N    int    `json:"n"`
+	R    int    `json:"r"`
+	P    int    `json:"p"`
+	Salt string `json:"salt"`
+}
+
+// File is the on-disk format of an encrypted file
+type File struct {
+	Version    int       `json:"version"`
+	KDF        string    `json:"kdf"`
+	KDFParams  kdfParams `json:"kdfparams"`
+	Cipher     string    `json:"cipher"`
+	Nonce      string    `json:"nonce"`
+	CipherText string    `json:"ciphertext"`
+}
+
+// IsEncrypted reports whether content is a keystore file
+func IsEncrypted(content []byte) bool {
+	var file File
+	if err := json.Unmarshal(content, &file); err != nil {
+		return false
+	}
+	return file.KDF != "" && file.Cipher != "" && file.CipherText != ""
+}
+
+// Encrypt seals plaintext with a key derived from passphrase and returns the keystore file content
+func Encrypt(plaintext []byte, passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, ErrNoPassphrase
+	}
+
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	params := kdfParams{N: scryptN, R: scryptR, P: scryptP, Salt: hex.EncodeToString(salt)}
+	aead, err := newAEAD(passphrase, params)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(File{
+		Version:    version,
+		KDF:        kdfScrypt,
+		KDFParams:  params,
+		Cipher:     cipherAES,
+		Nonce:      hex.EncodeToString(nonce),
+		CipherText: hex.EncodeToString(aead.Seal(nil, nonce, plaintext, nil)),
+	}, "", "  ")
+}
+
+// Decrypt opens the keystore file content with passphrase
+func Decrypt(content []byte, passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, ErrNoPassphrase
+	}
+
+	var file File
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("decoding keystore: %w", err)
+	}
+	if file.Version != version || file.KDF != kdfScrypt || file.Cipher != cipherAES {
+		return nil, fmt.Errorf("unsupported keystore version %d with kdf %q and cipher %q", file.Version, file.KDF, file.Cipher)
+	}
+
+	nonce, err := hex.DecodeString(file.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("decoding keystore nonce: %w", err)
+	}
+	cipherText, err := hex.DecodeString(file.CipherText)
+	if err != nil {
+		return nil, fmt.Errorf("decoding keystore ciphertext: %w", err)
+	}
+
+	aead, err := newAEAD(passphrase, file.KDFParams)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("invalid keystore nonce length %d", len(nonce))
+	}
+
+	plaintext, err := aead.Open(nil, nonce, cipherText, nil)
+	if err != nil {
+		return nil, errors.New("could not decrypt keystore, wrong passphrase or corrupted file")
+	}
+
+	return plaintext, nil
+}
+
+// ReadFile reads the file at path, falling back to its encrypted counterpart path+Extension.
+// Encrypted content is decrypted with the passphrase from PassphraseEnv, plaintext content is returned as is.
+func ReadFile(path string) ([]byte, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !strings.HasSuffix(path, Extension) {
+		content, err = os.ReadFile(path + Extension)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !IsEncrypted(content) {
+		return content, nil
+	}
+
+	plaintext, err := Decrypt(content, os.Getenv(PassphraseEnv))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return plaintext, nil
+}
+
+// EncryptFile writes the encrypted content of path to path+Extension
+func EncryptFile(path, passphrase string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	if IsEncrypted(content) {
+		return "", fmt.Errorf("%s is already encrypted", path)
+	}
+
+	encrypted, err := Encrypt(content, passphrase)
+	if err != nil {
+		return "", err
+	}
+
+	encryptedPath := path + Extension
+	return encryptedPath, os.WriteFile(encryptedPath, encrypted, defaultMode)
+}
+
+// DecryptFile writes the decrypted content of an encrypted path to path without Extension
+func DecryptFile(path, passphrase string) (string, error) {
+	if !strings.HasSuffix(path, Extension) {
+		return "", fmt.Errorf("%s has no %s extension", path, Extension)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := Decrypt(content, passphrase)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
+
+	plainPath := strings.TrimSuffix(path, Extension)
+	return plainPath, os.WriteFile(plainPath, plaintext, defaultMode)
+}
+
+// Materialize decrypts every encrypted file in dir that has no plaintext counterpart yet, so the CLIs can read them.
+// It returns the paths of the written files, which should be removed once the suite is done.
+// If a file cannot be decrypted, the files already written are removed before the error is returned.
+func Materialize(dir string) ([]string, error) {
+	encrypted, err := filepath.Glob(filepath.Join(dir, "*"+Extension))
+	if err != nil {
+		return nil, err
+	}
+
+	var written []string
+	for _, path := range encrypted {
+		if _, err := os.Stat(strings.TrimSuffix(path, Extension)); err == nil {
+			continue
+		}
+
+		plainPath, err := DecryptFile(path, os.Getenv(PassphraseEnv))
+		if err != nil {
+			for _, plain := range written {
+				_ = os.Remove(plain)
+			}
+			return nil, err
+		}
+		written = append(written, plainPath)
+	}
+
+	return written, nil
+}
+
+func newAEAD(passphrase string, params kdfParams) (cipher.AEAD, error) {
+	salt, err := hex.DecodeString(params.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("decoding keystore salt: %w", err)
+	}
+
+	key, err := scrypt.Key([]byte(passphrase), salt, params.N, params.R, params.P, keyLength)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
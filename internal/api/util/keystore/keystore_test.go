@@ -0,0 +1,134 @@
+package keystore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	passphrase = "correct horse battery staple"
+	wallet     = `{"client_id":"9636ab82","keys":[{"private_key":"secret"}]}`
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	encrypted, err := Encrypt([]byte(wallet), passphrase)
+	require.NoError(t, err)
+	require.True(t, IsEncrypted(encrypted))
+	require.NotContains(t, string(encrypted), "secret")
+
+	plaintext, err := Decrypt(encrypted, passphrase)
+	require.NoError(t, err)
+	require.Equal(t, wallet, string(plaintext))
+
+	again, err := Encrypt([]byte(wallet), passphrase)
+	require.NoError(t, err)
+	require.NotEqual(t, encrypted, again, "every encryption uses a fresh salt and nonce")
+
+	require.False(t, IsEncrypted([]byte(wallet)))
+	_, err = Encrypt([]byte(wallet), "")
+	require.ErrorIs(t, err, ErrNoPassphrase)
+}
+
+func TestDecryptWrongPassphrase(t *testing.T) {
+	encrypted, err := Encrypt([]byte(wallet), passphrase)
+	require.NoError(t, err)
+
+	_, err = Decrypt(encrypted, "wrong")
+	require.ErrorContains(t, err, "wrong passphrase")
+
+	_, err = Decrypt(encrypted, "")
+	require.ErrorIs(t, err, ErrNoPassphrase)
+}
+
+func TestReadFile(t *testing.T) {
+	dir := t.TempDir()
+	plainPath := filepath.Join(dir, "plain_wallet.json")
+	require.NoError(t, os.WriteFile(plainPath, []byte(wallet), 0600))
+
+	content, err := ReadFile(plainPath)
+	require.NoError(t, err)
+	require.Equal(t, wallet, string(content), "plaintext files are read as is")
+
+	encryptedPath, err := EncryptFile(plainPath, passphrase)
+	require.NoError(t, err)
+	require.Equal(t, plainPath+Extension, encryptedPath)
+	require.NoError(t, os.Remove(plainPath))
+
+	t.Setenv(PassphraseEnv, passphrase)
+	content, err = ReadFile(plainPath)
+	require.NoError(t, err, "a missing file falls back to its encrypted counterpart")
+	require.Equal(t, wallet, string(content))
+
+	t.Setenv(PassphraseEnv, "")
+	_, err = ReadFile(plainPath)
+	require.ErrorIs(t, err, ErrNoPassphrase)
+
+	_, err = ReadFile(filepath.Join(dir, "missing_wallet.json"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestMaterialize(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a_wallet.json", "b_wallet.json"} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(wallet), 0600))
+		_, err := EncryptFile(path, passphrase)
+		require.NoError(t, err)
+		require.NoError(t, os.Remove(path))
+	}
+	// a plaintext counterpart is kept as is
+	kept := filepath.Join(dir, "b_wallet.json")
+	require.NoError(t, os.WriteFile(kept, []byte("{}"), 0600))
+
+	t.Setenv(PassphraseEnv, passphrase)
+	written, err := Materialize(dir)
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(dir, "a_wallet.json")}, written)
+
+	content, err := os.ReadFile(written[0])
+	require.NoError(t, err)
+	require.Equal(t, wallet, string(content))
+	content, err = os.ReadFile(kept)
+	require.NoError(t, err)
+	require.Equal(t, "{}", string(content))
+
+	// the suites remove the written files once they are done
+	for _, path := range written {
+		require.NoError(t, os.Remove(path))
+	}
+	remaining, err := filepath.Glob(filepath.Join(dir, "*"))
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{
+		filepath.Join(dir, "a_wallet.json"+Extension),
+		filepath.Join(dir, "b_wallet.json"+Extension),
+		kept,
+	}, remaining)
+
+	t.Setenv(PassphraseEnv, "wrong")
+	_, err = Materialize(dir)
+	require.ErrorContains(t, err, "wrong passphrase")
+}
+
+func TestMaterializeRemovesPartialOutput(t *testing.T) {
+	dir := t.TempDir()
+	for name, key := range map[string]string{"a_wallet.json": passphrase, "b_wallet.json": "another passphrase"} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(wallet), 0600))
+		_, err := EncryptFile(path, key)
+		require.NoError(t, err)
+		require.NoError(t, os.Remove(path))
+	}
+
+	// a_wallet.json is decrypted before b_wallet.json fails
+	t.Setenv(PassphraseEnv, passphrase)
+	written, err := Materialize(dir)
+	require.ErrorContains(t, err, "wrong passphrase")
+	require.Empty(t, written)
+
+	plaintext, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	require.NoError(t, err)
+	require.Empty(t, plaintext, "no decrypted wallet is left on disk")
+}
@@ -0,0 +1,332 @@
+package ledger
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/0chain/system_test/internal/api/model"
+	"github.com/0chain/system_test/internal/api/util/client"
+	"github.com/0chain/system_test/internal/api/util/test"
+	"github.com/0chain/system_test/internal/currency"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	confirmationTimeout = time.Minute
+	confirmationBackoff = 2 * time.Second
+)
+
+// AccountKind is the type of a balance holder tracked by the ledger
+type AccountKind string
+
+const (
+	Wallet        AccountKind = "wallet"
+	StakePool     AccountKind = "stake_pool"
+	ReadPool      AccountKind = "read_pool"
+	WritePool     AccountKind = "write_pool"
+	ChallengePool AccountKind = "challenge_pool"
+)
+
+// Account is a balance holder, ID is the client, provider or allocation id depending on Kind
+type Account struct {
+	Kind AccountKind
+	ID   string
+	// ProviderType is the provider type of a stake pool, e.g. "3" for blobbers
+	ProviderType string
+}
+
+func (a Account) String() string {
+	return fmt.Sprintf("%s:%s", a.Kind, a.ID)
+}
+
+// Movement is a known flow of tokens into (positive) or out of (negative) the tracked accounts
+type Movement struct {
+	Reason string
+	Amount int64
+}
+
+// Ledger snapshots the balances of a set of accounts around a scenario and checks that
+// no tokens were created or lost, apart from the fees and mints it knows about
+type Ledger struct {
+	apiClient *client.APIClient
+	accounts  []Account
+	// balance reads the balance of an account in SAS, from the chain unless replaced by tests
+	balance func(t *test.SystemTest, account Account) int64
+
+	// Tolerance is the absolute drift in SAS accepted by AssertConserved
+	Tolerance int64
+
+	mu           sync.Mutex
+	before       map[Account]int64
+	transactions []model.TransactionPutRequest
+	movements    []Movement
+	stop         func()
+}
+
+// New creates a ledger reading balances and observing transactions through apiClient
+func New(apiClient *client.APIClient) *Ledger {
+	l := &Ledger{apiClient: apiClient}
+	l.balance = l.chainBalance
+	return l
+}
+
+// Track adds accounts to the ledger, it must be called before Begin
+func (l *Ledger) Track(accounts ...Account) *Ledger {
+	l.accounts = append(l.accounts, accounts...)
+	return l
+}
+
+// TrackWallet tracks the balance of the wallet and the transactions it submits
+func (l *Ledger) TrackWallet(clientID string) *Ledger {
+	return l.Track(Account{Kind: Wallet, ID: clientID})
+}
+
+// TrackStakePool tracks the stake, provider rewards and delegate rewards of a provider stake pool
+func (l *Ledger) TrackStakePool(providerID, providerType string) *Ledger {
+	return l.Track(Account{Kind: StakePool, ID: providerID, ProviderType: providerType})
+}
+
+// TrackReadPool tracks the read pool of a client
+func (l *Ledger) TrackReadPool(clientID string) *Ledger {
+	return l.Track(Account{Kind: ReadPool, ID: clientID})
+}
+
+// TrackWritePool tracks the write pool of an allocation
+func (l *Ledger) TrackWritePool(allocationID string) *Ledger {
+	return l.Track(Account{Kind: WritePool, ID: allocationID})
+}
+
+// TrackChallengePool tracks the challenge pool of an allocation
+func (l *Ledger) TrackChallengePool(allocationID string) *Ledger {
+	return l.Track(Account{Kind: ChallengePool, ID: allocationID})
+}
+
+// Mint records tokens entering the tracked accounts from outside, e.g. block rewards paid to a tracked stake pool
+func (l *Ledger) Mint(reason string, amount int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.movements = append(l.movements, Movement{Reason: reason, Amount: amount})
+}
+
+// Burn records tokens leaving the tracked accounts, e.g. tokens sent to an untracked smart contract pool
+func (l *Ledger) Burn(reason string, amount int64) {
+	l.Mint(reason, -amount)
+}
+
+// Begin snapshots the tracked balances and starts recording the transactions submitted by tracked wallets
+func (l *Ledger) Begin(t *test.SystemTest) {
+	require.Nil(t, l.before, "ledger already begun")
+	require.NotEmpty(t, l.accounts, "ledger tracks no accounts")
+
+	l.before = l.snapshot(t)
+	l.stop = func() {}
+	if l.apiClient != nil {
+		l.stop = l.apiClient.ObserveTransactions(l.observe)
+	}
+	t.Cleanup(l.stop)
+}
+
+// AssertConserved snapshots the tracked balances again and fails t if their change is not explained by
+// the fees of the recorded transactions, faucet pours, transfers to untracked wallets and the recorded mints
+func (l *Ledger) AssertConserved(t *test.SystemTest) {
+	drift, report := l.settle(t)
+	require.True(t, l.conserved(drift),
+		"tokens not conserved: drift of %+d SAS (%s ZCN) across tracked accounts\n%s", drift, currency.SASToZCN(drift), report)
+}
+
+// conserved reports whether a drift is within the tolerance
+func (l *Ledger) conserved(drift int64) bool {
+	return abs(drift) <= l.Tolerance
+}
+
+// settle snapshots the tracked balances again and returns the change not explained by the movements, with a report
+// of the balances and movements
+func (l *Ledger) settle(t *test.SystemTest) (drift int64, report string) {
+	require.NotNil(t, l.before, "ledger not begun")
+	l.stop()
+
+	movements := append(l.transactionMovements(t), l.recordedMovements()...)
+	after := l.snapshot(t)
+
+	var (
+		beforeTotal, afterTotal, expected int64
+		sheet                             strings.Builder
+	)
+	w := tabwriter.NewWriter(&sheet, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "account\tbefore\tafter\tdelta\t")
+	for _, account := range l.accounts {
+		beforeTotal += l.before[account]
+		afterTotal += after[account]
+		fmt.Fprintf(w, "%s\t%d\t%d\t%+d\t\n", account, l.before[account], after[account], after[account]-l.before[account])
+	}
+	fmt.Fprintln(w, "movement\t\t\tamount\t")
+	for _, movement := range movements {
+		expected += movement.Amount
+		fmt.Fprintf(w, "%s\t\t\t%+d\t\n", movement.Reason, movement.Amount)
+	}
+	_ = w.Flush()
+
+	drift = afterTotal - beforeTotal - expected
+	t.Logf("Ledger of %d accounts, total before %s, after %s, expected change %+d SAS, drift %+d SAS\n%s",
+		len(l.accounts), currency.Coin(beforeTotal), currency.Coin(afterTotal), expected, drift, sheet.String())
+	return drift, sheet.String()
+}
+
+func (l *Ledger) observe(request model.TransactionPutRequest) {
+	if !l.tracks(Account{Kind: Wallet, ID: request.ClientId}) {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.transactions = append(l.transactions, request)
+}
+
+func (l *Ledger) recordedMovements() []Movement {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Movement(nil), l.movements...)
+}
+
+// transactionMovements confirms the recorded transactions and returns the token flows they caused
+func (l *Ledger) transactionMovements(t *test.SystemTest) []Movement {
+	l.mu.Lock()
+	transactions := append([]model.TransactionPutRequest(nil), l.transactions...)
+	l.mu.Unlock()
+
+	var movements []Movement
+	for _, request := range transactions {
+		confirmation := l.confirm(t, request.Hash)
+		if confirmation == nil {
+			// never included in a block, so neither the fee nor the value were taken
+			continue
+		}
+
+		if confirmation.Transaction != nil && confirmation.Transaction.TransactionFee > 0 {
+			movements = append(movements, Movement{
+				Reason: fmt.Sprintf("fee of %s", shortHash(request.Hash)),
+				Amount: -confirmation.Transaction.TransactionFee,
+			})
+		}
+
+		if confirmation.Status != client.TxSuccessfulStatus || request.TransactionValue == 0 {
+			continue
+		}
+
+		switch {
+		case request.ToClientId == client.FaucetSmartContractAddress && transactionName(request) == "pour":
+			movements = append(movements, Movement{
+				Reason: fmt.Sprintf("faucet pour %s", shortHash(request.Hash)),
+				Amount: request.TransactionValue,
+			})
+		case request.TransactionType == client.SendTxType && !l.tracks(Account{Kind: Wallet, ID: request.ToClientId}):
+			movements = append(movements, Movement{
+				Reason: fmt.Sprintf("send %s to untracked %s", shortHash(request.Hash), request.ToClientId),
+				Amount: -request.TransactionValue,
+			})
+		}
+	}
+
+	return movements
+}
+
+func (l *Ledger) confirm(t *test.SystemTest, hash string) *model.TransactionGetConfirmationResponse {
+	deadline := time.Now().Add(confirmationTimeout)
+	for {
+		confirmation, resp, err := l.apiClient.V1TransactionGetConfirmation(t,
+			model.TransactionGetConfirmationRequest{Hash: hash},
+			client.HttpOkStatus)
+		if err == nil && resp != nil && confirmation != nil {
+			return confirmation
+		}
+
+		if time.Now().After(deadline) {
+			t.Logf("Transaction [%s] was not confirmed within [%v], assuming it was not included", hash, confirmationTimeout)
+			return nil
+		}
+		time.Sleep(confirmationBackoff)
+	}
+}
+
+func (l *Ledger) snapshot(t *test.SystemTest) map[Account]int64 {
+	balances := make(map[Account]int64, len(l.accounts))
+	for _, account := range l.accounts {
+		balances[account] = l.balance(t, account)
+	}
+	return balances
+}
+
+func (l *Ledger) chainBalance(t *test.SystemTest, account Account) int64 {
+	switch account.Kind {
+	case Wallet:
+		response, resp, err := l.apiClient.V1ClientGetBalance(t, model.ClientGetBalanceRequest{ClientID: account.ID}, client.HttpOkStatus)
+		if resp != nil && resp.StatusCode() == client.HttpBadRequestStatus {
+			// a wallet the chain has not seen yet holds nothing
+			return 0
+		}
+		require.NoError(t, err, "reading balance of %s", account)
+		return response.Balance
+	case ReadPool:
+		response, _, err := l.apiClient.V1ClientGetReadPoolBalance(t, model.ClientGetReadPoolBalanceRequest{ClientID: account.ID}, client.HttpOkStatus)
+		require.NoError(t, err, "reading balance of %s", account)
+		return response.Balance
+	case WritePool:
+		response, _, err := l.apiClient.V1SCRestGetAllocation(t, model.SCRestGetAllocationRequest{AllocationID: account.ID}, client.HttpOkStatus)
+		require.NoError(t, err, "reading balance of %s", account)
+		return response.WritePool
+	case ChallengePool:
+		response, _, err := l.apiClient.V1SCRestGetChallengePoolStat(t, model.SCRestGetChallengePoolStatRequest{AllocationID: account.ID}, client.HttpOkStatus)
+		require.NoError(t, err, "reading balance of %s", account)
+		return response.Balance
+	case StakePool:
+		response, _, err := l.apiClient.V1SCRestGetStakePoolStat(t, model.SCRestGetStakePoolStatRequest{
+			ProviderID:   account.ID,
+			ProviderType: account.ProviderType,
+		}, client.HttpOkStatus)
+		require.NoError(t, err, "reading balance of %s", account)
+		// unclaimed rewards are held by the stake pool until they are collected
+		balance := response.Balance + response.Rewards
+		for _, delegate := range response.Delegate {
+			balance += delegate.Rewards
+		}
+		return balance
+	}
+
+	require.FailNow(t, "unknown account kind", "%s", account)
+	return 0
+}
+
+func (l *Ledger) tracks(account Account) bool {
+	for _, tracked := range l.accounts {
+		if tracked.Kind == account.Kind && tracked.ID == account.ID {
+			return true
+		}
+	}
+	return false
+}
+
+func transactionName(request model.TransactionPutRequest) string {
+	var data struct {
+		Name string `json:"name"`
+	}
+	_ = json.Unmarshal([]byte(request.TransactionData), &data)
+	return data.Name
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 8 {
+		return hash[:8]
+	}
+	return hash
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
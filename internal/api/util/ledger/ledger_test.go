@@ -0,0 +1,130 @@
+package ledger
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/0chain/system_test/internal/api/util/test"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	alice     = Account{Kind: Wallet, ID: "alice"}
+	bob       = Account{Kind: Wallet, ID: "bob"}
+	readPool  = Account{Kind: ReadPool, ID: "alice"}
+	stakePool = Account{Kind: StakePool, ID: "blobber", ProviderType: "3"}
+)
+
+// fakeBalances is the balance source of a ledger, changed by the tests between Begin and settling
+type fakeBalances map[Account]int64
+
+func (f fakeBalances) balance(_ *test.SystemTest, account Account) int64 {
+	return f[account]
+}
+
+func newLedger(balances fakeBalances, accounts ...Account) *Ledger {
+	l := &Ledger{balance: balances.balance}
+	return l.Track(accounts...)
+}
+
+func TestConserved(testSetup *testing.T) {
+	t := test.NewSystemTest(testSetup)
+	balances := fakeBalances{alice: 1000, bob: 500, readPool: 0}
+	l := newLedger(balances, alice, bob, readPool)
+	l.Begin(t)
+
+	// alice sends 100 to bob and locks 200 in her read pool
+	balances[alice] -= 300
+	balances[bob] += 100
+	balances[readPool] += 200
+
+	drift, _ := l.settle(t)
+	require.Zero(t, drift)
+}
+
+func TestLeak(testSetup *testing.T) {
+	t := test.NewSystemTest(testSetup)
+	balances := fakeBalances{alice: 1000, readPool: 0}
+	l := newLedger(balances, alice, readPool)
+	l.Begin(t)
+
+	balances[alice] -= 300
+	balances[readPool] += 250
+
+	drift, report := l.settle(t)
+	require.Equal(t, int64(-50), drift)
+	require.False(t, l.conserved(drift), "AssertConserved fails on a leak")
+	require.Contains(t, report, "read_pool:alice")
+}
+
+func TestMintAndBurn(testSetup *testing.T) {
+	t := test.NewSystemTest(testSetup)
+	balances := fakeBalances{alice: 1000, stakePool: 10000}
+	l := newLedger(balances, alice, stakePool)
+	l.Begin(t)
+
+	balances[stakePool] += 60
+	l.Mint("block reward", 60)
+	balances[alice] -= 25
+	l.Burn("sent to untracked", 25)
+
+	l.AssertConserved(t)
+
+	l = newLedger(balances, alice, stakePool)
+	l.Begin(t)
+	balances[stakePool] += 60
+	drift, _ := l.settle(t)
+	require.Equal(t, int64(60), drift, "an unrecorded mint is drift")
+}
+
+func TestToleranceBoundary(testSetup *testing.T) {
+	t := test.NewSystemTest(testSetup)
+	for _, tc := range []struct {
+		change    int64
+		conserved bool
+	}{
+		{change: 10, conserved: true},
+		{change: -10, conserved: true},
+		{change: 11, conserved: false},
+		{change: -11, conserved: false},
+	} {
+		balances := fakeBalances{alice: 1000}
+		l := newLedger(balances, alice)
+		l.Tolerance = 10
+		l.Begin(t)
+		balances[alice] += tc.change
+
+		drift, _ := l.settle(t)
+		require.Equal(t, tc.change, drift)
+		require.Equal(t, tc.conserved, l.conserved(drift), "change of %+d", tc.change)
+	}
+
+	balances := fakeBalances{alice: 1000}
+	l := newLedger(balances, alice)
+	l.Tolerance = 10
+	l.Begin(t)
+	balances[alice] -= 10
+	l.AssertConserved(t)
+}
+
+func TestReport(testSetup *testing.T) {
+	t := test.NewSystemTest(testSetup)
+	balances := fakeBalances{alice: 1000, stakePool: 10000}
+	l := newLedger(balances, alice, stakePool)
+	l.Begin(t)
+
+	balances[alice] -= 40
+	balances[stakePool] += 100
+	l.Mint("block reward", 100)
+
+	drift, report := l.settle(t)
+	require.Equal(t, int64(-40), drift)
+
+	lines := strings.Split(strings.TrimRight(report, "\n"), "\n")
+	require.Len(t, lines, 5, report)
+	require.Equal(t, []string{"account", "before", "after", "delta"}, strings.Fields(lines[0]))
+	require.Equal(t, []string{"wallet:alice", "1000", "960", "-40"}, strings.Fields(lines[1]))
+	require.Equal(t, []string{"stake_pool:blobber", "10000", "10100", "+100"}, strings.Fields(lines[2]))
+	require.Equal(t, []string{"movement", "amount"}, strings.Fields(lines[3]))
+	require.Equal(t, []string{"block", "reward", "+100"}, strings.Fields(lines[4]))
+}
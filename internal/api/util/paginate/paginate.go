@@ -0,0 +1,188 @@
+package paginate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Defaults of a new Iterator
+const (
+	DefaultLimit   = 20
+	DefaultRetries = 3
+	DefaultBackoff = 500 * time.Millisecond
+)
+
+// Iterator yields the items of a paginated sharder list endpoint one at a time, fetching a page when the
+// previous one is consumed. Use it like bufio.Scanner:
+//
+//	it := paginate.Offset[model.StorageNode](url, nil)
+//	for it.Next() {
+//		node := it.Item()
+//	}
+//	if err := it.Err(); err != nil {
+type Iterator[T any] struct {
+	url     string
+	params  map[string]string
+	limit   int64
+	retries int
+	backoff time.Duration
+	client  *http.Client
+	decode  func([]byte) ([]T, error)
+
+	offset int64
+	page   []T
+	item   T
+	done   bool
+	err    error
+}
+
+// Offset iterates over a list paged with offset and limit, params are copied and not modified
+func Offset[T any](endpoint string, params map[string]string) *Iterator[T] {
+	it := &Iterator[T]{
+		url:     endpoint,
+		params:  make(map[string]string, len(params)+4),
+		limit:   DefaultLimit,
+		retries: DefaultRetries,
+		backoff: DefaultBackoff,
+		client:  http.DefaultClient,
+		decode: func(body []byte) ([]T, error) {
+			var page []T
+			err := json.Unmarshal(body, &page)
+			return page, err
+		},
+	}
+	for key, value := range params {
+		it.params[key] = value
+	}
+	return it
+}
+
+// Rounds iterates over the items of rounds from up to, but not including, to, paged with offset and limit
+func Rounds[T any](endpoint string, params map[string]string, from, to int64) *Iterator[T] {
+	it := Offset[T](endpoint, params)
+	it.params["start"] = strconv.FormatInt(from, 10)
+	it.params["end"] = strconv.FormatInt(to, 10)
+	return it
+}
+
+// WithLimit sets the page size, a page shorter than limit is the last one
+func (it *Iterator[T]) WithLimit(limit int64) *Iterator[T] {
+	it.limit = limit
+	return it
+}
+
+// WithRetries sets how often a failed page is fetched again, waiting backoff and then twice as long each retry
+func (it *Iterator[T]) WithRetries(retries int, backoff time.Duration) *Iterator[T] {
+	it.retries, it.backoff = retries, backoff
+	return it
+}
+
+// WithClient sets the HTTP client pages are fetched with
+func (it *Iterator[T]) WithClient(client *http.Client) *Iterator[T] {
+	it.client = client
+	return it
+}
+
+// WithDecoder sets how a page is decoded, for endpoints that wrap the list in an object
+func (it *Iterator[T]) WithDecoder(decode func([]byte) ([]T, error)) *Iterator[T] {
+	it.decode = decode
+	return it
+}
+
+// Next advances to the next item, it returns false at the end of the list or on error
+func (it *Iterator[T]) Next() bool {
+	for len(it.page) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		page, err := it.fetch()
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.page = page
+		it.offset += int64(len(page))
+		it.done = int64(len(page)) < it.limit
+	}
+	it.item, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Item returns the current item
+func (it *Iterator[T]) Item() T {
+	return it.item
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Collect reads all remaining items
+func Collect[T any](it *Iterator[T]) ([]T, error) {
+	var out []T
+	for it.Next() {
+		out = append(out, it.Item())
+	}
+	return out, it.Err()
+}
+
+// fetch reads the page at the current offset, retrying transport errors, server errors and throttling
+func (it *Iterator[T]) fetch() ([]T, error) {
+	// the endpoint may carry a query of its own, the params and paging are merged into it
+	u, err := url.Parse(it.url)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %v", it.url, err)
+	}
+	query := u.Query()
+	for key, value := range it.params {
+		query.Set(key, value)
+	}
+	query.Set("limit", strconv.FormatInt(it.limit, 10))
+	if it.offset > 0 {
+		query.Set("offset", strconv.FormatInt(it.offset, 10))
+	}
+	u.RawQuery = query.Encode()
+	pageUrl := u.String()
+
+	backoff := it.backoff
+	for try := 0; ; try++ {
+		body, retry, err := it.get(pageUrl)
+		if err == nil {
+			page, err := it.decode(body)
+			if err != nil {
+				return nil, fmt.Errorf("deserializing page %s `%s`: %v", pageUrl, string(body), err)
+			}
+			return page, nil
+		}
+		if !retry || try >= it.retries {
+			return nil, err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// get requests one page and reports whether a failure is worth retrying
+func (it *Iterator[T]) get(pageUrl string) ([]byte, bool, error) {
+	res, err := it.client.Get(pageUrl)
+	if err != nil {
+		return nil, true, fmt.Errorf("requesting %s: %v", pageUrl, err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, true, fmt.Errorf("reading response of %s: %v", pageUrl, err)
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		retry := res.StatusCode >= 500 || res.StatusCode == http.StatusTooManyRequests
+		return nil, retry, fmt.Errorf("failed API request %s, status code: %d, body: %s", pageUrl, res.StatusCode, string(body))
+	}
+	return body, false, nil
+}
@@ -0,0 +1,146 @@
+package paginate
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// listServer serves the numbers from 0 to n-1, or those from start up to end if given, paged by offset and limit
+func listServer(t *testing.T, n int, handle func(w http.ResponseWriter, r *http.Request) bool) string {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if handle != nil && !handle(w, r) {
+			return
+		}
+		query := r.URL.Query()
+		start, end := 0, n
+		if query.Has("start") {
+			start, _ = strconv.Atoi(query.Get("start"))
+			end, _ = strconv.Atoi(query.Get("end"))
+		}
+		offset, _ := strconv.Atoi(query.Get("offset"))
+		limit, _ := strconv.Atoi(query.Get("limit"))
+
+		page := []int{}
+		for i := start + offset; i < end && i < n && len(page) < limit; i++ {
+			page = append(page, i)
+		}
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestOffset(t *testing.T) {
+	url := listServer(t, 45, nil)
+
+	params := map[string]string{"sort": "asc"}
+	items, err := Collect(Offset[int](url, params).WithLimit(10))
+	require.NoError(t, err)
+	require.Len(t, items, 45)
+	require.Equal(t, 44, items[44])
+	require.Equal(t, map[string]string{"sort": "asc"}, params, "params must not be modified")
+
+	items, err = Collect(Offset[int](url, nil).WithLimit(15))
+	require.NoError(t, err)
+	require.Len(t, items, 45, "a full last page is followed by an empty one")
+}
+
+func TestOffsetMergesEndpointQuery(t *testing.T) {
+	url := listServer(t, 25, func(w http.ResponseWriter, r *http.Request) bool {
+		query := r.URL.Query()
+		if query.Get("sort") != "desc" || query.Get("active") != "true" || len(query["limit"]) != 1 {
+			http.Error(w, "unexpected query "+r.URL.RawQuery, http.StatusBadRequest)
+			return false
+		}
+		return true
+	})
+
+	items, err := Collect(Offset[int](url+"?sort=asc&limit=99", map[string]string{"sort": "desc", "active": "true"}).WithLimit(10))
+	require.NoError(t, err)
+	require.Len(t, items, 25)
+}
+
+func TestRounds(t *testing.T) {
+	items, err := Collect(Rounds[int](listServer(t, 100, nil), nil, 10, 35).WithLimit(10))
+	require.NoError(t, err)
+	require.Len(t, items, 25)
+	require.Equal(t, 10, items[0])
+	require.Equal(t, 34, items[24])
+}
+
+func TestIteratorFetchesLazily(t *testing.T) {
+	var requests int32
+	url := listServer(t, 100, func(http.ResponseWriter, *http.Request) bool {
+		atomic.AddInt32(&requests, 1)
+		return true
+	})
+
+	it := Offset[int](url, nil).WithLimit(10)
+	for i := 0; i < 15; i++ {
+		require.True(t, it.Next())
+		require.Equal(t, i, it.Item())
+	}
+	require.Equal(t, int32(2), atomic.LoadInt32(&requests))
+}
+
+func TestRetries(t *testing.T) {
+	var failures int32 = 2
+	url := listServer(t, 5, func(w http.ResponseWriter, r *http.Request) bool {
+		if atomic.AddInt32(&failures, -1) >= 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return false
+		}
+		return true
+	})
+
+	items, err := Collect(Offset[int](url, nil).WithRetries(2, time.Millisecond))
+	require.NoError(t, err)
+	require.Len(t, items, 5)
+
+	atomic.StoreInt32(&failures, 3)
+	_, err = Collect(Offset[int](url, nil).WithRetries(2, time.Millisecond))
+	require.ErrorContains(t, err, "status code: 503")
+}
+
+func TestRejectsClientErrors(t *testing.T) {
+	var requests int32
+	url := listServer(t, 5, func(w http.ResponseWriter, r *http.Request) bool {
+		atomic.AddInt32(&requests, 1)
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return false
+	})
+
+	it := Offset[int](url, nil).WithRetries(3, time.Millisecond)
+	require.False(t, it.Next())
+	require.ErrorContains(t, it.Err(), "status code: 400")
+	require.Equal(t, int32(1), atomic.LoadInt32(&requests), "client errors are not retried")
+}
+
+func TestWithDecoder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("offset") != "" {
+			_, _ = w.Write([]byte(`{"Nodes":[]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"Nodes":[{"id":"a"},{"id":"b"}]}`))
+	}))
+	defer server.Close()
+
+	type node struct {
+		ID string `json:"id"`
+	}
+	items, err := Collect(Offset[node](server.URL, nil).WithLimit(2).WithDecoder(func(body []byte) ([]node, error) {
+		var nodes struct{ Nodes []node }
+		err := json.Unmarshal(body, &nodes)
+		return nodes.Nodes, err
+	}))
+	require.NoError(t, err)
+	require.Equal(t, []node{{ID: "a"}, {ID: "b"}}, items)
+}
@@ -0,0 +1,177 @@
+package redact
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Mask replaces redacted secrets
+const Mask = "****"
+
+// minSecretLength is the length below which known values are not registered, so that short common strings are
+// not masked everywhere
+const minSecretLength = 6
+
+// SecretFlags are the flags of zbox, zwallet and s3mgrt taking a secret as value
+var SecretFlags = []string{"access-key", "secret-key", "mnemonic", "mnemonics", "authticket", "auth_ticket", "private_key", "private-key", "id_token"}
+
+// DefaultPatterns match the secrets the CLIs and services print or take as arguments. The first group of a
+// pattern is the secret, a pattern without group is a secret as a whole.
+var DefaultPatterns = []*regexp.Regexp{
+	// secret flags in command lines
+	regexp.MustCompile(`--(?:` + strings.Join(SecretFlags, "|") + `)(?:\s+|=)("[^"]*"|'[^']*'|[^\s"'\]]+)`),
+	// JSON fields of wallets, configs and API responses, also when escaped inside transaction data
+	regexp.MustCompile(`\\?"(?i:private_key|privateKey|mnemonics?|secret_key|access_key|secret|auth_ticket|id_token)\\?"\s*:\s*\\?"([^"\\]+)\\?"`),
+	// headers of 0box requests, as printed by resty or in a map
+	regexp.MustCompile(`(?i)x-app-id-token"?\s*[:=]\s*"?([^\s"\],]+)`),
+	// the output of zbox share
+	regexp.MustCompile(`Auth token :\s*(\S+)`),
+}
+
+var (
+	mu       sync.RWMutex
+	patterns = append([]*regexp.Regexp(nil), DefaultPatterns...)
+	secrets  []string
+)
+
+// AddPattern registers a pattern, its first group or else its whole match is masked
+func AddPattern(re *regexp.Regexp) {
+	mu.Lock()
+	defer mu.Unlock()
+	patterns = append(patterns, re)
+}
+
+// AddSecret registers known secret values, e.g. keys read from the configuration, empty and short values are ignored
+func AddSecret(values ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if len(value) < minSecretLength || contains(secrets, value) {
+			continue
+		}
+		secrets = append(secrets, value)
+	}
+	// longest first, so that a secret containing another is masked whole
+	sort.Slice(secrets, func(i, j int) bool { return len(secrets[i]) > len(secrets[j]) })
+}
+
+// String masks the secrets in s
+func String(s string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	for _, secret := range secrets {
+		s = strings.ReplaceAll(s, secret, Mask)
+	}
+	for _, re := range patterns {
+		s = mask(re, s)
+	}
+	return s
+}
+
+// Sprintf formats like fmt.Sprintf and masks the secrets in the result
+func Sprintf(format string, args ...any) string {
+	return String(fmt.Sprintf(format, args...))
+}
+
+// Sprint formats like testing.T.Log, with spaces between operands, and masks the secrets in the result
+func Sprint(args ...any) string {
+	return String(strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
+}
+
+// Args masks the secrets in the arguments of a command, including the values of secret flags given as separate arguments
+func Args(args []string) []string {
+	redacted := make([]string, len(args))
+	for i, arg := range args {
+		if i > 0 && isSecretFlag(args[i-1]) {
+			redacted[i] = Mask
+			continue
+		}
+		redacted[i] = String(arg)
+	}
+	return redacted
+}
+
+func isSecretFlag(arg string) bool {
+	return strings.HasPrefix(arg, "--") && contains(SecretFlags, strings.TrimPrefix(arg, "--"))
+}
+
+func mask(re *regexp.Regexp, s string) string {
+	matches := re.FindAllStringSubmatchIndex(s, -1)
+	if matches == nil {
+		return s
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if len(m) >= 4 && m[2] >= 0 {
+			start, end = m[2], m[3]
+		}
+		b.WriteString(s[last:start])
+		b.WriteString(Mask)
+		last = end
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Writer masks secrets in what is written to the underlying writer. Secrets are masked line by line,
+// a partial line is held back until it is completed or Flush is called.
+type Writer struct {
+	mu      sync.Mutex
+	w       io.Writer
+	pending []byte
+}
+
+// NewWriter returns a Writer writing to w
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Write masks the complete lines of p and writes them
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.pending = append(w.pending, p...)
+	end := bytes.LastIndexByte(w.pending, '\n')
+	if end < 0 {
+		return len(p), nil
+	}
+	lines := String(string(w.pending[:end+1]))
+	w.pending = append(w.pending[:0], w.pending[end+1:]...)
+	if _, err := io.WriteString(w.w, lines); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush masks and writes a pending partial line
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.pending) == 0 {
+		return nil
+	}
+	_, err := io.WriteString(w.w, String(string(w.pending)))
+	w.pending = w.pending[:0]
+	return err
+}
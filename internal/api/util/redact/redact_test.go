@@ -0,0 +1,99 @@
+package redact
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "both key flags",
+			input:    "./s3mgrt migrate --access-key AKIA1 --secret-key s3cr3t --bucket b",
+			expected: "./s3mgrt migrate --access-key **** --secret-key **** --bucket b",
+		},
+		{
+			name:     "secret key flag only",
+			input:    "./s3mgrt migrate --secret-key s3cr3t --bucket b",
+			expected: "./s3mgrt migrate --secret-key **** --bucket b",
+		},
+		{
+			name:     "quoted mnemonic",
+			input:    `./zwallet recoverwallet --mnemonic "pull floor crop best" --silent`,
+			expected: `./zwallet recoverwallet --mnemonic **** --silent`,
+		},
+		{
+			name:     "auth ticket flag",
+			input:    "./zbox download --authticket=eyJjbGllbnRfaWQiOiIifQ== --localpath /tmp/f",
+			expected: "./zbox download --authticket=**** --localpath /tmp/f",
+		},
+		{
+			name:     "share output",
+			input:    "Auth token :eyJjbGllbnRfaWQiOiIifQ==",
+			expected: "Auth token :****",
+		},
+		{
+			name:     "wallet json",
+			input:    `{"client_id":"c1","keys":[{"public_key":"pk","private_key":"sk"}],"mnemonics":"pull floor"}`,
+			expected: `{"client_id":"c1","keys":[{"public_key":"pk","private_key":"****"}],"mnemonics":"****"}`,
+		},
+		{
+			name:     "escaped json in transaction data",
+			input:    `{"transaction_data":"{\"name\":\"x\",\"auth_ticket\":\"abc\"}"}`,
+			expected: `{"transaction_data":"{\"name\":\"x\",\"auth_ticket\":\"****\"}"}`,
+		},
+		{
+			name:     "0box header",
+			input:    "map[X-App-ID-Token:eyJhbGciOi X-App-User-ID:user]",
+			expected: "map[X-App-ID-Token:**** X-App-User-ID:user]",
+		},
+		{
+			name:     "nothing to redact",
+			input:    "Allocation created: 5c2a0e",
+			expected: "Allocation created: 5c2a0e",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, String(tt.input))
+		})
+	}
+}
+
+func TestArgs(t *testing.T) {
+	args := []string{"recoverwallet", "--mnemonic", "pull floor crop best", "--wallet", "w.json", "--authticket=abcdef"}
+	require.Equal(t, []string{"recoverwallet", "--mnemonic", "****", "--wallet", "w.json", "--authticket=****"}, Args(args))
+}
+
+func TestAddSecretAndPattern(t *testing.T) {
+	AddSecret("", "short", "rootroot-access", "rootroot")
+	AddPattern(regexp.MustCompile(`token=(\w+)`))
+
+	require.Equal(t, "key **** and **** in short, url ?token=****",
+		Sprintf("key %s and %s in %s, url ?token=%s", "rootroot-access", "rootroot", "short", "abc"))
+	require.Equal(t, "the key is ****", Sprint("the key is", "rootroot"))
+}
+
+func TestWriter(t *testing.T) {
+	var out bytes.Buffer
+	w := NewWriter(&out)
+
+	_, err := w.Write([]byte("level=debug msg=\"Command [./zbox share --authticket "))
+	require.NoError(t, err)
+	require.Empty(t, out.String(), "a partial line is held back")
+
+	_, err = w.Write([]byte("abcdef]\"\nnext"))
+	require.NoError(t, err)
+	require.Equal(t, "level=debug msg=\"Command [./zbox share --authticket ****]\"\n", out.String())
+
+	require.NoError(t, w.Flush())
+	require.Equal(t, "level=debug msg=\"Command [./zbox share --authticket ****]\"\nnext", out.String())
+}
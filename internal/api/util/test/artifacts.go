@@ -0,0 +1,38 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/0chain/system_test/internal/api/util/redact"
+)
+
+// ArtifactsDirEnv overrides the directory test artifacts are written to, by default "artifacts" in the package directory
+const ArtifactsDirEnv = "TEST_ARTIFACTS_DIR"
+
+const defaultArtifactsDir = "artifacts"
+
+// ArtifactsRoot returns the directory holding the artifacts of all tests of the run
+func ArtifactsRoot() string {
+	if dir := os.Getenv(ArtifactsDirEnv); dir != "" {
+		return dir
+	}
+	return defaultArtifactsDir
+}
+
+// ArtifactDir returns the directory for the artifacts of the test, creating it if needed.
+// Unlike TempDir it is kept after the run, so that failures can be analysed offline.
+func (s *SystemTest) ArtifactDir() string {
+	dir := filepath.Join(ArtifactsRoot(), s.EscapedName())
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		s.Logf("creating artifact directory %s: %v", dir, err)
+	}
+	return dir
+}
+
+// WriteArtifact writes data to the named file in the artifact directory of the test, with secrets redacted,
+// and returns its path
+func (s *SystemTest) WriteArtifact(name string, data []byte) (string, error) {
+	path := filepath.Join(s.ArtifactDir(), name)
+	return path, os.WriteFile(path, []byte(redact.String(string(data))), 0644) //nolint:gosec
+}
@@ -0,0 +1,93 @@
+package test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CapabilitiesReportFile is the run report in ArtifactsRoot listing the versions and capabilities of the binaries under test
+const CapabilitiesReportFile = "capabilities.json"
+
+// Capabilities are the commands and flags supported by the binaries under test, as "zbox rollback" or
+// "zbox upload --web-streaming", along with their versions by binary name
+type Capabilities struct {
+	Versions map[string]string `json:"versions"`
+	Features []string          `json:"features"`
+
+	once sync.Once
+	set  map[string]bool
+}
+
+var capabilities *Capabilities
+
+// SetCapabilities sets the capabilities RequireCapability checks, probed once for the run in TestMain
+func SetCapabilities(c *Capabilities) {
+	capabilities = c
+}
+
+// Has reports whether a capability is supported, a command with flags is supported if it has all of them
+func (c *Capabilities) Has(capability string) bool {
+	c.once.Do(func() {
+		c.set = make(map[string]bool, len(c.Features))
+		for _, feature := range c.Features {
+			c.set[feature] = true
+		}
+	})
+
+	command, flags := splitCapability(capability)
+	if !c.set[command] {
+		return false
+	}
+	for _, flag := range flags {
+		if !c.set[command+" "+flag] {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteReport writes the versions and capabilities to CapabilitiesReportFile in dir and returns its path
+func (c *Capabilities) WriteReport(dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	sort.Strings(c.Features)
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, CapabilitiesReportFile)
+	return path, os.WriteFile(path, data, 0644) //nolint:gosec
+}
+
+// RequireCapability skips the test if a binary under test lacks the capability, as "zbox rollback" or
+// "zwallet bridge-mint-zcn --burn-txn-hash". Tests run as usual if the binaries were not probed.
+func (s *SystemTest) RequireCapability(capability string) {
+	s.Unwrap.Helper()
+	if capabilities == nil || capabilities.Has(capability) {
+		return
+	}
+	program := strings.Fields(capability)[0]
+	version, ok := capabilities.Versions[program]
+	if !ok {
+		s.Skipf("requires %q, but %s is not available", capability, program)
+	}
+	s.Skipf("requires %q, which %s %s does not support", capability, program, version)
+}
+
+// splitCapability splits a capability into the command, e.g. "zbox upload", and its flags
+func splitCapability(capability string) (string, []string) {
+	var words, flags []string
+	for _, word := range strings.Fields(capability) {
+		if strings.HasPrefix(word, "-") {
+			flags = append(flags, word)
+			continue
+		}
+		words = append(words, word)
+	}
+	return strings.Join(words, " "), flags
+}
@@ -7,6 +7,8 @@ import (
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/0chain/system_test/internal/api/util/redact"
 )
 
 var DefaultTestTimeout = 40 * time.Second
@@ -177,7 +179,7 @@ func (s *SystemTest) Error(args ...any) {
 	if !s.testComplete {
 		s.Unwrap.Helper()
 		defer handleTestCaseExit()
-		s.Unwrap.Error(args...)
+		s.Unwrap.Error(redact.Sprint(args...))
 	}
 }
 
@@ -185,7 +187,7 @@ func (s *SystemTest) Errorf(format string, args ...any) {
 	if !s.testComplete {
 		s.Unwrap.Helper()
 		defer handleTestCaseExit()
-		s.Unwrap.Errorf(format, args...)
+		s.Unwrap.Error(redact.Sprintf(format, args...))
 	}
 }
 
@@ -215,7 +217,7 @@ func (s *SystemTest) Fatal(args ...any) {
 	if !s.testComplete {
 		s.Unwrap.Helper()
 		defer handleTestCaseExit()
-		s.Unwrap.Fatal(args...)
+		s.Unwrap.Fatal(redact.Sprint(args...))
 	}
 }
 
@@ -223,7 +225,7 @@ func (s *SystemTest) Fatalf(format string, args ...any) {
 	if !s.testComplete {
 		s.Unwrap.Helper()
 		defer handleTestCaseExit()
-		s.Unwrap.Fatalf(format, args...)
+		s.Unwrap.Fatal(redact.Sprintf(format, args...))
 	}
 }
 
@@ -231,7 +233,7 @@ func (s *SystemTest) Log(args ...any) {
 	if !s.testComplete {
 		s.Unwrap.Helper()
 		defer handleTestCaseExit()
-		s.Unwrap.Log(args...)
+		s.Unwrap.Log(redact.Sprint(args...))
 	}
 }
 
@@ -239,7 +241,7 @@ func (s *SystemTest) Logf(format string, args ...any) {
 	if !s.testComplete {
 		s.Unwrap.Helper()
 		defer handleTestCaseExit()
-		s.Unwrap.Logf(format, args...)
+		s.Unwrap.Log(redact.Sprintf(format, args...))
 	}
 }
 
@@ -270,7 +272,7 @@ func (s *SystemTest) Skip(args ...any) {
 	if !s.testComplete {
 		s.Unwrap.Helper()
 		defer handleTestCaseExit()
-		s.Unwrap.Skip(args...)
+		s.Unwrap.Skip(redact.Sprint(args...))
 	}
 }
 
@@ -286,7 +288,7 @@ func (s *SystemTest) Skipf(format string, args ...any) {
 	if !s.testComplete {
 		s.Unwrap.Helper()
 		defer handleTestCaseExit()
-		s.Unwrap.Skipf(format, args...)
+		s.Unwrap.Skip(redact.Sprintf(format, args...))
 	}
 }
 
@@ -0,0 +1,425 @@
+package topology
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0chain/system_test/internal/api/util/keystore"
+	"github.com/0chain/system_test/internal/api/util/paginate"
+	"golang.org/x/crypto/sha3"
+)
+
+// ReportFile is the run report in ArtifactsRoot describing the discovered network
+const ReportFile = "topology.json"
+
+// Role is the kind of a node of the network
+type Role string
+
+const (
+	Miner     Role = "miner"
+	Sharder   Role = "sharder"
+	Blobber   Role = "blobber"
+	Validator Role = "validator"
+)
+
+const (
+	minerSmartContractAddress   = "6dba10422e368813802877a85039d3985d96760ed844092319743fb3a76712d9"
+	storageSmartContractAddress = "6dba10422e368813802877a85039d3985d96760ed844092319743fb3a76712d7"
+)
+
+// Node is a node of the network as registered in the smart contracts
+type Node struct {
+	Role      Role   `json:"role"`
+	ID        string `json:"id"`
+	URL       string `json:"url"`
+	PublicKey string `json:"public_key,omitempty"`
+	// DelegateWallet is the client ID of the wallet receiving the rewards of the node and allowed to update it
+	DelegateWallet string `json:"delegate_wallet"`
+	// Delegate is the name of the local wallet whose client ID is DelegateWallet, empty if there is none
+	Delegate string `json:"delegate,omitempty"`
+	// InMagicBlock is set for the miners and sharders the block worker lists
+	InMagicBlock bool `json:"in_magic_block,omitempty"`
+	// Killed is set for the nodes killed in the smart contracts, when discovered or later by Network.Kill
+	Killed bool `json:"killed,omitempty"`
+}
+
+// Network describes the nodes of a network, in the order the sharders list them
+type Network struct {
+	BlockWorker string  `json:"block_worker"`
+	Nodes       []*Node `json:"nodes"`
+
+	// mu guards Killed of the nodes, which the kill tests set while others run
+	mu sync.RWMutex
+}
+
+// Wallet is a local wallet file the nodes may delegate to
+type Wallet struct {
+	// Name is the path of the file relative to the directory it was loaded from, without the _wallet.json suffix
+	Name      string
+	ClientID  string
+	PublicKey string
+}
+
+// LoadWallets reads the wallet files matching pattern in dir, as "wallets/*_wallet.json" in the config directory
+// of the CLIs. Their client IDs are derived from their public keys.
+func LoadWallets(dir, pattern string) ([]Wallet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, pattern))
+	if err != nil {
+		return nil, err
+	}
+
+	var wallets []Wallet
+	for _, path := range paths {
+		content, err := keystore.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var file struct {
+			ClientKey string `json:"client_key"`
+			Keys      []struct {
+				PublicKey string `json:"public_key"`
+			} `json:"keys"`
+		}
+		if err := json.Unmarshal(content, &file); err != nil {
+			return nil, fmt.Errorf("parsing wallet %s: %w", path, err)
+		}
+		publicKey := file.ClientKey
+		if publicKey == "" && len(file.Keys) > 0 {
+			publicKey = file.Keys[0].PublicKey
+		}
+		clientID, err := ClientID(publicKey)
+		if err != nil {
+			return nil, fmt.Errorf("wallet %s: %w", path, err)
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return nil, err
+		}
+		wallets = append(wallets, Wallet{
+			Name:      strings.TrimSuffix(filepath.ToSlash(rel), "_wallet.json"),
+			ClientID:  clientID,
+			PublicKey: publicKey,
+		})
+	}
+	return wallets, nil
+}
+
+// ClientID returns the client ID of a wallet, which is the SHA3-256 hash of its public key
+func ClientID(publicKey string) (string, error) {
+	key, err := hex.DecodeString(publicKey)
+	if err != nil || len(key) == 0 {
+		return "", fmt.Errorf("invalid public key %q", publicKey)
+	}
+	hash := sha3.Sum256(key)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// Discover reads the miners and sharders of the network from the /network endpoint of the block worker, then the
+// miners, sharders, blobbers and validators registered in the smart contracts from the first sharder answering.
+// Nodes delegating to one of the wallets get its name as Delegate.
+func Discover(blockWorker string, wallets []Wallet) (*Network, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	var entrypoints struct {
+		Miners   []string `json:"miners"`
+		Sharders []string `json:"sharders"`
+	}
+	if err := getJSON(client, strings.TrimSuffix(blockWorker, "/")+"/network", &entrypoints); err != nil {
+		return nil, err
+	}
+	if len(entrypoints.Sharders) == 0 {
+		return nil, fmt.Errorf("no sharders in the network of %s", blockWorker)
+	}
+
+	var errs []error
+	for _, sharder := range entrypoints.Sharders {
+		nodes, err := registeredNodes(client, strings.TrimSuffix(sharder, "/"))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		network := &Network{BlockWorker: blockWorker, Nodes: nodes}
+		network.resolveURLs(append(entrypoints.Miners, entrypoints.Sharders...))
+		network.matchDelegates(wallets)
+		return network, nil
+	}
+	return nil, fmt.Errorf("discovering the network of %s: %w", blockWorker, errors.Join(errs...))
+}
+
+// registeredNodes lists the nodes registered in the miner and storage smart contracts
+func registeredNodes(client *http.Client, sharder string) ([]*Node, error) {
+	var nodes []*Node
+	for _, role := range []Role{Miner, Sharder} {
+		endpoint := "getMinerList"
+		if role == Sharder {
+			endpoint = "getSharderList"
+		}
+		list := paginate.Offset[minerSharder](sharder+"/v1/screst/"+minerSmartContractAddress+"/"+endpoint, nil).
+			WithClient(client).
+			WithDecoder(decodeNodes[minerSharder])
+		for list.Next() {
+			n := list.Item()
+			nodes = append(nodes, &Node{
+				Role:           role,
+				ID:             n.Node.ID,
+				URL:            nodeURL(n.Node.Host, n.Node.Port, n.Node.Path),
+				PublicKey:      n.Node.PublicKey,
+				DelegateWallet: n.StakePool.Settings.DelegateWallet,
+				Killed:         n.Node.IsKilled,
+			})
+		}
+		if err := list.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	blobbers := paginate.Offset[storageNode](sharder+"/v1/screst/"+storageSmartContractAddress+"/getblobbers", nil).
+		WithClient(client).
+		WithDecoder(decodeNodes[storageNode])
+	for blobbers.Next() {
+		b := blobbers.Item()
+		nodes = append(nodes, &Node{
+			Role:           Blobber,
+			ID:             b.ID,
+			URL:            b.URL,
+			PublicKey:      b.PublicKey,
+			DelegateWallet: b.StakePoolSettings.DelegateWallet,
+			Killed:         b.IsKilled,
+		})
+	}
+	if err := blobbers.Err(); err != nil {
+		return nil, err
+	}
+
+	validators := paginate.Offset[validator](sharder+"/v1/screst/"+storageSmartContractAddress+"/validators", nil).
+		WithClient(client)
+	for validators.Next() {
+		v := validators.Item()
+		nodes = append(nodes, &Node{
+			Role:           Validator,
+			ID:             v.ID,
+			URL:            v.URL,
+			PublicKey:      v.PublicKey,
+			DelegateWallet: v.DelegateWallet,
+			Killed:         v.IsKilled,
+		})
+	}
+	if err := validators.Err(); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// resolveURLs replaces the URLs of the miners and sharders built from their host, port and path by the URLs the
+// block worker gave for them, which have the scheme the network is served with, and marks them in the magic block
+func (n *Network) resolveURLs(entrypoints []string) {
+	byKey := make(map[string]string, len(entrypoints))
+	for _, entrypoint := range entrypoints {
+		if u, err := url.Parse(entrypoint); err == nil {
+			byKey[u.Hostname()+"/"+strings.Trim(u.Path, "/")] = strings.TrimSuffix(entrypoint, "/")
+			byKey[u.Host+"/"+strings.Trim(u.Path, "/")] = strings.TrimSuffix(entrypoint, "/")
+		}
+	}
+	for _, node := range n.Nodes {
+		if node.Role != Miner && node.Role != Sharder {
+			continue
+		}
+		u, err := url.Parse(node.URL)
+		if err != nil {
+			continue
+		}
+		path := strings.Trim(u.Path, "/")
+		resolved, ok := byKey[u.Host+"/"+path]
+		if !ok {
+			resolved, ok = byKey[u.Hostname()+"/"+path]
+		}
+		if ok {
+			node.URL = resolved
+			node.InMagicBlock = true
+		}
+	}
+}
+
+func (n *Network) matchDelegates(wallets []Wallet) {
+	byClientID := make(map[string]string, len(wallets))
+	for _, wallet := range wallets {
+		byClientID[wallet.ClientID] = wallet.Name
+	}
+	for _, node := range n.Nodes {
+		node.Delegate = byClientID[node.DelegateWallet]
+	}
+}
+
+// ByRole returns the nodes of a role
+func (n *Network) ByRole(role Role) []*Node {
+	var nodes []*Node
+	for _, node := range n.Nodes {
+		if node.Role == role {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// URLs returns the sorted URLs of the nodes of a role that are not killed, of the ones in the magic block for
+// miners and sharders unless none is
+func (n *Network) URLs(role Role) []string {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	var urls, all []string
+	for _, node := range n.ByRole(role) {
+		if node.Killed {
+			continue
+		}
+		all = append(all, node.URL)
+		if node.InMagicBlock {
+			urls = append(urls, node.URL)
+		}
+	}
+	if len(urls) == 0 {
+		urls = all
+	}
+	sort.Strings(urls)
+	return urls
+}
+
+// Kill marks the node with the ID as killed, so that URLs no longer returns it
+func (n *Network) Kill(id string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if node := n.Node(id); node != nil {
+		node.Killed = true
+	}
+}
+
+// Delegated returns the first node of a role delegating to the named wallet, or nil if there is none
+func (n *Network) Delegated(role Role, wallet string) *Node {
+	for _, node := range n.Nodes {
+		if node.Role == role && node.Delegate == wallet {
+			return node
+		}
+	}
+	return nil
+}
+
+// Node returns the node with the ID, or nil if there is none
+func (n *Network) Node(id string) *Node {
+	for _, node := range n.Nodes {
+		if node.ID == id {
+			return node
+		}
+	}
+	return nil
+}
+
+// String summarizes the network, as "3 miners, 2 sharders, 6 blobbers, 6 validators"
+func (n *Network) String() string {
+	var parts []string
+	for _, role := range []Role{Miner, Sharder, Blobber, Validator} {
+		parts = append(parts, strconv.Itoa(len(n.ByRole(role)))+" "+string(role)+"s")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// WriteReport writes the network to ReportFile in dir and returns its path
+func (n *Network) WriteReport(dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(n, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, ReportFile)
+	return path, os.WriteFile(path, data, 0644) //nolint:gosec
+}
+
+// nodeURL builds the URL of a miner or sharder from its registration
+func nodeURL(host string, port int, path string) string {
+	base := "http://" + host + ":" + strconv.Itoa(port)
+	if port == 443 {
+		base = "https://" + host
+	}
+	if path = strings.Trim(path, "/"); path != "" {
+		base += "/" + path
+	}
+	return base
+}
+
+type minerSharder struct {
+	Node struct {
+		ID        string `json:"id"`
+		Host      string `json:"host"`
+		Port      int    `json:"port"`
+		Path      string `json:"path"`
+		PublicKey string `json:"public_key"`
+		IsKilled  bool   `json:"is_killed"`
+	} `json:"simple_miner"`
+	StakePool struct {
+		Settings struct {
+			DelegateWallet string `json:"delegate_wallet"`
+		} `json:"settings"`
+	} `json:"stake_pool"`
+}
+
+type storageNode struct {
+	ID                string `json:"id"`
+	URL               string `json:"url"`
+	PublicKey         string `json:"public_key"`
+	IsKilled          bool   `json:"is_killed"`
+	StakePoolSettings struct {
+		DelegateWallet string `json:"delegate_wallet"`
+	} `json:"stake_pool_settings"`
+}
+
+type validator struct {
+	ID             string `json:"validator_id"`
+	URL            string `json:"url"`
+	PublicKey      string `json:"public_key"`
+	DelegateWallet string `json:"delegate_wallet"`
+	IsKilled       bool   `json:"is_killed"`
+}
+
+// decodeNodes decodes a page of the miner, sharder and blobber lists, which wrap the nodes in an object
+func decodeNodes[T any](body []byte) ([]T, error) {
+	var page struct {
+		Nodes []T `json:"Nodes"`
+	}
+	err := json.Unmarshal(body, &page)
+	return page.Nodes, err
+}
+
+func getJSON(client *http.Client, endpoint string, dst interface{}) error {
+	res, err := client.Get(endpoint)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("requesting %s: status %d: %s", endpoint, res.StatusCode, body)
+	}
+	if err := json.Unmarshal(body, dst); err != nil {
+		return fmt.Errorf("decoding %s: %w", endpoint, err)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package topology
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	delegateKey      = "02237d7edafc8c648b1e2ff64ad5d4e6a7cea949a40448e15a55f0a90c71bc1928d8f1409d8985757cc1f59f13d82d83fe6757e4a510d9b47c6385d91ea5d59b"
+	delegateClientID = "9636ab821fd93a37740ab4c6a27d9e2cf3a4072b4bc8fd1c6048f9c3ef9cf2a8"
+)
+
+func network(t *testing.T) *httptest.Server {
+	var server *httptest.Server
+	host := func() (string, int) {
+		var port int
+		addr := strings.TrimPrefix(server.URL, "http://")
+		_, _ = fmt.Sscanf(addr[strings.LastIndex(addr, ":")+1:], "%d", &port)
+		return addr[:strings.LastIndex(addr, ":")], port
+	}
+	page := func(r *http.Request, items ...interface{}) []interface{} {
+		if r.URL.Query().Get("offset") != "" {
+			return nil
+		}
+		return items
+	}
+	minerSharder := func(id, path, delegate string) map[string]interface{} {
+		h, port := host()
+		return map[string]interface{}{
+			"simple_miner": map[string]interface{}{"id": id, "host": h, "port": port, "path": path, "public_key": "key-" + id},
+			"stake_pool":   map[string]interface{}{"settings": map[string]interface{}{"delegate_wallet": delegate}},
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/network", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"miners":   []string{server.URL + "/miner01"},
+			"sharders": []string{server.URL + "/sharder01"},
+		})
+	})
+	mux.HandleFunc("/sharder01/v1/screst/"+minerSmartContractAddress+"/getMinerList", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"Nodes": page(r, minerSharder("m1", "miner01", delegateClientID), minerSharder("m2", "miner02", "someone"))})
+	})
+	mux.HandleFunc("/sharder01/v1/screst/"+minerSmartContractAddress+"/getSharderList", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"Nodes": page(r, minerSharder("s1", "sharder01", "someone"))})
+	})
+	mux.HandleFunc("/sharder01/v1/screst/"+storageSmartContractAddress+"/getblobbers", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"Nodes": page(r, map[string]interface{}{
+			"id": "b1", "url": "http://blobber01", "stake_pool_settings": map[string]interface{}{"delegate_wallet": delegateClientID},
+		}, map[string]interface{}{
+			"id": "b2", "url": "http://blobber02", "is_killed": true,
+		})})
+	})
+	mux.HandleFunc("/sharder01/v1/screst/"+storageSmartContractAddress+"/validators", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(page(r, map[string]interface{}{
+			"validator_id": "v1", "url": "http://validator01", "public_key": "key-v1", "delegate_wallet": "someone",
+		}))
+	})
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestLoadWallets(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "wallets"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "wallets", "miner01_node_delegate_wallet.json"), []byte(`{"client_key":"`+delegateKey+`"}`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "wallets", "staking_wallet.json"), []byte(`{"keys":[{"public_key":"`+delegateKey+`"}]}`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other_wallet.json"), []byte(`{}`), 0600))
+
+	wallets, err := LoadWallets(dir, "wallets/*_wallet.json")
+	require.NoError(t, err)
+	require.Equal(t, []Wallet{
+		{Name: "wallets/miner01_node_delegate", ClientID: delegateClientID, PublicKey: delegateKey},
+		{Name: "wallets/staking", ClientID: delegateClientID, PublicKey: delegateKey},
+	}, wallets)
+
+	_, err = LoadWallets(dir, "*_wallet.json")
+	require.ErrorContains(t, err, "invalid public key")
+}
+
+func TestDiscover(t *testing.T) {
+	server := network(t)
+	wallets := []Wallet{{Name: "wallets/miner01_node_delegate", ClientID: delegateClientID}}
+
+	n, err := Discover(server.URL+"/", wallets)
+	require.NoError(t, err)
+	require.Equal(t, "2 miners, 1 sharders, 2 blobbers, 1 validators", n.String())
+
+	miner := n.Delegated(Miner, "wallets/miner01_node_delegate")
+	require.NotNil(t, miner)
+	require.Equal(t, "m1", miner.ID)
+	require.Equal(t, server.URL+"/miner01", miner.URL)
+	require.Equal(t, "key-m1", miner.PublicKey)
+	require.True(t, miner.InMagicBlock)
+	require.False(t, n.Node("m2").InMagicBlock)
+	require.Equal(t, []string{server.URL + "/miner01"}, n.URLs(Miner))
+	require.Equal(t, []string{"http://validator01"}, n.URLs(Validator))
+	require.True(t, n.Node("b2").Killed)
+	require.Equal(t, []string{"http://blobber01"}, n.URLs(Blobber), "killed nodes are left out")
+
+	require.Equal(t, "wallets/miner01_node_delegate", n.Node("b1").Delegate)
+	require.Equal(t, "http://blobber01", n.Node("b1").URL)
+	require.Equal(t, Validator, n.Node("v1").Role)
+	require.Empty(t, n.Node("v1").Delegate)
+	require.Nil(t, n.Delegated(Sharder, "wallets/miner01_node_delegate"))
+	require.Nil(t, n.Node("unknown"))
+
+	path, err := n.WriteReport(t.TempDir())
+	require.NoError(t, err)
+	report, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(report), `"delegate": "wallets/miner01_node_delegate"`)
+}
+
+func TestDiscoverFailsWithoutSharders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"miners":[],"sharders":[]}`))
+	}))
+	defer server.Close()
+
+	_, err := Discover(server.URL, nil)
+	require.ErrorContains(t, err, "no sharders")
+}
+
+func TestNodeURL(t *testing.T) {
+	require.Equal(t, "https://dev.zus.network/sharder01", nodeURL("dev.zus.network", 443, "/sharder01/"))
+	require.Equal(t, "http://198.18.0.81:7071", nodeURL("198.18.0.81", 7071, ""))
+}
+
+func TestURLsSkipKilledNodes(t *testing.T) {
+	n := &Network{Nodes: []*Node{
+		{Role: Sharder, ID: "s1", URL: "http://sharder01", InMagicBlock: true},
+		{Role: Sharder, ID: "s2", URL: "http://sharder02", InMagicBlock: true},
+		{Role: Sharder, ID: "s3", URL: "http://sharder03"},
+	}}
+	require.Equal(t, []string{"http://sharder01", "http://sharder02"}, n.URLs(Sharder))
+
+	n.Kill("s1")
+	n.Kill("unknown")
+	require.Equal(t, []string{"http://sharder02"}, n.URLs(Sharder))
+
+	n.Kill("s2")
+	require.Equal(t, []string{"http://sharder03"}, n.URLs(Sharder), "the sharders outside the magic block are used once all in it are killed")
+}
@@ -0,0 +1,355 @@
+package walletpool
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/0chain/system_test/internal/api/util/test"
+	"github.com/0chain/system_test/internal/currency"
+	"github.com/stretchr/testify/require"
+)
+
+// StatePathEnv contains name of env variable overriding the pool state file
+const StatePathEnv = "WALLET_POOL_STATE"
+
+// ErrPoolExhausted is returned when every wallet of the pool is leased
+var ErrPoolExhausted = errors.New("no free wallets left in the pool")
+
+// DefaultLeaseExpiry is the age after which a lease found in the state file is reclaimed even if its holder runs
+const DefaultLeaseExpiry = 12 * time.Hour
+
+// Wallet is a pre-funded wallet leased from the pool
+type Wallet struct {
+	Index    int
+	ClientID string
+	Raw      json.RawMessage
+	// Holder is the name the wallet is currently leased to
+	Holder string
+}
+
+// Hooks contain the suite specific chain operations used by the pool
+type Hooks struct {
+	// Prepare makes a freshly leased wallet usable by the other hooks, e.g. writes its wallet file
+	Prepare func(wallet *Wallet) error
+	// Balance returns the current balance of the wallet
+	Balance func(t *test.SystemTest, wallet *Wallet) (currency.Coin, error)
+	// TopUp funds the wallet with the given amount from a funder wallet or the faucet
+	TopUp func(t *test.SystemTest, wallet *Wallet, amount currency.Coin) error
+	// Drain unlocks the pools of the wallet before it is returned to the pool, an error is logged
+	Drain func(t *test.SystemTest, wallet *Wallet) error
+}
+
+type Config struct {
+	// StatePath is the file the pool state is persisted to between runs
+	StatePath string
+	// Offset skips the first wallets, which are reserved for other suites
+	Offset int
+	// MinBalance is the balance below which a leased wallet is topped up
+	MinBalance currency.Coin
+	// TopUpAmount is the amount a wallet is topped up with
+	TopUpAmount currency.Coin
+	// LeaseExpiry is the age after which a lease of another run is reclaimed, DefaultLeaseExpiry if zero
+	LeaseExpiry time.Duration
+
+	Hooks
+}
+
+type walletState struct {
+	Leased  bool           `json:"leased"`
+	Holder  string         `json:"holder,omitempty"`
+	Balance *currency.Coin `json:"balance,omitempty"`
+	Leases  int            `json:"leases"`
+	// PID and Host identify the process holding the lease
+	PID       int       `json:"pid,omitempty"`
+	Host      string    `json:"host,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Pool leases wallets to tests and takes them back once the tests are done with them
+type Pool struct {
+	cfg     Config
+	wallets []*Wallet
+	pid     int
+	host    string
+
+	mu    sync.Mutex
+	state map[string]*walletState
+}
+
+// New creates a pool over the given wallet files, restoring its state from cfg.StatePath.
+// Wallets left leased by a run that is no longer running, or longer ago than cfg.LeaseExpiry, are reclaimed and
+// their balance is read again on the next lease. The leases of other runs sharing the state file are kept.
+func New(rawWallets []json.RawMessage, cfg Config) (*Pool, error) {
+	if cfg.LeaseExpiry == 0 {
+		cfg.LeaseExpiry = DefaultLeaseExpiry
+	}
+	host, _ := os.Hostname()
+	p := &Pool{
+		cfg:   cfg,
+		pid:   os.Getpid(),
+		host:  host,
+		state: make(map[string]*walletState),
+	}
+
+	for i, raw := range rawWallets {
+		var wallet struct {
+			ClientID string `json:"client_id"`
+		}
+		if err := json.Unmarshal(raw, &wallet); err != nil {
+			return nil, fmt.Errorf("decoding wallet %d: %w", i, err)
+		}
+		p.wallets = append(p.wallets, &Wallet{Index: i, ClientID: wallet.ClientID, Raw: raw})
+	}
+
+	if err := p.load(); err != nil {
+		return nil, err
+	}
+
+	for _, s := range p.state {
+		if s.Leased && p.stale(s) {
+			s.Leased = false
+			s.Holder = ""
+			s.Balance = nil
+		}
+	}
+
+	return p, p.save()
+}
+
+// stale reports whether a lease found in the state file is left over. A lease with the pid of this process is left by
+// an earlier run the pid was reused for, since a run creates its pool once. The holder of a lease taken on another
+// host cannot be checked, so the lease is only reclaimed once it expired.
+func (p *Pool) stale(s *walletState) bool {
+	if time.Since(s.UpdatedAt) > p.cfg.LeaseExpiry {
+		return true
+	}
+	if s.PID == 0 || s.Host != p.host {
+		return false
+	}
+	return s.PID == p.pid || !processRunning(s.PID)
+}
+
+func processRunning(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	// signal 0 only checks that the process exists, EPERM means it runs as another user
+	err = process.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// Lease leases a wallet to holder for the lifetime of t, topping it up when its balance is below the minimum.
+// The wallet is drained and returned to the pool on cleanup of t.
+func (p *Pool) Lease(t *test.SystemTest, holder string) *Wallet {
+	wallet, balance, err := p.acquire(holder)
+	require.NoError(t, err, "leasing wallet for %s", holder)
+	t.Cleanup(func() {
+		// t is already marked complete when cleanups run, which would swallow failures of the release hooks
+		p.Release(test.NewSystemTest(t.Unwrap), wallet)
+	})
+
+	if balance == nil && p.cfg.Balance != nil {
+		current, err := p.cfg.Balance(t, wallet)
+		require.NoError(t, err, "reading balance of wallet %s", wallet.ClientID)
+		balance = &current
+	}
+
+	if balance != nil && *balance < p.cfg.MinBalance && p.cfg.TopUp != nil {
+		t.Logf("Topping up wallet [%s] with balance [%s] by [%s]", wallet.ClientID, *balance, p.cfg.TopUpAmount)
+		err = p.cfg.TopUp(t, wallet, p.cfg.TopUpAmount)
+		require.NoError(t, err, "topping up wallet %s", wallet.ClientID)
+		toppedUp := *balance + p.cfg.TopUpAmount
+		balance = &toppedUp
+	}
+
+	p.mu.Lock()
+	p.state[wallet.ClientID].Balance = balance
+	p.mu.Unlock()
+
+	return wallet
+}
+
+// Reserve leases a wallet to holder for the lifetime of t without funding or draining it, for wallets used on
+// another network than the one the hooks talk to. The wallet is returned to the pool on cleanup of t.
+func (p *Pool) Reserve(t *test.SystemTest, holder string) *Wallet {
+	wallet, _, err := p.acquire(holder)
+	require.NoError(t, err, "reserving wallet for %s", holder)
+	t.Cleanup(func() {
+		// the balance on the network the hooks talk to is unknown, it is read again on the next lease
+		if err := p.giveBack(wallet, nil); err != nil {
+			t.Logf("Could not persist wallet pool state: %v", err)
+		}
+	})
+	return wallet
+}
+
+// Release drains the wallet, records its balance and returns it to the pool
+func (p *Pool) Release(t *test.SystemTest, wallet *Wallet) {
+	if p.cfg.Drain != nil {
+		if err := p.cfg.Drain(t, wallet); err != nil {
+			t.Logf("Could not drain released wallet [%s]: %v", wallet.ClientID, err)
+		}
+	}
+
+	var balance *currency.Coin
+	if p.cfg.Balance != nil {
+		current, err := p.cfg.Balance(t, wallet)
+		if err != nil {
+			t.Logf("Could not read balance of released wallet [%s]: %v", wallet.ClientID, err)
+		} else {
+			balance = &current
+		}
+	}
+
+	if err := p.giveBack(wallet, balance); err != nil {
+		t.Logf("Could not persist wallet pool state: %v", err)
+	}
+}
+
+func (p *Pool) giveBack(wallet *Wallet, balance *currency.Coin) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	s := p.state[wallet.ClientID]
+	s.Leased = false
+	s.Holder = ""
+	s.PID = 0
+	s.Host = ""
+	s.Balance = balance
+	s.UpdatedAt = time.Now()
+	wallet.Holder = ""
+
+	return p.save()
+}
+
+// Close returns every wallet still leased by this pool without draining it and persists the pool state.
+// The leases of other runs are kept.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, wallet := range p.wallets {
+		s, ok := p.state[wallet.ClientID]
+		if !ok || !s.Leased || s.PID != p.pid || s.Host != p.host {
+			continue
+		}
+		s.Leased = false
+		s.Holder = ""
+		s.PID = 0
+		s.Host = ""
+		s.Balance = nil
+		s.UpdatedAt = time.Now()
+		wallet.Holder = ""
+	}
+
+	return p.save()
+}
+
+func (p *Pool) acquire(holder string) (*Wallet, *currency.Coin, error) {
+	p.mu.Lock()
+
+	var (
+		wallet *Wallet
+		s      *walletState
+	)
+	for i := p.cfg.Offset; i < len(p.wallets); i++ {
+		candidate := p.wallets[i]
+		candidateState, ok := p.state[candidate.ClientID]
+		if !ok {
+			candidateState = &walletState{}
+			p.state[candidate.ClientID] = candidateState
+		}
+		if !candidateState.Leased {
+			wallet, s = candidate, candidateState
+			break
+		}
+	}
+
+	if wallet == nil {
+		p.mu.Unlock()
+		return nil, nil, ErrPoolExhausted
+	}
+
+	s.Leased = true
+	s.Holder = holder
+	s.PID = p.pid
+	s.Host = p.host
+	s.Leases++
+	s.UpdatedAt = time.Now()
+	wallet.Holder = holder
+	balance := s.Balance
+	err := p.save()
+	p.mu.Unlock()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if p.cfg.Prepare != nil {
+		if err := p.cfg.Prepare(wallet); err != nil {
+			p.mu.Lock()
+			s.Leased = false
+			s.Holder = ""
+			s.PID = 0
+			s.Host = ""
+			wallet.Holder = ""
+			p.mu.Unlock()
+			return nil, nil, err
+		}
+	}
+
+	return wallet, balance, nil
+}
+
+func (p *Pool) load() error {
+	if p.cfg.StatePath == "" {
+		return nil
+	}
+
+	content, err := os.ReadFile(p.cfg.StatePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading wallet pool state: %w", err)
+	}
+
+	if err := json.Unmarshal(content, &p.state); err != nil {
+		return fmt.Errorf("decoding wallet pool state %s: %w", p.cfg.StatePath, err)
+	}
+	if p.state == nil {
+		p.state = make(map[string]*walletState)
+	}
+
+	return nil
+}
+
+// save must be called with the pool mutex held
+func (p *Pool) save() error {
+	if p.cfg.StatePath == "" {
+		return nil
+	}
+
+	content, err := json.MarshalIndent(p.state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p.cfg.StatePath), 0700); err != nil {
+		return err
+	}
+
+	tmp := p.cfg.StatePath + ".tmp"
+	if err := os.WriteFile(tmp, content, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, p.cfg.StatePath)
+}
@@ -0,0 +1,269 @@
+package walletpool
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0chain/system_test/internal/api/util/test"
+	"github.com/0chain/system_test/internal/currency"
+	"github.com/stretchr/testify/require"
+)
+
+// chain fakes the wallet balances the hooks read and change
+type chain struct {
+	mu       sync.Mutex
+	balances map[string]currency.Coin
+	prepared []string
+	topUps   map[string]currency.Coin
+	drained  map[string]int
+	reads    int
+}
+
+func newChain() *chain {
+	return &chain{balances: map[string]currency.Coin{}, topUps: map[string]currency.Coin{}, drained: map[string]int{}}
+}
+
+func (c *chain) hooks() Hooks {
+	return Hooks{
+		Prepare: func(wallet *Wallet) error {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			c.prepared = append(c.prepared, wallet.Holder)
+			return nil
+		},
+		Balance: func(t *test.SystemTest, wallet *Wallet) (currency.Coin, error) {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			c.reads++
+			return c.balances[wallet.ClientID], nil
+		},
+		TopUp: func(t *test.SystemTest, wallet *Wallet, amount currency.Coin) error {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			c.balances[wallet.ClientID] += amount
+			c.topUps[wallet.ClientID] += amount
+			return nil
+		},
+		Drain: func(t *test.SystemTest, wallet *Wallet) error {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			c.drained[wallet.ClientID]++
+			return nil
+		},
+	}
+}
+
+func rawWallets(t *testing.T, n int) []json.RawMessage {
+	wallets := make([]json.RawMessage, n)
+	for i := range wallets {
+		raw, err := json.Marshal(map[string]string{"client_id": fmt.Sprintf("client%d", i)})
+		require.NoError(t, err)
+		wallets[i] = raw
+	}
+	return wallets
+}
+
+func newPool(t *testing.T, n int, c *chain, statePath string) *Pool {
+	pool, err := New(rawWallets(t, n), Config{
+		StatePath:   statePath,
+		Offset:      1,
+		MinBalance:  100,
+		TopUpAmount: 500,
+		Hooks:       c.hooks(),
+	})
+	require.NoError(t, err)
+	return pool
+}
+
+// lease leases a wallet in a subtest, so that it is released when the function returns
+func lease(t *testing.T, pool *Pool, holder string, f func(wallet *Wallet)) {
+	t.Run(holder, func(t *testing.T) {
+		f(pool.Lease(test.NewSystemTest(t), holder))
+	})
+}
+
+func TestLeaseAndRelease(t *testing.T) {
+	c := newChain()
+	c.balances["client1"] = 1000
+	pool := newPool(t, 3, c, "")
+
+	lease(t, pool, "first", func(first *Wallet) {
+		require.Equal(t, 1, first.Index, "the wallets before the offset are reserved for other suites")
+		require.Equal(t, "first", first.Holder)
+		require.Empty(t, c.topUps, "a wallet above the minimum balance is not topped up")
+
+		lease(t, pool, "second", func(second *Wallet) {
+			require.Equal(t, 2, second.Index)
+		})
+		require.Equal(t, 1, c.drained["client2"], "a released wallet is drained")
+	})
+	require.Equal(t, 1, c.drained["client1"])
+	require.Equal(t, []string{"first", "second"}, c.prepared)
+
+	reads := c.reads
+	lease(t, pool, "again", func(again *Wallet) {
+		require.Equal(t, 1, again.Index, "a released wallet is leased again")
+		require.Equal(t, "again", again.Holder)
+	})
+	require.Equal(t, reads+1, c.reads, "the balance recorded on release is reused on the next lease, only the release reads it")
+	require.Equal(t, 2, pool.state["client1"].Leases)
+}
+
+func TestLeaseTopsUpBelowMinBalance(t *testing.T) {
+	c := newChain()
+	c.balances["client1"] = 99
+	pool := newPool(t, 2, c, "")
+
+	lease(t, pool, "poor", func(wallet *Wallet) {
+		require.Equal(t, currency.Coin(500), c.topUps["client1"])
+		require.Equal(t, currency.Coin(599), *pool.state["client1"].Balance)
+	})
+
+	lease(t, pool, "rich", func(wallet *Wallet) {
+		require.Equal(t, currency.Coin(500), c.topUps["client1"], "the topped up wallet is not topped up again")
+	})
+}
+
+func TestPoolExhausted(t *testing.T) {
+	pool := newPool(t, 2, newChain(), "")
+
+	lease(t, pool, "only", func(wallet *Wallet) {
+		_, _, err := pool.acquire("other")
+		require.ErrorIs(t, err, ErrPoolExhausted)
+	})
+
+	_, _, err := pool.acquire("other")
+	require.NoError(t, err, "the released wallet can be leased again")
+}
+
+func TestConcurrentLeasesGetDistinctWallets(t *testing.T) {
+	const n = 8
+	pool := newPool(t, n+1, newChain(), filepath.Join(t.TempDir(), "state.json"))
+
+	wallets := make([]*Wallet, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			wallets[i], _, errs[i] = pool.acquire(fmt.Sprintf("holder%d", i))
+		}(i)
+	}
+	wg.Wait()
+
+	indexes := map[int]bool{}
+	for i, wallet := range wallets {
+		require.NoError(t, errs[i])
+		require.False(t, indexes[wallet.Index], "wallet %d leased twice", wallet.Index)
+		indexes[wallet.Index] = true
+	}
+	_, _, err := pool.acquire("one too many")
+	require.ErrorIs(t, err, ErrPoolExhausted)
+
+	for _, wallet := range wallets {
+		wg.Add(1)
+		go func(wallet *Wallet) {
+			defer wg.Done()
+			require.NoError(t, pool.giveBack(wallet, nil))
+		}(wallet)
+	}
+	wg.Wait()
+	for _, s := range pool.state {
+		require.False(t, s.Leased)
+	}
+}
+
+func TestReserve(t *testing.T) {
+	c := newChain()
+	pool := newPool(t, 2, c, "")
+
+	t.Run("reserved", func(t *testing.T) {
+		wallet := pool.Reserve(test.NewSystemTest(t), "chimney")
+		require.Equal(t, "chimney", wallet.Holder)
+		require.True(t, pool.state["client1"].Leased)
+	})
+	require.False(t, pool.state["client1"].Leased, "a reserved wallet is returned on cleanup")
+	require.Zero(t, c.reads, "a reserved wallet is neither funded nor drained")
+	require.Empty(t, c.drained)
+	require.Empty(t, c.topUps)
+}
+
+func TestStateRoundTrip(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "state", "wallet_pool_state.json")
+	c := newChain()
+	c.balances["client1"] = 250
+
+	pool := newPool(t, 3, c, statePath)
+	lease(t, pool, "first", func(*Wallet) {})
+	lease(t, pool, "second", func(*Wallet) {
+		// a run interrupted before Close leaves its wallets leased in the state file
+		content, err := os.ReadFile(statePath)
+		require.NoError(t, err)
+		var saved map[string]*walletState
+		require.NoError(t, json.Unmarshal(content, &saved))
+		require.True(t, saved["client1"].Leased)
+		require.Equal(t, "second", saved["client1"].Holder)
+		require.Equal(t, 2, saved["client1"].Leases)
+		require.Equal(t, currency.Coin(250), *saved["client1"].Balance)
+
+		restored := newPool(t, 3, c, statePath)
+		require.Equal(t, 2, restored.state["client1"].Leases)
+		require.False(t, restored.state["client1"].Leased, "wallets left leased by a previous run with the same pid are reclaimed")
+		require.Nil(t, restored.state["client1"].Balance, "the balance of a reclaimed wallet is read again")
+	})
+
+	released := newPool(t, 3, c, statePath)
+	require.Equal(t, currency.Coin(250), *released.state["client1"].Balance, "the balance recorded on release is persisted")
+
+	_, _, err := pool.acquire("left")
+	require.NoError(t, err)
+	require.NoError(t, pool.Close())
+	closed := newPool(t, 3, c, statePath)
+	for id, s := range closed.state {
+		require.False(t, s.Leased, id)
+	}
+}
+
+func TestNewReclaimsOnlyStaleLeases(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "wallet_pool_state.json")
+	host, err := os.Hostname()
+	require.NoError(t, err)
+	exited := exec.Command("true")
+	require.NoError(t, exited.Run())
+	balance := currency.Coin(250)
+
+	now := time.Now()
+	leases := map[string]*walletState{
+		"client1": {Leased: true, Holder: "running", PID: os.Getppid(), Host: host, UpdatedAt: now, Balance: &balance},
+		"client2": {Leased: true, Holder: "exited", PID: exited.Process.Pid, Host: host, UpdatedAt: now, Balance: &balance},
+		"client3": {Leased: true, Holder: "remote", PID: 1, Host: "elsewhere", UpdatedAt: now},
+		"client4": {Leased: true, Holder: "expired", PID: os.Getppid(), Host: host, UpdatedAt: now.Add(-DefaultLeaseExpiry - time.Minute)},
+	}
+	content, err := json.Marshal(leases)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(statePath, content, 0600))
+
+	pool := newPool(t, 5, newChain(), statePath)
+	require.True(t, pool.state["client1"].Leased, "the lease of a running process is kept")
+	require.Equal(t, currency.Coin(250), *pool.state["client1"].Balance)
+	require.False(t, pool.state["client2"].Leased, "the lease of an exited process is reclaimed")
+	require.Nil(t, pool.state["client2"].Balance)
+	require.True(t, pool.state["client3"].Leased, "the lease of another host is kept until it expired")
+	require.False(t, pool.state["client4"].Leased, "an expired lease is reclaimed")
+
+	wallet, _, err := pool.acquire("new")
+	require.NoError(t, err)
+	require.Equal(t, 2, wallet.Index, "the wallets leased by other runs are skipped")
+
+	require.NoError(t, pool.Close())
+	closed := newPool(t, 5, newChain(), statePath)
+	require.True(t, closed.state["client1"].Leased, "closing keeps the leases of other runs")
+	require.False(t, closed.state["client2"].Leased)
+}
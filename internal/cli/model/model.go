@@ -42,20 +42,20 @@ func (p PoolStatus) String() string {
 }
 
 type Wallet struct {
-	ClientID            string `json:"client_id"`
-	ClientPublicKey     string `json:"client_public_key"`
+	ClientID            string `json:"client_id" cli:"required"`
+	ClientPublicKey     string `json:"client_public_key" cli:"required"`
 	EncryptionPublicKey string `json:"encryption_public_key"`
 }
 
 type Allocation struct {
-	ID             string    `json:"id"`
+	ID             string    `json:"id" cli:"required"`
 	Tx             string    `json:"tx"`
 	Name           string    `json:"name"`
 	ExpirationDate int64     `json:"expiration_date"`
 	DataShards     int       `json:"data_shards"`
 	ParityShards   int       `json:"parity_shards"`
 	Size           int64     `json:"size"`
-	Owner          string    `json:"owner_id"`
+	Owner          string    `json:"owner_id" cli:"required"`
 	OwnerPublicKey string    `json:"owner_public_key"`
 	Payer          string    `json:"payer_id"`
 	Blobbers       []Blobber `json:"blobbers"`
@@ -284,6 +284,14 @@ type StakePoolDelegatePoolInfo struct {
 	TotalPenalty int64  `json:"total_penalty"`
 	Status       string `json:"status"`
 	RoundCreated int64  `json:"round_created"`
+
+	ProviderId   string   `json:"provider_id"`
+	ProviderType Provider `json:"provider_type"`
+}
+
+// StakePoolUserInfo are the delegate pools of a wallet by provider, as printed by zbox sp-user-info
+type StakePoolUserInfo struct {
+	Pools map[string][]StakePoolDelegatePoolInfo `json:"pools"`
 }
 
 type StakePoolSettings struct {
@@ -596,6 +604,23 @@ type Challenges struct {
 	RoundCreatedAt int64             `json:"round_created_at"`
 }
 
+// Challenge is a challenge as stored in the event database of the sharders
+type Challenge struct {
+	ChallengeID    string `json:"challenge_id"`
+	CreatedAt      int64  `json:"created_at"`
+	AllocationID   string `json:"allocation_id"`
+	BlobberID      string `json:"blobber_id"`
+	ValidatorsID   string `json:"validators_id"`
+	Seed           int64  `json:"seed"`
+	AllocationRoot string `json:"allocation_root"`
+	Responded      int64  `json:"responded"`
+	Passed         bool   `json:"passed"`
+	RoundResponded int64  `json:"round_responded"`
+	RoundCreatedAt int64  `json:"round_created_at"`
+	ExpiredN       int    `json:"expired_n"`
+	Timestamp      int64  `json:"timestamp"`
+}
+
 type Transaction struct {
 	Hash              string `json:"hash"`
 	Signature         string `json:"signature"`
@@ -654,23 +679,26 @@ const (
 	NumOfRewards
 )
 
-var rewardString = []string{
-	"min lock demand",
-	"block_reward_miner",
-	"block_reward_sharder",
-	"block_reward_blobber",
-	"fees miner",
-	"fees_authorizer",
-	"fees sharder",
-	"validation reward",
-	"file download reward",
-	"challenge pass reward",
-	"challenge slash",
-	"cancellation charge",
-	"invalid",
+// rewardString is indexed by Reward, so that every label stays next to its constant
+var rewardString = [...]string{
+	BlockRewardMiner:         "block_reward_miner",
+	BlockRewardSharder:       "block_reward_sharder",
+	BlockRewardBlobber:       "block_reward_blobber",
+	FeeRewardMiner:           "fees miner",
+	FeeRewardAuthorizer:      "fees_authorizer",
+	FeeRewardSharder:         "fees sharder",
+	ValidationReward:         "validation reward",
+	FileDownloadReward:       "file download reward",
+	ChallengePassReward:      "challenge pass reward",
+	ChallengeSlashPenalty:    "challenge slash",
+	CancellationChargeReward: "cancellation charge",
+	NumOfRewards:             "invalid",
 }
 
 func (r Reward) String() string {
+	if r < 0 || r >= NumOfRewards {
+		return rewardString[NumOfRewards]
+	}
 	return rewardString[r]
 }
 
@@ -678,6 +706,23 @@ func (r Reward) Int() int {
 	return int(r)
 }
 
+// Provider returns the type of provider paid the reward
+func (r Reward) Provider() Provider {
+	switch r {
+	case BlockRewardMiner, FeeRewardMiner:
+		return ProviderMiner
+	case BlockRewardSharder, FeeRewardSharder:
+		return ProviderSharder
+	case BlockRewardBlobber, FileDownloadReward, ChallengePassReward, ChallengeSlashPenalty, CancellationChargeReward:
+		return ProviderBlobber
+	case ValidationReward:
+		return ProviderValidator
+	case FeeRewardAuthorizer:
+		return ProviderAuthorizer
+	}
+	return 0
+}
+
 type RewardProvider struct {
 	Amount      int64  `json:"amount"`
 	BlockNumber int64  `json:"block_number"`
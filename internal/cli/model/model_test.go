@@ -0,0 +1,32 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewardString(t *testing.T) {
+	for reward, label := range map[Reward]string{
+		BlockRewardMiner:         "block_reward_miner",
+		BlockRewardSharder:       "block_reward_sharder",
+		BlockRewardBlobber:       "block_reward_blobber",
+		FeeRewardMiner:           "fees miner",
+		FeeRewardAuthorizer:      "fees_authorizer",
+		FeeRewardSharder:         "fees sharder",
+		ValidationReward:         "validation reward",
+		FileDownloadReward:       "file download reward",
+		ChallengePassReward:      "challenge pass reward",
+		ChallengeSlashPenalty:    "challenge slash",
+		CancellationChargeReward: "cancellation charge",
+		NumOfRewards:             "invalid",
+		Reward(-1):               "invalid",
+		NumOfRewards + 1:         "invalid",
+	} {
+		require.Equal(t, label, reward.String(), "reward %d", int(reward))
+	}
+
+	// the reward types are the ones of the chain, paid as their int value
+	require.Equal(t, 8, ChallengePassReward.Int())
+	require.Equal(t, 11, int(NumOfRewards))
+}
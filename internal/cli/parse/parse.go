@@ -0,0 +1,229 @@
+package cliparse
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RequiredTag marks model fields that must be present in the output of a command, as in `cli:"required"`
+const RequiredTag = "cli"
+
+// ErrNoJSON is returned for output without a JSON document, e.g. of a command run without --json
+var ErrNoJSON = errors.New("no JSON in command output")
+
+// Report is the result of checking the JSON output of a command against its model
+type Report struct {
+	// Unknown are the paths of fields in the output that the model does not have, the model drifted from the CLI
+	Unknown []string
+	// Missing are the paths of required fields absent from the output
+	Missing []string
+}
+
+// Drifted reports whether the output has fields the model does not know
+func (r *Report) Drifted() bool {
+	return len(r.Unknown) > 0
+}
+
+// FindJSON returns the JSON document printed by a command, which may follow progress and warning lines.
+// It is the longest document ending the output, possibly spanning lines, or else the last line holding one.
+func FindJSON(output []string) (string, bool) {
+	for i := range output {
+		if !isDocument(strings.TrimSpace(output[i])) {
+			continue
+		}
+		joined := strings.Join(output[i:], "\n")
+		if json.Valid([]byte(joined)) {
+			return joined, true
+		}
+	}
+	for i := len(output) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(output[i])
+		if isDocument(line) && json.Valid([]byte(line)) {
+			return line, true
+		}
+	}
+	return "", false
+}
+
+func isDocument(line string) bool {
+	return strings.HasPrefix(line, "{") || strings.HasPrefix(line, "[")
+}
+
+// JSON decodes the JSON document in the output into v and checks it against the type of v.
+// Missing required fields are an error, unknown fields are only reported.
+func JSON(output []string, v interface{}) (*Report, error) {
+	document, ok := FindJSON(output)
+	if !ok {
+		return nil, ErrNoJSON
+	}
+	if err := json.Unmarshal([]byte(document), v); err != nil {
+		return nil, fmt.Errorf("decoding %T from `%s`: %w", v, document, err)
+	}
+
+	var raw interface{}
+	if err := json.Unmarshal([]byte(document), &raw); err != nil {
+		return nil, err
+	}
+	report := &Report{}
+	check(raw, reflect.TypeOf(v), "", report)
+	report.Unknown = uniqueSorted(report.Unknown)
+	report.Missing = uniqueSorted(report.Missing)
+	if len(report.Missing) > 0 {
+		return report, fmt.Errorf("%T output misses required fields %s", v, strings.Join(report.Missing, ", "))
+	}
+	return report, nil
+}
+
+// Decode decodes the output into a T, from JSON if the command printed JSON and otherwise with fallback,
+// for commands that have no --json flag. The report is nil if fallback was used.
+func Decode[T any](output []string, fallback func(output []string) (T, error)) (T, *Report, error) {
+	var v T
+	report, err := JSON(output, &v)
+	if errors.Is(err, ErrNoJSON) && fallback != nil {
+		v, err = fallback(output)
+		return v, nil, err
+	}
+	return v, report, err
+}
+
+// Regex returns the submatches of the first line of output matching re, for commands without JSON output
+func Regex(output []string, re *regexp.Regexp) ([]string, error) {
+	for _, line := range output {
+		if match := re.FindStringSubmatch(line); match != nil {
+			return match, nil
+		}
+	}
+	return nil, fmt.Errorf("no line of output matches %s", re)
+}
+
+var (
+	timeType        = reflect.TypeOf(time.Time{})
+	rawMessageType  = reflect.TypeOf(json.RawMessage{})
+	unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// check walks the decoded JSON alongside the model type, recording unknown and missing required fields
+func check(raw interface{}, t reflect.Type, path string, report *Report) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType || t == rawMessageType || reflect.PointerTo(t).Implements(unmarshalerType) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := raw.(map[string]interface{})
+		if !ok {
+			return
+		}
+		fields := jsonFields(t)
+		for key, value := range object {
+			field, ok := lookup(fields, key)
+			if !ok {
+				report.Unknown = append(report.Unknown, join(path, key))
+				continue
+			}
+			check(value, field.Type, join(path, key), report)
+		}
+		for name, field := range fields {
+			if field.Tag.Get(RequiredTag) != "required" {
+				continue
+			}
+			if !hasKey(object, name) {
+				report.Missing = append(report.Missing, join(path, name))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		items, ok := raw.([]interface{})
+		if !ok {
+			return
+		}
+		for _, item := range items {
+			check(item, t.Elem(), path+"[]", report)
+		}
+	case reflect.Map:
+		object, ok := raw.(map[string]interface{})
+		if !ok {
+			return
+		}
+		for _, value := range object {
+			check(value, t.Elem(), join(path, "*"), report)
+		}
+	}
+}
+
+// jsonFields returns the fields of a struct by JSON name, including those of embedded structs
+func jsonFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			for embeddedName, embedded := range jsonFields(fieldType) {
+				if _, ok := fields[embeddedName]; !ok {
+					fields[embeddedName] = embedded
+				}
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field
+	}
+	return fields
+}
+
+// lookup finds the field of a JSON key, case insensitively like encoding/json
+func lookup(fields map[string]reflect.StructField, key string) (reflect.StructField, bool) {
+	if field, ok := fields[key]; ok {
+		return field, true
+	}
+	for name, field := range fields {
+		if strings.EqualFold(name, key) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func hasKey(object map[string]interface{}, name string) bool {
+	for key := range object {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func uniqueSorted(values []string) []string {
+	sort.Strings(values)
+	var out []string
+	for i, value := range values {
+		if i == 0 || value != values[i-1] {
+			out = append(out, value)
+		}
+	}
+	return out
+}
@@ -0,0 +1,88 @@
+package cliparse
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type terms struct {
+	ReadPrice int64 `json:"read_price"`
+}
+
+type blobber struct {
+	ID    string `json:"id" cli:"required"`
+	Terms terms  `json:"terms"`
+}
+
+type allocation struct {
+	ID       string            `json:"id" cli:"required"`
+	Size     int64             `json:"size"`
+	Owner    string            `json:"owner_id" cli:"required"`
+	Blobbers []*blobber        `json:"blobbers"`
+	Meta     map[string]terms  `json:"meta"`
+	Hidden   string            `json:"-"`
+	Labels   map[string]string `json:"labels"`
+}
+
+func TestJSON(t *testing.T) {
+	output := []string{
+		"Warning: config file not found",
+		`{"id":"alloc","size":1024,"owner_id":"owner","stats":{"used":1},` +
+			`"blobbers":[{"id":"b1","terms":{"read_price":1,"min_lock":2}},{"id":"b2","url":"http://b2"}],` +
+			`"meta":{"x":{"read_price":1,"new":true}},"labels":{"any":"thing"}}`,
+	}
+
+	var a allocation
+	report, err := JSON(output, &a)
+	require.NoError(t, err)
+	require.Equal(t, "alloc", a.ID)
+	require.Len(t, a.Blobbers, 2)
+	require.Equal(t, []string{"blobbers[].terms.min_lock", "blobbers[].url", "meta.*.new", "stats"}, report.Unknown)
+	require.True(t, report.Drifted())
+	require.Empty(t, report.Missing)
+}
+
+func TestJSONMissingRequiredFields(t *testing.T) {
+	var a allocation
+	report, err := JSON([]string{`{"size":1,"blobbers":[{"terms":{}}]}`}, &a)
+	require.ErrorContains(t, err, "misses required fields")
+	require.Equal(t, []string{"blobbers[].id", "id", "owner_id"}, report.Missing)
+}
+
+func TestJSONSpanningLines(t *testing.T) {
+	var bs []blobber
+	report, err := JSON([]string{"Fetching blobbers", "[", `{"id":"b1"},`, `{"id":"b2"}`, "]"}, &bs)
+	require.NoError(t, err)
+	require.False(t, report.Drifted())
+	require.Equal(t, []blobber{{ID: "b1"}, {ID: "b2"}}, bs)
+}
+
+func TestDecodeFallsBackWithoutJSON(t *testing.T) {
+	allocationCreated := regexp.MustCompile(`^Allocation created: (.+)$`)
+	fallback := func(output []string) (allocation, error) {
+		match, err := Regex(output, allocationCreated)
+		if err != nil {
+			return allocation{}, err
+		}
+		return allocation{ID: match[1]}, nil
+	}
+
+	a, report, err := Decode([]string{"Allocation created: alloc"}, fallback)
+	require.NoError(t, err)
+	require.Nil(t, report)
+	require.Equal(t, "alloc", a.ID)
+
+	a, report, err = Decode([]string{`{"id":"alloc2","owner_id":"owner"}`}, fallback)
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Equal(t, "alloc2", a.ID)
+
+	_, _, err = Decode([]string{"Error: not enough tokens"}, fallback)
+	require.ErrorContains(t, err, "no line of output matches")
+
+	_, err = JSON([]string{"Error: not enough tokens"}, &a)
+	require.True(t, errors.Is(err, ErrNoJSON))
+}
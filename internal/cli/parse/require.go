@@ -0,0 +1,42 @@
+package cliparse
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/0chain/system_test/internal/api/util/test"
+	"github.com/stretchr/testify/require"
+)
+
+// reportedDrift holds the drift already logged, so that every drift is logged once per run
+var reportedDrift sync.Map
+
+// RequireJSON decodes the JSON output of a command into a T, failing the test if it cannot or if required
+// fields are missing. Fields unknown to T are logged as schema drift.
+func RequireJSON[T any](t *test.SystemTest, output []string) T {
+	var v T
+	report, err := JSON(output, &v)
+	require.NoError(t, err, "parsing output %q", output)
+	logDrift(t, fmt.Sprintf("%T", v), report)
+	return v
+}
+
+// RequireDecode is RequireJSON with a fallback for commands without JSON output, see Decode
+func RequireDecode[T any](t *test.SystemTest, output []string, fallback func(output []string) (T, error)) T {
+	v, report, err := Decode(output, fallback)
+	require.NoError(t, err, "parsing output %q", output)
+	logDrift(t, fmt.Sprintf("%T", v), report)
+	return v
+}
+
+func logDrift(t *test.SystemTest, model string, report *Report) {
+	if report == nil || !report.Drifted() {
+		return
+	}
+	unknown := strings.Join(report.Unknown, ", ")
+	if _, reported := reportedDrift.LoadOrStore(model+":"+unknown, true); reported {
+		return
+	}
+	t.Logf("schema drift: the CLI printed fields unknown to %s: %s", model, unknown)
+}
@@ -0,0 +1,73 @@
+package s3stub
+
+import (
+	"debug/buildinfo"
+	"fmt"
+	"runtime/debug"
+	"strconv"
+	"strings"
+)
+
+// EndpointModule is the module of aws-sdk-go-v2 reading EndpointEnv, from MinEndpointVersion on
+const (
+	EndpointModule     = "github.com/aws/aws-sdk-go-v2/config"
+	MinEndpointVersion = "v1.25.0"
+)
+
+// HonoursEndpointEnv reports whether the Go binary at path, as s3mgrt, reads the endpoint of its S3 client from
+// EndpointEnv. Builds with an older aws-sdk-go-v2 ignore it and send the requests of the stand-in to AWS.
+func HonoursEndpointEnv(path string) (bool, error) {
+	info, err := buildinfo.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("reading build info of %s: %w", path, err)
+	}
+	return honoursEndpointEnv(info), nil
+}
+
+func honoursEndpointEnv(info *debug.BuildInfo) bool {
+	for _, dep := range info.Deps {
+		if dep.Path != EndpointModule {
+			continue
+		}
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+		return !olderVersion(dep.Version, MinEndpointVersion)
+	}
+	return false
+}
+
+// olderVersion compares the major, minor and patch of two semantic versions, an unparsable version is older
+func olderVersion(version, than string) bool {
+	v, ok := versionNumbers(version)
+	if !ok {
+		return true
+	}
+	w, _ := versionNumbers(than)
+	for i := range v {
+		if v[i] != w[i] {
+			return v[i] < w[i]
+		}
+	}
+	return false
+}
+
+func versionNumbers(version string) ([3]int, bool) {
+	var numbers [3]int
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	parts := strings.Split(version, ".")
+	if len(parts) != len(numbers) {
+		return numbers, false
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return numbers, false
+		}
+		numbers[i] = n
+	}
+	return numbers, true
+}
@@ -0,0 +1,59 @@
+package s3stub
+
+import (
+	"bytes"
+	"time"
+)
+
+const (
+	// EmptyBucket holds no objects
+	EmptyBucket = "system-tests-empty"
+	// ForeignBucket belongs to another account, like the bucket named "invalid" on AWS
+	ForeignBucket = "invalid"
+)
+
+// Bucket is a bucket the server is seeded with
+type Bucket struct {
+	Name    string
+	Objects []Object
+	// Foreign buckets belong to another account, every request for them is denied
+	Foreign bool
+}
+
+// Object is an object of a seeded bucket
+type Object struct {
+	Key     string
+	Content []byte
+	// Size is the length of the generated content of objects without Content
+	Size     int
+	Modified time.Time
+}
+
+// Fixtures returns the buckets the migration tests use: the small bucket with nested prefixes, too large for a 5 KB
+// allocation, the alternate bucket the tests fill themselves, the empty and the foreign bucket
+func Fixtures(smallBucket, alternateBucket string) []Bucket {
+	now := time.Now().UTC()
+	return []Bucket{
+		{Name: smallBucket, Objects: []Object{
+			{Key: "index.html", Content: []byte("<html><body>system tests</body></html>\n")},
+			{Key: "docs/readme.txt", Size: 2 * 1024},
+			{Key: "docs/2023/report.csv", Size: 3 * 1024, Modified: now.AddDate(-1, 0, 0)},
+			{Key: "docs/2023/q4/summary.csv", Size: 1024, Modified: now.AddDate(0, -3, 0)},
+			{Key: "images/logo.png", Size: 4 * 1024},
+		}},
+		{Name: alternateBucket},
+		{Name: EmptyBucket},
+		{Name: ForeignBucket, Foreign: true, Objects: []Object{
+			{Key: "private.txt", Size: 100},
+		}},
+	}
+}
+
+// content returns the content of the object, generated from its key if it has none
+func (o Object) content() []byte {
+	if o.Content != nil {
+		return o.Content
+	}
+	pattern := []byte(o.Key + "\n")
+	return bytes.Repeat(pattern, o.Size/len(pattern)+1)[:o.Size]
+}
@@ -0,0 +1,469 @@
+// Package s3stub serves a small S3-compatible API in process, so that the S3 migration tests run without AWS
+package s3stub
+
+import (
+	"crypto/md5" //nolint
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EndpointEnv points the S3 clients of aws-sdk-go-v2, as the one of s3mgrt, to another endpoint
+const EndpointEnv = "AWS_ENDPOINT_URL_S3"
+
+// DefaultRegion is the region the buckets report, the one of the AWS buckets the tests used
+const DefaultRegion = "us-east-2"
+
+const (
+	xmlns           = "http://s3.amazonaws.com/doc/2006-03-01/"
+	timestampFormat = "2006-01-02T15:04:05.000Z"
+	defaultMaxKeys  = 1000
+)
+
+// Server answers ListBuckets, HeadBucket, GetBucketLocation, ListObjectsV2 and Get, Head, Put and DeleteObject on
+// path-style URLs, for requests signed with SigV4 by its credentials
+type Server struct {
+	URL       string
+	AccessKey string
+	SecretKey string
+	Region    string
+
+	server    *httptest.Server
+	requestID atomic.Int64
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	created time.Time
+	foreign bool
+	objects map[string]*object
+}
+
+// object is immutable once stored, a put replaces it
+type object struct {
+	content     []byte
+	etag        string
+	modified    time.Time
+	contentType string
+	metadata    map[string]string
+}
+
+// Start serves the buckets on a local port, to requests signed with the credentials
+func Start(accessKey, secretKey string, buckets ...Bucket) *Server {
+	s := &Server{
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+		Region:    DefaultRegion,
+		buckets:   make(map[string]*bucket, len(buckets)),
+	}
+	now := time.Now().UTC()
+	for _, fixture := range buckets {
+		b := &bucket{created: now, foreign: fixture.Foreign, objects: make(map[string]*object, len(fixture.Objects))}
+		for _, o := range fixture.Objects {
+			modified := o.Modified
+			if modified.IsZero() {
+				modified = now
+			}
+			b.objects[o.Key] = newObject(o.content(), modified, "", nil)
+		}
+		s.buckets[fixture.Name] = b
+	}
+	s.server = httptest.NewServer(s)
+	s.URL = s.server.URL
+	return s
+}
+
+// Close stops serving and waits for the pending requests
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Keys returns the sorted keys of the objects of the bucket
+func (s *Server) Keys(bucketName string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.buckets[bucketName]
+	if !ok {
+		return nil
+	}
+	return b.keys()
+}
+
+func newObject(content []byte, modified time.Time, contentType string, metadata map[string]string) *object {
+	sum := md5.Sum(content) //nolint
+	if contentType == "" {
+		contentType = "binary/octet-stream"
+	}
+	return &object{
+		content:     content,
+		etag:        `"` + hex.EncodeToString(sum[:]) + `"`,
+		modified:    modified.Truncate(time.Millisecond),
+		contentType: contentType,
+		metadata:    metadata,
+	}
+}
+
+func (b *bucket) keys() []string {
+	keys := make([]string, 0, len(b.objects))
+	for key := range b.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// apiError is an S3 error response
+type apiError struct {
+	status  int
+	code    string
+	message string
+}
+
+func errorf(status int, code, format string, args ...interface{}) *apiError {
+	return &apiError{status: status, code: code, message: fmt.Sprintf(format, args...)}
+}
+
+type errorResponse struct {
+	XMLName   xml.Name `xml:"Error"`
+	Code      string
+	Message   string
+	Resource  string
+	RequestID string `xml:"RequestId"`
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Amz-Request-Id", strconv.FormatInt(s.requestID.Add(1), 10))
+
+	body, aerr := s.verify(r)
+	if aerr != nil {
+		s.fail(w, r, aerr)
+		return
+	}
+
+	bucketName, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucketName == "" {
+		if r.Method != http.MethodGet {
+			s.fail(w, r, errorf(http.StatusMethodNotAllowed, "MethodNotAllowed", "The specified method is not allowed against this resource."))
+			return
+		}
+		s.listBuckets(w)
+		return
+	}
+
+	s.mu.Lock()
+	b, ok := s.buckets[bucketName]
+	s.mu.Unlock()
+	switch {
+	case !ok:
+		s.fail(w, r, errorf(http.StatusNotFound, "NoSuchBucket", "The specified bucket does not exist"))
+		return
+	case b.foreign:
+		s.fail(w, r, errorf(http.StatusForbidden, "AccessDenied", "Access Denied"))
+		return
+	}
+
+	query := r.URL.Query()
+	switch {
+	case key == "" && r.Method == http.MethodHead:
+		w.Header().Set("X-Amz-Bucket-Region", s.Region)
+	case key == "" && r.Method == http.MethodGet && query.Has("location"):
+		s.getBucketLocation(w)
+	case key == "" && r.Method == http.MethodGet && query.Get("list-type") == "2":
+		s.listObjectsV2(w, r, bucketName, b)
+	case key != "" && (r.Method == http.MethodGet || r.Method == http.MethodHead):
+		s.getObject(w, r, b, key)
+	case key != "" && r.Method == http.MethodPut && r.Header.Get("X-Amz-Copy-Source") == "" && len(query) == 0:
+		s.putObject(w, r, b, key, body)
+	case key != "" && r.Method == http.MethodDelete && len(query) == 0:
+		s.mu.Lock()
+		delete(b.objects, key)
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		s.fail(w, r, errorf(http.StatusNotImplemented, "NotImplemented", "%s %s is not implemented by the stand-in", r.Method, r.URL.RequestURI()))
+	}
+}
+
+func (s *Server) fail(w http.ResponseWriter, r *http.Request, aerr *apiError) {
+	if r.Method == http.MethodHead {
+		w.WriteHeader(aerr.status)
+		return
+	}
+	s.writeXML(w, aerr.status, errorResponse{
+		Code:      aerr.code,
+		Message:   aerr.message,
+		Resource:  r.URL.Path,
+		RequestID: w.Header().Get("X-Amz-Request-Id"),
+	})
+}
+
+func (s *Server) writeXML(w http.ResponseWriter, status int, v interface{}) {
+	out, err := xml.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(xml.Header))
+	_, _ = w.Write(out)
+}
+
+type listAllMyBucketsResult struct {
+	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListAllMyBucketsResult"`
+	Owner   struct {
+		ID string
+	}
+	Buckets []bucketEntry `xml:"Buckets>Bucket"`
+}
+
+type bucketEntry struct {
+	Name         string
+	CreationDate string
+}
+
+func (s *Server) listBuckets(w http.ResponseWriter) {
+	s.mu.Lock()
+	result := listAllMyBucketsResult{}
+	result.Owner.ID = s.AccessKey
+	for name, b := range s.buckets {
+		if !b.foreign {
+			result.Buckets = append(result.Buckets, bucketEntry{Name: name, CreationDate: b.created.Format(timestampFormat)})
+		}
+	}
+	s.mu.Unlock()
+	sort.Slice(result.Buckets, func(i, j int) bool { return result.Buckets[i].Name < result.Buckets[j].Name })
+	s.writeXML(w, http.StatusOK, result)
+}
+
+type locationConstraint struct {
+	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ LocationConstraint"`
+	Region  string   `xml:",chardata"`
+}
+
+func (s *Server) getBucketLocation(w http.ResponseWriter) {
+	// us-east-1 is reported as an empty constraint
+	region := s.Region
+	if region == "us-east-1" {
+		region = ""
+	}
+	s.writeXML(w, http.StatusOK, locationConstraint{Region: region})
+}
+
+type listBucketResult struct {
+	XMLName               xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListBucketResult"`
+	Name                  string
+	Prefix                string
+	Delimiter             string `xml:",omitempty"`
+	StartAfter            string `xml:",omitempty"`
+	ContinuationToken     string `xml:",omitempty"`
+	NextContinuationToken string `xml:",omitempty"`
+	EncodingType          string `xml:",omitempty"`
+	KeyCount              int
+	MaxKeys               int
+	IsTruncated           bool
+	Contents              []objectEntry
+	CommonPrefixes        []commonPrefix
+}
+
+type objectEntry struct {
+	Key          string
+	LastModified string
+	ETag         string
+	Size         int
+	StorageClass string
+}
+
+type commonPrefix struct {
+	Prefix string
+}
+
+// listObjectsV2 lists the keys after the continuation token or start-after key, rolling the keys sharing a prefix up
+// to the delimiter into a common prefix. The continuation token is the last key or common prefix listed.
+func (s *Server) listObjectsV2(w http.ResponseWriter, r *http.Request, bucketName string, b *bucket) {
+	query := r.URL.Query()
+	result := listBucketResult{
+		Name:              bucketName,
+		Prefix:            query.Get("prefix"),
+		Delimiter:         query.Get("delimiter"),
+		StartAfter:        query.Get("start-after"),
+		ContinuationToken: query.Get("continuation-token"),
+		MaxKeys:           defaultMaxKeys,
+	}
+	if value := query.Get("max-keys"); value != "" {
+		maxKeys, err := strconv.Atoi(value)
+		if err != nil || maxKeys < 0 {
+			s.fail(w, r, errorf(http.StatusBadRequest, "InvalidArgument", "Provided max-keys not an integer or within integer range"))
+			return
+		}
+		result.MaxKeys = min(maxKeys, defaultMaxKeys)
+	}
+
+	after, skipPrefix := result.StartAfter, ""
+	if result.ContinuationToken != "" {
+		token, err := base64.RawURLEncoding.DecodeString(result.ContinuationToken)
+		if err != nil {
+			s.fail(w, r, errorf(http.StatusBadRequest, "InvalidArgument", "The continuation token provided is incorrect"))
+			return
+		}
+		after = max(after, string(token))
+		if result.Delimiter != "" && strings.HasSuffix(string(token), result.Delimiter) {
+			skipPrefix = string(token)
+		}
+	}
+
+	s.mu.Lock()
+	keys := b.keys()
+	objects := b.objects
+	var last string
+	for _, key := range keys {
+		if !strings.HasPrefix(key, result.Prefix) || key <= after || (skipPrefix != "" && strings.HasPrefix(key, skipPrefix)) {
+			continue
+		}
+		entry, rolledUp := key, false
+		if result.Delimiter != "" {
+			if i := strings.Index(key[len(result.Prefix):], result.Delimiter); i >= 0 {
+				entry, rolledUp = key[:len(result.Prefix)+i+len(result.Delimiter)], true
+			}
+		}
+		if rolledUp && entry == last {
+			continue
+		}
+		if result.KeyCount == result.MaxKeys {
+			result.IsTruncated = true
+			result.NextContinuationToken = base64.RawURLEncoding.EncodeToString([]byte(last))
+			break
+		}
+		result.KeyCount++
+		last = entry
+		if rolledUp {
+			result.CommonPrefixes = append(result.CommonPrefixes, commonPrefix{Prefix: entry})
+			continue
+		}
+		o := objects[key]
+		result.Contents = append(result.Contents, objectEntry{
+			Key:          key,
+			LastModified: o.modified.Format(timestampFormat),
+			ETag:         o.etag,
+			Size:         len(o.content),
+			StorageClass: "STANDARD",
+		})
+	}
+	s.mu.Unlock()
+
+	if query.Get("encoding-type") == "url" {
+		result.EncodingType = "url"
+		result.Prefix = escape(result.Prefix, false)
+		result.Delimiter = escape(result.Delimiter, false)
+		result.StartAfter = escape(result.StartAfter, false)
+		for i := range result.Contents {
+			result.Contents[i].Key = escape(result.Contents[i].Key, false)
+		}
+		for i := range result.CommonPrefixes {
+			result.CommonPrefixes[i].Prefix = escape(result.CommonPrefixes[i].Prefix, false)
+		}
+	}
+	s.writeXML(w, http.StatusOK, result)
+}
+
+// getObject answers GetObject and HeadObject, for the whole object or the byte range of the Range header
+func (s *Server) getObject(w http.ResponseWriter, r *http.Request, b *bucket, key string) {
+	s.mu.Lock()
+	o, ok := b.objects[key]
+	s.mu.Unlock()
+	if !ok {
+		s.fail(w, r, errorf(http.StatusNotFound, "NoSuchKey", "The specified key does not exist."))
+		return
+	}
+
+	content, status := o.content, http.StatusOK
+	if header := r.Header.Get("Range"); header != "" {
+		start, end, ok := parseRange(header, len(o.content))
+		if !ok {
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", len(o.content)))
+			s.fail(w, r, errorf(http.StatusRequestedRangeNotSatisfiable, "InvalidRange", "The requested range is not satisfiable"))
+			return
+		}
+		content, status = o.content[start:end+1], http.StatusPartialContent
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, len(o.content)))
+	}
+
+	header := w.Header()
+	header.Set("Accept-Ranges", "bytes")
+	header.Set("Content-Length", strconv.Itoa(len(content)))
+	header.Set("Content-Type", o.contentType)
+	header.Set("ETag", o.etag)
+	header.Set("Last-Modified", o.modified.Format(http.TimeFormat))
+	for name, value := range o.metadata {
+		header.Set("X-Amz-Meta-"+name, value)
+	}
+	w.WriteHeader(status)
+	if r.Method != http.MethodHead {
+		_, _ = w.Write(content)
+	}
+}
+
+func (s *Server) putObject(w http.ResponseWriter, r *http.Request, b *bucket, key string, body []byte) {
+	if digest := r.Header.Get("Content-MD5"); digest != "" {
+		sum := md5.Sum(body) //nolint
+		if digest != base64.StdEncoding.EncodeToString(sum[:]) {
+			s.fail(w, r, errorf(http.StatusBadRequest, "BadDigest", "The Content-MD5 you specified did not match what we received."))
+			return
+		}
+	}
+
+	metadata := map[string]string{}
+	for name, values := range r.Header {
+		if meta, ok := strings.CutPrefix(strings.ToLower(name), "x-amz-meta-"); ok {
+			metadata[meta] = values[0]
+		}
+	}
+	o := newObject(body, time.Now().UTC(), r.Header.Get("Content-Type"), metadata)
+
+	s.mu.Lock()
+	b.objects[key] = o
+	s.mu.Unlock()
+	w.Header().Set("ETag", o.etag)
+}
+
+// parseRange returns the first and last byte of a single range header, as "bytes=0-99", "bytes=100-" or "bytes=-100"
+func parseRange(header string, size int) (int, int, bool) {
+	spec, ok := strings.CutPrefix(header, "bytes=")
+	if !ok || strings.Contains(spec, ",") {
+		return 0, 0, false
+	}
+	first, last, ok := strings.Cut(spec, "-")
+	if !ok {
+		return 0, 0, false
+	}
+	if first == "" {
+		n, err := strconv.Atoi(last)
+		if err != nil || n <= 0 || size == 0 {
+			return 0, 0, false
+		}
+		return max(size-n, 0), size - 1, true
+	}
+	start, err := strconv.Atoi(first)
+	if err != nil || start >= size {
+		return 0, 0, false
+	}
+	end := size - 1
+	if last != "" {
+		if end, err = strconv.Atoi(last); err != nil || end < start {
+			return 0, 0, false
+		}
+		end = min(end, size-1)
+	}
+	return start, end, true
+}
@@ -0,0 +1,229 @@
+package s3stub
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"runtime/debug"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	accessKey = "AKIASTUBEXAMPLE"
+	secretKey = "stub-secret-key"
+)
+
+func start(t *testing.T) *Server {
+	fixtures := append(Fixtures("small", "alternate"), Bucket{Name: "escaped", Objects: []Object{
+		{Key: "with space/ünïcode+plus.txt", Size: 10},
+	}}, Bucket{Name: "large", Objects: []Object{
+		{Key: "large/part-0.bin", Size: 6 * 1024 * 1024},
+	}})
+	s := Start(accessKey, secretKey, fixtures...)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func client(s *Server, accessKey, secretKey string) *s3.S3 {
+	return s3.New(session.Must(session.NewSession(&aws.Config{
+		Endpoint:         aws.String(s.URL),
+		Region:           aws.String(DefaultRegion),
+		Credentials:      credentials.NewStaticCredentials(accessKey, secretKey, ""),
+		S3ForcePathStyle: aws.Bool(true),
+		MaxRetries:       aws.Int(0),
+	})))
+}
+
+func requireS3Error(t *testing.T, err error, status int, code string) {
+	t.Helper()
+	var failure awserr.RequestFailure
+	require.ErrorAs(t, err, &failure)
+	require.Equal(t, status, failure.StatusCode(), err.Error())
+	if code != "" {
+		require.Equal(t, code, failure.Code(), err.Error())
+	}
+}
+
+func TestListObjectsV2(t *testing.T) {
+	svc := client(start(t), accessKey, secretKey)
+
+	var (
+		keys  []string
+		pages int
+	)
+	err := svc.ListObjectsV2Pages(&s3.ListObjectsV2Input{Bucket: aws.String("small"), MaxKeys: aws.Int64(2)}, func(page *s3.ListObjectsV2Output, last bool) bool {
+		pages++
+		for _, o := range page.Contents {
+			keys = append(keys, aws.StringValue(o.Key))
+		}
+		return true
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, pages)
+	require.Equal(t, []string{"docs/2023/q4/summary.csv", "docs/2023/report.csv", "docs/readme.txt", "images/logo.png", "index.html"}, keys)
+
+	var entries []string
+	err = svc.ListObjectsV2Pages(&s3.ListObjectsV2Input{Bucket: aws.String("small"), Delimiter: aws.String("/"), MaxKeys: aws.Int64(1)}, func(page *s3.ListObjectsV2Output, last bool) bool {
+		for _, p := range page.CommonPrefixes {
+			entries = append(entries, aws.StringValue(p.Prefix))
+		}
+		for _, o := range page.Contents {
+			entries = append(entries, aws.StringValue(o.Key))
+		}
+		return true
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"docs/", "images/", "index.html"}, entries)
+
+	nested, err := svc.ListObjectsV2(&s3.ListObjectsV2Input{Bucket: aws.String("small"), Prefix: aws.String("docs/"), Delimiter: aws.String("/")})
+	require.NoError(t, err)
+	require.Len(t, nested.CommonPrefixes, 1)
+	require.Equal(t, "docs/2023/", aws.StringValue(nested.CommonPrefixes[0].Prefix))
+	require.Len(t, nested.Contents, 1)
+	require.Equal(t, int64(2048), aws.Int64Value(nested.Contents[0].Size))
+
+	empty, err := svc.ListObjectsV2(&s3.ListObjectsV2Input{Bucket: aws.String(EmptyBucket)})
+	require.NoError(t, err)
+	require.Empty(t, empty.Contents)
+	require.False(t, aws.BoolValue(empty.IsTruncated))
+
+	escaped, err := svc.ListObjectsV2(&s3.ListObjectsV2Input{Bucket: aws.String("escaped")})
+	require.NoError(t, err)
+	require.Equal(t, "with space/ünïcode+plus.txt", aws.StringValue(escaped.Contents[0].Key))
+
+	_, err = svc.ListObjectsV2(&s3.ListObjectsV2Input{Bucket: aws.String("missing")})
+	requireS3Error(t, err, http.StatusNotFound, "NoSuchBucket")
+}
+
+func TestObjects(t *testing.T) {
+	s := start(t)
+	svc := client(s, accessKey, secretKey)
+
+	_, err := svc.HeadBucket(&s3.HeadBucketInput{Bucket: aws.String("large")})
+	require.NoError(t, err)
+	location, err := svc.GetBucketLocation(&s3.GetBucketLocationInput{Bucket: aws.String("small")})
+	require.NoError(t, err)
+	require.Equal(t, DefaultRegion, aws.StringValue(location.LocationConstraint))
+
+	head, err := svc.HeadObject(&s3.HeadObjectInput{Bucket: aws.String("large"), Key: aws.String("large/part-0.bin")})
+	require.NoError(t, err)
+	require.Equal(t, int64(6*1024*1024), aws.Int64Value(head.ContentLength))
+
+	_, err = svc.PutObject(&s3.PutObjectInput{
+		Bucket:   aws.String("alternate"),
+		Key:      aws.String("nested/dir/file.txt"),
+		Body:     bytes.NewReader([]byte("0123456789")),
+		Metadata: aws.StringMap(map[string]string{"Origin": "test"}),
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"nested/dir/file.txt"}, s.Keys("alternate"))
+
+	ranged, err := svc.GetObject(&s3.GetObjectInput{Bucket: aws.String("alternate"), Key: aws.String("nested/dir/file.txt"), Range: aws.String("bytes=2-4")})
+	require.NoError(t, err)
+	content, err := io.ReadAll(ranged.Body)
+	require.NoError(t, err)
+	require.Equal(t, "234", string(content))
+	require.Equal(t, "bytes 2-4/10", aws.StringValue(ranged.ContentRange))
+	require.Equal(t, "test", aws.StringValue(ranged.Metadata["Origin"]))
+
+	_, err = svc.DeleteObject(&s3.DeleteObjectInput{Bucket: aws.String("alternate"), Key: aws.String("nested/dir/file.txt")})
+	require.NoError(t, err)
+	_, err = svc.GetObject(&s3.GetObjectInput{Bucket: aws.String("alternate"), Key: aws.String("nested/dir/file.txt")})
+	requireS3Error(t, err, http.StatusNotFound, "NoSuchKey")
+
+	escaped, err := svc.GetObject(&s3.GetObjectInput{Bucket: aws.String("escaped"), Key: aws.String("with space/ünïcode+plus.txt")})
+	require.NoError(t, err)
+	require.Equal(t, int64(10), aws.Int64Value(escaped.ContentLength))
+}
+
+func TestSignatureVerification(t *testing.T) {
+	s := start(t)
+
+	_, err := client(s, accessKey, "wrong").ListObjectsV2(&s3.ListObjectsV2Input{Bucket: aws.String("small")})
+	requireS3Error(t, err, http.StatusForbidden, "SignatureDoesNotMatch")
+
+	_, err = client(s, "invalid", secretKey).GetBucketLocation(&s3.GetBucketLocationInput{Bucket: aws.String("small")})
+	requireS3Error(t, err, http.StatusForbidden, "InvalidAccessKeyId")
+
+	_, err = client(s, accessKey, secretKey).GetBucketLocation(&s3.GetBucketLocationInput{Bucket: aws.String(ForeignBucket)})
+	requireS3Error(t, err, http.StatusForbidden, "AccessDenied")
+
+	resp, err := http.Get(s.URL + "/small/index.html")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	req, _ := client(s, accessKey, secretKey).GetObjectRequest(&s3.GetObjectInput{Bucket: aws.String("small"), Key: aws.String("index.html")})
+	presigned, err := req.Presign(time.Minute)
+	require.NoError(t, err)
+	resp, err = http.Get(presigned)
+	require.NoError(t, err)
+	content, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Contains(t, string(content), "system tests")
+
+	resp, err = http.Get(presigned + "&tampered=true")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func TestParseRange(t *testing.T) {
+	for header, want := range map[string][3]int{
+		"bytes=0-4":   {0, 4, 1},
+		"bytes=5-":    {5, 9, 1},
+		"bytes=-3":    {7, 9, 1},
+		"bytes=8-100": {8, 9, 1},
+		"bytes=10-":   {0, 0, 0},
+		"bytes=4-2":   {0, 0, 0},
+		"bytes=0-1,3": {0, 0, 0},
+		"items=0-1":   {0, 0, 0},
+	} {
+		start, end, ok := parseRange(header, 10)
+		if want[2] == 0 {
+			require.False(t, ok, header)
+			continue
+		}
+		require.True(t, ok, header)
+		require.Equal(t, [2]int{want[0], want[1]}, [2]int{start, end}, header)
+	}
+}
+
+func TestHonoursEndpointEnv(t *testing.T) {
+	for version, want := range map[string]bool{
+		"v1.25.0":                            true,
+		"v1.25.1":                            true,
+		"v1.27.11":                           true,
+		"v2.0.0":                             true,
+		"v1.24.9":                            false,
+		"v1.18.45":                           false,
+		"v1.25.0-rc.1":                       true,
+		"v0.0.0-20231101000000-0123456789ab": false,
+		"(devel)":                            false,
+	} {
+		info := &debug.BuildInfo{Deps: []*debug.Module{
+			{Path: "github.com/aws/aws-sdk-go-v2", Version: "v1.30.0"},
+			{Path: EndpointModule, Version: version},
+		}}
+		require.Equal(t, want, honoursEndpointEnv(info), version)
+	}
+
+	replaced := &debug.BuildInfo{Deps: []*debug.Module{
+		{Path: EndpointModule, Version: "v1.18.0", Replace: &debug.Module{Path: EndpointModule, Version: "v1.26.0"}},
+	}}
+	require.True(t, honoursEndpointEnv(replaced))
+	require.False(t, honoursEndpointEnv(&debug.BuildInfo{}), "a binary without aws-sdk-go-v2 config")
+
+	_, err := HonoursEndpointEnv("./does-not-exist")
+	require.Error(t, err)
+}
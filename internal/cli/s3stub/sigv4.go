@@ -0,0 +1,218 @@
+package s3stub
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	algorithm       = "AWS4-HMAC-SHA256"
+	amzDateFormat   = "20060102T150405Z"
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+	maxClockSkew    = 15 * time.Minute
+)
+
+// signature is the SigV4 signature of a request, from its Authorization header or its presigned query
+type signature struct {
+	accessKey     string
+	scope         string
+	region        string
+	service       string
+	signedHeaders []string
+	value         string
+	date          time.Time
+	expires       time.Duration
+	presigned     bool
+}
+
+// verify checks the SigV4 signature of the request against the credentials of the server and returns the body the
+// request was signed with
+func (s *Server) verify(r *http.Request) ([]byte, *apiError) {
+	sig, aerr := parseSignature(r)
+	if aerr != nil {
+		return nil, aerr
+	}
+	if sig.accessKey != s.AccessKey {
+		return nil, errorf(http.StatusForbidden, "InvalidAccessKeyId", "The AWS Access Key Id you provided does not exist in our records.")
+	}
+
+	now := time.Now().UTC()
+	if sig.presigned && now.After(sig.date.Add(sig.expires)) {
+		return nil, errorf(http.StatusForbidden, "AccessDenied", "Request has expired")
+	}
+	if !sig.presigned && (now.Sub(sig.date) > maxClockSkew || sig.date.Sub(now) > maxClockSkew) {
+		return nil, errorf(http.StatusForbidden, "RequestTimeTooSkewed", "The difference between the request time and the current time is too large.")
+	}
+
+	payloadHash := r.Header.Get("X-Amz-Content-Sha256")
+	switch {
+	case payloadHash == "" && sig.presigned:
+		payloadHash = unsignedPayload
+	case payloadHash == "":
+		return nil, errorf(http.StatusBadRequest, "InvalidRequest", "Missing required header for this request: x-amz-content-sha256")
+	}
+
+	stringToSign := strings.Join([]string{
+		algorithm,
+		sig.date.Format(amzDateFormat),
+		sig.scope,
+		hashHex([]byte(canonicalRequest(r, sig, payloadHash))),
+	}, "\n")
+	expected := hex.EncodeToString(hmacSHA256(signingKey(s.SecretKey, sig), stringToSign))
+	if !hmac.Equal([]byte(expected), []byte(sig.value)) {
+		return nil, errorf(http.StatusForbidden, "SignatureDoesNotMatch", "The request signature we calculated does not match the signature you provided. Check your key and signing method.")
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, errorf(http.StatusBadRequest, "IncompleteBody", "reading body: %v", err)
+	}
+	switch {
+	case payloadHash == unsignedPayload:
+	case strings.HasPrefix(payloadHash, "STREAMING-"):
+		return nil, errorf(http.StatusNotImplemented, "NotImplemented", "Chunked payload %s is not supported by the stand-in", payloadHash)
+	case payloadHash != hashHex(body):
+		return nil, errorf(http.StatusBadRequest, "XAmzContentSHA256Mismatch", "The provided 'x-amz-content-sha256' header does not match what was computed.")
+	}
+	return body, nil
+}
+
+func parseSignature(r *http.Request) (*signature, *apiError) {
+	var (
+		sig    = &signature{}
+		params = map[string]string{}
+		date   string
+	)
+	query := r.URL.Query()
+	switch auth := r.Header.Get("Authorization"); {
+	case query.Get("X-Amz-Algorithm") != "":
+		if query.Get("X-Amz-Algorithm") != algorithm {
+			return nil, errorf(http.StatusBadRequest, "AuthorizationQueryParametersError", "X-Amz-Algorithm only supports \"%s\"", algorithm)
+		}
+		sig.presigned = true
+		params["Credential"] = query.Get("X-Amz-Credential")
+		params["SignedHeaders"] = query.Get("X-Amz-SignedHeaders")
+		params["Signature"] = query.Get("X-Amz-Signature")
+		date = query.Get("X-Amz-Date")
+		seconds, err := strconv.Atoi(query.Get("X-Amz-Expires"))
+		if err != nil || seconds < 0 {
+			return nil, errorf(http.StatusBadRequest, "AuthorizationQueryParametersError", "X-Amz-Expires should be a number")
+		}
+		sig.expires = time.Duration(seconds) * time.Second
+	case strings.HasPrefix(auth, algorithm+" "):
+		for _, param := range strings.Split(strings.TrimPrefix(auth, algorithm+" "), ",") {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			params[name] = value
+		}
+		date = r.Header.Get("X-Amz-Date")
+	case auth != "":
+		return nil, errorf(http.StatusBadRequest, "InvalidArgument", "Unsupported Authorization Type")
+	default:
+		return nil, errorf(http.StatusForbidden, "AccessDenied", "Anonymous access is denied by the stand-in")
+	}
+
+	credential := strings.Split(params["Credential"], "/")
+	if len(credential) != 5 || credential[4] != "aws4_request" || params["SignedHeaders"] == "" || params["Signature"] == "" {
+		return nil, errorf(http.StatusBadRequest, "AuthorizationHeaderMalformed", "The authorization header is malformed; the Credential, SignedHeaders and Signature are required.")
+	}
+	sig.accessKey, sig.region, sig.service = credential[0], credential[2], credential[3]
+	sig.scope = strings.Join(credential[1:], "/")
+	sig.signedHeaders = strings.Split(params["SignedHeaders"], ";")
+	sig.value = params["Signature"]
+
+	var err error
+	if sig.date, err = time.Parse(amzDateFormat, date); err != nil || !strings.HasPrefix(date, credential[1]) {
+		return nil, errorf(http.StatusBadRequest, "AuthorizationHeaderMalformed", "The authorization header is malformed; the date %q does not match the credential scope %s.", date, credential[1])
+	}
+	return sig, nil
+}
+
+func canonicalRequest(r *http.Request, sig *signature, payloadHash string) string {
+	query := r.URL.Query()
+	if sig.presigned {
+		query.Del("X-Amz-Signature")
+	}
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var params []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			params = append(params, escape(key, true)+"="+escape(value, true))
+		}
+	}
+
+	var headers strings.Builder
+	for _, name := range sig.signedHeaders {
+		var value string
+		switch name {
+		case "host":
+			value = r.Host
+		case "content-length":
+			value = strconv.FormatInt(r.ContentLength, 10)
+		default:
+			value = strings.Join(strings.Fields(strings.Join(r.Header.Values(name), ",")), " ")
+		}
+		fmt.Fprintf(&headers, "%s:%s\n", name, value)
+	}
+
+	path := r.URL.Path
+	if path == "" {
+		path = "/"
+	}
+	return strings.Join([]string{
+		r.Method,
+		escape(path, false),
+		strings.Join(params, "&"),
+		headers.String(),
+		strings.Join(sig.signedHeaders, ";"),
+		payloadHash,
+	}, "\n")
+}
+
+func signingKey(secretKey string, sig *signature) []byte {
+	key := hmacSHA256([]byte("AWS4"+secretKey), sig.date.Format("20060102"))
+	key = hmacSHA256(key, sig.region)
+	key = hmacSHA256(key, sig.service)
+	return hmacSHA256(key, "aws4_request")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// escape URI-encodes s the way SigV4 does, keeping only the unreserved characters and, in paths, the slashes
+func escape(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,61 @@
+package stub
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+// Package is the import path of the clistub command
+const Package = "github.com/0chain/system_test/cmd/clistub"
+
+var (
+	buildOnce sync.Once
+	binDir    string
+	buildErr  error
+)
+
+// Build builds the clistub command as zbox and zwallet once per test binary and returns their directory
+func Build(t testing.TB) string {
+	t.Helper()
+	buildOnce.Do(func() {
+		binDir, buildErr = build()
+	})
+	if buildErr != nil {
+		t.Fatalf("building %s: %v", Package, buildErr)
+	}
+	return binDir
+}
+
+func build() (string, error) {
+	dir, err := os.MkdirTemp("", "clistub")
+	if err != nil {
+		return "", err
+	}
+	for _, program := range []string{"zbox", "zwallet"} {
+		if runtime.GOOS == "windows" {
+			program += ".exe"
+		}
+		output, err := exec.Command("go", "build", "-o", filepath.Join(dir, program), Package).CombinedOutput()
+		if err != nil {
+			return "", fmt.Errorf("%w: %s", err, output)
+		}
+	}
+	return dir, nil
+}
+
+// Use writes the fixtures for the test, points the stub binaries at them and returns the directory of the binaries
+func Use(t *testing.T, fixtures ...Fixture) string {
+	t.Helper()
+	dir := Build(t)
+	path := filepath.Join(t.TempDir(), "fixtures.yaml")
+	if err := Save(path, fixtures); err != nil {
+		t.Fatalf("writing fixtures: %v", err)
+	}
+	t.Setenv(FixturesEnv, path)
+	return dir
+}
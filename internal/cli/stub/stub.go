@@ -0,0 +1,154 @@
+// Package stub impersonates zbox and zwallet from scripted fixtures, so that the harness can be tested without a network
+package stub
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3" //nolint
+)
+
+// FixturesEnv is the path of the YAML fixtures file the stub binaries answer from
+const FixturesEnv = "CLI_STUB_FIXTURES"
+
+// NoFixtureExitCode is the exit code of invocations no fixture matches
+const NoFixtureExitCode = 127
+
+// Fixture is the canned answer to the invocations it matches
+type Fixture struct {
+	// Program is the binary the fixture applies to, zbox or zwallet, any if empty
+	Program string `yaml:"program,omitempty"`
+	// Args must start the arguments of the invocation, "*" matches any argument
+	Args []string `yaml:"args,omitempty"`
+	// Match is a regular expression the arguments joined by spaces must match
+	Match string `yaml:"match,omitempty"`
+
+	Stdout string        `yaml:"stdout,omitempty"`
+	Stderr string        `yaml:"stderr,omitempty"`
+	Exit   int           `yaml:"exit,omitempty"`
+	Delay  time.Duration `yaml:"delay,omitempty"`
+	// Times limits the invocations the fixture answers, later fixtures answer the next ones. Zero is unlimited.
+	Times int `yaml:"times,omitempty"`
+}
+
+// Load reads the fixtures file
+func Load(path string) ([]Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var fixtures []Fixture
+	if err := yaml.Unmarshal(data, &fixtures); err != nil {
+		return nil, fmt.Errorf("reading fixtures %s: %w", path, err)
+	}
+	for i := range fixtures {
+		if _, err := regexp.Compile(fixtures[i].Match); err != nil {
+			return nil, fmt.Errorf("fixture %d: %w", i, err)
+		}
+	}
+	return fixtures, nil
+}
+
+// Save writes the fixtures file
+func Save(path string, fixtures []Fixture) error {
+	data, err := yaml.Marshal(fixtures)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644) //nolint:gosec
+}
+
+// Matches reports whether the fixture applies to the invocation of program with args
+func (f *Fixture) Matches(program string, args []string) bool {
+	if f.Program != "" && f.Program != program {
+		return false
+	}
+	if len(args) < len(f.Args) {
+		return false
+	}
+	for i, arg := range f.Args {
+		if arg != "*" && arg != args[i] {
+			return false
+		}
+	}
+	return f.Match == "" || regexp.MustCompile(f.Match).MatchString(strings.Join(args, " "))
+}
+
+// Run answers the invocation of program with args from the fixtures file and returns the exit code.
+// The number of invocations answered by each fixture is kept in a file next to the fixtures, so invocations are
+// expected to be sequential.
+func Run(fixturesPath, program string, args []string, stdout, stderr io.Writer) int {
+	fixtures, err := Load(fixturesPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "clistub: %v\n", err)
+		return NoFixtureExitCode
+	}
+
+	callsPath := fixturesPath + ".calls"
+	calls, err := readCalls(callsPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "clistub: %v\n", err)
+		return NoFixtureExitCode
+	}
+
+	for i := range fixtures {
+		fixture := &fixtures[i]
+		if !fixture.Matches(program, args) || (fixture.Times > 0 && calls[i] >= fixture.Times) {
+			continue
+		}
+		calls[i]++
+		if err := writeCalls(callsPath, calls); err != nil {
+			fmt.Fprintf(stderr, "clistub: %v\n", err)
+			return NoFixtureExitCode
+		}
+
+		time.Sleep(fixture.Delay)
+		_, _ = io.WriteString(stdout, fixture.Stdout)
+		_, _ = io.WriteString(stderr, fixture.Stderr)
+		return fixture.Exit
+	}
+
+	fmt.Fprintf(stderr, "clistub: no fixture in %s matches %s %s\n", fixturesPath, program, strings.Join(args, " "))
+	return NoFixtureExitCode
+}
+
+// Calls returns the number of invocations answered by each fixture, by index
+func Calls(fixturesPath string) (map[int]int, error) {
+	return readCalls(fixturesPath + ".calls")
+}
+
+func readCalls(path string) (map[int]int, error) {
+	calls := make(map[int]int)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return calls, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return calls, json.Unmarshal(data, &calls)
+}
+
+func writeCalls(path string, calls map[int]int) error {
+	data, err := json.Marshal(calls)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil { //nolint:gosec
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// ProgramName returns the program a stub binary impersonates, from the name it was run as
+func ProgramName(arg0 string) string {
+	return strings.TrimSuffix(filepath.Base(arg0), ".exe")
+}
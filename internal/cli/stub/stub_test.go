@@ -0,0 +1,49 @@
+package stub
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixtureMatches(t *testing.T) {
+	fixture := Fixture{Program: "zbox", Args: []string{"upload", "--allocation", "*"}, Match: `--remotepath /dir/`}
+
+	require.True(t, fixture.Matches("zbox", []string{"upload", "--allocation", "a1", "--remotepath", "/dir/f.txt"}))
+	require.False(t, fixture.Matches("zwallet", []string{"upload", "--allocation", "a1", "--remotepath", "/dir/f.txt"}))
+	require.False(t, fixture.Matches("zbox", []string{"upload", "--allocation"}))
+	require.False(t, fixture.Matches("zbox", []string{"upload", "--allocation", "a1", "--remotepath", "/f.txt"}))
+	require.True(t, (&Fixture{}).Matches("zwallet", nil))
+}
+
+func TestRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixtures.yaml")
+	require.NoError(t, Save(path, []Fixture{
+		{Program: "zbox", Args: []string{"newallocation"}, Stderr: "Error: not enough tokens\n", Exit: 1, Times: 1},
+		{Program: "zbox", Args: []string{"newallocation"}, Stdout: "Allocation created: a1\n", Delay: 10 * time.Millisecond},
+	}))
+
+	fixtures, err := Load(path)
+	require.NoError(t, err)
+	require.Equal(t, 10*time.Millisecond, fixtures[1].Delay)
+
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, 1, Run(path, "zbox", []string{"newallocation", "--lock", "1"}, &stdout, &stderr))
+	require.Equal(t, "Error: not enough tokens\n", stderr.String())
+
+	stdout.Reset()
+	stderr.Reset()
+	require.Equal(t, 0, Run(path, "zbox", []string{"newallocation", "--lock", "1"}, &stdout, &stderr))
+	require.Equal(t, "Allocation created: a1\n", stdout.String())
+	require.Empty(t, stderr.String())
+
+	require.Equal(t, NoFixtureExitCode, Run(path, "zwallet", []string{"faucet"}, &stdout, &stderr))
+	require.Contains(t, stderr.String(), "no fixture")
+
+	calls, err := Calls(path)
+	require.NoError(t, err)
+	require.Equal(t, map[int]int{0: 1, 1: 1}, calls)
+}
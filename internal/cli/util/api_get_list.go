@@ -5,8 +5,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"strconv"
 
+	"github.com/0chain/system_test/internal/api/util/paginate"
 	"github.com/0chain/system_test/internal/api/util/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -37,8 +37,8 @@ func ApiGetError[T any](url string, params map[string]string) (*T, error) {
 	}
 
 	defer res.Body.Close()
-	if res.StatusCode < 200 && res.StatusCode >= 300 {
-		return nil, fmt.Errorf("failed API request %s, status code: %d: %v", url, res.StatusCode, err)
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return nil, fmt.Errorf("failed API request %s, status code: %d", url, res.StatusCode)
 	}
 	if res.Body == nil {
 		return nil, fmt.Errorf("request %s, API response must not be nil", url)
@@ -77,44 +77,15 @@ func ApiGet[T any](t *test.SystemTest, url string, params map[string]string) *T
 }
 
 func ApiGetList[T any](t *test.SystemTest, url string, params map[string]string, from, to int64) []T {
-	var out []T
-	var offset int64
-	for {
-		var temp []T
-		raw := getNext(t, url, from, to, MaxQueryLimit, offset, params)
-
-		err := json.Unmarshal(raw, &temp)
-		assert.NoError(t, err, "deserializing JSON string `%s`: %v", string(raw), err)
-		out = append(out, temp...)
-		if len(temp) < MaxQueryLimit {
-			return out
-		}
-
-		offset += int64(len(temp))
-	}
+	out, err := ApiGetListError[T](url, params, from, to)
+	require.NoError(t, err, "retrieving %s from %d to %d", url, from, to)
+	return out
 }
 
-func getNext(t *test.SystemTest, url string, from, to, limit, offset int64, params map[string]string) []byte {
-	params["start"] = strconv.FormatInt(from, 10)
-	params["end"] = strconv.FormatInt(to, 10)
-	if limit > 0 {
-		params["limit"] = strconv.FormatInt(limit, 10)
-	}
-	if offset > 0 {
-		params["offset"] = strconv.FormatInt(offset, 10)
-	}
-	url = addParms(url, params)
-	res, err := http.Get(url) //nolint:gosec
-
-	require.NoError(t, err, "retrieving blocks %d to %d", from, to)
-	defer res.Body.Close()
-	require.True(t, res.StatusCode >= 200 && res.StatusCode < 300,
-		"failed API request to get blocks %d to %d, status code: %d", from, to, res.StatusCode)
-	require.NotNil(t, res.Body, "balance API response must not be nil")
-
-	resBody, err := io.ReadAll(res.Body)
-	require.NoError(t, err, "reading response body: %v", err)
-	return resBody
+// ApiGetListError reads all pages of a list from round from up to, but not including, round to.
+// Use paginate.Rounds directly to process the items without holding all of them in memory.
+func ApiGetListError[T any](url string, params map[string]string, from, to int64) ([]T, error) {
+	return paginate.Collect(paginate.Rounds[T](url, params, from, to).WithLimit(MaxQueryLimit))
 }
 
 func addParms(url string, params map[string]string) string {
@@ -0,0 +1,120 @@
+package cliutils
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0chain/system_test/internal/api/util/test"
+)
+
+const (
+	// probeTimeout is the deadline of each command run to probe a binary
+	probeTimeout = 30 * time.Second
+	// probeConcurrency is the number of help commands run at once
+	probeConcurrency = 8
+	// maxProbeDepth limits the nesting of subcommands that are probed
+	maxProbeDepth = 3
+)
+
+var (
+	helpSection = regexp.MustCompile(`^(Available Commands|Flags|Global Flags):\s*$`)
+	helpCommand = regexp.MustCompile(`^\s+([a-zA-Z][\w-]*)\s`)
+	helpFlag    = regexp.MustCompile(`^\s+(?:-\w, )?(--[\w-]+)`)
+)
+
+// ProbeCapabilities runs the version command and walks the --help tree of the binaries, e.g. ./zbox and ./zwallet,
+// to build their capabilities. A binary that cannot be run has no capabilities and no version.
+func ProbeCapabilities(binaries ...string) (*test.Capabilities, error) {
+	capabilities := &test.Capabilities{Versions: make(map[string]string)}
+	var errs []string
+	for _, binary := range binaries {
+		name := filepath.Base(binary)
+		output, err := executeCommand(nil, probeTimeout, binary, []string{"version"})
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s version: %v", name, err))
+			continue
+		}
+		capabilities.Versions[name] = strings.Join(sanitizeOutput(output), "; ")
+		capabilities.Features = append(capabilities.Features, probeHelp(binary, name)...)
+	}
+	if len(errs) > 0 {
+		return capabilities, fmt.Errorf("probing binaries: %s", strings.Join(errs, ", "))
+	}
+	return capabilities, nil
+}
+
+// probeHelp returns the subcommands of the binary and their flags, walking the help tree breadth first
+func probeHelp(binary, name string) []string {
+	features := []string{name}
+	level := [][]string{nil}
+	for depth := 0; depth < maxProbeDepth && len(level) > 0; depth++ {
+		helps := make([]commandHelp, len(level))
+		var wg sync.WaitGroup
+		semaphore := make(chan struct{}, probeConcurrency)
+		for i, path := range level {
+			wg.Add(1)
+			go func(i int, path []string) {
+				defer wg.Done()
+				semaphore <- struct{}{}
+				defer func() { <-semaphore }()
+				output, _ := executeCommand(nil, probeTimeout, binary, append(append([]string{}, path...), "--help"))
+				helps[i] = parseHelp(string(output))
+			}(i, path)
+		}
+		wg.Wait()
+
+		var next [][]string
+		for i, path := range level {
+			command := strings.Join(append([]string{name}, path...), " ")
+			for _, flag := range helps[i].flags {
+				features = append(features, command+" "+flag)
+			}
+			for _, subcommand := range helps[i].commands {
+				if subcommand == "help" || subcommand == "completion" {
+					continue
+				}
+				subpath := append(append([]string{}, path...), subcommand)
+				features = append(features, command+" "+subcommand)
+				next = append(next, subpath)
+			}
+		}
+		level = next
+	}
+	return features
+}
+
+// commandHelp is the parsed --help output of a cobra command
+type commandHelp struct {
+	commands []string
+	flags    []string
+}
+
+func parseHelp(output string) commandHelp {
+	var help commandHelp
+	section := ""
+	for _, line := range strings.Split(output, "\n") {
+		if match := helpSection.FindStringSubmatch(line); match != nil {
+			section = match[1]
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			section = ""
+			continue
+		}
+		switch section {
+		case "Available Commands":
+			if match := helpCommand.FindStringSubmatch(line); match != nil {
+				help.commands = append(help.commands, match[1])
+			}
+		case "Flags", "Global Flags":
+			if match := helpFlag.FindStringSubmatch(line); match != nil {
+				help.flags = append(help.flags, match[1])
+			}
+		}
+	}
+	return help
+}
@@ -0,0 +1,44 @@
+package cliutils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const fakeZbox = `#!/bin/sh
+case "$*" in
+version)
+	echo "zbox version v1.2.3"
+	;;
+--help)
+	printf 'Usage:\n  zbox [command]\n\nAvailable Commands:\n  upload      Upload a file\n  help        Help about any command\n\nFlags:\n      --config string   config file\n  -h, --help            help for zbox\n'
+	;;
+"upload --help")
+	printf 'Usage:\n  zbox upload [flags]\n\nFlags:\n  -a, --allocation string   allocation ID\n      --web-streaming       transcode\n\nGlobal Flags:\n      --config string   config file\n'
+	;;
+esac
+`
+
+func TestProbeCapabilities(t *testing.T) {
+	zbox := filepath.Join(t.TempDir(), "zbox")
+	require.NoError(t, os.WriteFile(zbox, []byte(fakeZbox), 0755)) //nolint:gosec
+
+	capabilities, err := ProbeCapabilities(zbox, filepath.Join(t.TempDir(), "zwallet"))
+	require.ErrorContains(t, err, "zwallet version")
+	require.Equal(t, map[string]string{"zbox": "zbox version v1.2.3"}, capabilities.Versions)
+
+	require.True(t, capabilities.Has("zbox upload"))
+	require.True(t, capabilities.Has("zbox upload --web-streaming --allocation"))
+	require.True(t, capabilities.Has("zbox upload --config"))
+	require.False(t, capabilities.Has("zbox upload --chunknumber"))
+	require.False(t, capabilities.Has("zbox rollback"))
+	require.False(t, capabilities.Has("zbox help"))
+	require.False(t, capabilities.Has("zwallet bridge-mint-zcn"))
+
+	path, err := capabilities.WriteReport(t.TempDir())
+	require.NoError(t, err)
+	require.FileExists(t, path)
+}
@@ -0,0 +1,185 @@
+package cliutils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/0chain/system_test/internal/api/util/test"
+)
+
+// ErrorClass tells whether a failed command may be run again
+type ErrorClass int
+
+const (
+	// Retryable failures are transient, such as network errors or consensus not being reached
+	Retryable ErrorClass = iota
+	// Permanent failures fail again on retry, such as an insufficient balance or a missing flag
+	Permanent
+	// Uncertain failures may have taken effect, such as a transaction sent but not verified, retrying may duplicate it
+	Uncertain
+)
+
+func (c ErrorClass) String() string {
+	switch c {
+	case Retryable:
+		return "retryable"
+	case Permanent:
+		return "permanent"
+	case Uncertain:
+		return "side-effect-uncertain"
+	}
+	return fmt.Sprintf("ErrorClass(%d)", int(c))
+}
+
+// ErrorRule classifies the failures whose output matches Pattern
+type ErrorRule struct {
+	Pattern *regexp.Regexp
+	Class   ErrorClass
+}
+
+// ErrorRules classify failures of zbox and zwallet by their output, the first matching rule wins.
+// Failures no rule matches are retryable, or uncertain for subcommands that may change the chain, see classifyCommand.
+var ErrorRules = []ErrorRule{
+	// a transaction was sent, whether it was applied is unknown
+	{regexp.MustCompile(`(?i)transaction_not_found|transaction (was )?not found|transaction verification (failed|timed out|timeout)|verify_transaction|not (yet )?confirmed`), Uncertain},
+	{regexp.MustCompile(`(?i)consensus_not_met|consensus not reached|consensus_failed|connection (refused|reset)|i/o timeout|deadline exceeded|too many requests|bad gateway|service unavailable|gateway timeout|\bnonce\b|unexpected EOF`), Retryable},
+	{regexp.MustCompile(`(?i)insufficient balance|not enough (tokens|balance)|greater than balance|record not found|not found|already exists|is already|flag is missing|required flag|unknown (flag|command|shorthand)|invalid argument|invalid mnemonic|mnemonic not provided|not allowed|not permitted|permission denied|no such file or directory|not enough blobbers|changes nothing`), Permanent},
+}
+
+// readOnlyCommand matches the subcommands that do not change the chain, which are safe to retry whatever happened
+var readOnlyCommand = regexp.MustCompile(`^(get[a-z-]*|list[a-z-]*|ls-[a-z-]+|[a-z]+-info|version|meta|stats|verify|download|recent-refs|sc-config|mn-config|global-config|bridge-config|bridge-verify|bridge-list-[a-z-]+|bridge-get-[a-z-]+)$`)
+
+// transactionHash finds the hash of the transaction a command sent in its output
+var transactionHash = regexp.MustCompile(`(?i)(?:txn|transaction|hash)[^0-9a-f]{0,20}([0-9a-f]{64})\b`)
+
+// TransactionVerifier reports whether a transaction sent no later than sentAt was applied on the chain. It is set by
+// the suites, for RunCommand to check the chain before retrying an uncertain failure. Uncertain failures are not
+// retried without it.
+var TransactionVerifier func(t *test.SystemTest, hash string, sentAt time.Time) (applied bool, err error)
+
+// TransactionExpiry is how long after it was sent a transaction may still be applied. The miners reject older
+// transactions, so one the sharders do not know by then never will be.
+var TransactionExpiry = 2 * time.Minute
+
+var (
+	// ErrTransactionPending is returned by ConfirmTransaction for a transaction the sharders do not know yet,
+	// which may still be applied
+	ErrTransactionPending = errors.New("transaction not confirmed and not expired yet")
+	// ErrAppliedAfterUncertainFailure is returned by RunCommand for a command that failed although the chain shows
+	// its transaction was applied, so the output of the command may be incomplete
+	ErrAppliedAfterUncertainFailure = errors.New("transaction applied after the command failed")
+)
+
+// Classify returns the class of a failed command from its error and output
+func Classify(output []string, err error) ErrorClass {
+	class, _ := classify(output, err)
+	return class
+}
+
+// classify is Classify, also reporting whether the error or a rule matched. Unmatched failures are retryable.
+func classify(output []string, err error) (class ErrorClass, matched bool) {
+	if errors.Is(err, ErrCommandTimeout) {
+		return Uncertain, true
+	}
+	if errors.Is(err, exec.ErrNotFound) || errors.Is(err, fs.ErrNotExist) {
+		return Permanent, true
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && (exitErr.ExitCode() == 126 || exitErr.ExitCode() == 127) {
+		// not executable or not found by the shell
+		return Permanent, true
+	}
+
+	text := strings.Join(output, "\n")
+	for _, rule := range ErrorRules {
+		if rule.Pattern.MatchString(text) {
+			return rule.Class, true
+		}
+	}
+	return Retryable, false
+}
+
+// classifyCommand is Classify for a subcommand. Uncertain failures of read-only subcommands are retryable, and
+// unmatched failures of the other subcommands are uncertain, since they may have sent a transaction.
+func classifyCommand(subcommand string, output []string, err error) ErrorClass {
+	class, matched := classify(output, err)
+	readOnly := readOnlyCommand.MatchString(subcommand)
+	switch {
+	case class == Uncertain && readOnly:
+		return Retryable
+	case !matched && !readOnly:
+		return Uncertain
+	}
+	return class
+}
+
+// resolveUncertain checks on the chain whether the transaction of an uncertain failure, sent no later than sentAt,
+// was applied. A transaction the sharders do not know yet is checked again once it expired. A failure is retried
+// only if its transaction is known not to have been applied.
+func resolveUncertain(t *test.SystemTest, output []string, sentAt time.Time) (hash string, applied, again bool) {
+	match := transactionHash.FindAllStringSubmatch(strings.Join(output, "\n"), -1)
+	if match == nil || TransactionVerifier == nil {
+		t.Logf("Cannot tell whether the command took effect, not retrying")
+		return "", false, false
+	}
+	hash = match[len(match)-1][1]
+	applied, err := TransactionVerifier(t, hash, sentAt)
+	if errors.Is(err, ErrTransactionPending) {
+		wait := time.Until(sentAt.Add(TransactionExpiry))
+		t.Logf("Transaction [%s] is not confirmed yet, checking again once it expired in %v", hash, wait)
+		time.Sleep(wait)
+		applied, err = TransactionVerifier(t, hash, sentAt)
+	}
+	if err != nil {
+		t.Logf("Cannot verify transaction [%s], not retrying: %v", hash, err)
+		return hash, false, false
+	}
+	if applied {
+		t.Logf("Transaction [%s] was applied, not retrying", hash)
+		return hash, true, false
+	}
+	t.Logf("Transaction [%s] was not applied", hash)
+	return hash, false, true
+}
+
+// ConfirmTransaction reports whether a transaction sent no later than sentAt was applied successfully according to
+// the sharder. A transaction the sharder does not know was not applied once it expired, see TransactionExpiry,
+// before that ErrTransactionPending is returned.
+func ConfirmTransaction(sharderBaseURL, hash string, sentAt time.Time) (bool, error) {
+	client := http.Client{Timeout: 30 * time.Second}
+	res, err := client.Get(addParms(sharderBaseURL+"/v1/transaction/get/confirmation", map[string]string{"hash": hash}))
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		if age := time.Since(sentAt); age < TransactionExpiry {
+			return false, fmt.Errorf("%w: %s sent %v ago", ErrTransactionPending, hash, age.Round(time.Second))
+		}
+		return false, nil
+	}
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return false, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("confirming transaction %s: status %d: %s", hash, res.StatusCode, body)
+	}
+
+	var confirmation struct {
+		Status int `json:"transaction_status"`
+	}
+	if err := json.Unmarshal(body, &confirmation); err != nil {
+		return false, fmt.Errorf("confirming transaction %s: %w", hash, err)
+	}
+	return confirmation.Status == txSuccessful, nil
+}
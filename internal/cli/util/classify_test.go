@@ -0,0 +1,136 @@
+package cliutils
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0chain/system_test/internal/api/util/test"
+	"github.com/0chain/system_test/internal/cli/stub"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassify(t *testing.T) {
+	failed := errors.New("exit status 1")
+	tests := []struct {
+		output   string
+		err      error
+		expected ErrorClass
+	}{
+		{"Error: consensus_not_met", failed, Retryable},
+		{"Post http://198.18.0.98:7171/v1/transaction/put: connection refused", failed, Retryable},
+		{"Error: lock amount is greater than balance", failed, Permanent},
+		{"insufficient balance to send", failed, Permanent},
+		{"error: can't get allocation: error retrieving allocation: ab12, error: record not found", failed, Permanent},
+		{"Error: allocation flag is missing", failed, Permanent},
+		{"Failed to lock tokens in stake pool: transaction_not_found: Transaction was not found on any of the sharders", failed, Uncertain},
+		{"", fmt.Errorf("%w after 10m0s: signal: quit", ErrCommandTimeout), Uncertain},
+		{"", fmt.Errorf("exec: %q: %w", "./zbox", os.ErrNotExist), Permanent},
+		{"panic: something unexpected", failed, Retryable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			require.Equal(t, tt.expected, Classify(strings.Split(tt.output, "\n"), tt.err))
+		})
+	}
+
+	require.Equal(t, Retryable, classifyCommand("getallocation", nil, ErrCommandTimeout))
+	require.Equal(t, Uncertain, classifyCommand("sp-lock", nil, ErrCommandTimeout))
+
+	// unmatched failures are retryable only for read-only subcommands
+	unexpected := []string{"panic: something unexpected"}
+	require.Equal(t, Retryable, classifyCommand("getallocation", unexpected, failed))
+	require.Equal(t, Uncertain, classifyCommand("sp-lock", unexpected, failed))
+	require.Equal(t, Retryable, classifyCommand("sp-lock", []string{"Error: consensus_not_met"}, failed))
+	require.Equal(t, Permanent, classifyCommand("sp-lock", []string{"insufficient balance to send"}, failed))
+}
+
+func TestRunCommandDoesNotRetryPermanentFailures(t *testing.T) {
+	t.Setenv(test.ArtifactsDirEnv, t.TempDir())
+	dir := stub.Use(t, stub.Fixture{Program: "zwallet", Args: []string{"send"}, Stderr: "insufficient balance to send\n", Exit: 1})
+
+	output, err := RunCommand(test.NewSystemTest(t), filepath.Join(dir, "zwallet")+" send --tokens 100 --silent", 3, time.Millisecond)
+	require.Error(t, err)
+	require.Equal(t, []string{"insufficient balance to send"}, output)
+
+	calls, err := stub.Calls(os.Getenv(stub.FixturesEnv))
+	require.NoError(t, err)
+	require.Equal(t, map[int]int{0: 1}, calls, "a permanent failure is run once")
+}
+
+func TestRunCommandChecksChainOnUncertainFailures(t *testing.T) {
+	t.Setenv(test.ArtifactsDirEnv, t.TempDir())
+	applied := strings.Repeat("a", 64)
+	notApplied := strings.Repeat("b", 64)
+	defer func(verifier func(*test.SystemTest, string, time.Time) (bool, error)) { TransactionVerifier = verifier }(TransactionVerifier)
+	var checks int
+	TransactionVerifier = func(_ *test.SystemTest, hash string, _ time.Time) (bool, error) {
+		checks++
+		if checks == 1 {
+			return false, ErrTransactionPending
+		}
+		return hash == applied, nil
+	}
+	defer func(expiry time.Duration) { TransactionExpiry = expiry }(TransactionExpiry)
+	TransactionExpiry = time.Millisecond
+
+	dir := stub.Use(t,
+		stub.Fixture{Program: "zbox", Args: []string{"sp-lock"}, Stderr: "Failed to lock tokens. txn: " + notApplied + " transaction_not_found\n", Exit: 1, Times: 1},
+		stub.Fixture{Program: "zbox", Args: []string{"sp-lock"}, Stderr: "Failed to lock tokens. txn: " + applied + " transaction_not_found\n", Exit: 1},
+	)
+
+	output, err := RunCommand(test.NewSystemTest(t), filepath.Join(dir, "zbox")+" sp-lock --tokens 1 --silent", 3, time.Millisecond)
+	require.ErrorIs(t, err, ErrAppliedAfterUncertainFailure, "the second attempt was applied")
+	require.ErrorContains(t, err, applied)
+	require.Len(t, output, 1)
+	require.Equal(t, 3, checks, "the pending transaction is checked again once it expired")
+
+	calls, err := stub.Calls(os.Getenv(stub.FixturesEnv))
+	require.NoError(t, err)
+	require.Equal(t, map[int]int{0: 1, 1: 1}, calls, "retried once, as the first transaction was not applied")
+}
+
+func TestConfirmTransaction(t *testing.T) {
+	confirmed := strings.Repeat("c", 64)
+	failed := strings.Repeat("f", 64)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("hash") {
+		case confirmed:
+			_, _ = w.Write([]byte(`{"transaction_status":1}`))
+		case failed:
+			_, _ = w.Write([]byte(`{"transaction_status":2}`))
+		case "bad":
+			http.Error(w, "invalid hash", http.StatusBadRequest)
+		default:
+			http.Error(w, "entity not found", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	applied, err := ConfirmTransaction(server.URL, confirmed, time.Now())
+	require.NoError(t, err)
+	require.True(t, applied)
+
+	applied, err = ConfirmTransaction(server.URL, failed, time.Now())
+	require.NoError(t, err)
+	require.False(t, applied, "a failed transaction was not applied")
+
+	_, err = ConfirmTransaction(server.URL, strings.Repeat("d", 64), time.Now())
+	require.ErrorIs(t, err, ErrTransactionPending, "an unknown transaction may still be applied before it expired")
+
+	applied, err = ConfirmTransaction(server.URL, strings.Repeat("d", 64), time.Now().Add(-TransactionExpiry))
+	require.NoError(t, err)
+	require.False(t, applied, "an unknown transaction that expired was not applied")
+
+	// only a 404 means the transaction is unknown, any other failure tells nothing about it
+	_, err = ConfirmTransaction(server.URL, "bad", time.Now().Add(-TransactionExpiry))
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrTransactionPending)
+}
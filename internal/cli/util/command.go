@@ -0,0 +1,259 @@
+package cliutils
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0chain/system_test/internal/api/util/redact"
+	"github.com/0chain/system_test/internal/api/util/test"
+)
+
+// Flags are command line flags by name, a nil value is a flag without a value
+type Flags map[string]interface{}
+
+// GlobalFlags are the flags accepted by every zbox and zwallet command
+type GlobalFlags struct {
+	Wallet    string `flag:"wallet"`
+	ConfigDir string `flag:"configDir"`
+	Config    string `flag:"config"`
+	Silent    bool   `flag:"silent"`
+}
+
+// Typed flags cover the subcommands of the helpers creating allocations, uploading files and staking, whose values
+// may contain spaces or quotes. Other helpers still build command lines for RunCommand.
+
+// ZboxUpload are the flags of zbox upload
+type ZboxUpload struct {
+	Allocation    string `flag:"allocation"`
+	LocalPath     string `flag:"localpath"`
+	RemotePath    string `flag:"remotepath"`
+	ThumbnailPath string `flag:"thumbnailpath"`
+	ChunkNumber   int    `flag:"chunknumber"`
+	Encrypt       bool   `flag:"encrypt"`
+	WebStreaming  bool   `flag:"web-streaming"`
+	// Extra holds flags without a field, and values of the wrong type or zero, e.g. for negative tests
+	Extra Flags
+}
+
+// ZboxNewAllocation are the flags of zbox newallocation
+type ZboxNewAllocation struct {
+	Size               int64   `flag:"size"`
+	Lock               float64 `flag:"lock"`
+	Data               int     `flag:"data"`
+	Parity             int     `flag:"parity"`
+	ReadPrice          string  `flag:"read_price"`
+	WritePrice         string  `flag:"write_price"`
+	Owner              string  `flag:"owner"`
+	OwnerPublicKey     string  `flag:"owner_public_key"`
+	FreeStorage        string  `flag:"free_storage"`
+	AllocationFileName string  `flag:"allocationFileName"`
+	Extra              Flags
+}
+
+// ZboxStakeLock are the flags of zbox sp-lock
+type ZboxStakeLock struct {
+	BlobberID   string  `flag:"blobber_id"`
+	ValidatorID string  `flag:"validator_id"`
+	Tokens      float64 `flag:"tokens"`
+	Extra       Flags
+}
+
+// ZboxStakeUnlock are the flags of zbox sp-unlock
+type ZboxStakeUnlock struct {
+	BlobberID   string `flag:"blobber_id"`
+	ValidatorID string `flag:"validator_id"`
+	Extra       Flags
+}
+
+// ZwalletMinerUnlock are the flags of zwallet mn-unlock
+type ZwalletMinerUnlock struct {
+	MinerID   string `flag:"miner_id"`
+	SharderID string `flag:"sharder_id"`
+	Extra     Flags
+}
+
+// ZboxStakePoolInfo are the flags of zbox sp-info
+type ZboxStakePoolInfo struct {
+	BlobberID   string `flag:"blobber_id"`
+	ValidatorID string `flag:"validator_id"`
+	JSON        bool   `flag:"json"`
+	Extra       Flags
+}
+
+// Command is a program and its arguments. It is run without a shell, so arguments may contain spaces and quotes.
+type Command struct {
+	Program string
+	Args    []string
+	// Timeout is the deadline of the command, CommandTimeout if zero
+	Timeout time.Duration
+}
+
+// Zbox builds a zbox command from the flags of the subcommand, a struct with flag tags, and the global flags
+func Zbox(subcommand string, flags interface{}, global GlobalFlags) Command {
+	return Command{Program: "./zbox", Args: append(append([]string{subcommand}, Argv(flags)...), Argv(global)...)}
+}
+
+// Zwallet builds a zwallet command from the flags of the subcommand, a struct with flag tags, and the global flags
+func Zwallet(subcommand string, flags interface{}, global GlobalFlags) Command {
+	return Command{Program: "./zwallet", Args: append(append([]string{subcommand}, Argv(flags)...), Argv(global)...)}
+}
+
+// Run runs the command like RunCommand
+func (c Command) Run(t *test.SystemTest, maxAttempts int, backoff time.Duration) ([]string, error) {
+	var subcommand string
+	if len(c.Args) > 0 {
+		subcommand = c.Args[0]
+	}
+	return retry(t, c.String(), subcommand, maxAttempts, backoff,
+		func() ([]string, error) {
+			return c.RunWithoutRetry(t)
+		})
+}
+
+// RunWithoutRetry runs the command once like RunCommandWithoutRetry
+func (c Command) RunWithoutRetry(t *test.SystemTest) ([]string, error) {
+	rawOutput, err := executeCommand(t, c.timeout(), c.Program, c.Args)
+	Logger.Debugf("Command [%v] exited with error [%v] and output [%v]", c, err, sanitizeOutput(rawOutput))
+	return sanitizeOutput(rawOutput), err
+}
+
+func (c Command) timeout() time.Duration {
+	if c.Timeout > 0 {
+		return c.Timeout
+	}
+	return CommandTimeout
+}
+
+// String quotes the arguments that need it, for logs. Secrets are redacted.
+func (c Command) String() string {
+	words := []string{c.Program}
+	for _, arg := range c.Args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n\"'\\$`") {
+			arg = strconv.Quote(arg)
+		}
+		words = append(words, arg)
+	}
+	return redact.String(strings.Join(words, " "))
+}
+
+// Argv converts the fields of a struct with flag tags to arguments, fields with zero values are left out and
+// a Flags field is appended in the order of its names
+func Argv(flags interface{}) []string {
+	if flags == nil {
+		return nil
+	}
+	value := reflect.Indirect(reflect.ValueOf(flags))
+	if extra, ok := value.Interface().(Flags); ok {
+		return extra.argv()
+	}
+
+	var args []string
+	var extra Flags
+	for i := 0; i < value.NumField(); i++ {
+		field, fieldType := value.Field(i), value.Type().Field(i)
+		if f, ok := field.Interface().(Flags); ok {
+			extra = f
+			continue
+		}
+		name := fieldType.Tag.Get("flag")
+		if name == "" || field.IsZero() {
+			continue
+		}
+		switch {
+		case field.Kind() == reflect.Bool:
+			args = append(args, "--"+name)
+		case isFloat(field.Kind()):
+			args = append(args, "--"+name, strconv.FormatFloat(field.Float(), 'f', -1, 64))
+		default:
+			args = append(args, "--"+name, fmt.Sprint(field.Interface()))
+		}
+	}
+	return append(args, extra.argv()...)
+}
+
+func (f Flags) argv() []string {
+	names := make([]string, 0, len(f))
+	for name := range f {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var args []string
+	for _, name := range names {
+		switch value := f[name].(type) {
+		case nil:
+			args = append(args, "--"+name)
+		case bool:
+			args = append(args, fmt.Sprintf("--%s=%v", name, value))
+		default:
+			args = append(args, "--"+name, fmt.Sprint(value))
+		}
+	}
+	return args
+}
+
+// DecodeFlags sets the fields of dst, a pointer to a struct with flag tags, from flags. Flags without a field,
+// zero values and values that do not fit the field are kept in the Flags field of dst, so that they are
+// still passed to the command as given.
+func DecodeFlags(flags Flags, dst interface{}) {
+	value := reflect.ValueOf(dst).Elem()
+	fields := make(map[string]reflect.Value)
+	var extra reflect.Value
+	for i := 0; i < value.NumField(); i++ {
+		if value.Field(i).Type() == reflect.TypeOf(Flags{}) {
+			extra = value.Field(i)
+			continue
+		}
+		if name := value.Type().Field(i).Tag.Get("flag"); name != "" {
+			fields[name] = value.Field(i)
+		}
+	}
+
+	for name, v := range flags {
+		if field, ok := fields[name]; ok && setFlag(field, v) {
+			continue
+		}
+		if extra.IsNil() {
+			extra.Set(reflect.ValueOf(Flags{}))
+		}
+		extra.SetMapIndex(reflect.ValueOf(name), reflect.ValueOf(&v).Elem())
+	}
+}
+
+// setFlag sets field to v if v is a non zero value of the same kind, numbers are converted if no precision is lost
+func setFlag(field reflect.Value, v interface{}) bool {
+	if v == nil {
+		return false
+	}
+	value := reflect.ValueOf(v)
+	if value.IsZero() {
+		return false
+	}
+
+	switch {
+	case value.Kind() == field.Kind() && value.Type().ConvertibleTo(field.Type()):
+		field.Set(value.Convert(field.Type()))
+	case isInt(value.Kind()) && isFloat(field.Kind()):
+		field.SetFloat(float64(value.Int()))
+	case isFloat(value.Kind()) && isInt(field.Kind()) && value.Float() == math.Trunc(value.Float()):
+		field.SetInt(int64(value.Float()))
+	case isInt(value.Kind()) && isInt(field.Kind()):
+		field.SetInt(value.Int())
+	default:
+		return false
+	}
+	return true
+}
+
+func isInt(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Int64
+}
+
+func isFloat(kind reflect.Kind) bool {
+	return kind == reflect.Float32 || kind == reflect.Float64
+}
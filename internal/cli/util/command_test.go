@@ -0,0 +1,70 @@
+package cliutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestZboxArgv(t *testing.T) {
+	cmd := Zbox("upload", ZboxUpload{
+		Allocation: "alloc",
+		LocalPath:  "/tmp/my file.txt",
+		RemotePath: `/dir/"quoted".txt`,
+		Encrypt:    true,
+		Extra:      Flags{"commit": nil, "web-streaming": false, "chunknumber": 0},
+	}, GlobalFlags{Wallet: "w_wallet.json", ConfigDir: "./config", Config: "zbox_config.yaml", Silent: true})
+
+	require.Equal(t, "./zbox", cmd.Program)
+	require.Equal(t, []string{
+		"upload",
+		"--allocation", "alloc", "--localpath", "/tmp/my file.txt", "--remotepath", `/dir/"quoted".txt`, "--encrypt",
+		"--chunknumber", "0", "--commit", "--web-streaming=false",
+		"--wallet", "w_wallet.json", "--configDir", "./config", "--config", "zbox_config.yaml", "--silent",
+	}, cmd.Args)
+	require.Equal(t, `./zbox upload --allocation alloc --localpath "/tmp/my file.txt" --remotepath "/dir/\"quoted\".txt" `+
+		`--encrypt --chunknumber 0 --commit --web-streaming=false --wallet w_wallet.json --configDir ./config `+
+		`--config zbox_config.yaml --silent`, cmd.String())
+}
+
+func TestDecodeFlags(t *testing.T) {
+	var flags ZboxNewAllocation
+	DecodeFlags(Flags{
+		"size":         10000,
+		"lock":         1,
+		"data":         3.0,
+		"parity":       "two",
+		"owner":        "",
+		"expire":       "1h",
+		"free_storage": "marker.json",
+	}, &flags)
+
+	require.Equal(t, ZboxNewAllocation{
+		Size:        10000,
+		Lock:        1,
+		Data:        3,
+		FreeStorage: "marker.json",
+		Extra:       Flags{"parity": "two", "owner": "", "expire": "1h"},
+	}, flags)
+	require.Equal(t, []string{
+		"--size", "10000", "--lock", "1", "--data", "3", "--free_storage", "marker.json",
+		"--expire", "1h", "--owner", "", "--parity", "two",
+	}, Argv(flags))
+
+	var stake ZboxStakeLock
+	DecodeFlags(Flags{"blobber_id": "blobber", "tokens": 10000000000}, &stake)
+	require.Equal(t, []string{"--blobber_id", "blobber", "--tokens", "10000000000"}, Argv(stake))
+}
+
+func TestStakePoolArgv(t *testing.T) {
+	global := GlobalFlags{Wallet: "w_wallet.json", Silent: true}
+	require.Equal(t, []string{"sp-unlock", "--validator_id", "validator", "--wallet", "w_wallet.json", "--silent"},
+		Zbox("sp-unlock", ZboxStakeUnlock{ValidatorID: "validator"}, global).Args)
+	require.Equal(t, []string{"sp-info", "--blobber_id", "blobber", "--json", "--wallet", "w_wallet.json", "--silent"},
+		Zbox("sp-info", ZboxStakePoolInfo{BlobberID: "blobber", JSON: true}, global).Args)
+}
+
+func TestCommandStringRedactsKeys(t *testing.T) {
+	cmd := Command{Program: "./zbox", Args: []string{"migrate", "--access-key", "AKIA", "--secret-key", "s3cr3t", "--bucket", "b"}}
+	require.Equal(t, "./zbox migrate --access-key **** --secret-key **** --bucket b", cmd.String())
+}
@@ -0,0 +1,87 @@
+package cliutils
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/0chain/system_test/internal/api/util/test"
+	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
+	"github.com/0chain/system_test/internal/cli/stub"
+	"github.com/stretchr/testify/require"
+)
+
+// The harness tests run the commands against the clistub binaries instead of a network
+
+func TestRunCommandRetriesWithStub(t *testing.T) {
+	t.Setenv(test.ArtifactsDirEnv, t.TempDir())
+	dir := stub.Use(t,
+		stub.Fixture{Program: "zwallet", Args: []string{"faucet"}, Stderr: "Error: consensus not reached\n", Exit: 1, Times: 2},
+		stub.Fixture{Program: "zwallet", Args: []string{"faucet"}, Stdout: "Execute faucet smart contract success with txn : 4b9f\n"},
+	)
+	zwallet := filepath.Join(dir, "zwallet")
+
+	output, err := RunCommand(test.NewSystemTest(t), zwallet+" faucet --methodName pour --tokens 1 --silent", 3, time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Execute faucet smart contract success with txn : 4b9f"}, output)
+
+	calls, err := stub.Calls(os.Getenv(stub.FixturesEnv))
+	require.NoError(t, err)
+	require.Equal(t, map[int]int{0: 2, 1: 1}, calls, "two failed attempts and a successful one")
+}
+
+func TestRunCommandGivesUpWithStub(t *testing.T) {
+	t.Setenv(test.ArtifactsDirEnv, t.TempDir())
+	dir := stub.Use(t, stub.Fixture{Program: "zwallet", Args: []string{"faucet"}, Stderr: "Error: consensus not reached\n", Exit: 1})
+
+	output, err := RunCommand(test.NewSystemTest(t), filepath.Join(dir, "zwallet")+" faucet --silent", 2, time.Millisecond)
+	require.Error(t, err)
+	require.Equal(t, []string{"Error: consensus not reached"}, output)
+}
+
+func TestParseStubOutput(t *testing.T) {
+	t.Setenv(test.ArtifactsDirEnv, t.TempDir())
+	dir := stub.Use(t,
+		stub.Fixture{Program: "zbox", Args: []string{"newallocation"}, Stdout: "Allocation created: 6f2d0c\n"},
+		stub.Fixture{
+			Program: "zbox",
+			Args:    []string{"getallocation", "--allocation", "6f2d0c"},
+			Stdout:  "Fetching allocation\n" + `{"id":"6f2d0c","owner_id":"c1","size":1024,"data_shards":2,"parity_shards":1}` + "\n",
+		},
+	)
+	st := test.NewSystemTest(t)
+	zbox := filepath.Join(dir, "zbox")
+
+	output, err := RunCommandWithoutRetry(st, zbox+" newallocation --lock 1 --silent")
+	require.NoError(t, err)
+	match, err := cliparse.Regex(output, regexp.MustCompile(`^Allocation created: (.+)$`))
+	require.NoError(t, err)
+	allocationID := match[1]
+	require.Equal(t, "6f2d0c", allocationID)
+
+	output, err = RunCommandWithoutRetry(st, zbox+" getallocation --allocation "+allocationID+" --json --silent")
+	require.NoError(t, err)
+	allocation := cliparse.RequireJSON[climodel.Allocation](st, output)
+	require.Equal(t, "c1", allocation.Owner)
+	require.Equal(t, int64(1024), allocation.Size)
+}
+
+func TestCommandTimeoutWithStub(t *testing.T) {
+	t.Setenv(test.ArtifactsDirEnv, t.TempDir())
+	dir := stub.Use(t, stub.Fixture{Program: "zbox", Args: []string{"download"}, Delay: time.Minute})
+
+	cmd := Command{Program: filepath.Join(dir, "zbox"), Args: []string{"download", "--allocation", "a1"}, Timeout: 500 * time.Millisecond}
+	_, err := cmd.RunWithoutRetry(test.NewSystemTest(t))
+	require.True(t, errors.Is(err, ErrCommandTimeout), "got %v", err)
+
+	value, ok := transcripts.Load(t)
+	require.True(t, ok)
+	invocation := value.(*transcript).invocations[0]
+	require.True(t, invocation.TimedOut)
+	require.Contains(t, invocation.Stderr, "SIGQUIT", "the Go runtime of the stub dumps its goroutines")
+	require.Contains(t, invocation.Stderr, "goroutine 1")
+}
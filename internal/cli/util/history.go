@@ -2,7 +2,6 @@ package cliutils
 
 import (
 	"fmt"
-	"strconv"
 
 	"github.com/stretchr/testify/require"
 
@@ -24,20 +23,49 @@ type ChainHistory struct {
 	providerRewards []model.RewardProvider
 	transactions    []model.EventDBTransaction
 	roundHistories  map[int64]RoundHistory
+	cache           *HistoryCache
+	divergent       []string
+
+	// optional, see LoadChallenges, LoadReadMarkers and LoadAllocationEvents
+	challenges       []model.Challenge
+	readMarkers      []model.ReadMarker
+	allocationEvents []AllocationEvent
 }
 
 type RoundHistory struct {
-	Block           *model.EventDBBlock
-	DelegateRewards []model.RewardDelegate
-	ProviderRewards []model.RewardProvider
-	Transactions    []model.EventDBTransaction
+	Block            *model.EventDBBlock
+	DelegateRewards  []model.RewardDelegate
+	ProviderRewards  []model.RewardProvider
+	Transactions     []model.EventDBTransaction
+	Challenges       []model.Challenge  `json:",omitempty"`
+	ReadMarkers      []model.ReadMarker `json:",omitempty"`
+	AllocationEvents []AllocationEvent  `json:",omitempty"`
 }
 
 func NewHistory(from, to int64) *ChainHistory {
 	return &ChainHistory{
-		from: from,
-		to:   to,
+		from:  from,
+		to:    to,
+		cache: DefaultHistoryCache(),
+	}
+}
+
+// NewHistoryFromRounds builds a history from already known rounds, every round from from to to must be present
+func NewHistoryFromRounds(t *test.SystemTest, from, to int64, rounds []RoundHistory) *ChainHistory {
+	ch := NewHistory(from, to).WithCache(nil)
+	byRound := make(map[int64]RoundHistory, len(rounds))
+	for _, rh := range rounds {
+		require.NotNil(t, rh.Block, "round history without block")
+		byRound[rh.Block.Round] = rh
 	}
+	ch.assemble(t, byRound, true)
+	return ch
+}
+
+// WithCache replaces the default history cache, nil disables caching
+func (ch *ChainHistory) WithCache(cache *HistoryCache) *ChainHistory {
+	ch.cache = cache
+	return ch
 }
 
 func (ch *ChainHistory) RoundHistory(t *test.SystemTest, round int64) RoundHistory {
@@ -80,47 +108,25 @@ func (ch *ChainHistory) FeesForRound(t *test.SystemTest, round int64) int64 {
 }
 
 func (ch *ChainHistory) Read(t *test.SystemTest, sharderBaseUrl string, includeTransactions bool) {
-	ch.readBlocks(t, sharderBaseUrl)
-	ch.readDelegateRewards(t, sharderBaseUrl)
-	ch.readProviderRewards(t, sharderBaseUrl)
-	if includeTransactions {
-		ch.readTransaction(t, sharderBaseUrl)
+	ch.ReadFromSharders(t, []string{sharderBaseUrl}, includeTransactions)
+}
+
+// assemble rebuilds the history from per round histories, in round order
+func (ch *ChainHistory) assemble(t *test.SystemTest, rounds map[int64]RoundHistory, includeTransactions bool) {
+	ch.blocks, ch.DelegateRewards, ch.providerRewards, ch.transactions = nil, nil, nil, nil
+	for round := ch.from; round <= ch.to; round++ {
+		rh, ok := rounds[round]
+		require.True(t, ok, "no history for round %d", round)
+		ch.blocks = append(ch.blocks, *rh.Block)
+		ch.DelegateRewards = append(ch.DelegateRewards, rh.DelegateRewards...)
+		ch.providerRewards = append(ch.providerRewards, rh.ProviderRewards...)
+		if includeTransactions {
+			ch.transactions = append(ch.transactions, rh.Transactions...)
+		}
 	}
 	ch.setup(t)
 }
 
-func (ch *ChainHistory) readBlocks(t *test.SystemTest, sharderBaseUrl string) {
-	url := fmt.Sprintf(sharderBaseUrl + "/v1/screst/" + StorageScAddress + "/get_blocks")
-	params := map[string]string{
-		"contents": "full",
-	}
-	ch.blocks = ApiGetList[model.EventDBBlock](t, url, params, ch.from, ch.to+1)
-}
-
-func (ch *ChainHistory) readDelegateRewards(t *test.SystemTest, sharderBaseUrl string) {
-	url := fmt.Sprintf(sharderBaseUrl + "/v1/screst/" + MinerScAddress + "/delegate-rewards")
-	params := map[string]string{
-		"start": strconv.FormatInt(ch.from, 10),
-		"end":   strconv.FormatInt(ch.to+1, 10),
-	}
-	ch.DelegateRewards = ApiGetList[model.RewardDelegate](t, url, params, ch.from, ch.to+1)
-}
-
-func (ch *ChainHistory) readProviderRewards(t *test.SystemTest, sharderBaseUrl string) {
-	url := fmt.Sprintf(sharderBaseUrl + "/v1/screst/" + MinerScAddress + "/provider-rewards")
-	params := map[string]string{
-		"start": strconv.FormatInt(ch.from, 10),
-		"end":   strconv.FormatInt(ch.to+1, 10),
-	}
-	ch.providerRewards = ApiGetList[model.RewardProvider](t, url, params, ch.from, ch.to+1)
-}
-
-func (ch *ChainHistory) readTransaction(t *test.SystemTest, sharderBaseUrl string) {
-	url := fmt.Sprintf(sharderBaseUrl + "/v1/screst/" + StorageScAddress + "/transactions")
-	params := map[string]string{}
-	ch.transactions = ApiGetList[model.EventDBTransaction](t, url, params, ch.from, ch.to+1)
-}
-
 func (ch *ChainHistory) setup(t *test.SystemTest) { // nolint:
 	ch.roundHistories = make(map[int64]RoundHistory, ch.to-ch.from+1)
 
@@ -164,7 +170,11 @@ func (ch *ChainHistory) setup(t *test.SystemTest) { // nolint:
 		}
 		currentHistory.DelegateRewards = append(currentHistory.DelegateRewards, dr)
 	}
+	if currentRound > 0 {
+		ch.roundHistories[currentRound] = currentHistory
+	}
 	ch.setupTransactions(t)
+	ch.setupStorage()
 
 	require.Equalf(t, int(ch.to-ch.from+1), len(ch.roundHistories),
 		"mismatched round count recorded, from %d, to %d", ch.to, ch.from)
@@ -0,0 +1,220 @@
+package cliutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/0chain/system_test/internal/api/util/test"
+	"github.com/0chain/system_test/internal/cli/model"
+	"github.com/stretchr/testify/require"
+)
+
+// HistoryCacheEnv overrides the directory of the default history cache, set it to "off" to disable caching
+const HistoryCacheEnv = "CHAIN_HISTORY_CACHE"
+
+const historyCacheOff = "off"
+
+// HistoryFinalityMargin is the number of rounds before the latest finalised one that are not cached,
+// as their rewards and events may not all be indexed yet
+const HistoryFinalityMargin = 20
+
+// HistoryCache stores the history of finalised rounds on disk, one file per network and round,
+// so that overlapping ChainHistory reads within and across test packages fetch each round once
+type HistoryCache struct {
+	dir string
+}
+
+// cachedRound is the on-disk form of a round, BlockHash is used to detect a stale entry
+type cachedRound struct {
+	Round           int64        `json:"round"`
+	BlockHash       string       `json:"block_hash"`
+	HasTransactions bool         `json:"has_transactions"`
+	History         RoundHistory `json:"history"`
+}
+
+// NewHistoryCache creates a cache in dir, the directory is created on first write
+func NewHistoryCache(dir string) *HistoryCache {
+	return &HistoryCache{dir: dir}
+}
+
+// DefaultHistoryCache returns the cache in HistoryCacheEnv or, if not set, in the system temp directory.
+// It returns nil if caching is disabled.
+func DefaultHistoryCache() *HistoryCache {
+	dir := os.Getenv(HistoryCacheEnv)
+	switch dir {
+	case historyCacheOff:
+		return nil
+	case "":
+		dir = filepath.Join(os.TempDir(), "0chain_chain_history")
+	}
+	return NewHistoryCache(dir)
+}
+
+func (c *HistoryCache) path(networkID string, round int64) string {
+	return filepath.Join(c.dir, networkID, strconv.FormatInt(round, 10)+".json")
+}
+
+// load returns the cached round, an unreadable entry is treated as a miss
+func (c *HistoryCache) load(networkID string, round int64) (*cachedRound, bool) {
+	data, err := os.ReadFile(c.path(networkID, round))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry cachedRound
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Round != round || entry.History.Block == nil {
+		return nil, false
+	}
+	return &entry, true
+}
+
+// store writes the round through a temporary file so that concurrent readers never see a partial entry
+func (c *HistoryCache) store(networkID string, entry *cachedRound) error {
+	path := c.path(networkID, entry.Round)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// readCached fills the history from the cache, fetching only the rounds that are missing or whose block changed.
+// The cached blocks are compared with the chain at the first and last rounds and at the finality edge only, if one
+// of them changed the blocks of every round are compared.
+func (ch *ChainHistory) readCached(
+	t *test.SystemTest, sharderBaseUrl string, includeTransactions bool, fetch func(from, to int64) *ChainHistory,
+) {
+	networkID := readNetworkID(t, sharderBaseUrl)
+	settled := readLatestRound(t, sharderBaseUrl) - HistoryFinalityMargin
+
+	entries := make(map[int64]*cachedRound, ch.to-ch.from+1)
+	for round := ch.from; round <= ch.to; round++ {
+		if entry, ok := ch.cache.load(networkID, round); ok && (entry.HasTransactions || !includeTransactions) {
+			entries[round] = entry
+		}
+	}
+
+	edge := settled
+	if edge > ch.to {
+		edge = ch.to
+	}
+	hashes := make(map[int64]string)
+	for _, round := range []int64{ch.from, edge, ch.to} {
+		if _, ok := hashes[round]; !ok && round >= ch.from {
+			hashes[round] = readBlockHashes(t, sharderBaseUrl, round, round)[round]
+		}
+	}
+	changed := false
+	for round, hash := range hashes {
+		if entry, ok := entries[round]; ok && entry.BlockHash != hash {
+			t.Logf("cached block of round %d differs from the chain, checking every cached round", round)
+			changed = true
+		}
+	}
+	if changed {
+		hashes = readBlockHashes(t, sharderBaseUrl, ch.from, ch.to)
+		for round, entry := range entries {
+			if entry.BlockHash != hashes[round] {
+				delete(entries, round)
+			}
+		}
+	}
+
+	rounds := make(map[int64]RoundHistory, ch.to-ch.from+1)
+	var missing []int64
+	for round := ch.from; round <= ch.to; round++ {
+		if entry, ok := entries[round]; ok {
+			rounds[round] = entry.History
+			continue
+		}
+		missing = append(missing, round)
+	}
+
+	for _, span := range contiguous(missing) {
+		fetched := fetch(span[0], span[1])
+		for round := span[0]; round <= span[1]; round++ {
+			rh := fetched.RoundHistory(t, round)
+			block := *rh.Block
+			rh.Block = &block
+			rounds[round] = rh
+
+			if round > settled {
+				continue
+			}
+			err := ch.cache.store(networkID, &cachedRound{
+				Round:           round,
+				BlockHash:       block.Hash,
+				HasTransactions: includeTransactions,
+				History:         rh,
+			})
+			if err != nil {
+				t.Logf("caching history of round %d: %v", round, err)
+			}
+		}
+	}
+	t.Logf("history of rounds %d to %d: %d cached, %d fetched",
+		ch.from, ch.to, int(ch.to-ch.from+1)-len(missing), len(missing))
+
+	ch.assemble(t, rounds, includeTransactions)
+}
+
+// readNetworkID identifies the network by the hash of its first block, which differs on every deployment
+func readNetworkID(t *test.SystemTest, sharderBaseUrl string) string {
+	url := fmt.Sprintf(sharderBaseUrl + "/v1/screst/" + StorageScAddress + "/get_blocks")
+	blocks := ApiGetList[model.EventDBBlock](t, url, map[string]string{}, 1, 2)
+	require.NotEmpty(t, blocks, "reading first block of the network from %s", sharderBaseUrl)
+	return blocks[0].Hash
+}
+
+// readLatestRound returns the round of the latest block finalised by the sharder
+func readLatestRound(t *test.SystemTest, sharderBaseUrl string) int64 {
+	latest := ApiGet[struct {
+		Round int64 `json:"round"`
+	}](t, sharderBaseUrl+"/v1/block/get/latest_finalized", map[string]string{})
+	return latest.Round
+}
+
+func readBlockHashes(t *test.SystemTest, sharderBaseUrl string, from, to int64) map[int64]string {
+	url := fmt.Sprintf(sharderBaseUrl + "/v1/screst/" + StorageScAddress + "/get_blocks")
+	blocks := ApiGetList[model.EventDBBlock](t, url, map[string]string{}, from, to+1)
+
+	hashes := make(map[int64]string, len(blocks))
+	for i := range blocks {
+		hashes[blocks[i].Round] = blocks[i].Hash
+	}
+	return hashes
+}
+
+// contiguous groups sorted rounds into inclusive [from, to] spans
+func contiguous(rounds []int64) [][2]int64 {
+	var spans [][2]int64
+	for _, round := range rounds {
+		if n := len(spans); n > 0 && spans[n-1][1]+1 == round {
+			spans[n-1][1] = round
+			continue
+		}
+		spans = append(spans, [2]int64{round, round})
+	}
+	return spans
+}
@@ -0,0 +1,149 @@
+package cliutils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/0chain/system_test/internal/api/util/test"
+	"github.com/0chain/system_test/internal/cli/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistoryCacheRoundTrip(t *testing.T) {
+	cache := NewHistoryCache(t.TempDir())
+
+	_, ok := cache.load("network", 7)
+	require.False(t, ok)
+
+	entry := &cachedRound{
+		Round:           7,
+		BlockHash:       "abc",
+		HasTransactions: true,
+		History: RoundHistory{
+			Block:           &model.EventDBBlock{Hash: "abc", Round: 7},
+			DelegateRewards: []model.RewardDelegate{{Amount: 10, BlockNumber: 7}},
+		},
+	}
+	require.NoError(t, cache.store("network", entry))
+
+	loaded, ok := cache.load("network", 7)
+	require.True(t, ok)
+	require.Equal(t, entry, loaded)
+
+	_, ok = cache.load("other", 7)
+	require.False(t, ok, "entries are per network")
+
+	require.NoError(t, os.WriteFile(cache.path("network", 7), []byte("{"), 0600))
+	_, ok = cache.load("network", 7)
+	require.False(t, ok, "a corrupt entry is a miss")
+}
+
+func TestContiguous(t *testing.T) {
+	require.Empty(t, contiguous(nil))
+	require.Equal(t, [][2]int64{{3, 5}, {7, 7}, {9, 10}}, contiguous([]int64{3, 4, 5, 7, 9, 10}))
+}
+
+func TestHistoryCacheSkipsUnsettledRounds(testSetup *testing.T) {
+	t := test.NewSystemTest(testSetup)
+	sharder := fakeSharder(testSetup, 100, func(round int64) int64 { return 100 })
+	cache := NewHistoryCache(testSetup.TempDir())
+
+	NewHistory(60, 100).WithCache(cache).Read(t, sharder, true)
+
+	// the network is identified by the hash of its first block
+	for round := int64(60); round <= 100; round++ {
+		_, ok := cache.load("hash1", round)
+		require.Equal(t, round <= 100-HistoryFinalityMargin, ok, "round %d cached", round)
+	}
+}
+
+// countingProxy forwards requests to target, counting them by path and recording the rounds of get_blocks requests
+type countingProxy struct {
+	mu          sync.Mutex
+	paths       map[string]int
+	blockRanges [][2]int64
+}
+
+func newCountingProxy(t *testing.T, target string) (*countingProxy, string) {
+	targetURL, err := url.Parse(target)
+	require.NoError(t, err)
+	forward := httputil.NewSingleHostReverseProxy(targetURL)
+
+	p := &countingProxy{paths: map[string]int{}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p.mu.Lock()
+		p.paths[r.URL.Path]++
+		if strings.HasSuffix(r.URL.Path, "/get_blocks") {
+			start, _ := strconv.ParseInt(r.URL.Query().Get("start"), 10, 64)
+			end, _ := strconv.ParseInt(r.URL.Query().Get("end"), 10, 64)
+			p.blockRanges = append(p.blockRanges, [2]int64{start, end})
+		}
+		p.mu.Unlock()
+		forward.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return p, server.URL
+}
+
+func (p *countingProxy) reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paths = map[string]int{}
+	p.blockRanges = nil
+}
+
+func TestHistoryCacheWarmReadChecksBoundariesOnly(testSetup *testing.T) {
+	t := test.NewSystemTest(testSetup)
+	proxy, sharder := newCountingProxy(testSetup, fakeSharder(testSetup, 100, func(round int64) int64 { return 100 }))
+	cache := NewHistoryCache(testSetup.TempDir())
+
+	cold := NewHistory(10, 70).WithCache(cache)
+	cold.Read(t, sharder, true)
+	proxy.reset()
+
+	warm := NewHistory(10, 70).WithCache(cache)
+	warm.Read(t, sharder, true)
+	for round := int64(10); round <= 70; round++ {
+		require.Equal(t, cold.RoundHistory(t, round), warm.RoundHistory(t, round))
+	}
+
+	// the first block identifies the network, the last round is checked to be on the sharder and the first and last
+	// rounds are compared with the cache, the finality edge is past the last round
+	require.Equal(t, [][2]int64{{1, 2}, {10, 11}, {70, 71}, {70, 71}}, sortedRanges(proxy.blockRanges))
+	require.Equal(t, 1, proxy.paths["/v1/block/get/latest_finalized"])
+	require.Len(t, proxy.paths, 2, "only blocks and the latest round are read, %v", proxy.paths)
+
+	// a changed boundary block makes every cached round checked, and the changed round fetched again
+	entry, ok := cache.load("hash1", 10)
+	require.True(t, ok)
+	entry.BlockHash = "stale"
+	require.NoError(t, cache.store("hash1", entry))
+	proxy.reset()
+
+	NewHistory(10, 70).WithCache(cache).Read(t, sharder, true)
+	require.Contains(t, proxy.blockRanges, [2]int64{10, 71}, "every cached round is checked")
+	require.Equal(t, 1, proxy.paths["/v1/screst/"+MinerScAddress+"/provider-rewards"], "only the changed round is fetched")
+	fixed, ok := cache.load("hash1", 10)
+	require.True(t, ok)
+	require.Equal(t, "hash10", fixed.BlockHash)
+
+	// rounds past the finality edge are not cached, the edge is compared instead
+	NewHistory(60, 90).WithCache(cache).Read(t, sharder, true)
+	proxy.reset()
+	NewHistory(60, 90).WithCache(cache).Read(t, sharder, true)
+	require.Contains(t, proxy.blockRanges, [2]int64{100 - HistoryFinalityMargin, 101 - HistoryFinalityMargin})
+}
+
+func sortedRanges(ranges [][2]int64) [][2]int64 {
+	sorted := append([][2]int64(nil), ranges...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i][0] < sorted[j][0] })
+	return sorted
+}
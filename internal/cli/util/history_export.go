@@ -0,0 +1,266 @@
+package cliutils
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0chain/system_test/internal/api/util/redact"
+	"github.com/0chain/system_test/internal/api/util/test"
+	"github.com/0chain/system_test/internal/cli/model"
+	"github.com/stretchr/testify/require"
+)
+
+// errCSVReplay is returned for exports whose JSON Lines files are missing, the CSV files are lossy
+var errCSVReplay = errors.New("only the CSV export is present, replaying a history needs the JSON Lines files")
+
+// The files written by Export, each table is written as .csv and as .jsonl
+const (
+	historyMetaFile       = "history.json"
+	blocksTable           = "blocks"
+	providerRewardsTable  = "provider_rewards"
+	delegateRewardsTable  = "delegate_rewards"
+	transactionsTable     = "transactions"
+	challengesTable       = "challenges"
+	readMarkersTable      = "read_markers"
+	allocationEventsTable = "allocation_events"
+)
+
+// historyMeta is the range of an exported history
+type historyMeta struct {
+	From              int64    `json:"from"`
+	To                int64    `json:"to"`
+	DivergentSharders []string `json:"divergent_sharders,omitempty"`
+}
+
+// Export writes the blocks, rewards, transactions and loaded storage events of the history to dir as CSV, for spreadsheets,
+// and as JSON Lines, which ImportHistory reads back without loss. The CSV files leave out nested fields, such as the
+// transactions of blocks, and cannot be replayed. Secrets in transaction data are redacted from the CSV files only, the
+// JSON Lines files are written as read and only readable by the owner.
+func (ch *ChainHistory) Export(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	meta, err := json.MarshalIndent(historyMeta{From: ch.from, To: ch.to, DivergentSharders: ch.divergent}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, historyMetaFile), meta, 0644); err != nil { //nolint:gosec
+		return err
+	}
+
+	return errors.Join(
+		exportTable(dir, blocksTable, ch.blocks),
+		exportTable(dir, providerRewardsTable, ch.providerRewards),
+		exportTable(dir, delegateRewardsTable, ch.DelegateRewards),
+		exportTable(dir, transactionsTable, ch.transactions),
+		exportTable(dir, challengesTable, ch.challenges),
+		exportTable(dir, readMarkersTable, ch.readMarkers),
+		exportTable(dir, allocationEventsTable, ch.allocationEvents),
+	)
+}
+
+// ExportOnFailure exports the history to the artifact directory of the test if the test fails
+func (ch *ChainHistory) ExportOnFailure(t *test.SystemTest) {
+	t.Cleanup(func() {
+		if !t.Failed() {
+			return
+		}
+		// t no longer logs once the test has completed
+		st := test.NewSystemTest(t.Unwrap)
+		dir := filepath.Join(st.ArtifactDir(), "chain_history")
+		if err := ch.Export(dir); err != nil {
+			st.Logf("exporting history of rounds %d to %d: %v", ch.from, ch.to, err)
+			return
+		}
+		st.Logf("history of rounds %d to %d exported to %s, replay it with cliutils.ImportHistory", ch.from, ch.to, dir)
+	})
+}
+
+// ImportHistory rebuilds a history written by Export from its JSON Lines files
+func ImportHistory(t *test.SystemTest, dir string) *ChainHistory {
+	data, err := os.ReadFile(filepath.Join(dir, historyMetaFile))
+	require.NoError(t, err, "reading exported history")
+	var meta historyMeta
+	require.NoError(t, json.Unmarshal(data, &meta), "reading %s", historyMetaFile)
+
+	ch := NewHistory(meta.From, meta.To).WithCache(nil)
+	ch.divergent = meta.DivergentSharders
+	ch.blocks, err = importTable[model.EventDBBlock](dir, blocksTable)
+	require.NoError(t, err)
+	ch.providerRewards, err = importTable[model.RewardProvider](dir, providerRewardsTable)
+	require.NoError(t, err)
+	ch.DelegateRewards, err = importTable[model.RewardDelegate](dir, delegateRewardsTable)
+	require.NoError(t, err)
+	ch.transactions, err = importTable[model.EventDBTransaction](dir, transactionsTable)
+	require.NoError(t, err)
+	// exports of older histories have no storage tables
+	ch.challenges, err = importOptionalTable[model.Challenge](dir, challengesTable)
+	require.NoError(t, err)
+	ch.readMarkers, err = importOptionalTable[model.ReadMarker](dir, readMarkersTable)
+	require.NoError(t, err)
+	ch.allocationEvents, err = importOptionalTable[AllocationEvent](dir, allocationEventsTable)
+	require.NoError(t, err)
+
+	ch.setup(t)
+	return ch
+}
+
+func exportTable[T any](dir, table string, rows []T) error {
+	if err := writeJSONLines(filepath.Join(dir, table+".jsonl"), rows); err != nil {
+		return fmt.Errorf("exporting %s: %w", table, err)
+	}
+	if err := writeCSV(filepath.Join(dir, table+".csv"), rows); err != nil {
+		return fmt.Errorf("exporting %s: %w", table, err)
+	}
+	return nil
+}
+
+func importTable[T any](dir, table string) ([]T, error) {
+	path := filepath.Join(dir, table+".jsonl")
+	if _, err := os.Stat(path); err != nil {
+		if _, csvErr := os.Stat(filepath.Join(dir, table+".csv")); csvErr == nil {
+			return nil, fmt.Errorf("importing %s: %w", table, errCSVReplay)
+		}
+		return nil, fmt.Errorf("importing %s: %w", table, err)
+	}
+	rows, err := readJSONLines[T](path)
+	if err != nil {
+		return nil, fmt.Errorf("importing %s: %w", path, err)
+	}
+	return rows, nil
+}
+
+func importOptionalTable[T any](dir, table string) ([]T, error) {
+	for _, ext := range []string{".jsonl", ".csv"} {
+		if _, err := os.Stat(filepath.Join(dir, table+ext)); err == nil {
+			return importTable[T](dir, table)
+		}
+	}
+	return nil, nil
+}
+
+// writeJSONLines writes the rows unredacted, so that they are replayed as they were read
+func writeJSONLines[T any](path string, rows []T) error {
+	// tables hold transaction data, which may carry secrets
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	encoder := json.NewEncoder(w)
+	for i := range rows {
+		if err := encoder.Encode(&rows[i]); err != nil {
+			_ = f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func readJSONLines[T any](path string) ([]T, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var rows []T
+	decoder := json.NewDecoder(f)
+	for decoder.More() {
+		var row T
+		if err := decoder.Decode(&row); err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// csvColumn is a field written to CSV, nested slices such as the transactions of a block are left out
+type csvColumn struct {
+	name  string
+	index int
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func csvColumns(rowType reflect.Type) []csvColumn {
+	var columns []csvColumn
+	for i := 0; i < rowType.NumField(); i++ {
+		field := rowType.Field(i)
+		kind := field.Type.Kind()
+		if !field.IsExported() || kind == reflect.Slice || kind == reflect.Map ||
+			(kind == reflect.Struct && field.Type != timeType) {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" {
+			name = field.Name
+		}
+		columns = append(columns, csvColumn{name: name, index: i})
+	}
+	return columns
+}
+
+func writeCSV[T any](path string, rows []T) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	columns := csvColumns(reflect.TypeOf((*T)(nil)).Elem())
+	w := csv.NewWriter(f)
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.name
+	}
+	_ = w.Write(header)
+
+	record := make([]string, len(columns))
+	for i := range rows {
+		value := reflect.ValueOf(rows[i])
+		for j, column := range columns {
+			record[j] = redact.String(formatCell(value.Field(column.index)))
+		}
+		_ = w.Write(record)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func formatCell(v reflect.Value) string {
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).Format(time.RFC3339Nano)
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	}
+	return fmt.Sprint(v.Interface())
+}
@@ -0,0 +1,75 @@
+package cliutils
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/0chain/system_test/internal/api/util/test"
+	"github.com/0chain/system_test/internal/cli/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportImportHistory(testSetup *testing.T) {
+	t := test.NewSystemTest(testSetup)
+	history := queryHistory(t)
+	history.blocks[1].CreatedAt = time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	history.blocks[1].MinerID = `miner, "quoted"`
+	history.setup(t)
+
+	dir := filepath.Join(t.TempDir(), "history")
+	require.NoError(t, history.Export(dir))
+	for _, table := range []string{blocksTable, providerRewardsTable, delegateRewardsTable, transactionsTable} {
+		require.FileExists(t, filepath.Join(dir, table+".csv"))
+		require.FileExists(t, filepath.Join(dir, table+".jsonl"))
+	}
+
+	imported := ImportHistory(t, dir)
+	require.Equal(t, history.From(), imported.From())
+	require.Equal(t, history.To(), imported.To())
+	for round := history.From(); round <= history.To(); round++ {
+		require.Equal(t, history.RoundHistory(t, round), imported.RoundHistory(t, round))
+	}
+
+	// the CSV files leave out the transactions of blocks, they are not replayed
+	require.NoError(t, os.Remove(filepath.Join(dir, blocksTable+".jsonl")))
+	_, err := importTable[model.EventDBBlock](dir, blocksTable)
+	require.ErrorIs(t, err, errCSVReplay)
+}
+
+func TestExportImportHistoryRoundTrip(testSetup *testing.T) {
+	t := test.NewSystemTest(testSetup)
+	history := queryHistory(t)
+	secretData := `{"name":"add_free_storage_assigner","input":{"auth_ticket":"c2VjcmV0IHRpY2tldA"}}`
+	history.transactions[0].TransactionData = secretData
+	history.setup(t)
+
+	dir := filepath.Join(t.TempDir(), "history")
+	require.NoError(t, history.Export(dir))
+	imported := ImportHistory(t, dir)
+
+	require.Equal(t, history.blocks, imported.blocks)
+	require.Equal(t, history.providerRewards, imported.providerRewards)
+	require.Equal(t, history.DelegateRewards, imported.DelegateRewards)
+	require.Equal(t, history.transactions, imported.transactions, "the JSON Lines files are not redacted")
+	require.Equal(t, secretData, imported.transactions[0].TransactionData)
+
+	exportedCSV, err := os.ReadFile(filepath.Join(dir, transactionsTable+".csv"))
+	require.NoError(t, err)
+	require.NotContains(t, string(exportedCSV), "c2VjcmV0IHRpY2tldA", "the CSV files are redacted")
+
+	info, err := os.Stat(filepath.Join(dir, transactionsTable+".jsonl"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestCSVColumns(t *testing.T) {
+	var names []string
+	for _, column := range csvColumns(reflect.TypeOf(model.EventDBBlock{})) {
+		names = append(names, column.name)
+	}
+	require.Contains(t, names, "created_at")
+	require.NotContains(t, names, "transactions")
+}
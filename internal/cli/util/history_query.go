@@ -0,0 +1,307 @@
+package cliutils
+
+import (
+	"encoding/json"
+
+	"github.com/0chain/system_test/internal/cli/model"
+)
+
+// RewardEntry is a provider or delegate reward of a round, PoolID is empty for provider rewards
+type RewardEntry struct {
+	Round        int64
+	ProviderID   string
+	PoolID       string
+	ProviderType model.Provider
+	RewardType   model.Reward
+	Amount       int64
+}
+
+// IsDelegate reports whether the reward was paid to a delegate pool rather than to the provider
+func (r RewardEntry) IsDelegate() bool {
+	return r.PoolID != ""
+}
+
+// Group is the count and sum of the amounts of the entries sharing a key
+type Group struct {
+	Count int
+	Sum   int64
+}
+
+// RewardKey groups rewards, see GroupBy
+type RewardKey func(RewardEntry) string
+
+var (
+	RewardProviderKey     RewardKey = func(r RewardEntry) string { return r.ProviderID }
+	RewardPoolKey         RewardKey = func(r RewardEntry) string { return r.PoolID }
+	RewardTypeKey         RewardKey = func(r RewardEntry) string { return r.RewardType.String() }
+	RewardProviderTypeKey RewardKey = func(r RewardEntry) string { return r.ProviderType.String() }
+)
+
+// RewardQuery selects rewards of a ChainHistory, filters are combined with and
+type RewardQuery struct {
+	history *ChainHistory
+	filters []func(RewardEntry) bool
+}
+
+// Rewards queries the provider and delegate rewards of all rounds, in round order
+func (ch *ChainHistory) Rewards() *RewardQuery {
+	return &RewardQuery{history: ch}
+}
+
+// Where keeps the rewards for which keep returns true
+func (q *RewardQuery) Where(keep func(RewardEntry) bool) *RewardQuery {
+	filters := append(append([]func(RewardEntry) bool(nil), q.filters...), keep)
+	return &RewardQuery{history: q.history, filters: filters}
+}
+
+// OfProviders keeps the rewards paid to providers
+func (q *RewardQuery) OfProviders() *RewardQuery {
+	return q.Where(func(r RewardEntry) bool { return !r.IsDelegate() })
+}
+
+// OfDelegates keeps the rewards paid to delegate pools
+func (q *RewardQuery) OfDelegates() *RewardQuery {
+	return q.Where(RewardEntry.IsDelegate)
+}
+
+// ByProvider keeps the rewards of the providers, and of their delegate pools
+func (q *RewardQuery) ByProvider(providerIDs ...string) *RewardQuery {
+	return q.Where(func(r RewardEntry) bool { return contains(providerIDs, r.ProviderID) })
+}
+
+// ByPool keeps the rewards of the delegate pools
+func (q *RewardQuery) ByPool(poolIDs ...string) *RewardQuery {
+	return q.Where(func(r RewardEntry) bool { return r.IsDelegate() && contains(poolIDs, r.PoolID) })
+}
+
+// ByProviderType keeps the rewards paid to the types of provider
+func (q *RewardQuery) ByProviderType(providerTypes ...model.Provider) *RewardQuery {
+	return q.Where(func(r RewardEntry) bool {
+		for _, providerType := range providerTypes {
+			if r.ProviderType == providerType {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// ByRewardType keeps the rewards of the types
+func (q *RewardQuery) ByRewardType(rewardTypes ...model.Reward) *RewardQuery {
+	return q.Where(func(r RewardEntry) bool {
+		for _, rewardType := range rewardTypes {
+			if r.RewardType == rewardType {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// InRounds keeps the rewards of rounds from to to, inclusive
+func (q *RewardQuery) InRounds(from, to int64) *RewardQuery {
+	return q.Where(func(r RewardEntry) bool { return r.Round >= from && r.Round <= to })
+}
+
+// All returns the selected rewards
+func (q *RewardQuery) All() []RewardEntry {
+	var out []RewardEntry
+	q.each(func(r RewardEntry) { out = append(out, r) })
+	return out
+}
+
+// Count returns the number of selected rewards
+func (q *RewardQuery) Count() int {
+	var count int
+	q.each(func(RewardEntry) { count++ })
+	return count
+}
+
+// Sum returns the total amount of the selected rewards
+func (q *RewardQuery) Sum() int64 {
+	var sum int64
+	q.each(func(r RewardEntry) { sum += r.Amount })
+	return sum
+}
+
+// GroupBy counts and sums the selected rewards by key
+func (q *RewardQuery) GroupBy(key RewardKey) map[string]Group {
+	groups := make(map[string]Group)
+	q.each(func(r RewardEntry) {
+		group := groups[key(r)]
+		group.Count++
+		group.Sum += r.Amount
+		groups[key(r)] = group
+	})
+	return groups
+}
+
+// SumBy sums the selected rewards by key, e.g. the total challenge pass rewards per blobber with
+// history.Rewards().OfProviders().ByRewardType(model.ChallengePassReward).SumBy(RewardProviderKey)
+func (q *RewardQuery) SumBy(key RewardKey) map[string]int64 {
+	sums := make(map[string]int64)
+	for k, group := range q.GroupBy(key) {
+		sums[k] = group.Sum
+	}
+	return sums
+}
+
+func (q *RewardQuery) each(f func(RewardEntry)) {
+	emit := func(r RewardEntry) {
+		for _, keep := range q.filters {
+			if !keep(r) {
+				return
+			}
+		}
+		f(r)
+	}
+
+	ch := q.history
+	for round := ch.from; round <= ch.to; round++ {
+		rh, ok := ch.roundHistories[round]
+		if !ok {
+			continue
+		}
+		for _, pr := range rh.ProviderRewards {
+			emit(RewardEntry{
+				Round:        pr.BlockNumber,
+				ProviderID:   pr.ProviderId,
+				ProviderType: pr.RewardType.Provider(),
+				RewardType:   pr.RewardType,
+				Amount:       pr.Amount,
+			})
+		}
+		for _, dr := range rh.DelegateRewards {
+			emit(RewardEntry{
+				Round:        dr.BlockNumber,
+				ProviderID:   dr.ProviderID,
+				PoolID:       dr.PoolID,
+				ProviderType: dr.RewardType.Provider(),
+				RewardType:   dr.RewardType,
+				Amount:       dr.Amount,
+			})
+		}
+	}
+}
+
+// TransactionGroup is the count, fees and value of the transactions sharing a key
+type TransactionGroup struct {
+	Count int
+	Fees  int64
+	Value int64
+}
+
+// TransactionKey groups transactions, see GroupBy
+type TransactionKey func(model.EventDBTransaction) string
+
+var (
+	TransactionFunctionKey TransactionKey = TransactionFunction
+	TransactionClientKey   TransactionKey = func(tx model.EventDBTransaction) string { return tx.ClientId }
+	TransactionToClientKey TransactionKey = func(tx model.EventDBTransaction) string { return tx.ToClientId }
+)
+
+// TransactionQuery selects transactions of a ChainHistory read with transactions, filters are combined with and
+type TransactionQuery struct {
+	history *ChainHistory
+	filters []func(model.EventDBTransaction) bool
+}
+
+// Transactions queries the transactions of all rounds, in round order
+func (ch *ChainHistory) Transactions() *TransactionQuery {
+	return &TransactionQuery{history: ch}
+}
+
+// Where keeps the transactions for which keep returns true
+func (q *TransactionQuery) Where(keep func(model.EventDBTransaction) bool) *TransactionQuery {
+	filters := append(append([]func(model.EventDBTransaction) bool(nil), q.filters...), keep)
+	return &TransactionQuery{history: q.history, filters: filters}
+}
+
+// ByFunction keeps the smart contract transactions calling the functions, e.g. "new_allocation_request"
+func (q *TransactionQuery) ByFunction(names ...string) *TransactionQuery {
+	return q.Where(func(tx model.EventDBTransaction) bool { return contains(names, TransactionFunction(tx)) })
+}
+
+// ByClient keeps the transactions sent by the clients
+func (q *TransactionQuery) ByClient(clientIDs ...string) *TransactionQuery {
+	return q.Where(func(tx model.EventDBTransaction) bool { return contains(clientIDs, tx.ClientId) })
+}
+
+// ToClient keeps the transactions sent to the clients or smart contracts
+func (q *TransactionQuery) ToClient(clientIDs ...string) *TransactionQuery {
+	return q.Where(func(tx model.EventDBTransaction) bool { return contains(clientIDs, tx.ToClientId) })
+}
+
+// InRounds keeps the transactions of rounds from to to, inclusive
+func (q *TransactionQuery) InRounds(from, to int64) *TransactionQuery {
+	return q.Where(func(tx model.EventDBTransaction) bool { return tx.Round >= from && tx.Round <= to })
+}
+
+// All returns the selected transactions
+func (q *TransactionQuery) All() []model.EventDBTransaction {
+	var out []model.EventDBTransaction
+	q.each(func(tx model.EventDBTransaction) { out = append(out, tx) })
+	return out
+}
+
+// Count returns the number of selected transactions
+func (q *TransactionQuery) Count() int {
+	var count int
+	q.each(func(model.EventDBTransaction) { count++ })
+	return count
+}
+
+// Fees returns the total fees of the selected transactions
+func (q *TransactionQuery) Fees() int64 {
+	var fees int64
+	q.each(func(tx model.EventDBTransaction) { fees += tx.Fee })
+	return fees
+}
+
+// Value returns the total value of the selected transactions
+func (q *TransactionQuery) Value() int64 {
+	var value int64
+	q.each(func(tx model.EventDBTransaction) { value += tx.Value })
+	return value
+}
+
+// GroupBy counts and sums the fees and values of the selected transactions by key
+func (q *TransactionQuery) GroupBy(key TransactionKey) map[string]TransactionGroup {
+	groups := make(map[string]TransactionGroup)
+	q.each(func(tx model.EventDBTransaction) {
+		group := groups[key(tx)]
+		group.Count++
+		group.Fees += tx.Fee
+		group.Value += tx.Value
+		groups[key(tx)] = group
+	})
+	return groups
+}
+
+func (q *TransactionQuery) each(f func(model.EventDBTransaction)) {
+	ch := q.history
+	for round := ch.from; round <= ch.to; round++ {
+		rh, ok := ch.roundHistories[round]
+		if !ok {
+			continue
+		}
+	next:
+		for _, tx := range rh.Transactions {
+			for _, keep := range q.filters {
+				if !keep(tx) {
+					continue next
+				}
+			}
+			f(tx)
+		}
+	}
+}
+
+// TransactionFunction returns the smart contract function called by the transaction, empty if it is not a call
+func TransactionFunction(tx model.EventDBTransaction) string {
+	var data struct {
+		Name string `json:"name"`
+	}
+	_ = json.Unmarshal([]byte(tx.TransactionData), &data)
+	return data.Name
+}
@@ -0,0 +1,68 @@
+package cliutils
+
+import (
+	"testing"
+
+	"github.com/0chain/system_test/internal/api/util/test"
+	"github.com/0chain/system_test/internal/cli/model"
+	"github.com/stretchr/testify/require"
+)
+
+func queryHistory(t *test.SystemTest) *ChainHistory {
+	var rounds []RoundHistory
+	for round := int64(1); round <= 3; round++ {
+		rounds = append(rounds, RoundHistory{
+			Block: &model.EventDBBlock{Round: round, Hash: "hash"},
+			ProviderRewards: []model.RewardProvider{
+				{Amount: 10, BlockNumber: round, ProviderId: "miner", RewardType: model.BlockRewardMiner},
+				{Amount: 5, BlockNumber: round, ProviderId: "blobber1", RewardType: model.ChallengePassReward},
+				{Amount: round, BlockNumber: round, ProviderId: "blobber2", RewardType: model.ChallengePassReward},
+			},
+			DelegateRewards: []model.RewardDelegate{
+				{Amount: 3, BlockNumber: round, PoolID: "pool", ProviderID: "blobber1", RewardType: model.ChallengePassReward},
+			},
+			Transactions: []model.EventDBTransaction{
+				{Round: round, ClientId: "client", Fee: 7, Value: 100, TransactionData: `{"name":"new_allocation_request"}`},
+				{Round: round, ClientId: "other", Fee: 1, TransactionData: `{"name":"read_redeem"}`},
+			},
+		})
+	}
+	return NewHistoryFromRounds(t, 1, 3, rounds)
+}
+
+func TestRewardQuery(testSetup *testing.T) {
+	t := test.NewSystemTest(testSetup)
+	history := queryHistory(t)
+
+	require.Equal(t, 12, history.Rewards().Count())
+	require.Equal(t, int64(30), history.Rewards().ByProviderType(model.ProviderMiner).Sum())
+	require.Equal(t, int64(9), history.Rewards().OfDelegates().Sum())
+	require.Equal(t, 2, history.Rewards().ByProvider("blobber1").InRounds(2, 3).OfProviders().Count())
+	require.Equal(t, int64(9), history.Rewards().ByPool("pool").Sum())
+
+	require.Equal(t, map[string]int64{"blobber1": 15, "blobber2": 6},
+		history.Rewards().OfProviders().ByRewardType(model.ChallengePassReward).SumBy(RewardProviderKey))
+	require.Equal(t, map[string]Group{"miner": {Count: 3, Sum: 30}, "blobber": {Count: 9, Sum: 30}},
+		history.Rewards().GroupBy(RewardProviderTypeKey))
+	require.Equal(t, map[string]int64{"block_reward_miner": 30, "challenge pass reward": 21},
+		history.Rewards().OfProviders().SumBy(RewardTypeKey))
+
+	entries := history.Rewards().ByProvider("blobber2").All()
+	require.Len(t, entries, 3)
+	require.Equal(t, RewardEntry{Round: 3, ProviderID: "blobber2", ProviderType: model.ProviderBlobber,
+		RewardType: model.ChallengePassReward, Amount: 3}, entries[2])
+}
+
+func TestTransactionQuery(testSetup *testing.T) {
+	t := test.NewSystemTest(testSetup)
+	history := queryHistory(t)
+
+	require.Equal(t, 3, history.Transactions().ByFunction("new_allocation_request").Count())
+	require.Equal(t, int64(24), history.Transactions().Fees())
+	require.Equal(t, int64(200), history.Transactions().ByClient("client").InRounds(2, 3).Value())
+	require.Equal(t, map[string]TransactionGroup{
+		"new_allocation_request": {Count: 3, Fees: 21, Value: 300},
+		"read_redeem":            {Count: 3, Fees: 3},
+	}, history.Transactions().GroupBy(TransactionFunctionKey))
+	require.Equal(t, history.FeesForRound(t, 2), history.Transactions().InRounds(2, 2).Fees())
+}
@@ -0,0 +1,318 @@
+package cliutils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/0chain/system_test/internal/api/util/paginate"
+	"github.com/0chain/system_test/internal/api/util/test"
+	"github.com/0chain/system_test/internal/cli/model"
+	"github.com/stretchr/testify/require"
+)
+
+// crossValidationSamples is the number of rounds fetched from every sharder to compare their event databases
+const crossValidationSamples = 3
+
+// errNoMajority is returned if no history is shared by more than half of the sharders that answered
+var errNoMajority = errors.New("no majority of sharders agree on the history")
+
+// roundData is the raw history of a range of rounds as returned by one sharder
+type roundData struct {
+	blocks          []model.EventDBBlock
+	delegateRewards []model.RewardDelegate
+	providerRewards []model.RewardProvider
+	transactions    []model.EventDBTransaction
+}
+
+// chunk is a range of rounds fetched from one sharder
+type chunk struct {
+	from, to int64
+	sharder  string
+	data     *roundData
+	err      error
+}
+
+// ReadFromSharders reads the history like Read, splitting the rounds between the healthy sharders and fetching
+// them in parallel. A sample of rounds is read from every sharder and compared, sharders that differ from the
+// majority are reported by DivergentSharders and their rounds are fetched again from a sharder in the majority.
+// The read fails if more than half of the sharders do not agree.
+func (ch *ChainHistory) ReadFromSharders(t *test.SystemTest, sharderBaseUrls []string, includeTransactions bool) {
+	sharders := healthySharders(t, sharderBaseUrls, ch.to)
+	require.NotEmpty(t, sharders, "no healthy sharder has round %d, sharders %v", ch.to, sharderBaseUrls)
+
+	fetch := func(from, to int64) *ChainHistory {
+		return ch.fetchParallel(t, sharders, from, to, includeTransactions)
+	}
+	if ch.cache != nil {
+		ch.readCached(t, sharders[0], includeTransactions, fetch)
+		return
+	}
+
+	fetched := fetch(ch.from, ch.to)
+	ch.blocks, ch.DelegateRewards, ch.providerRewards, ch.transactions, ch.roundHistories =
+		fetched.blocks, fetched.DelegateRewards, fetched.providerRewards, fetched.transactions, fetched.roundHistories
+	ch.setupStorage()
+}
+
+// DivergentSharders returns the sharders whose history differed from the majority on the last read
+func (ch *ChainHistory) DivergentSharders() []string {
+	return ch.divergent
+}
+
+// fetchParallel reads rounds from to to from sharders and returns them as a history
+func (ch *ChainHistory) fetchParallel(t *test.SystemTest, sharders []string, from, to int64, includeTransactions bool) *ChainHistory {
+	chunks := splitRounds(from, to, sharders)
+	fetchChunks(chunks, includeTransactions)
+
+	if len(sharders) > 1 {
+		for _, divergent := range crossValidate(t, sharders, sampleRounds(from, to)) {
+			if !contains(ch.divergent, divergent) {
+				ch.divergent = append(ch.divergent, divergent)
+			}
+		}
+	}
+
+	// fetch failed and divergent chunks again, from the first sharder that agrees with the majority and answers
+	for i := range chunks {
+		c := &chunks[i]
+		if c.err == nil && !contains(ch.divergent, c.sharder) {
+			continue
+		}
+		failed := c.sharder
+		require.NoError(t, refetch(c, sharders, ch.divergent, includeTransactions),
+			"reading rounds %d to %d from any sharder", c.from, c.to)
+		t.Logf("rounds %d to %d read from %s instead of %s", c.from, c.to, c.sharder, failed)
+	}
+
+	fetched := NewHistory(from, to).WithCache(nil)
+	for _, c := range chunks {
+		fetched.blocks = append(fetched.blocks, c.data.blocks...)
+		fetched.DelegateRewards = append(fetched.DelegateRewards, c.data.delegateRewards...)
+		fetched.providerRewards = append(fetched.providerRewards, c.data.providerRewards...)
+		fetched.transactions = append(fetched.transactions, c.data.transactions...)
+	}
+	fetched.setup(t)
+	return fetched
+}
+
+// refetch reads the rounds of c again from the first other sharder that is not divergent and answers. The data of
+// c is dropped if there is none, so that divergent rounds are never kept.
+func refetch(c *chunk, sharders, divergent []string, includeTransactions bool) error {
+	failed := c.sharder
+	c.data, c.err = nil, fmt.Errorf("no sharder other than %s agrees with the majority", failed)
+	for _, sharder := range sharders {
+		if sharder == failed || contains(divergent, sharder) {
+			continue
+		}
+		c.sharder = sharder
+		c.data, c.err = fetchRoundData(sharder, c.from, c.to, includeTransactions)
+		if c.err == nil {
+			break
+		}
+	}
+	return c.err
+}
+
+// healthySharders returns the sharders that have the block of round, checked in parallel
+func healthySharders(t *test.SystemTest, sharderBaseUrls []string, round int64) []string {
+	healthy := make([]bool, len(sharderBaseUrls))
+	var wg sync.WaitGroup
+	for i, sharder := range sharderBaseUrls {
+		wg.Add(1)
+		go func(i int, sharder string) {
+			defer wg.Done()
+			healthy[i] = paginate.Rounds[model.EventDBBlock](blocksUrl(sharder), nil, round, round+1).WithLimit(1).Next()
+		}(i, sharder)
+	}
+	wg.Wait()
+
+	var sharders []string
+	for i, sharder := range sharderBaseUrls {
+		if healthy[i] {
+			sharders = append(sharders, sharder)
+		} else {
+			t.Logf("sharder %s does not have round %d, not reading history from it", sharder, round)
+		}
+	}
+	return sharders
+}
+
+// splitRounds splits rounds from to to into one chunk per sharder, of at least one page each
+func splitRounds(from, to int64, sharders []string) []chunk {
+	size := (to - from + int64(len(sharders))) / int64(len(sharders))
+	if size < MaxQueryLimit {
+		size = MaxQueryLimit
+	}
+
+	var chunks []chunk
+	for start := from; start <= to; start += size {
+		end := start + size - 1
+		if end > to {
+			end = to
+		}
+		chunks = append(chunks, chunk{from: start, to: end, sharder: sharders[len(chunks)%len(sharders)]})
+	}
+	return chunks
+}
+
+func fetchChunks(chunks []chunk, includeTransactions bool) {
+	var wg sync.WaitGroup
+	for i := range chunks {
+		wg.Add(1)
+		go func(c *chunk) {
+			defer wg.Done()
+			c.data, c.err = fetchRoundData(c.sharder, c.from, c.to, includeTransactions)
+		}(&chunks[i])
+	}
+	wg.Wait()
+}
+
+// fetchRoundData reads rounds from to to, inclusive, from one sharder
+func fetchRoundData(sharderBaseUrl string, from, to int64, includeTransactions bool) (*roundData, error) {
+	var (
+		data roundData
+		err  error
+	)
+	data.blocks, err = ApiGetListError[model.EventDBBlock](blocksUrl(sharderBaseUrl), map[string]string{"contents": "full"}, from, to+1)
+	if err != nil {
+		return nil, err
+	}
+	data.delegateRewards, err = ApiGetListError[model.RewardDelegate](
+		sharderBaseUrl+"/v1/screst/"+MinerScAddress+"/delegate-rewards", map[string]string{}, from, to+1)
+	if err != nil {
+		return nil, err
+	}
+	data.providerRewards, err = ApiGetListError[model.RewardProvider](
+		sharderBaseUrl+"/v1/screst/"+MinerScAddress+"/provider-rewards", map[string]string{}, from, to+1)
+	if err != nil {
+		return nil, err
+	}
+	if includeTransactions {
+		data.transactions, err = ApiGetListError[model.EventDBTransaction](
+			sharderBaseUrl+"/v1/screst/"+StorageScAddress+"/transactions", map[string]string{}, from, to+1)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &data, nil
+}
+
+// sampleRounds returns up to crossValidationSamples rounds spread evenly from from to to
+func sampleRounds(from, to int64) []int64 {
+	n := to - from + 1
+	if n <= crossValidationSamples {
+		var rounds []int64
+		for round := from; round <= to; round++ {
+			rounds = append(rounds, round)
+		}
+		return rounds
+	}
+
+	rounds := make([]int64, crossValidationSamples)
+	for i := range rounds {
+		rounds[i] = from + int64(i)*(n-1)/(crossValidationSamples-1)
+	}
+	return rounds
+}
+
+// crossValidate reads the sample rounds from every sharder and returns the sharders that differ from the majority
+func crossValidate(t *test.SystemTest, sharders []string, rounds []int64) []string {
+	var divergent []string
+	for _, round := range rounds {
+		digests := make([]string, len(sharders))
+		var wg sync.WaitGroup
+		for i, sharder := range sharders {
+			wg.Add(1)
+			go func(i int, sharder string) {
+				defer wg.Done()
+				data, err := fetchRoundData(sharder, round, round, false)
+				if err == nil {
+					digests[i] = data.digest()
+				}
+			}(i, sharder)
+		}
+		wg.Wait()
+
+		indexes, err := minority(digests)
+		require.NoError(t, err, "cross-validating round %d between sharders %v, digests %v", round, sharders, digests)
+		for _, i := range indexes {
+			t.Logf("sharder %s differs from the majority of sharders on round %d", sharders[i], round)
+			if !contains(divergent, sharders[i]) {
+				divergent = append(divergent, sharders[i])
+			}
+		}
+	}
+	return divergent
+}
+
+// minority returns the indexes of the digests that differ from the one shared by more than half of them, empty
+// digests are not counted. errNoMajority is returned if no digest is shared by more than half.
+func minority(digests []string) ([]int, error) {
+	counts := make(map[string]int)
+	var majority string
+	answered := 0
+	for _, digest := range digests {
+		if digest == "" {
+			continue
+		}
+		answered++
+		counts[digest]++
+		if counts[digest] > counts[majority] {
+			majority = digest
+		}
+	}
+	if answered == 0 {
+		return nil, nil
+	}
+	if 2*counts[majority] <= answered {
+		return nil, errNoMajority
+	}
+
+	var indexes []int
+	for i, digest := range digests {
+		if digest != "" && digest != majority {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes, nil
+}
+
+// digest hashes the blocks and rewards independently of the order the sharder returned them in
+func (d *roundData) digest() string {
+	var hashes []string
+	for i := range d.blocks {
+		hashes = append(hashes, strconv.FormatInt(d.blocks[i].Round, 10)+":"+d.blocks[i].Hash)
+	}
+	var rewards []string
+	for _, pr := range d.providerRewards {
+		rewards = append(rewards, fmt.Sprintf("p:%d:%s:%d:%d", pr.BlockNumber, pr.ProviderId, pr.RewardType, pr.Amount))
+	}
+	for _, dr := range d.delegateRewards {
+		rewards = append(rewards, fmt.Sprintf("d:%d:%s:%s:%d:%d", dr.BlockNumber, dr.ProviderID, dr.PoolID, dr.RewardType, dr.Amount))
+	}
+	sort.Strings(hashes)
+	sort.Strings(rewards)
+
+	encoded, _ := json.Marshal([][]string{hashes, rewards})
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:])
+}
+
+func blocksUrl(sharderBaseUrl string) string {
+	return sharderBaseUrl + "/v1/screst/" + StorageScAddress + "/get_blocks"
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package cliutils
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/0chain/system_test/internal/api/util/test"
+	"github.com/0chain/system_test/internal/cli/model"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSharder serves the history endpoints from rounds 1 to lastRound, paying each round's miner a block reward
+func fakeSharder(t *testing.T, lastRound int64, reward func(round int64) int64) string {
+	page := func(w http.ResponseWriter, r *http.Request, items func(round int64) []interface{}) {
+		query := r.URL.Query()
+		start, _ := strconv.ParseInt(query.Get("start"), 10, 64)
+		end, _ := strconv.ParseInt(query.Get("end"), 10, 64)
+		offset, _ := strconv.Atoi(query.Get("offset"))
+		limit, _ := strconv.Atoi(query.Get("limit"))
+
+		all := []interface{}{}
+		for round := start; round < end && round <= lastRound; round++ {
+			all = append(all, items(round)...)
+		}
+		if offset > len(all) {
+			offset = len(all)
+		}
+		all = all[offset:]
+		if limit > 0 && limit < len(all) {
+			all = all[:limit]
+		}
+		_ = json.NewEncoder(w).Encode(all)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v1/block/get/latest_finalized":
+			_ = json.NewEncoder(w).Encode(model.EventDBBlock{Round: lastRound, Hash: "hash" + strconv.FormatInt(lastRound, 10)})
+		case strings.HasSuffix(r.URL.Path, "/get_blocks"):
+			page(w, r, func(round int64) []interface{} {
+				return []interface{}{model.EventDBBlock{Round: round, Hash: "hash" + strconv.FormatInt(round, 10), MinerID: "miner"}}
+			})
+		case strings.HasSuffix(r.URL.Path, "/provider-rewards"):
+			page(w, r, func(round int64) []interface{} {
+				return []interface{}{model.RewardProvider{Amount: reward(round), BlockNumber: round, ProviderId: "miner"}}
+			})
+		case strings.HasSuffix(r.URL.Path, "/delegate-rewards"), strings.HasSuffix(r.URL.Path, "/transactions"):
+			page(w, r, func(round int64) []interface{} { return nil })
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestReadFromShardersCrossValidates(testSetup *testing.T) {
+	t := test.NewSystemTest(testSetup)
+	honest := func(round int64) int64 { return 100 }
+	sharders := []string{
+		fakeSharder(testSetup, 100, honest),
+		fakeSharder(testSetup, 100, honest),
+		fakeSharder(testSetup, 100, func(round int64) int64 { return 99 }),
+		fakeSharder(testSetup, 40, honest), // lagging behind, not used
+	}
+
+	history := NewHistory(10, 90).WithCache(nil)
+	history.ReadFromSharders(t, sharders, true)
+
+	require.Equal(t, []string{sharders[2]}, history.DivergentSharders())
+	for round := int64(10); round <= 90; round++ {
+		rh := history.RoundHistory(t, round)
+		require.Equal(t, "hash"+strconv.FormatInt(round, 10), rh.Block.Hash)
+		require.Len(t, rh.ProviderRewards, 1)
+		require.Equal(t, int64(100), rh.ProviderRewards[0].Amount, "round %d read from the divergent sharder", round)
+	}
+}
+
+func TestSplitRounds(t *testing.T) {
+	chunks := splitRounds(1, 100, []string{"a", "b", "c"})
+	require.Len(t, chunks, 3)
+	require.Equal(t, chunk{from: 1, to: 34, sharder: "a"}, chunks[0])
+	require.Equal(t, chunk{from: 35, to: 68, sharder: "b"}, chunks[1])
+	require.Equal(t, chunk{from: 69, to: 100, sharder: "c"}, chunks[2])
+
+	// small ranges are not split below a page
+	require.Equal(t, []chunk{{from: 5, to: 14, sharder: "a"}}, splitRounds(5, 14, []string{"a", "b"}))
+}
+
+func TestSampleRounds(t *testing.T) {
+	require.Equal(t, []int64{5, 6}, sampleRounds(5, 6))
+	require.Equal(t, []int64{10, 50, 90}, sampleRounds(10, 90))
+}
+
+func TestMinority(t *testing.T) {
+	for _, tc := range []struct {
+		digests []string
+		want    []int
+		err     error
+	}{
+		{digests: []string{"a", "a", "a"}},
+		{digests: []string{"a", "b", "a", ""}, want: []int{1}},
+		{digests: []string{"", "a"}},
+		{digests: []string{"", ""}},
+		{digests: []string{"a", "b"}, err: errNoMajority},
+		{digests: []string{"b", "a", "a", "b"}, err: errNoMajority},
+		{digests: []string{"a", "b", "c"}, err: errNoMajority},
+	} {
+		indexes, err := minority(tc.digests)
+		require.ErrorIs(t, err, tc.err, "%v", tc.digests)
+		require.Equal(t, tc.want, indexes, "%v", tc.digests)
+	}
+}
+
+func TestRefetchSkipsDivergentSharders(t *testing.T) {
+	honest := func(round int64) int64 { return 100 }
+	sharders := []string{
+		fakeSharder(t, 100, honest),
+		fakeSharder(t, 100, func(round int64) int64 { return 99 }),
+		fakeSharder(t, 100, func(round int64) int64 { return 98 }),
+	}
+
+	// every other sharder diverges, the chunk must not keep the data it was read with
+	c := chunk{from: 1, to: 10, sharder: sharders[0], data: &roundData{}}
+	require.Error(t, refetch(&c, sharders, []string{sharders[1], sharders[2]}, false))
+	require.Nil(t, c.data)
+
+	c = chunk{from: 1, to: 10, sharder: sharders[1], data: &roundData{}}
+	require.NoError(t, refetch(&c, sharders, []string{sharders[1]}, false))
+	require.Equal(t, sharders[0], c.sharder)
+	require.Len(t, c.data.providerRewards, 10)
+	require.Equal(t, int64(100), c.data.providerRewards[0].Amount)
+}
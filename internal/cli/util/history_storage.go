@@ -0,0 +1,171 @@
+package cliutils
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/0chain/system_test/internal/api/util/test"
+	"github.com/0chain/system_test/internal/cli/model"
+	"github.com/stretchr/testify/require"
+)
+
+// txSuccessful is the status of a transaction that was applied
+const txSuccessful = 1
+
+// AllocationEvent is a successful storage smart contract transaction that changed the state of an allocation
+type AllocationEvent struct {
+	Round           int64  `json:"round"`
+	AllocationID    string `json:"allocation_id"`
+	Function        string `json:"function"`
+	ClientID        string `json:"client_id"`
+	TransactionHash string `json:"transaction_hash"`
+	Value           int64  `json:"value"`
+}
+
+// allocationFunctions maps the storage smart contract functions changing an allocation to the
+// input field holding the allocation id, empty if the allocation id is the transaction hash
+var allocationFunctions = map[string]string{
+	"new_allocation_request":    "",
+	"free_allocation_request":   "",
+	"update_allocation_request": "id",
+	"free_update_allocation":    "allocation_id",
+	"cancel_allocation":         "allocation_id",
+	"finalize_allocation":       "allocation_id",
+	"write_pool_lock":           "allocation_id",
+}
+
+// LoadChallenges attaches the challenges of the allocations created in the rounds of the history to their round
+func (ch *ChainHistory) LoadChallenges(t *test.SystemTest, sharderBaseUrl string, allocationIDs ...string) {
+	ch.challenges = nil
+	for _, allocationID := range allocationIDs {
+		challenges, err := ApiGetError[[]model.Challenge](
+			sharderBaseUrl+"/v1/screst/"+StorageScAddress+"/all-challenges", map[string]string{"allocation_id": allocationID})
+		require.NoError(t, err, "reading challenges of allocation %s", allocationID)
+		for _, c := range *challenges {
+			if ch.inRange(c.RoundCreatedAt) {
+				ch.challenges = append(ch.challenges, c)
+			}
+		}
+	}
+	sort.SliceStable(ch.challenges, func(i, j int) bool {
+		return ch.challenges[i].RoundCreatedAt < ch.challenges[j].RoundCreatedAt
+	})
+	ch.setup(t)
+}
+
+// LoadReadMarkers attaches the read markers of the allocations redeemed in the rounds of the history to their round
+func (ch *ChainHistory) LoadReadMarkers(t *test.SystemTest, sharderBaseUrl string, allocationIDs ...string) {
+	ch.readMarkers = nil
+	for _, allocationID := range allocationIDs {
+		readMarkers, err := ApiGetListError[model.ReadMarker](
+			sharderBaseUrl+"/v1/screst/"+StorageScAddress+"/readmarkers", map[string]string{"allocation_id": allocationID}, ch.from, ch.to+1)
+		require.NoError(t, err, "reading read markers of allocation %s", allocationID)
+		for _, rm := range readMarkers {
+			if ch.inRange(rm.BlockNumber) {
+				ch.readMarkers = append(ch.readMarkers, rm)
+			}
+		}
+	}
+	sort.SliceStable(ch.readMarkers, func(i, j int) bool {
+		return ch.readMarkers[i].BlockNumber < ch.readMarkers[j].BlockNumber
+	})
+	ch.setup(t)
+}
+
+// LoadAllocationEvents attaches the state changes of the allocations, all allocations if none are given, to the
+// round of the transaction. The transactions are read from the sharder unless the history was read with them.
+func (ch *ChainHistory) LoadAllocationEvents(t *test.SystemTest, sharderBaseUrl string, allocationIDs ...string) {
+	transactions := ch.transactions
+	if len(transactions) == 0 {
+		var err error
+		transactions, err = ApiGetListError[model.EventDBTransaction](
+			sharderBaseUrl+"/v1/screst/"+StorageScAddress+"/transactions", map[string]string{}, ch.from, ch.to+1)
+		require.NoError(t, err, "reading transactions of rounds %d to %d", ch.from, ch.to)
+	}
+
+	ch.allocationEvents = nil
+	for _, tx := range transactions {
+		event, ok := allocationEvent(tx)
+		if ok && ch.inRange(event.Round) && (len(allocationIDs) == 0 || contains(allocationIDs, event.AllocationID)) {
+			ch.allocationEvents = append(ch.allocationEvents, event)
+		}
+	}
+	sort.SliceStable(ch.allocationEvents, func(i, j int) bool {
+		return ch.allocationEvents[i].Round < ch.allocationEvents[j].Round
+	})
+	ch.setup(t)
+}
+
+// Challenges returns the loaded challenges, in order of creation round
+func (ch *ChainHistory) Challenges() []model.Challenge {
+	return ch.challenges
+}
+
+// ReadMarkers returns the loaded read markers, in round order
+func (ch *ChainHistory) ReadMarkers() []model.ReadMarker {
+	return ch.readMarkers
+}
+
+// AllocationEvents returns the loaded allocation state changes, in round order
+func (ch *ChainHistory) AllocationEvents() []AllocationEvent {
+	return ch.allocationEvents
+}
+
+// setupStorage attaches the loaded challenges, read markers and allocation events to the round histories
+func (ch *ChainHistory) setupStorage() {
+	for _, c := range ch.challenges {
+		if rh, ok := ch.roundHistories[c.RoundCreatedAt]; ok {
+			rh.Challenges = append(rh.Challenges, c)
+			ch.roundHistories[c.RoundCreatedAt] = rh
+		}
+	}
+	for _, rm := range ch.readMarkers {
+		if rh, ok := ch.roundHistories[rm.BlockNumber]; ok {
+			rh.ReadMarkers = append(rh.ReadMarkers, rm)
+			ch.roundHistories[rm.BlockNumber] = rh
+		}
+	}
+	for _, event := range ch.allocationEvents {
+		if rh, ok := ch.roundHistories[event.Round]; ok {
+			rh.AllocationEvents = append(rh.AllocationEvents, event)
+			ch.roundHistories[event.Round] = rh
+		}
+	}
+}
+
+func (ch *ChainHistory) inRange(round int64) bool {
+	return round >= ch.from && round <= ch.to
+}
+
+// allocationEvent returns the allocation state change made by the transaction, if any
+func allocationEvent(tx model.EventDBTransaction) (AllocationEvent, bool) {
+	if tx.Status != txSuccessful || tx.ToClientId != StorageScAddress {
+		return AllocationEvent{}, false
+	}
+	var data struct {
+		Name  string                     `json:"name"`
+		Input map[string]json.RawMessage `json:"input"`
+	}
+	if err := json.Unmarshal([]byte(tx.TransactionData), &data); err != nil {
+		return AllocationEvent{}, false
+	}
+	field, ok := allocationFunctions[data.Name]
+	if !ok {
+		return AllocationEvent{}, false
+	}
+
+	allocationID := tx.Hash
+	if field != "" {
+		if err := json.Unmarshal(data.Input[field], &allocationID); err != nil || allocationID == "" {
+			return AllocationEvent{}, false
+		}
+	}
+	return AllocationEvent{
+		Round:           tx.Round,
+		AllocationID:    allocationID,
+		Function:        data.Name,
+		ClientID:        tx.ClientId,
+		TransactionHash: tx.Hash,
+		Value:           tx.Value,
+	}, true
+}
@@ -0,0 +1,77 @@
+package cliutils
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/0chain/system_test/internal/api/util/test"
+	"github.com/0chain/system_test/internal/cli/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadStorageHistory(testSetup *testing.T) {
+	t := test.NewSystemTest(testSetup)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(testSetup, "alloc", r.URL.Query().Get("allocation_id"))
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/all-challenges"):
+			_ = json.NewEncoder(w).Encode([]model.Challenge{
+				{ChallengeID: "late", AllocationID: "alloc", RoundCreatedAt: 3, RoundResponded: 3, Passed: true},
+				{ChallengeID: "early", AllocationID: "alloc", RoundCreatedAt: 1},
+				{ChallengeID: "outside", AllocationID: "alloc", RoundCreatedAt: 9},
+			})
+		case strings.HasSuffix(r.URL.Path, "/readmarkers"):
+			_ = json.NewEncoder(w).Encode([]model.ReadMarker{{AllocationID: "alloc", BlockNumber: 2, ReadCounter: 4}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	var rounds []RoundHistory
+	for round := int64(1); round <= 3; round++ {
+		rounds = append(rounds, RoundHistory{Block: &model.EventDBBlock{Round: round, Hash: "hash"}})
+	}
+	rounds[0].Transactions = []model.EventDBTransaction{
+		{Hash: "alloc", Round: 1, ToClientId: StorageScAddress, Status: txSuccessful, Value: 10,
+			TransactionData: `{"name":"new_allocation_request","input":{"size":1024}}`},
+		{Hash: "failed", Round: 1, ToClientId: StorageScAddress, Status: txSuccessful + 1,
+			TransactionData: `{"name":"cancel_allocation","input":{"allocation_id":"alloc"}}`},
+	}
+	rounds[2].Transactions = []model.EventDBTransaction{
+		{Hash: "update", Round: 3, ToClientId: StorageScAddress, Status: txSuccessful,
+			TransactionData: `{"name":"update_allocation_request","input":{"id":"alloc","size":2048}}`},
+		{Hash: "other", Round: 3, ToClientId: StorageScAddress, Status: txSuccessful,
+			TransactionData: `{"name":"cancel_allocation","input":{"allocation_id":"other"}}`},
+		{Hash: "read", Round: 3, ToClientId: StorageScAddress, Status: txSuccessful,
+			TransactionData: `{"name":"read_redeem","input":{}}`},
+	}
+	history := NewHistoryFromRounds(t, 1, 3, rounds)
+
+	history.LoadChallenges(t, server.URL, "alloc")
+	history.LoadReadMarkers(t, server.URL, "alloc")
+	history.LoadAllocationEvents(t, server.URL, "alloc")
+
+	require.Len(t, history.Challenges(), 2)
+	require.Equal(t, "early", history.RoundHistory(t, 1).Challenges[0].ChallengeID)
+	require.Equal(t, "late", history.RoundHistory(t, 3).Challenges[0].ChallengeID)
+	require.Empty(t, history.RoundHistory(t, 2).Challenges)
+
+	require.Len(t, history.RoundHistory(t, 2).ReadMarkers, 1)
+	require.Equal(t, int64(4), history.RoundHistory(t, 2).ReadMarkers[0].ReadCounter)
+
+	require.Equal(t, []AllocationEvent{
+		{Round: 1, AllocationID: "alloc", Function: "new_allocation_request", TransactionHash: "alloc", Value: 10},
+		{Round: 3, AllocationID: "alloc", Function: "update_allocation_request", TransactionHash: "update"},
+	}, history.AllocationEvents())
+	require.Len(t, history.RoundHistory(t, 3).AllocationEvents, 1)
+
+	dir := testSetup.TempDir()
+	require.NoError(t, history.Export(dir))
+	imported := ImportHistory(t, dir)
+	require.Equal(t, history.Challenges(), imported.Challenges())
+	require.Equal(t, history.RoundHistory(t, 2), imported.RoundHistory(t, 2))
+}
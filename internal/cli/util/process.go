@@ -0,0 +1,99 @@
+package cliutils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/0chain/system_test/internal/api/util/test"
+	"github.com/0chain/system_test/internal/cli/util/specific"
+)
+
+// CommandTimeoutEnv overrides the default deadline of a command, as a duration such as "15m"
+const CommandTimeoutEnv = "CLI_COMMAND_TIMEOUT"
+
+// CommandTimeout is the deadline of a command unless Command.Timeout is set, after which it is stopped
+var CommandTimeout = commandTimeout()
+
+// QuitGracePeriod is the time a command has to print its goroutines and exit after SIGQUIT before it is killed
+var QuitGracePeriod = 5 * time.Second
+
+// ErrCommandTimeout is returned for commands stopped at their deadline
+var ErrCommandTimeout = errors.New("command timed out")
+
+func commandTimeout() time.Duration {
+	if value := os.Getenv(CommandTimeoutEnv); value != "" {
+		if timeout, err := time.ParseDuration(value); err == nil {
+			return timeout
+		}
+		Logger.Warnf("ignoring invalid %s %q", CommandTimeoutEnv, value)
+	}
+	return 10 * time.Minute
+}
+
+// runWithDeadline runs the command in its own process group and stops the group if it does not exit in time.
+// The group first gets SIGQUIT, so that the goroutine dump of zbox or zwallet ends up in stderr, and is killed
+// if it still runs after QuitGracePeriod. A timeout of 0 means no deadline.
+func runWithDeadline(cmd *exec.Cmd, timeout time.Duration) (timedOut bool, err error) {
+	specific.Setpgid(cmd)
+	// children holding stdout or stderr open must not block Wait once the command is stopped
+	cmd.WaitDelay = QuitGracePeriod
+	if err := cmd.Start(); err != nil {
+		return false, err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+	if timeout <= 0 {
+		return false, <-done
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return false, err
+	case <-timer.C:
+	}
+
+	err = stop(cmd, done)
+	return true, fmt.Errorf("%w after %s: %v", ErrCommandTimeout, timeout, err)
+}
+
+// stop quits the process group of a running command, kills it if it does not exit in time and returns the result of
+// waiting for the command, which done delivers
+func stop(cmd *exec.Cmd, done <-chan error) error {
+	_ = specific.Quit(cmd)
+	select {
+	case err := <-done:
+		// children of the command may still run
+		_ = specific.Kill(cmd)
+		return err
+	case <-time.After(QuitGracePeriod):
+	}
+	_ = specific.Kill(cmd)
+	return <-done
+}
+
+// reapOnCleanup stops a command started in the background at the end of the test, unless it already exited,
+// and waits for it so that no process outlives the test. The invocation of the command is completed with its result.
+func reapOnCleanup(t *test.SystemTest, cmd *exec.Cmd, invocation *Invocation, stdout, stderr *lockedBuffer) {
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	t.Cleanup(func() {
+		var err error
+		select {
+		case err = <-done:
+		default:
+			err = stop(cmd, done)
+		}
+		invocation.finish(cmd, err, stdout.Bytes(), stderr.Bytes())
+	})
+}
@@ -0,0 +1,56 @@
+package cliutils
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/0chain/system_test/internal/api/util/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandTimeout(t *testing.T) {
+	t.Setenv(test.ArtifactsDirEnv, t.TempDir())
+	st := test.NewSystemTest(t)
+
+	// like zbox, the command prints its state on SIGQUIT
+	cmd := Command{Program: "sh", Args: []string{"-c", `trap "echo dumping goroutines >&2; exit 2" QUIT; while :; do sleep 0.1; done`}, Timeout: 200 * time.Millisecond}
+	start := time.Now()
+	output, err := cmd.RunWithoutRetry(st)
+	require.True(t, errors.Is(err, ErrCommandTimeout), "got %v", err)
+	require.Less(t, time.Since(start), QuitGracePeriod)
+	require.Contains(t, output, "dumping goroutines")
+
+	value, ok := transcripts.Load(t)
+	require.True(t, ok)
+	invocation := value.(*transcript).invocations[0]
+	require.True(t, invocation.TimedOut)
+	require.Contains(t, invocation.Stderr, "dumping goroutines")
+}
+
+func TestCommandKilledAfterGracePeriod(t *testing.T) {
+	t.Setenv(test.ArtifactsDirEnv, t.TempDir())
+	defer func(grace time.Duration) { QuitGracePeriod = grace }(QuitGracePeriod)
+	QuitGracePeriod = 100 * time.Millisecond
+
+	cmd := Command{Program: "sh", Args: []string{"-c", `trap "" QUIT; sleep 30`}, Timeout: 100 * time.Millisecond}
+	start := time.Now()
+	_, err := cmd.RunWithoutRetry(test.NewSystemTest(t))
+	require.True(t, errors.Is(err, ErrCommandTimeout), "got %v", err)
+	require.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestStartCommandReapedAtCleanup(t *testing.T) {
+	t.Setenv(test.ArtifactsDirEnv, t.TempDir())
+
+	var cmd *exec.Cmd
+	t.Run("background", func(inner *testing.T) {
+		var err error
+		cmd, err = StartCommandWithoutRetry(test.NewSystemTest(inner), "sleep 30")
+		require.NoError(inner, err)
+	})
+
+	require.NotNil(t, cmd.ProcessState, "the command is waited for")
+	require.False(t, cmd.ProcessState.Success())
+}
@@ -0,0 +1,463 @@
+package rewards
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/0chain/system_test/internal/api/util/test"
+	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliutil "github.com/0chain/system_test/internal/cli/util"
+	"github.com/0chain/system_test/internal/currency"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+// Config is the part of the miner smart contract config that determines block and fee rewards
+type Config struct {
+	Epoch                       int64
+	RewardDeclineRate           float64
+	BlockReward                 currency.Coin
+	ShareRatio                  float64
+	NumMinerDelegatesRewarded   int
+	NumShardersRewarded         int
+	NumSharderDelegatesRewarded int
+}
+
+// ConfigFromMap reads the config from the numeric settings printed by `zwallet mn-config`
+func ConfigFromMap(minerScConfig map[string]float64) Config {
+	return Config{
+		Epoch:                       int64(minerScConfig["epoch"]),
+		RewardDeclineRate:           minerScConfig["reward_decline_rate"],
+		BlockReward:                 currency.Coin(currency.ZCNToSAS(minerScConfig["block_reward"])),
+		ShareRatio:                  minerScConfig["share_ratio"],
+		NumMinerDelegatesRewarded:   int(minerScConfig["num_miner_delegates_rewarded"]),
+		NumShardersRewarded:         int(minerScConfig["num_sharders_rewarded"]),
+		NumSharderDelegatesRewarded: int(minerScConfig["num_sharder_delegates_rewarded"]),
+	}
+}
+
+// BlockRewards returns the miner and sharder shares of the block reward of round
+func (c Config) BlockRewards(round int64) (minerReward, sharderReward int64) {
+	var epoch int64
+	if c.Epoch > 0 {
+		epoch = round / c.Epoch
+	}
+	decline := decimal.NewFromInt(1).Sub(decimal.NewFromFloat(c.RewardDeclineRate)).Pow(decimal.NewFromInt(epoch))
+	blockReward := c.BlockReward.Decimal(currency.SAS).Mul(decline).IntPart()
+	minerReward = share(blockReward, decimal.NewFromFloat(c.ShareRatio))
+	sharderReward = blockReward - minerReward
+	return minerReward, sharderReward
+}
+
+// share returns the part ratio of amount, truncating the fractional SAS the same way the chain does
+func share(amount int64, ratio decimal.Decimal) int64 {
+	return decimal.NewFromInt(amount).Mul(ratio).IntPart()
+}
+
+// Discrepancy is a difference between the expected and the recorded rewards.
+// Round is zero for discrepancies over the whole period, PoolID is empty for provider rewards.
+type Discrepancy struct {
+	Round      int64
+	ProviderID string
+	PoolID     string
+	RewardType climodel.Reward
+	Expected   int64
+	Actual     int64
+	Reason     string
+}
+
+// Report lists the discrepancies found by Reconcile, ordered by round and provider
+type Report struct {
+	From, To      int64
+	Discrepancies []Discrepancy
+}
+
+func (r *Report) String() string {
+	if len(r.Discrepancies) == 0 {
+		return fmt.Sprintf("rewards of rounds %d to %d reconciled", r.From, r.To)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d reward discrepancies in rounds %d to %d\n", len(r.Discrepancies), r.From, r.To)
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "round\tprovider\tpool\treward\texpected\tactual\treason")
+	for _, d := range r.Discrepancies {
+		round := "all"
+		if d.Round > 0 {
+			round = fmt.Sprint(d.Round)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d\t%s\n",
+			round, d.ProviderID, d.PoolID, d.RewardType, d.Expected, d.Actual, d.Reason)
+	}
+	_ = w.Flush()
+	return b.String()
+}
+
+// Require fails t if there are discrepancies, listing all of them
+func (r *Report) Require(t *test.SystemTest) {
+	t.Log(r.String())
+	require.Empty(t, r.Discrepancies, r.String())
+}
+
+type providerKind int
+
+const (
+	miner providerKind = iota
+	sharder
+)
+
+type provider struct {
+	kind          providerKind
+	before, after climodel.Node
+}
+
+// Engine computes the rewards expected from a ChainHistory and the miner smart contract config,
+// and diffs them against the provider and delegate rewards recorded in that history
+type Engine struct {
+	history   *cliutil.ChainHistory
+	config    Config
+	providers []provider
+
+	// Tolerance is the absolute difference in SAS accepted for each reward, to allow for rounding
+	Tolerance int64
+}
+
+// New creates an engine reconciling the rewards in history
+func New(history *cliutil.ChainHistory, config Config) *Engine {
+	return &Engine{history: history, config: config, Tolerance: 1}
+}
+
+// AddMiners adds the miners to reconcile, before and after are their snapshots around the history, in the same order
+func (e *Engine) AddMiners(before, after []climodel.Node) *Engine {
+	return e.add(miner, before, after)
+}
+
+// AddSharders adds the sharders to reconcile, before and after are their snapshots around the history, in the same order
+func (e *Engine) AddSharders(before, after []climodel.Node) *Engine {
+	return e.add(sharder, before, after)
+}
+
+func (e *Engine) add(kind providerKind, before, after []climodel.Node) *Engine {
+	for i := range before {
+		e.providers = append(e.providers, provider{kind: kind, before: before[i], after: after[i]})
+	}
+	return e
+}
+
+func (e *Engine) numShardersRewarded() int {
+	var sharders int
+	for _, p := range e.providers {
+		if p.kind == sharder {
+			sharders++
+		}
+	}
+	if e.config.NumShardersRewarded < sharders {
+		return e.config.NumShardersRewarded
+	}
+	return sharders
+}
+
+// Reconcile checks every round of the history and returns the discrepancies found.
+//
+// For each provider, each round between its before and after snapshots is checked:
+// the winning miner receives its service charge of the miner block reward and fees, each rewarded sharder
+// its service charge of an even split of the sharder block reward and fees, and the remainder is split
+// between the rewarded delegate pools in proportion to their stake. The change in each provider and pool
+// reward between the snapshots must equal the rewards recorded.
+//
+// The number of miners and sharders paid is checked on all but the first and last rounds,
+// whose rewards may be recorded outside the history.
+func (e *Engine) Reconcile(t *test.SystemTest) *Report {
+	report := &Report{From: e.history.From(), To: e.history.To()}
+	numShardersRewarded := e.numShardersRewarded()
+
+	for round := e.history.From() + 1; round < e.history.To(); round++ {
+		e.countRound(t, report, round, numShardersRewarded)
+	}
+
+	for _, p := range e.providers {
+		providerTotal, poolTotals := e.reconcileProvider(t, report, p, numShardersRewarded)
+
+		id := p.before.ID
+		if actual := p.after.Reward - p.before.Reward; !e.within(actual, providerTotal) {
+			report.add(Discrepancy{ProviderID: id, Expected: providerTotal, Actual: actual,
+				Reason: "change in provider reward differs from rewards recorded"})
+		}
+		for poolID, pool := range p.after.Pools {
+			var beforeReward int64
+			if beforePool, ok := p.before.Pools[poolID]; ok {
+				beforeReward = beforePool.Reward
+			}
+			if actual := pool.Reward - beforeReward; !e.within(actual, poolTotals[poolID]) {
+				report.add(Discrepancy{ProviderID: id, PoolID: poolID, Expected: poolTotals[poolID], Actual: actual,
+					Reason: "change in pool reward differs from rewards recorded"})
+			}
+		}
+	}
+
+	sort.SliceStable(report.Discrepancies, func(i, j int) bool {
+		a, b := report.Discrepancies[i], report.Discrepancies[j]
+		if a.Round != b.Round {
+			return a.Round < b.Round
+		}
+		return a.ProviderID < b.ProviderID
+	})
+	return report
+}
+
+// countRound checks the number of block and fee rewards paid in round
+func (e *Engine) countRound(t *test.SystemTest, report *Report, round int64, numShardersRewarded int) {
+	roundHistory := e.history.RoundHistory(t, round)
+	hasFees := e.history.FeesForRound(t, round) > 0
+
+	paid := make(map[climodel.Reward]map[string]int)
+	for _, pReward := range roundHistory.ProviderRewards {
+		if paid[pReward.RewardType] == nil {
+			paid[pReward.RewardType] = make(map[string]int)
+		}
+		paid[pReward.RewardType][pReward.ProviderId]++
+	}
+	for rewardType, providers := range paid {
+		for id, count := range providers {
+			if count > 1 {
+				report.add(Discrepancy{Round: round, ProviderID: id, RewardType: rewardType, Expected: 1, Actual: int64(count),
+					Reason: "provider paid more than once"})
+			}
+		}
+	}
+
+	// only the kinds of provider being reconciled are counted, as sharder rounds can be read without miners
+	expected := make(map[climodel.Reward]int)
+	if e.has(miner) {
+		expected[climodel.BlockRewardMiner] = 1
+		if hasFees {
+			expected[climodel.FeeRewardMiner] = 1
+		}
+	}
+	if e.has(sharder) {
+		expected[climodel.BlockRewardSharder] = numShardersRewarded
+		if hasFees {
+			expected[climodel.FeeRewardSharder] = numShardersRewarded
+		}
+	}
+	for _, rewardType := range []climodel.Reward{
+		climodel.BlockRewardMiner, climodel.BlockRewardSharder, climodel.FeeRewardMiner, climodel.FeeRewardSharder,
+	} {
+		if !e.counts(rewardType) {
+			continue
+		}
+		if actual := len(paid[rewardType]); actual != expected[rewardType] {
+			report.add(Discrepancy{Round: round, RewardType: rewardType, Expected: int64(expected[rewardType]), Actual: int64(actual),
+				Reason: "number of providers paid"})
+		}
+	}
+}
+
+func (e *Engine) has(kind providerKind) bool {
+	for _, p := range e.providers {
+		if p.kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Engine) counts(rewardType climodel.Reward) bool {
+	switch rewardType {
+	case climodel.BlockRewardMiner, climodel.FeeRewardMiner:
+		return e.has(miner)
+	case climodel.BlockRewardSharder, climodel.FeeRewardSharder:
+		return e.has(sharder)
+	}
+	return false
+}
+
+// reconcileProvider checks the rewards of p round by round and returns the totals recorded for it and its pools
+func (e *Engine) reconcileProvider(
+	t *test.SystemTest, report *Report, p provider, numShardersRewarded int,
+) (providerTotal int64, poolTotals map[string]int64) {
+	id := p.before.ID
+	poolTotals = make(map[string]int64, len(p.after.Pools))
+
+	blockType, feeType := climodel.BlockRewardMiner, climodel.FeeRewardMiner
+	numDelegates := e.config.NumMinerDelegatesRewarded
+	if p.kind == sharder {
+		blockType, feeType = climodel.BlockRewardSharder, climodel.FeeRewardSharder
+		numDelegates = e.config.NumSharderDelegatesRewarded
+	}
+	payAllToProvider := len(p.before.Pools) == 0 || numDelegates == 0
+
+	from := max(p.before.RoundServiceChargeLastUpdated+1, e.history.From())
+	to := min(p.after.RoundServiceChargeLastUpdated, e.history.To())
+	for round := from; round <= to; round++ {
+		roundHistory := e.history.RoundHistory(t, round)
+		recorded := providerRewards(report, round, p, roundHistory, blockType, feeType)
+		delegates := delegateRewards(report, round, p, roundHistory, blockType, feeType, poolTotals)
+		for _, amount := range recorded {
+			providerTotal += amount
+		}
+
+		// the total each reward type pays to this provider and its delegates, zero if it was not rewarded
+		fees := e.history.FeesForRound(t, round)
+		minerReward, sharderReward := e.config.BlockRewards(round)
+		shareRatio := decimal.NewFromFloat(e.config.ShareRatio)
+		var blockTotal, feeTotal int64
+		switch {
+		case p.kind == miner && roundHistory.Block.MinerID == id:
+			blockTotal = minerReward
+			feeTotal = share(fees, shareRatio)
+		case p.kind == sharder && numShardersRewarded > 0:
+			// sharders are chosen at random, so only the amount paid to the chosen ones can be checked
+			sharders := decimal.NewFromInt(int64(numShardersRewarded))
+			if _, paid := recorded[blockType]; paid || delegates[blockType] != nil {
+				blockTotal = decimal.NewFromInt(sharderReward).Div(sharders).IntPart()
+			}
+			if _, paid := recorded[feeType]; paid || delegates[feeType] != nil {
+				feeTotal = share(fees, decimal.NewFromInt(1).Sub(shareRatio).Div(sharders))
+			}
+		}
+
+		for _, expected := range []struct {
+			rewardType climodel.Reward
+			total      int64
+		}{{blockType, blockTotal}, {feeType, feeTotal}} {
+			expectedServiceCharge, delegateTotal := expected.total, int64(0)
+			if !payAllToProvider {
+				serviceCharge := decimal.NewFromFloat(p.before.Settings.ServiceCharge)
+				expectedServiceCharge = share(expected.total, serviceCharge)
+				delegateTotal = share(expected.total, decimal.NewFromInt(1).Sub(serviceCharge))
+			}
+			if actual := recorded[expected.rewardType]; !e.within(actual, expectedServiceCharge) {
+				report.add(Discrepancy{Round: round, ProviderID: id, RewardType: expected.rewardType, Expected: expectedServiceCharge,
+					Actual: actual, Reason: "service charge"})
+			}
+			e.reconcileDelegates(report, round, id, expected.rewardType, delegateTotal, delegates[expected.rewardType],
+				p.before.Pools, numDelegates)
+		}
+	}
+
+	return providerTotal, poolTotals
+}
+
+// providerRewards returns the block and fee rewards recorded for p in the round, by reward type
+func providerRewards(
+	report *Report, round int64, p provider, roundHistory cliutil.RoundHistory, blockType, feeType climodel.Reward,
+) map[climodel.Reward]int64 {
+	id := p.before.ID
+	recorded := make(map[climodel.Reward]int64)
+	for _, pReward := range roundHistory.ProviderRewards {
+		if pReward.ProviderId != id {
+			continue
+		}
+		if pReward.RewardType != blockType && pReward.RewardType != feeType {
+			report.add(Discrepancy{Round: round, ProviderID: id, RewardType: pReward.RewardType, Actual: pReward.Amount,
+				Reason: "reward type not paid to this provider type"})
+			continue
+		}
+		if p.before.IsKilled {
+			report.add(Discrepancy{Round: round, ProviderID: id, RewardType: pReward.RewardType, Actual: pReward.Amount,
+				Reason: "killed provider rewarded"})
+		}
+		recorded[pReward.RewardType] += pReward.Amount
+	}
+	return recorded
+}
+
+// delegateRewards returns the rewards recorded for the pools of p in the round, by reward type and pool,
+// and adds them to poolTotals
+func delegateRewards(
+	report *Report,
+	round int64,
+	p provider,
+	roundHistory cliutil.RoundHistory,
+	blockType, feeType climodel.Reward,
+	poolTotals map[string]int64,
+) map[climodel.Reward]map[string]int64 {
+	id := p.before.ID
+	delegates := make(map[climodel.Reward]map[string]int64)
+	for _, dReward := range roundHistory.DelegateRewards {
+		if dReward.ProviderID != id {
+			continue
+		}
+		if _, ok := p.after.Pools[dReward.PoolID]; !ok {
+			report.add(Discrepancy{Round: round, ProviderID: id, PoolID: dReward.PoolID, RewardType: dReward.RewardType,
+				Actual: dReward.Amount, Reason: "reward paid to unknown pool"})
+			continue
+		}
+		poolTotals[dReward.PoolID] += dReward.Amount
+		if dReward.RewardType != blockType && dReward.RewardType != feeType {
+			report.add(Discrepancy{Round: round, ProviderID: id, PoolID: dReward.PoolID, RewardType: dReward.RewardType,
+				Actual: dReward.Amount, Reason: "reward type not paid to this provider type"})
+			continue
+		}
+		if delegates[dReward.RewardType] == nil {
+			delegates[dReward.RewardType] = make(map[string]int64)
+		}
+		if _, found := delegates[dReward.RewardType][dReward.PoolID]; found {
+			report.add(Discrepancy{Round: round, ProviderID: id, PoolID: dReward.PoolID, RewardType: dReward.RewardType,
+				Actual: dReward.Amount, Reason: "pool paid more than once"})
+		}
+		delegates[dReward.RewardType][dReward.PoolID] += dReward.Amount
+	}
+	return delegates
+}
+
+// reconcileDelegates checks that numDelegates pools, or all if fewer, were paid total in proportion to their stake
+func (e *Engine) reconcileDelegates(
+	report *Report,
+	round int64,
+	providerID string,
+	rewardType climodel.Reward,
+	total int64,
+	paid map[string]int64,
+	pools map[string]*climodel.DelegatePool,
+	numDelegates int,
+) {
+	if total == 0 {
+		for poolID, amount := range paid {
+			report.add(Discrepancy{Round: round, ProviderID: providerID, PoolID: poolID, RewardType: rewardType,
+				Actual: amount, Reason: "pool paid without a reward to share"})
+		}
+		return
+	}
+
+	if numDelegates > len(pools) {
+		numDelegates = len(pools)
+	}
+	if len(paid) != numDelegates {
+		report.add(Discrepancy{Round: round, ProviderID: providerID, RewardType: rewardType,
+			Expected: int64(numDelegates), Actual: int64(len(paid)), Reason: "number of pools paid"})
+	}
+
+	var stake int64
+	for poolID := range paid {
+		if pool, ok := pools[poolID]; ok {
+			stake += pool.Balance
+		}
+	}
+	for poolID, amount := range paid {
+		pool, ok := pools[poolID]
+		if !ok || stake == 0 {
+			continue
+		}
+		expected := decimal.NewFromInt(total).Mul(decimal.NewFromInt(pool.Balance)).Div(decimal.NewFromInt(stake)).IntPart()
+		if !e.within(amount, expected) {
+			report.add(Discrepancy{Round: round, ProviderID: providerID, PoolID: poolID, RewardType: rewardType,
+				Expected: expected, Actual: amount, Reason: "pool share not in proportion to stake"})
+		}
+	}
+}
+
+func (e *Engine) within(actual, expected int64) bool {
+	diff := actual - expected
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= e.Tolerance
+}
+
+func (r *Report) add(d Discrepancy) {
+	r.Discrepancies = append(r.Discrepancies, d)
+}
@@ -0,0 +1,124 @@
+package rewards
+
+import (
+	"testing"
+
+	"github.com/0chain/system_test/internal/api/util/test"
+	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliutil "github.com/0chain/system_test/internal/cli/util"
+	"github.com/stretchr/testify/require"
+)
+
+var testConfig = Config{
+	Epoch:                       1000,
+	BlockReward:                 1e9,
+	ShareRatio:                  0.5,
+	NumMinerDelegatesRewarded:   2,
+	NumShardersRewarded:         1,
+	NumSharderDelegatesRewarded: 0,
+}
+
+// testRounds pays block rewards of 1e9 SAS each round: the miner keeps a service charge of 0.2
+// of its 5e8 share and splits the rest 1:3 between its pools, the sharder has no pools and keeps 5e8.
+func testRounds(from, to int64) []cliutil.RoundHistory {
+	var rounds []cliutil.RoundHistory
+	for round := from; round <= to; round++ {
+		rounds = append(rounds, cliutil.RoundHistory{
+			Block: &climodel.EventDBBlock{Round: round, MinerID: "miner"},
+			ProviderRewards: []climodel.RewardProvider{
+				{Amount: 1e8, BlockNumber: round, ProviderId: "miner", RewardType: climodel.BlockRewardMiner},
+				{Amount: 5e8, BlockNumber: round, ProviderId: "sharder", RewardType: climodel.BlockRewardSharder},
+			},
+			DelegateRewards: []climodel.RewardDelegate{
+				{Amount: 1e8, BlockNumber: round, PoolID: "pool1", ProviderID: "miner", RewardType: climodel.BlockRewardMiner},
+				{Amount: 3e8, BlockNumber: round, PoolID: "pool2", ProviderID: "miner", RewardType: climodel.BlockRewardMiner},
+			},
+		})
+	}
+	return rounds
+}
+
+func testNodes(rounds int64) (before, after []climodel.Node) {
+	miner := func(lastUpdated, reward, pool1Reward, pool2Reward int64) climodel.Node {
+		var node climodel.Node
+		node.ID = "miner"
+		node.RoundServiceChargeLastUpdated = lastUpdated
+		node.Reward = reward
+		node.Settings.ServiceCharge = 0.2
+		node.Pools = map[string]*climodel.DelegatePool{
+			"pool1": {Balance: 1e10, Reward: pool1Reward},
+			"pool2": {Balance: 3e10, Reward: pool2Reward},
+		}
+		return node
+	}
+	sharder := func(lastUpdated, reward int64) climodel.Node {
+		var node climodel.Node
+		node.ID = "sharder"
+		node.RoundServiceChargeLastUpdated = lastUpdated
+		node.Reward = reward
+		return node
+	}
+	before = []climodel.Node{miner(0, 0, 0, 0), sharder(0, 0)}
+	after = []climodel.Node{miner(rounds, rounds*1e8, rounds*1e8, rounds*3e8), sharder(rounds, rounds*5e8)}
+	return before, after
+}
+
+func TestReconcileConsistentRewards(testSetup *testing.T) {
+	t := test.NewSystemTest(testSetup)
+	history := cliutil.NewHistoryFromRounds(t, 1, 3, testRounds(1, 3))
+	before, after := testNodes(3)
+
+	report := New(history, testConfig).
+		AddMiners(before[:1], after[:1]).
+		AddSharders(before[1:], after[1:]).
+		Reconcile(t)
+	require.Empty(t, report.Discrepancies, report.String())
+}
+
+func TestReconcileReportsDiscrepancies(testSetup *testing.T) {
+	t := test.NewSystemTest(testSetup)
+	rounds := testRounds(1, 3)
+	rounds[1].DelegateRewards[1].Amount = 2e8
+	rounds[1].ProviderRewards = rounds[1].ProviderRewards[:1]
+	history := cliutil.NewHistoryFromRounds(t, 1, 3, rounds)
+	before, after := testNodes(3)
+
+	report := New(history, testConfig).
+		AddMiners(before[:1], after[:1]).
+		AddSharders(before[1:], after[1:]).
+		Reconcile(t)
+
+	var reasons []string
+	for _, d := range report.Discrepancies {
+		reasons = append(reasons, d.Reason)
+	}
+	require.Equal(t, []string{
+		// whole period, ordered by provider
+		"change in pool reward differs from rewards recorded",
+		"change in provider reward differs from rewards recorded",
+		// round 2
+		"number of providers paid",
+		"pool share not in proportion to stake",
+	}, reasons, report.String())
+	require.Equal(t, "pool2", report.Discrepancies[0].PoolID)
+	require.Equal(t, "sharder", report.Discrepancies[1].ProviderID)
+	require.Equal(t, int64(2), report.Discrepancies[3].Round)
+}
+
+func TestBlockRewards(t *testing.T) {
+	config := Config{Epoch: 10, BlockReward: 1e10, ShareRatio: 0.8, RewardDeclineRate: 0.5}
+
+	minerReward, sharderReward := config.BlockRewards(9)
+	require.Equal(t, int64(8e9), minerReward)
+	require.Equal(t, int64(2e9), sharderReward)
+
+	minerReward, sharderReward = config.BlockRewards(10)
+	require.Equal(t, int64(4e9), minerReward)
+	require.Equal(t, int64(1e9), sharderReward)
+
+	// 0.9^3 of the reward, exactly, where float64 gives 0.7290000000000001
+	config = Config{Epoch: 10, BlockReward: 3e10, ShareRatio: 0.7, RewardDeclineRate: 0.1}
+	minerReward, sharderReward = config.BlockRewards(30)
+	require.Equal(t, int64(15309e6), minerReward)
+	require.Equal(t, int64(6561e6), sharderReward)
+}
@@ -11,3 +11,14 @@ import (
 func Setpgid(cmd *exec.Cmd) {
 	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 }
+
+// Quit sends SIGQUIT to the process group of cmd, Go programs such as zbox and zwallet print the stacks of
+// their goroutines and exit
+func Quit(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGQUIT)
+}
+
+// Kill kills the process group of cmd
+func Kill(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
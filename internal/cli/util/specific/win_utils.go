@@ -11,3 +11,13 @@ import (
 func Setpgid(cmd *exec.Cmd) {
 	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
 }
+
+// Quit kills the process of cmd, windows has no SIGQUIT to make it print its goroutines
+func Quit(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
+
+// Kill kills the process of cmd
+func Kill(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
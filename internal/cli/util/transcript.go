@@ -0,0 +1,163 @@
+package cliutils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0chain/system_test/internal/api/util/redact"
+	"github.com/0chain/system_test/internal/api/util/test"
+)
+
+// TranscriptFile is the name of the transcript of CLI invocations in the artifact directory of a test
+const TranscriptFile = "cli_transcript.jsonl"
+
+// Invocation is a run of zbox, zwallet or s3mgrt recorded in the transcript of a test, with secrets redacted
+type Invocation struct {
+	Argv []string `json:"argv"`
+	// Env are the variables set for the command on top of the environment of the tests
+	Env      []string   `json:"env,omitempty"`
+	Dir      string     `json:"dir"`
+	Start    time.Time  `json:"start"`
+	End      *time.Time `json:"end,omitempty"`
+	ExitCode int        `json:"exit_code"`
+	Error    string     `json:"error,omitempty"`
+	Stdout   string     `json:"stdout"`
+	Stderr   string     `json:"stderr"`
+	// TimedOut is set for commands stopped at their deadline, their stderr holds the goroutine dump
+	TimedOut   bool `json:"timed_out,omitempty"`
+	Background bool `json:"background,omitempty"`
+}
+
+// transcript holds the invocations of a test until it completes
+type transcript struct {
+	mu          sync.Mutex
+	invocations []*Invocation
+}
+
+// transcripts are the transcripts of the running tests by *testing.T
+var transcripts sync.Map
+
+func newInvocation(cmd *exec.Cmd) *Invocation {
+	dir := cmd.Dir
+	if dir == "" {
+		dir, _ = os.Getwd()
+	}
+	env := envOverrides(cmd.Env)
+	for i := range env {
+		env[i] = redact.String(env[i])
+	}
+	return &Invocation{
+		Argv:     redact.Args(cmd.Args),
+		Env:      env,
+		Dir:      dir,
+		Start:    time.Now(),
+		ExitCode: -1,
+	}
+}
+
+// finish completes the invocation once the command exited
+func (i *Invocation) finish(cmd *exec.Cmd, err error, stdout, stderr []byte) {
+	end := time.Now()
+	i.End = &end
+	if cmd.ProcessState != nil {
+		i.ExitCode = cmd.ProcessState.ExitCode()
+	}
+	i.TimedOut = errors.Is(err, ErrCommandTimeout)
+	var exitErr *exec.ExitError
+	if err != nil && (i.TimedOut || !errors.As(err, &exitErr)) {
+		i.Error = redact.String(err.Error())
+	}
+	i.Stdout = redact.String(string(stdout))
+	i.Stderr = redact.String(string(stderr))
+}
+
+// envOverrides returns the variables of env that are not inherited from the environment of the tests
+func envOverrides(env []string) []string {
+	if env == nil {
+		return nil
+	}
+	inherited := make(map[string]bool)
+	for _, variable := range os.Environ() {
+		inherited[variable] = true
+	}
+	var overrides []string
+	for _, variable := range env {
+		if !inherited[variable] {
+			overrides = append(overrides, variable)
+		}
+	}
+	return overrides
+}
+
+// record adds the invocation to the transcript of the test. The transcript is written to the artifact directory
+// of the test when it completes and logged if the test failed, invocations may still be completed until then.
+func record(t *test.SystemTest, invocation *Invocation) {
+	if t == nil || t.Unwrap == nil {
+		return
+	}
+	value, loaded := transcripts.LoadOrStore(t.Unwrap, &transcript{})
+	tr := value.(*transcript)
+	if !loaded {
+		t.Cleanup(func() {
+			writeTranscript(t.Unwrap, tr)
+		})
+	}
+
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.invocations = append(tr.invocations, invocation)
+}
+
+// transcriptPath returns the path the transcript of the test is written to when it completes
+func transcriptPath(t *test.SystemTest) string {
+	return filepath.Join(test.ArtifactsRoot(), t.EscapedName(), TranscriptFile)
+}
+
+func writeTranscript(unwrap *testing.T, tr *transcript) {
+	transcripts.Delete(unwrap)
+	// t no longer logs once the test has completed
+	st := test.NewSystemTest(unwrap)
+
+	tr.mu.Lock()
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	for i := range tr.invocations {
+		_ = encoder.Encode(tr.invocations[i])
+	}
+	count := len(tr.invocations)
+	tr.mu.Unlock()
+
+	path, err := st.WriteArtifact(TranscriptFile, data.Bytes())
+	if err != nil {
+		st.Logf("writing transcript of %d CLI invocations: %v", count, err)
+		return
+	}
+	if unwrap.Failed() {
+		st.Logf("transcript of %d CLI invocations: %s", count, path)
+	}
+}
+
+// lockedBuffer is a buffer written by the goroutines copying stdout and stderr of a command
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Bytes()
+}
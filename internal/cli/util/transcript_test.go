@@ -0,0 +1,51 @@
+package cliutils
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/0chain/system_test/internal/api/util/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranscript(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv(test.ArtifactsDirEnv, root)
+
+	var artifactDir, loggedPath string
+	t.Run("commands", func(inner *testing.T) {
+		st := test.NewSystemTest(inner)
+		artifactDir = st.ArtifactDir()
+		loggedPath = transcriptPath(st)
+
+		output, err := RunCommandWithoutRetry(st, `sh -c "echo out; echo err >&2; exit 3"`)
+		require.Error(inner, err)
+		require.ElementsMatch(inner, []string{"out", "err"}, output)
+
+		_, err = Command{Program: "sh", Args: []string{"-c", "echo recovered", "--mnemonic", "pull floor crop best"}}.RunWithoutRetry(st)
+		require.NoError(inner, err)
+	})
+
+	require.Equal(t, filepath.Join(artifactDir, TranscriptFile), loggedPath, "failed commands log the path of the transcript")
+	data, err := os.ReadFile(loggedPath)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+
+	var failed, recovered Invocation
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &failed))
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &recovered))
+
+	require.Equal(t, []string{"sh", "-c", "echo out; echo err >&2; exit 3"}, failed.Argv)
+	require.Equal(t, 3, failed.ExitCode)
+	require.Equal(t, "out\n", failed.Stdout)
+	require.Equal(t, "err\n", failed.Stderr)
+	require.NotNil(t, failed.End)
+	require.NotEmpty(t, failed.Dir)
+
+	require.Equal(t, []string{"sh", "-c", "echo recovered", "--mnemonic", "****"}, recovered.Argv)
+	require.Equal(t, 0, recovered.ExitCode)
+}
@@ -1,8 +1,10 @@
 package cliutils
 
 import (
+	"bytes"
 	"crypto/rand"
 	"fmt"
+	"io"
 	"math/big"
 	"os"
 	"os/exec"
@@ -11,6 +13,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/0chain/system_test/internal/api/util/redact"
 	"github.com/0chain/system_test/internal/api/util/test"
 
 	"github.com/0chain/system_test/internal/cli/util/specific"
@@ -20,102 +23,97 @@ import (
 
 var Logger = getLogger()
 
-func RunCommandWithoutRetry(commandString string) ([]string, error) {
+func RunCommandWithoutRetry(t *test.SystemTest, commandString string) ([]string, error) {
 	command := parseCommand(commandString)
 	commandName := command[0]
 	args := command[1:]
 
 	sanitizedArgs := sanitizeArgs(args)
-	rawOutput, err := executeCommand(commandName, sanitizedArgs)
-	var commandStringForLog string
-
-	// Redact keys before logging
-	if strings.Contains(commandString, "--access-key") {
-		index := strings.Index(commandString, "--access-key")
-		// get next single word after "--access-key"
-		accessKey := strings.Fields(commandString[index:])[1]
-		commandStringForLog = strings.ReplaceAll(commandString, accessKey, "****")
-	}
-	if strings.Contains(commandString, "--secret-key") {
-		index := strings.Index(commandString, "--secret-key")
-		// get next single word after "--secret-key"
-		secretKey := strings.Fields(commandString[index:])[1]
-		commandStringForLog = strings.ReplaceAll(commandString, secretKey, "****")
-	}
-	Logger.Debugf("Command [%v] exited with error [%v] and output [%v]", commandStringForLog, err, sanitizeOutput(rawOutput))
+	rawOutput, err := executeCommand(t, CommandTimeout, commandName, sanitizedArgs)
+	Logger.Debugf("Command [%v] exited with error [%v] and output [%v]", redact.String(commandString), err, sanitizeOutput(rawOutput))
 
 	return sanitizeOutput(rawOutput), err
 }
 
-func RunCommandWithRawOutput(commandString string) ([]string, error) {
+func RunCommandWithRawOutput(t *test.SystemTest, commandString string) ([]string, error) {
 	command := parseCommand(commandString)
 	commandName := command[0]
 	args := command[1:]
 
 	sanitizedArgs := sanitizeArgs(args)
-	rawOutput, err := executeCommand(commandName, sanitizedArgs)
+	rawOutput, err := executeCommand(t, CommandTimeout, commandName, sanitizedArgs)
 
-	Logger.Debugf("Command [%v] exited with error [%v] and output [%v]", commandString, err, string(rawOutput))
+	Logger.Debugf("Command [%v] exited with error [%v] and output [%v]", redact.String(commandString), err, string(rawOutput))
 
 	output := strings.Split(string(rawOutput), "\n")
 
 	return output, err
 }
 
+// RunCommand runs a command line until it succeeds, see retry. The command line is split by parseCommand, so values
+// with spaces or quotes break it. The subcommands with typed flags, see Zbox and Zwallet, are run as argv instead.
 func RunCommand(t *test.SystemTest, commandString string, maxAttempts int, backoff time.Duration) ([]string, error) {
+	var subcommand string
+	if command := parseCommand(commandString); len(command) > 1 {
+		subcommand = command[1]
+	}
+	return retry(t, redact.String(commandString), subcommand, maxAttempts, backoff,
+		func() ([]string, error) {
+			return RunCommandWithoutRetry(t, commandString)
+		})
+}
+
+// retry runs a command until it succeeds or maxAttempts are used. Failures are classified, permanent ones are not
+// retried and uncertain ones only if the chain shows that the transaction of the command was not applied. If it
+// was applied, ErrAppliedAfterUncertainFailure is returned with the output of the failed command.
+// The full output of every attempt is kept in the transcript of the test, whose path is logged on failure.
+func retry(t *test.SystemTest, commandForLog, subcommand string, maxAttempts int, backoff time.Duration, run func() ([]string, error)) ([]string, error) {
 	red := "\033[31m"
 	yellow := "\033[33m"
 	green := "\033[32m"
-	var commandStringForLog string
 
 	var count int
 	for {
 		count++
-		output, err := RunCommandWithoutRetry(commandString)
+		output, err := run()
+		finished := time.Now()
 
 		if err == nil {
 			if count > 1 {
 				t.Logf("%sCommand passed on retry [%v/%v]. Output: [%v]\n", green, count, maxAttempts, strings.Join(output, " -<NEWLINE>- "))
 			}
 			return output, nil
-		} else if count < maxAttempts {
-			t.Logf("%sCommand failed on attempt [%v/%v] due to error [%v]. Output: [%v]\n", yellow, count, maxAttempts, err, strings.Join(output, " -<NEWLINE>- "))
+		}
+
+		class := classifyCommand(subcommand, output, err)
+		retryable := count < maxAttempts && class != Permanent
+		if retryable {
+			t.Logf("%sCommand failed on attempt [%v/%v] due to %s error [%v]. Output: [%v]\n", yellow, count, maxAttempts, class, err, strings.Join(output, " -<NEWLINE>- "))
 			time.Sleep(backoff)
-		} else {
-			// Redact keys before logging
-			if strings.Contains(commandString, "--access-key") {
-				index := strings.Index(commandString, "--access-key")
-				// get next single word after "--access-key"
-				accessKey := strings.Fields(commandString[index:])[1]
-				commandStringForLog = strings.ReplaceAll(commandString, accessKey, "****")
+			if class != Uncertain {
+				continue
 			}
-			if strings.Contains(commandString, "--secret-key") {
-				index := strings.Index(commandString, "--secret-key")
-				// get next single word after "--secret-key"
-				secretKey := strings.Fields(commandString[index:])[1]
-				commandStringForLog = strings.ReplaceAll(commandString, secretKey, "****")
+			hash, applied, again := resolveUncertain(t, output, finished)
+			if applied {
+				return output, fmt.Errorf("%w: transaction %s: %w", ErrAppliedAfterUncertainFailure, hash, err)
 			}
-			t.Logf("%sCommand failed on final attempt [%v/%v] due to error [%v]. Command String: [%v] Output: [%v]\n", red, count, maxAttempts, err, commandStringForLog, strings.Join(output, " -<NEWLINE>- "))
-
-			if err != nil {
-				t.Logf("%sThe verbose output for the command is:", red)
-				commandString = strings.Replace(commandString, "--silent", "", 1)
-				out, _ := RunCommandWithoutRetry(commandString) // Only for logging!
-				for _, line := range out {
-					t.Logf("%s%s", red, line)
-				}
+			if again {
+				continue
 			}
-
-			return output, err
 		}
+
+		t.Logf("%sCommand failed on attempt [%v/%v] due to %s error [%v], not retrying. Command String: [%v] Output: [%v]\n", red, count, maxAttempts, class, err, commandForLog, strings.Join(output, " -<NEWLINE>- "))
+		t.Logf("%sThe stdout and stderr of every attempt are in the transcript: %s", red, transcriptPath(t))
+		return output, err
 	}
 }
 
+// StartCommand starts a command in the background, retrying if it cannot be started, see StartCommandWithoutRetry
 func StartCommand(t *test.SystemTest, commandString string, maxAttempts int, backoff time.Duration) (cmd *exec.Cmd, err error) {
 	var count int
 	for {
 		count++
-		cmd, err := StartCommandWithoutRetry(commandString)
+		cmd, err := StartCommandWithoutRetry(t, commandString)
 
 		if err == nil {
 			if count > 1 {
@@ -125,17 +123,17 @@ func StartCommand(t *test.SystemTest, commandString string, maxAttempts int, bac
 		} else if count < maxAttempts {
 			t.Logf("Command failed on attempt [%v/%v] due to error [%v]\n", count, maxAttempts, err)
 			t.Logf("Sleeping for backoff duration: %v\n", backoff)
-			_ = cmd.Process.Kill()
 			time.Sleep(backoff)
 		} else {
 			t.Logf("Command failed on final attempt [%v/%v] due to error [%v].\n", count, maxAttempts, err)
-			_ = cmd.Process.Kill()
 			return cmd, err
 		}
 	}
 }
 
-func StartCommandWithoutRetry(commandString string) (cmd *exec.Cmd, err error) {
+// StartCommandWithoutRetry starts a command in the background in its own process group. The group is stopped at the
+// end of the test if it still runs, and the command is waited for, so callers only kill it and must not Wait.
+func StartCommandWithoutRetry(t *test.SystemTest, commandString string) (cmd *exec.Cmd, err error) {
 	command := parseCommand(commandString)
 	commandName := command[0]
 	args := command[1:]
@@ -143,7 +141,20 @@ func StartCommandWithoutRetry(commandString string) (cmd *exec.Cmd, err error) {
 
 	cmd = exec.Command(commandName, sanitizedArgs...)
 	specific.Setpgid(cmd)
+	stdout, stderr := &lockedBuffer{}, &lockedBuffer{}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.WaitDelay = QuitGracePeriod
+	invocation := newInvocation(cmd)
+	invocation.Background = true
+	record(t, invocation)
+
 	err = cmd.Start()
+	if err != nil {
+		invocation.finish(cmd, err, nil, nil)
+		return cmd, err
+	}
+	reapOnCleanup(t, cmd, invocation, stdout, stderr)
 
 	return cmd, err
 }
@@ -197,11 +208,20 @@ func unique(slice []string) []string {
 	return uniqueOutput
 }
 
-func executeCommand(commandName string, args []string) ([]byte, error) {
+// executeCommand runs the command with a deadline and returns its combined output, recording it in the transcript of the test
+func executeCommand(t *test.SystemTest, timeout time.Duration, commandName string, args []string) ([]byte, error) {
 	cmd := exec.Command(commandName, args...)
-	rawOutput, err := cmd.CombinedOutput()
+	var stdout, stderr bytes.Buffer
+	combined := &lockedBuffer{}
+	cmd.Stdout = io.MultiWriter(&stdout, combined)
+	cmd.Stderr = io.MultiWriter(&stderr, combined)
+
+	invocation := newInvocation(cmd)
+	_, err := runWithDeadline(cmd, timeout)
+	invocation.finish(cmd, err, stdout.Bytes(), stderr.Bytes())
+	record(t, invocation)
 
-	return rawOutput, err
+	return combined.Bytes(), err
 }
 
 func sanitizeArgs(args []string) []string {
@@ -222,7 +242,7 @@ func parseCommand(command string) []string {
 
 func getLogger() *logrus.Logger {
 	logger := logrus.New()
-	logger.Out = os.Stdout
+	logger.Out = redact.NewWriter(os.Stdout)
 
 	logger.SetFormatter(&logrus.TextFormatter{
 		DisableQuote: true,
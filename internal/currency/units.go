@@ -0,0 +1,233 @@
+package currency
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3" //nolint
+)
+
+// Unit is a denomination of Coin, its value is the exponent of the denomination in SAS
+type Unit int32
+
+const (
+	SAS  Unit = 0
+	UZCN Unit = UZCNExponent
+	MZCN Unit = MZCNExponent
+	ZCN  Unit = ZCNExponent
+)
+
+var (
+	// ErrInvalidCoin is returned if a string is not an amount optionally followed by a unit
+	ErrInvalidCoin = errors.New("invalid coin value")
+	// ErrUnknownUnit is returned if a unit is not one of SAS, uZCN, mZCN and ZCN
+	ErrUnknownUnit = errors.New("unknown coin unit")
+	// ErrFractionalSAS is returned if a value is not a whole number of SAS
+	ErrFractionalSAS = errors.New("value is not a whole number of SAS")
+	// ErrMissingUnit is returned if a coin is decoded from a string without unit
+	ErrMissingUnit = errors.New("coin value has no unit")
+)
+
+var coinPattern = regexp.MustCompile(`^\s*([+-]?(?:\d+\.?\d*|\.\d+)(?:[eE][+-]?\d+)?)\s*([a-zA-Z]*)\s*$`)
+
+func (u Unit) String() string {
+	switch u {
+	case SAS:
+		return "SAS"
+	case UZCN:
+		return "uZCN"
+	case MZCN:
+		return "mZCN"
+	case ZCN:
+		return "ZCN"
+	}
+	return fmt.Sprintf("unit(%d)", int32(u))
+}
+
+// ParseUnit parses the unit names printed by the CLIs, case insensitively
+func ParseUnit(s string) (Unit, error) {
+	switch strings.ToLower(s) {
+	case "sas":
+		return SAS, nil
+	case "uzcn":
+		return UZCN, nil
+	case "mzcn":
+		return MZCN, nil
+	case "zcn":
+		return ZCN, nil
+	}
+	return 0, fmt.Errorf("%w: %q", ErrUnknownUnit, s)
+}
+
+// ParseCoin parses an amount followed by an optional unit, e.g. "1.5 mZCN", "0.1ZCN" or "10000 SAS".
+// An amount without unit is in ZCN, as accepted by the --tokens flag of the CLIs.
+func ParseCoin(s string) (Coin, error) {
+	matches := coinPattern.FindStringSubmatch(s)
+	if matches == nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidCoin, s)
+	}
+
+	amount, err := decimal.NewFromString(matches[1])
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidCoin, s)
+	}
+
+	unit := ZCN
+	if matches[2] != "" {
+		if unit, err = ParseUnit(matches[2]); err != nil {
+			return 0, err
+		}
+	}
+
+	return NewCoin(amount, unit)
+}
+
+// NewCoin converts an exact amount of unit to Coin
+func NewCoin(amount decimal.Decimal, unit Unit) (Coin, error) {
+	if amount.Sign() == -1 {
+		return 0, ErrNegativeValue
+	}
+
+	sas := amount.Shift(int32(unit))
+	if !sas.Equal(sas.Truncate(0)) {
+		return 0, ErrFractionalSAS
+	}
+	if sas.GreaterThan(maxDecimal) {
+		return 0, ErrTooLarge
+	}
+
+	return Coin(sas.IntPart()), nil
+}
+
+// Decimal returns the exact amount of c in unit
+func (c Coin) Decimal(unit Unit) decimal.Decimal {
+	return decimal.NewFromBigInt(new(big.Int).SetUint64(uint64(c)), -int32(unit))
+}
+
+// Format returns the exact amount of c in unit followed by the unit, e.g. "1.5 mZCN"
+func (c Coin) Format(unit Unit) string {
+	return c.Decimal(unit).String() + " " + unit.String()
+}
+
+// Unit returns the largest unit c is at least one of
+func (c Coin) Unit() Unit {
+	for _, unit := range []Unit{ZCN, MZCN, UZCN} {
+		if c >= Coin(decimal.New(1, int32(unit)).IntPart()) {
+			return unit
+		}
+	}
+	return SAS
+}
+
+func (c Coin) String() string {
+	return c.Format(c.Unit())
+}
+
+// MulDecimal multiplies c by d, truncating the fractional SAS the same way the chain does
+func (c Coin) MulDecimal(d decimal.Decimal) (Coin, error) {
+	if d.Sign() == -1 {
+		return 0, ErrNegativeValue
+	}
+
+	product := c.Decimal(SAS).Mul(d).Truncate(0)
+	if product.GreaterThan(maxDecimal) {
+		return 0, ErrTooLarge
+	}
+	return Coin(product.IntPart()), nil
+}
+
+// Diff returns the signed difference a - b in SAS
+func Diff(a, b Coin) decimal.Decimal {
+	return a.Decimal(SAS).Sub(b.Decimal(SAS))
+}
+
+// SASToZCN converts a signed amount of SAS, as returned by the chain, to ZCN
+func SASToZCN(sas int64) decimal.Decimal {
+	return decimal.New(sas, -ZCNExponent)
+}
+
+// ZCNToSAS converts a signed amount of ZCN to SAS, truncating anything below one SAS
+func ZCNToSAS(zcn float64) int64 {
+	return decimal.NewFromFloat(zcn).Shift(ZCNExponent).IntPart()
+}
+
+// MarshalJSON encodes c as a number of SAS, the way the chain does
+func (c Coin) MarshalJSON() ([]byte, error) {
+	return json.Marshal(uint64(c))
+}
+
+// UnmarshalJSON decodes a number of SAS or a string with unit, see parseCoinWithUnit
+func (c *Coin) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		coin, err := parseCoinWithUnit(s)
+		if err != nil {
+			return err
+		}
+		*c = coin
+		return nil
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidCoin, data)
+	}
+
+	coin, err := numberToCoin(n.String())
+	if err != nil {
+		return err
+	}
+	*c = coin
+	return nil
+}
+
+// MarshalYAML encodes c as a string with unit, since yaml files are written by hand
+func (c Coin) MarshalYAML() (interface{}, error) {
+	return c.String(), nil
+}
+
+// UnmarshalYAML decodes an integer number of SAS or a string with unit, see parseCoinWithUnit
+func (c *Coin) UnmarshalYAML(value *yaml.Node) error {
+	var (
+		coin Coin
+		err  error
+	)
+	if value.Kind == yaml.ScalarNode && (value.Tag == "!!int" || value.Tag == "!!float") {
+		coin, err = numberToCoin(value.Value)
+	} else {
+		var s string
+		if err = value.Decode(&s); err != nil {
+			return err
+		}
+		coin, err = parseCoinWithUnit(s)
+	}
+	if err != nil {
+		return err
+	}
+
+	*c = coin
+	return nil
+}
+
+// parseCoinWithUnit parses a string of an encoded coin. Unlike ParseCoin it requires the unit: a bare number is
+// in SAS while a unitless string would be in ZCN, so the unit of "1" would depend on its quoting.
+func parseCoinWithUnit(s string) (Coin, error) {
+	if matches := coinPattern.FindStringSubmatch(s); matches != nil && matches[2] == "" {
+		return 0, fmt.Errorf("%w: %q", ErrMissingUnit, s)
+	}
+	return ParseCoin(s)
+}
+
+// numberToCoin converts a number without unit, which is in SAS
+func numberToCoin(s string) (Coin, error) {
+	amount, err := decimal.NewFromString(s)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidCoin, s)
+	}
+	return NewCoin(amount, SAS)
+}
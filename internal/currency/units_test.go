@@ -0,0 +1,127 @@
+package currency
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3" //nolint
+)
+
+func TestParseCoin(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		want  Coin
+		err   error
+	}{
+		{input: "1.5 mZCN", want: 15000000},
+		{input: "1.5mzcn", want: 15000000},
+		{input: "0.100 ZCN", want: 1000000000},
+		{input: "0.1", want: 1000000000},
+		{input: "42 uZCN", want: 420000},
+		{input: "10000 SAS", want: 10000},
+		{input: " 2 zcn ", want: 20000000000},
+		{input: "1e-10 ZCN", want: 1},
+		{input: "0.5 SAS", err: ErrFractionalSAS},
+		{input: "0.00000000001", err: ErrFractionalSAS},
+		{input: "-1 ZCN", err: ErrNegativeValue},
+		{input: "1 BTC", err: ErrUnknownUnit},
+		{input: "ZCN", err: ErrInvalidCoin},
+		{input: "1000000000 ZCN", err: ErrTooLarge},
+	} {
+		got, err := ParseCoin(tc.input)
+		if tc.err != nil {
+			require.ErrorIs(t, err, tc.err, tc.input)
+			continue
+		}
+		require.NoError(t, err, tc.input)
+		require.Equal(t, tc.want, got, tc.input)
+	}
+}
+
+func TestCoinFormat(t *testing.T) {
+	require.Equal(t, "1.5 mZCN", Coin(15000000).String())
+	require.Equal(t, "1.0000000001 ZCN", Coin(10000000001).String())
+	require.Equal(t, "42 uZCN", Coin(420000).String())
+	require.Equal(t, "9999 SAS", Coin(9999).String())
+	require.Equal(t, "0 SAS", Coin(0).String())
+	require.Equal(t, "0.0015 ZCN", Coin(15000000).Format(ZCN))
+
+	for _, c := range []Coin{0, 1, 9999, 420000, 15000000, 10000000001, Coin(maxDecimal.IntPart())} {
+		parsed, err := ParseCoin(c.String())
+		require.NoError(t, err, c.String())
+		require.Equal(t, c, parsed, c.String())
+	}
+}
+
+func TestCoinJSON(t *testing.T) {
+	type payload struct {
+		Balance Coin `json:"balance"`
+	}
+
+	encoded, err := json.Marshal(payload{Balance: 15000000})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"balance":15000000}`, string(encoded))
+
+	var decoded payload
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	require.Equal(t, Coin(15000000), decoded.Balance)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"balance":"1.5 mZCN"}`), &decoded))
+	require.Equal(t, Coin(15000000), decoded.Balance)
+
+	require.Error(t, json.Unmarshal([]byte(`{"balance":1.5}`), &decoded))
+	require.Error(t, json.Unmarshal([]byte(`{"balance":true}`), &decoded))
+
+	// a bare number is in SAS, a string must name its unit
+	require.NoError(t, json.Unmarshal([]byte(`{"balance":10}`), &decoded))
+	require.Equal(t, Coin(10), decoded.Balance)
+	require.NoError(t, json.Unmarshal([]byte(`{"balance":"10 SAS"}`), &decoded))
+	require.Equal(t, Coin(10), decoded.Balance)
+	require.ErrorIs(t, json.Unmarshal([]byte(`{"balance":"10"}`), &decoded), ErrMissingUnit)
+}
+
+func TestCoinYAML(t *testing.T) {
+	type payload struct {
+		MinStake Coin `yaml:"min_stake"`
+		MaxStake Coin `yaml:"max_stake"`
+	}
+
+	var decoded payload
+	require.NoError(t, yaml.Unmarshal([]byte("min_stake: 1.5 mZCN\nmax_stake: 20000000000\n"), &decoded)) //nolint
+	require.Equal(t, payload{MinStake: 15000000, MaxStake: 20000000000}, decoded)
+
+	encoded, err := yaml.Marshal(decoded) //nolint
+	require.NoError(t, err)
+	require.Equal(t, "min_stake: 1.5 mZCN\nmax_stake: 2 ZCN\n", string(encoded))
+
+	var roundTrip payload
+	require.NoError(t, yaml.Unmarshal(encoded, &roundTrip)) //nolint
+	require.Equal(t, decoded, roundTrip)
+
+	// a bare number is in SAS, a string must name its unit
+	require.NoError(t, yaml.Unmarshal([]byte("min_stake: 10\nmax_stake: 10 SAS\n"), &decoded)) //nolint
+	require.Equal(t, payload{MinStake: 10, MaxStake: 10}, decoded)
+	require.ErrorIs(t, yaml.Unmarshal([]byte("min_stake: \"10\"\n"), &decoded), ErrMissingUnit) //nolint
+}
+
+func TestCoinArithmetic(t *testing.T) {
+	// 0.1 + 0.2 ZCN is exact in SAS, unlike float64
+	a, err := ParseCoin("0.1 ZCN")
+	require.NoError(t, err)
+	b, err := ParseCoin("0.2 ZCN")
+	require.NoError(t, err)
+	sum, err := AddCoin(a, b)
+	require.NoError(t, err)
+	require.Equal(t, "0.3 ZCN", sum.Format(ZCN))
+
+	share, err := Coin(10).MulDecimal(decimal.RequireFromString("0.33"))
+	require.NoError(t, err)
+	require.Equal(t, Coin(3), share)
+
+	require.Equal(t, "-2000000000", Diff(a, sum).String())
+	require.Equal(t, "-0.01", SASToZCN(-100000000).String())
+	require.Equal(t, int64(1000000000), ZCNToSAS(0.1))
+	require.Equal(t, int64(-3000000000), ZCNToSAS(-0.3))
+}
@@ -9,13 +9,12 @@ import (
 
 	"github.com/0chain/gosdk/zboxcore/sdk"
 
-	"github.com/0chain/system_test/internal/api/util/tokenomics"
-
 	"github.com/0chain/system_test/internal/api/model"
 	"github.com/0chain/system_test/internal/api/util/client"
 	"github.com/0chain/system_test/internal/api/util/config"
 	"github.com/0chain/system_test/internal/api/util/test"
 	"github.com/0chain/system_test/internal/api/util/wait"
+	"github.com/0chain/system_test/internal/currency"
 	"github.com/stretchr/testify/require"
 )
 
@@ -31,10 +30,7 @@ func Test0boxGraphAndTotalEndpoints(testSetup *testing.T) {
 	ownerWallet.Nonce = int(ownerBalance.Nonce)
 	blobberOwnerWallet.Nonce = int(blobberOwnerBalance.Nonce)
 
-	testWallet := initialisedWallets[walletIdx]
-	walletIdx++
-	balance := apiClient.GetWalletBalance(t, testWallet, client.HttpOkStatus)
-	testWallet.Nonce = int(balance.Nonce)
+	testWallet := createWallet(t)
 
 	// Stake 6 blobbers, each with 1 token
 	targetBlobbers, resp, err := apiClient.V1SCRestGetFirstBlobbers(t, 6, client.HttpOkStatus)
@@ -648,8 +644,8 @@ func Test0boxGraphAndTotalEndpoints(testSetup *testing.T) {
 		targetBlobbers[0].Capacity += 10 * 1024 * 1024 * 1024
 		targetBlobbers[1].Capacity -= 10 * 1024 * 1024 * 1024
 
-		targetBlobbers[0].Terms.WritePrice += *tokenomics.IntToZCN(0.1)
-		targetBlobbers[1].Terms.WritePrice += *tokenomics.IntToZCN(0.1)
+		targetBlobbers[0].Terms.WritePrice += currency.ZCNToSAS(0.1)
+		targetBlobbers[1].Terms.WritePrice += currency.ZCNToSAS(0.1)
 
 		t.Log("Blobber : ", blobberOwnerWallet.Id)
 
@@ -682,8 +678,8 @@ func Test0boxGraphAndTotalEndpoints(testSetup *testing.T) {
 		})
 
 		// Cleanup: Revert write price to 0.1
-		targetBlobbers[0].Terms.WritePrice = *tokenomics.IntToZCN(0.1)
-		targetBlobbers[1].Terms.WritePrice = *tokenomics.IntToZCN(0.1)
+		targetBlobbers[0].Terms.WritePrice = currency.ZCNToSAS(0.1)
+		targetBlobbers[1].Terms.WritePrice = currency.ZCNToSAS(0.1)
 		apiClient.UpdateBlobber(t, blobberOwnerWallet, targetBlobbers[0], client.TxSuccessfulStatus)
 		apiClient.UpdateBlobber(t, blobberOwnerWallet, targetBlobbers[1], client.TxSuccessfulStatus)
 	})
@@ -692,10 +688,7 @@ func Test0boxGraphAndTotalEndpoints(testSetup *testing.T) {
 		t.RunSequentially("endpoint parameters ( test /v2/graph-total-staked )", graphEndpointTestCases(zboxClient.GetGraphTotalStaked))
 
 		t.RunSequentiallyWithTimeout("test graph data ( test /v2/graph-total-staked )", 5*time.Minute, func(t *test.SystemTest) {
-			wallet := initialisedWallets[walletIdx]
-			walletIdx++
-			balance := apiClient.GetWalletBalance(t, wallet, client.HttpOkStatus)
-			wallet.Nonce = int(balance.Nonce)
+			wallet := createWallet(t)
 
 			PrintBalance(t, ownerWallet, blobberOwnerWallet, wallet)
 			data, resp, err := zboxClient.GetGraphTotalStaked(t, &model.ZboxGraphRequest{DataPoints: "1"})
@@ -722,7 +715,7 @@ func Test0boxGraphAndTotalEndpoints(testSetup *testing.T) {
 				latest, resp, err := zboxClient.GetTotalStaked(t)
 				require.NoError(t, err)
 				require.Equal(t, 200, resp.StatusCode())
-				cond := (totalStakedAfter-totalStaked) == *(tokenomics.IntToZCN(1)) && totalStakedAfter == int64(*latest)
+				cond := (totalStakedAfter-totalStaked) == currency.ZCNToSAS(1) && totalStakedAfter == int64(*latest)
 				if cond {
 					totalStaked = totalStakedAfter
 				}
@@ -772,7 +765,7 @@ func Test0boxGraphAndTotalEndpoints(testSetup *testing.T) {
 				latest, resp, err := zboxClient.GetTotalStaked(t)
 				require.NoError(t, err)
 				require.Equal(t, 200, resp.StatusCode())
-				cond := totalStakedAfter-totalStaked == *tokenomics.IntToZCN(1.0) && totalStakedAfter == int64(*latest)
+				cond := totalStakedAfter-totalStaked == currency.ZCNToSAS(1) && totalStakedAfter == int64(*latest)
 				if cond {
 					totalStaked = totalStakedAfter
 				}
@@ -793,7 +786,7 @@ func Test0boxGraphAndTotalEndpoints(testSetup *testing.T) {
 				latest, resp, err := zboxClient.GetTotalStaked(t)
 				require.NoError(t, err)
 				require.Equal(t, 200, resp.StatusCode())
-				cond := totalStaked-totalStakedAfter == *tokenomics.IntToZCN(1.0) && totalStakedAfter == int64(*latest)
+				cond := totalStaked-totalStakedAfter == currency.ZCNToSAS(1) && totalStakedAfter == int64(*latest)
 				if cond {
 					totalStaked = totalStakedAfter
 				}
@@ -820,7 +813,7 @@ func Test0boxGraphAndTotalEndpoints(testSetup *testing.T) {
 				latest, resp, err := zboxClient.GetTotalStaked(t)
 				require.NoError(t, err)
 				require.Equal(t, 200, resp.StatusCode())
-				cond := totalStakedAfter-totalStaked == *tokenomics.IntToZCN(1.0) && totalStakedAfter == int64(*latest)
+				cond := totalStakedAfter-totalStaked == currency.ZCNToSAS(1) && totalStakedAfter == int64(*latest)
 				if cond {
 					totalStaked = totalStakedAfter
 				}
@@ -841,7 +834,7 @@ func Test0boxGraphAndTotalEndpoints(testSetup *testing.T) {
 				latest, resp, err := zboxClient.GetTotalStaked(t)
 				require.NoError(t, err)
 				require.Equal(t, 200, resp.StatusCode())
-				cond := totalStaked-totalStakedAfter == *tokenomics.IntToZCN(1.0) && totalStakedAfter == int64(*latest)
+				cond := totalStaked-totalStakedAfter == currency.ZCNToSAS(1) && totalStakedAfter == int64(*latest)
 				if cond {
 					totalStaked = totalStakedAfter
 				}
@@ -867,7 +860,7 @@ func Test0boxGraphAndTotalEndpoints(testSetup *testing.T) {
 				latest, resp, err := zboxClient.GetTotalStaked(t)
 				require.NoError(t, err)
 				require.Equal(t, 200, resp.StatusCode())
-				cond := totalStakedAfter-totalStaked == *tokenomics.IntToZCN(1.0) && totalStakedAfter == int64(*latest)
+				cond := totalStakedAfter-totalStaked == currency.ZCNToSAS(1) && totalStakedAfter == int64(*latest)
 				if cond {
 					totalStaked = totalStakedAfter
 				}
@@ -888,7 +881,7 @@ func Test0boxGraphAndTotalEndpoints(testSetup *testing.T) {
 				latest, resp, err := zboxClient.GetTotalStaked(t)
 				require.NoError(t, err)
 				require.Equal(t, 200, resp.StatusCode())
-				cond := totalStaked-totalStakedAfter == *tokenomics.IntToZCN(1.0) && totalStakedAfter == int64(*latest)
+				cond := totalStaked-totalStakedAfter == currency.ZCNToSAS(1) && totalStakedAfter == int64(*latest)
 				if cond {
 					totalStaked = totalStakedAfter
 				}
@@ -942,10 +935,7 @@ func Test0boxGraphAndTotalEndpoints(testSetup *testing.T) {
 		})
 
 		t.RunSequentiallyWithTimeout("test graph data ( test /v2/graph-challenges )", 5*time.Minute, func(t *test.SystemTest) {
-			wallet := initialisedWallets[walletIdx]
-			walletIdx++
-			balance := apiClient.GetWalletBalance(t, wallet, client.HttpOkStatus)
-			wallet.Nonce = int(balance.Nonce)
+			wallet := createWallet(t)
 
 			sdkClient.SetWallet(t, wallet)
 
@@ -1088,10 +1078,7 @@ func Test0boxGraphAndTotalEndpoints(testSetup *testing.T) {
 func Test0boxGraphBlobberEndpoints(testSetup *testing.T) {
 	t := test.NewSystemTest(testSetup)
 
-	testWallet := initialisedWallets[walletIdx]
-	walletIdx++
-	balance := apiClient.GetWalletBalance(t, testWallet, client.HttpOkStatus)
-	testWallet.Nonce = int(balance.Nonce)
+	testWallet := createWallet(t)
 
 	// Faucet the used initialisedWallets
 	blobberOwnerBalance := apiClient.GetWalletBalance(t, blobberOwnerWallet, client.HttpOkStatus)
@@ -70,8 +70,9 @@ func Test1ChimneyBlobberRewards(testSetup *testing.T) {
 		actualBlockReward   float64
 	)
 
-	sdkWallet := initialisedWallets[walletIdx]
-	walletIdx++
+	// the wallet is used on the chimney network, so it is reserved without being funded on the main one
+	leased := walletPool.Reserve(t, t.Name())
+	sdkWallet := initialisedWallets[leased.Index]
 
 	allBlobbers, resp, err := chimneyClient.V1SCRestGetAllBlobbers(t, client.HttpOkStatus)
 	require.NoError(t, err)
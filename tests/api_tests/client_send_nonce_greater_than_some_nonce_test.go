@@ -10,6 +10,7 @@ import (
 	"github.com/0chain/system_test/internal/api/model"
 	"github.com/0chain/system_test/internal/api/util/client"
 	"github.com/0chain/system_test/internal/api/util/test"
+	"github.com/0chain/system_test/internal/currency"
 	"github.com/gocolly/colly"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/errgo.v2/errors"
@@ -20,23 +21,21 @@ func TestClientSendNonceGreaterThanFutureNonceLimit(testSetup *testing.T) {
 	t.Skip()
 	t.Parallel()
 
-	wallet1 := initialisedWallets[walletIdx]
-	walletIdx++
+	wallet1 := createWallet(t)
 
 	err := zcncore.SetWallet(*wallet1.ToZCNCryptoWallet(wallet1.Mnemonics), false)
 	require.NoError(t, err)
 
 	faucetAmount := float64(9)
 	balResp := apiClient.GetWalletBalance(t, wallet1, client.HttpOkStatus)
-	require.EqualValues(t, zcncore.ConvertToValue(faucetAmount), balResp.Balance)
+	require.EqualValues(t, currency.ZCNToSAS(faucetAmount), balResp.Balance)
 
-	wallet2 := initialisedWallets[walletIdx]
-	walletIdx++
+	wallet2 := createWallet(t)
 	futureNonce := GetFutureNonceConfig(t)
 	currentNonce := balResp.Nonce
 
 	tokens := float64(1)
-	value := int64(zcncore.ConvertToValue(tokens))
+	value := currency.Coin(currency.ZCNToSAS(tokens))
 
 	// Add transactions with nonce + future nonce
 	_, resp, err := apiClient.V1TransactionPutWithNonceAndServiceProviders(
@@ -61,25 +60,23 @@ func TestClientSendSameNonceForDifferentTransactions(testSetup *testing.T) {
 	t := test.NewSystemTest(testSetup)
 	t.Skip()
 	t.Parallel()
-	wallet1 := initialisedWallets[walletIdx]
-	walletIdx++
+	wallet1 := createWallet(t)
 
 	err := zcncore.SetWallet(*wallet1.ToZCNCryptoWallet(wallet1.Mnemonics), false)
 	require.NoError(t, err)
 
 	faucetAmount := float64(9)
 	balResp := apiClient.GetWalletBalance(t, wallet1, client.HttpOkStatus)
-	require.EqualValues(t, zcncore.ConvertToValue(faucetAmount), balResp.Balance)
+	require.EqualValues(t, currency.ZCNToSAS(faucetAmount), balResp.Balance)
 
 	currentNonce := balResp.Nonce
 	sameNonce := currentNonce + 2
 	numSameTxns := 5
 	wallets := make([]*model.Wallet, numSameTxns)
 	transactions := make(map[string]struct{}, numSameTxns)
-	value := int64(1)
+	value := currency.Coin(1)
 	for i := 0; i < numSameTxns; i++ {
-		wallets[i] = initialisedWallets[walletIdx]
-		walletIdx++
+		wallets[i] = createWallet(t)
 
 		txnResp, _, err := apiClient.V1TransactionPutWithNonceAndServiceProviders(
 			t,
@@ -109,8 +106,7 @@ func TestClientSendSameNonceForDifferentTransactions(testSetup *testing.T) {
 		require.True(t, ok, "hash: ", txn, " does not exist in extracted transaction list")
 	}
 
-	wallet2 := initialisedWallets[walletIdx]
-	walletIdx++
+	wallet2 := createWallet(t)
 
 	txnResp, _, err := apiClient.V1TransactionPutWithNonceAndServiceProviders(
 		t,
@@ -180,21 +176,19 @@ func TestClientSendTransactionToOnlyOneMiner(testSetup *testing.T) {
 	t := test.NewSystemTest(testSetup)
 	t.Skip()
 	t.Parallel()
-	wallet1 := initialisedWallets[walletIdx]
-	walletIdx++
+	wallet1 := createWallet(t)
 
 	err := zcncore.SetWallet(*wallet1.ToZCNCryptoWallet(wallet1.Mnemonics), false)
 	require.NoError(t, err)
 
 	faucetAmount := float64(9)
 	balResp := apiClient.GetWalletBalance(t, wallet1, client.HttpOkStatus)
-	require.EqualValues(t, zcncore.ConvertToValue(faucetAmount), balResp.Balance)
+	require.EqualValues(t, currency.ZCNToSAS(faucetAmount), balResp.Balance)
 	require.GreaterOrEqual(t, len(apiClient.Miners), 1)
 
-	wallet2 := initialisedWallets[walletIdx]
-	walletIdx++
+	wallet2 := createWallet(t)
 
-	value := int64(1)
+	value := currency.Coin(1)
 	miner := apiClient.Miners[0]
 	txnResp, _, err := apiClient.V1TransactionPutWithNonceAndServiceProviders(
 		t,
@@ -2,10 +2,10 @@ package api_tests
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"os"
-	"strconv"
-	"sync"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,7 +13,10 @@ import (
 	"github.com/0chain/system_test/internal/api/model"
 	"github.com/0chain/system_test/internal/api/util/client"
 	"github.com/0chain/system_test/internal/api/util/config"
+	"github.com/0chain/system_test/internal/api/util/keystore"
 	"github.com/0chain/system_test/internal/api/util/test"
+	"github.com/0chain/system_test/internal/api/util/walletpool"
+	"github.com/0chain/system_test/internal/currency"
 	"github.com/stretchr/testify/require"
 )
 
@@ -33,8 +36,15 @@ var (
 	parsedConfig                *config.Config
 
 	initialisedWallets []*model.Wallet
-	walletIdx          int64
-	walletMutex        sync.Mutex
+	walletPool         *walletpool.Pool
+)
+
+const (
+	// wallets with less than walletPoolMinBalance SAS are topped up by walletPoolTopUpAmount SAS when leased
+	walletPoolMinBalance  = 5 * 1e10
+	walletPoolTopUpAmount = 10 * 1e10
+
+	defaultWalletPoolStatePath = "./config/wallet_pool_state.json"
 )
 
 func TestMain(m *testing.M) {
@@ -44,23 +54,21 @@ func TestMain(m *testing.M) {
 		log.Printf("CONFIG_PATH environment variable is not set so has defaulted to [%v]", configPath)
 	}
 
-	parsedConfig = config.Parse(configPath)
+	var err error
+	parsedConfig, err = config.Load(config.API, configPath)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	log.Printf("Effective config from %s:\n%s", strings.Join(parsedConfig.Sources, ", "), parsedConfig.Redacted())
+	parsedConfig.Apply()
+
 	apiClient = client.NewAPIClient(parsedConfig.BlockWorker)
-	zs3Client = client.NewZS3Client(parsedConfig.ZS3ServerUrl)
+	zs3Client = client.NewZS3Client(parsedConfig.ZS3ServerUrl, parsedConfig.ZS3AccessKey, parsedConfig.ZS3SecretKey)
 	zboxClient = client.NewZboxClient(parsedConfig.ZboxUrl)
 	chimneyClient = client.NewAPIClient(parsedConfig.ChimneyTestNetwork)
 	chimneySdkClient = client.NewSDKClient(parsedConfig.ChimneyTestNetwork)
 	sdkClient = client.NewSDKClient(parsedConfig.BlockWorker)
 
-	defaultTestTimeout, err := time.ParseDuration(parsedConfig.DefaultTestCaseTimeout)
-	if err != nil {
-		log.Printf("Default test case timeout could not be parsed so has defaulted to [%v]", test.DefaultTestTimeout)
-	} else {
-		test.DefaultTestTimeout = defaultTestTimeout
-		test.SmokeTestMode, _ = strconv.ParseBool(os.Getenv("SMOKE_TEST_MODE"))
-		log.Printf("Default test case timeout is [%v]", test.DefaultTestTimeout)
-	}
-
 	t := test.NewSystemTest(new(testing.T))
 
 	err = zcncore.Init(getConfigForZcnCoreInit(parsedConfig.BlockWorker))
@@ -73,23 +81,29 @@ func TestMain(m *testing.M) {
 	ownerWallet = apiClient.CreateWalletForMnemonic(t, ownerWalletMnemonics)
 
 	// Read the content of the file
-	fileContent, err := os.ReadFile("./config/wallets.json")
+	fileContent, err := keystore.ReadFile("./config/wallets.json")
 	if err != nil {
 		log.Println("Error reading file:", err)
 		return
 	}
 
-	fileWallets := []WalletFile{}
+	var rawWallets []json.RawMessage
 
-	// Parse the JSON data into a list of strings
-	err = json.Unmarshal(fileContent, &fileWallets)
+	// Parse the JSON data into a list of wallets
+	err = json.Unmarshal(fileContent, &rawWallets)
 	if err != nil {
 		log.Println("Error decoding JSON:", err)
 		return
 	}
 
-	for i := range fileWallets {
-		wallet := fileWallets[i]
+	for i := range rawWallets {
+		var wallet WalletFile
+		err = json.Unmarshal(rawWallets[i], &wallet)
+		if err != nil {
+			log.Println("Error decoding JSON:", err)
+			return
+		}
+
 		initialisedWallet := &model.Wallet{
 			Id:        wallet.ClientId,
 			Version:   wallet.Version,
@@ -111,7 +125,32 @@ func TestMain(m *testing.M) {
 		initialisedWallets = append(initialisedWallets, initialisedWallet)
 	}
 
-	os.Exit(m.Run())
+	walletPoolStatePath, ok := os.LookupEnv(walletpool.StatePathEnv)
+	if !ok {
+		walletPoolStatePath = defaultWalletPoolStatePath
+	}
+
+	walletPool, err = walletpool.New(rawWallets, walletpool.Config{
+		StatePath:   walletPoolStatePath,
+		MinBalance:  walletPoolMinBalance,
+		TopUpAmount: walletPoolTopUpAmount,
+		Hooks: walletpool.Hooks{
+			Balance: pooledWalletBalance,
+			TopUp:   topUpPooledWallet,
+			Drain:   drainPooledWallet,
+		},
+	})
+	if err != nil {
+		log.Fatalln("Failed to create wallet pool:", err)
+	}
+
+	exitRun := m.Run()
+
+	if err := walletPool.Close(); err != nil {
+		log.Println("Error persisting wallet pool:", err)
+	}
+
+	os.Exit(exitRun)
 }
 
 func getConfigForZcnCoreInit(blockWorker string) string {
@@ -144,13 +183,57 @@ type WalletFile struct {
 	SignatureScheme interface{} `json:"SignatureScheme"`
 }
 
+// createWallet leases a funded wallet from the pool for the duration of the test
 func createWallet(t *test.SystemTest) *model.Wallet {
-	walletMutex.Lock()
-	wallet := initialisedWallets[walletIdx]
-	walletIdx++
+	leased := walletPool.Lease(t, t.Name())
+	wallet := initialisedWallets[leased.Index]
 	balance := apiClient.GetWalletBalance(t, wallet, client.HttpOkStatus)
 	wallet.Nonce = int(balance.Nonce)
-	walletMutex.Unlock()
 
 	return wallet
 }
+
+func pooledWalletBalance(t *test.SystemTest, leased *walletpool.Wallet) (currency.Coin, error) {
+	balance, _, err := apiClient.V1ClientGetBalance(t, model.ClientGetBalanceRequest{ClientID: leased.ClientID}, client.HttpOkStatus)
+	if err != nil {
+		return 0, err
+	}
+	if balance == nil {
+		return 0, fmt.Errorf("no balance returned for wallet %s", leased.ClientID)
+	}
+
+	return currency.Int64ToCoin(balance.Balance)
+}
+
+func topUpPooledWallet(t *test.SystemTest, leased *walletpool.Wallet, amount currency.Coin) error {
+	tokens, err := amount.ToZCN()
+	if err != nil {
+		return err
+	}
+
+	wallet := initialisedWallets[leased.Index]
+	balance, _, err := apiClient.V1ClientGetBalance(t, model.ClientGetBalanceRequest{ClientID: wallet.Id}, client.HttpOkStatus)
+	if err == nil && balance != nil {
+		wallet.Nonce = int(balance.Nonce)
+	}
+
+	apiClient.ExecuteFaucetWithTokens(t, wallet, tokens, client.TxSuccessfulStatus)
+
+	return nil
+}
+
+// drainPooledWallet unlocks the read pool of the wallet. Stake and write pools stay locked, their tokens are not part
+// of the balance recorded on release, so a wallet left short by them is topped up on its next lease.
+func drainPooledWallet(t *test.SystemTest, leased *walletpool.Wallet) error {
+	wallet := initialisedWallets[leased.Index]
+
+	readPool := apiClient.GetReadPoolBalance(t, wallet, client.HttpOkStatus)
+	if readPool == nil || readPool.Balance == 0 {
+		return nil
+	}
+
+	balance := apiClient.GetWalletBalance(t, wallet, client.HttpOkStatus)
+	wallet.Nonce = int(balance.Nonce)
+	apiClient.UnlockReadPool(t, wallet, client.TxSuccessfulStatus)
+	return nil
+}
@@ -295,10 +295,7 @@ func randName() string {
 }
 
 func createAllocationAndPerformMultiOperation(t *test.SystemTest, allocSize int64, filesCount, expectedFilesCount int, fileWithFormats bool, fileSizes []int64, secondaryOperation string) {
-	wallet := initialisedWallets[walletIdx]
-	walletIdx++
-	balance := apiClient.GetWalletBalance(t, wallet, client.HttpOkStatus)
-	wallet.Nonce = int(balance.Nonce)
+	wallet := createWallet(t)
 	sdkClient.SetWallet(t, wallet)
 
 	blobberRequirements := model.DefaultBlobberRequirements(wallet.Id, wallet.PublicKey)
@@ -19,10 +19,7 @@ import (
 func TestRepairAllocation(testSetup *testing.T) {
 	t := test.NewSystemTest(testSetup)
 
-	wallet := initialisedWallets[walletIdx]
-	walletIdx++
-	balance := apiClient.GetWalletBalance(t, wallet, client.HttpOkStatus)
-	wallet.Nonce = int(balance.Nonce)
+	wallet := createWallet(t)
 
 	sdkClient.SetWallet(t, wallet)
 
@@ -0,0 +1,91 @@
+package api_tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/0chain/system_test/internal/api/model"
+	"github.com/0chain/system_test/internal/api/util/client"
+	"github.com/0chain/system_test/internal/api/util/crypto"
+	"github.com/0chain/system_test/internal/api/util/test"
+	"github.com/0chain/system_test/internal/api/util/wait"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThresholdSignature(testSetup *testing.T) {
+	t := test.NewSystemTest(testSetup)
+	t.SetSmokeTests("Send transaction signed with threshold number of key shares should work")
+
+	const (
+		threshold = 3
+		numShares = 5
+	)
+
+	t.Run("Send transaction signed with threshold number of key shares should work", func(t *test.SystemTest) {
+		sender := createWallet(t)
+		receiver := createWallet(t)
+
+		shares := crypto.GenerateWalletThresholdShares(t, sender.Keys, threshold, numShares)
+
+		for _, signers := range [][]crypto.SignatureScheme{
+			shares[:threshold],
+			shares[numShares-threshold:],
+			{shares[0], shares[2], shares[4]},
+		} {
+			txnPutResponse, resp, err := apiClient.V1TransactionPutWithThresholdShares(
+				t,
+				model.InternalTransactionPutRequest{
+					Wallet:     sender,
+					ToClientID: receiver.Id,
+					Value:      client.TokenValue(0.1),
+					TxnType:    client.SendTxType,
+				},
+				signers,
+				client.HttpOkStatus)
+			require.Nil(t, err)
+			require.NotNil(t, resp)
+			require.NotNil(t, txnPutResponse)
+
+			var confirmation *model.TransactionGetConfirmationResponse
+			wait.PoolImmediately(t, time.Minute*2, func() bool {
+				confirmation, resp, err = apiClient.V1TransactionGetConfirmation(
+					t,
+					model.TransactionGetConfirmationRequest{
+						Hash: txnPutResponse.Request.Hash,
+					},
+					client.HttpOkStatus)
+				if err != nil || resp == nil || confirmation == nil {
+					return false
+				}
+
+				return confirmation.Status == client.TxSuccessfulStatus
+			})
+
+			sender.IncNonce()
+		}
+	})
+
+	t.Run("Send transaction signed with less than threshold number of key shares should fail", func(t *test.SystemTest) {
+		sender := createWallet(t)
+		receiver := createWallet(t)
+
+		shares := crypto.GenerateWalletThresholdShares(t, sender.Keys, threshold, numShares)
+
+		_, resp, err := apiClient.V1TransactionPutWithThresholdShares(
+			t,
+			model.InternalTransactionPutRequest{
+				Wallet:     sender,
+				ToClientID: receiver.Id,
+				Value:      client.TokenValue(0.1),
+				TxnType:    client.SendTxType,
+			},
+			shares[:threshold-1],
+			client.HttpBadRequestStatus)
+		require.Nil(t, err)
+		require.NotNil(t, resp)
+		require.Contains(t, string(resp.Body()), "invalid_signature")
+
+		balance := apiClient.GetWalletBalance(t, sender, client.HttpOkStatus)
+		require.EqualValues(t, sender.Nonce, balance.Nonce, "rejected transaction must not be applied")
+	})
+}
@@ -0,0 +1,78 @@
+package api_tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/0chain/system_test/internal/api/model"
+	"github.com/0chain/system_test/internal/api/util/client"
+	"github.com/0chain/system_test/internal/api/util/ledger"
+	"github.com/0chain/system_test/internal/api/util/test"
+	"github.com/0chain/system_test/internal/api/util/wait"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenConservation(testSetup *testing.T) {
+	t := test.NewSystemTest(testSetup)
+	t.SetSmokeTests("Tokens should be conserved across faucet, transfer and read pool lock")
+
+	t.Run("Tokens should be conserved across faucet, transfer and read pool lock", func(t *test.SystemTest) {
+		sender := createWallet(t)
+		receiver := createWallet(t)
+
+		l := ledger.New(apiClient).
+			TrackWallet(sender.Id).
+			TrackWallet(receiver.Id).
+			TrackReadPool(sender.Id)
+		l.Begin(t)
+
+		apiClient.ExecuteFaucetWithTokens(t, sender, 1, client.TxSuccessfulStatus)
+		sendTokens(t, sender, receiver.Id, 0.5)
+		apiClient.CreateReadPool(t, sender, 0.5, client.TxSuccessfulStatus)
+
+		l.AssertConserved(t)
+	})
+
+	t.Run("Tokens sent to an untracked wallet should leave the ledger", func(t *test.SystemTest) {
+		sender := createWallet(t)
+		receiver := createWallet(t)
+
+		l := ledger.New(apiClient).TrackWallet(sender.Id)
+		l.Begin(t)
+
+		sendTokens(t, sender, receiver.Id, 0.5)
+
+		l.AssertConserved(t)
+	})
+}
+
+func sendTokens(t *test.SystemTest, sender *model.Wallet, toClientID string, tokens float64) {
+	txnPutResponse, resp, err := apiClient.V1TransactionPut(
+		t,
+		model.InternalTransactionPutRequest{
+			Wallet:     sender,
+			ToClientID: toClientID,
+			Value:      client.TokenValue(tokens),
+			TxnType:    client.SendTxType,
+		},
+		client.HttpOkStatus)
+	require.Nil(t, err)
+	require.NotNil(t, resp)
+	require.NotNil(t, txnPutResponse)
+
+	wait.PoolImmediately(t, time.Minute*2, func() bool {
+		confirmation, resp, err := apiClient.V1TransactionGetConfirmation(
+			t,
+			model.TransactionGetConfirmationRequest{
+				Hash: txnPutResponse.Request.Hash,
+			},
+			client.HttpOkStatus)
+		if err != nil || resp == nil || confirmation == nil {
+			return false
+		}
+
+		return confirmation.Status == client.TxSuccessfulStatus
+	})
+
+	sender.IncNonce()
+}
@@ -1,17 +1,24 @@
 package api_tests
 
 import (
+	"bytes"
+	"crypto/md5" //nolint:gosec
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/0chain/system_test/internal/api/util/client"
 	"github.com/0chain/system_test/internal/api/util/test"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/stretchr/testify/require"
 )
 
-const (
-	AccessKey       = "rootroot"
-	SecretAccessKey = "rootroot"
-)
-
 func TestZs3ServerOperations(testSetup *testing.T) {
 	t := test.NewSystemTest(testSetup)
 	t.Parallel()
@@ -26,8 +33,8 @@ func TestZs3ServerOperations(testSetup *testing.T) {
 
 	t.Run("Zs3 server should return 500 when the action doesn't exist", func(t *test.SystemTest) {
 		queryParams := map[string]string{
-			"accessKey":       AccessKey,
-			"secretAccessKey": SecretAccessKey,
+			"accessKey":       zs3Client.AccessKey,
+			"secretAccessKey": zs3Client.SecretKey,
 			"action":          "random-action",
 		}
 		resp, err := zs3Client.BucketOperation(t, queryParams, map[string]string{})
@@ -38,7 +45,7 @@ func TestZs3ServerOperations(testSetup *testing.T) {
 	t.Run("zs3 server should return 500 when the credentials aren't correct", func(t *test.SystemTest) {
 		queryParams := map[string]string{
 			"accessKey":       "wrong-access-key",
-			"secretAccessKey": SecretAccessKey,
+			"secretAccessKey": zs3Client.SecretKey,
 			"action":          "createBucket",
 			"bucketName":      "test",
 		}
@@ -49,8 +56,8 @@ func TestZs3ServerOperations(testSetup *testing.T) {
 
 	t.RunSequentially("CreateBucket should return 200 when all the parameters are correct", func(t *test.SystemTest) {
 		queryParams := map[string]string{
-			"accessKey":       AccessKey,
-			"secretAccessKey": SecretAccessKey,
+			"accessKey":       zs3Client.AccessKey,
+			"secretAccessKey": zs3Client.SecretKey,
 			"action":          "createBucket",
 			"bucketName":      "system-test",
 		}
@@ -61,8 +68,8 @@ func TestZs3ServerOperations(testSetup *testing.T) {
 
 	t.RunSequentially("CreateBucket should not return error when bucket name already exist", func(t *test.SystemTest) {
 		queryParams := map[string]string{
-			"accessKey":       AccessKey,
-			"secretAccessKey": SecretAccessKey,
+			"accessKey":       zs3Client.AccessKey,
+			"secretAccessKey": zs3Client.SecretKey,
 			"action":          "createBucket",
 			"bucketName":      "system-test",
 		}
@@ -73,8 +80,8 @@ func TestZs3ServerOperations(testSetup *testing.T) {
 
 	t.RunSequentially("ListBucket should return 200 all the parameter are correct", func(t *test.SystemTest) {
 		queryParams := map[string]string{
-			"accessKey":       AccessKey,
-			"secretAccessKey": SecretAccessKey,
+			"accessKey":       zs3Client.AccessKey,
+			"secretAccessKey": zs3Client.SecretKey,
 			"action":          "listBuckets",
 		}
 		resp, err := zs3Client.BucketOperation(t, queryParams, map[string]string{})
@@ -84,8 +91,8 @@ func TestZs3ServerOperations(testSetup *testing.T) {
 
 	t.RunSequentially("ListObjects should return 200 all the parameter are correct", func(t *test.SystemTest) {
 		queryParams := map[string]string{
-			"accessKey":       AccessKey,
-			"secretAccessKey": SecretAccessKey,
+			"accessKey":       zs3Client.AccessKey,
+			"secretAccessKey": zs3Client.SecretKey,
 			"action":          "listObjects",
 			"bucketName":      "system-test",
 		}
@@ -96,8 +103,8 @@ func TestZs3ServerOperations(testSetup *testing.T) {
 
 	t.RunSequentially("PutObjects should return 200 all the parameter are correct", func(t *test.SystemTest) {
 		queryParams := map[string]string{
-			"accessKey":       AccessKey,
-			"secretAccessKey": SecretAccessKey,
+			"accessKey":       zs3Client.AccessKey,
+			"secretAccessKey": zs3Client.SecretKey,
 			"action":          "createBucket",
 			"bucketName":      "system-test",
 		}
@@ -106,8 +113,8 @@ func TestZs3ServerOperations(testSetup *testing.T) {
 		require.Equal(t, 200, resp.StatusCode())
 
 		queryParams = map[string]string{
-			"accessKey":       AccessKey,
-			"secretAccessKey": SecretAccessKey,
+			"accessKey":       zs3Client.AccessKey,
+			"secretAccessKey": zs3Client.SecretKey,
 			"action":          "putObject",
 			"bucketName":      "system-test",
 		}
@@ -121,8 +128,8 @@ func TestZs3ServerOperations(testSetup *testing.T) {
 
 	t.RunSequentially("GetObjects should return 200 all the parameter are correct", func(t *test.SystemTest) {
 		queryParams := map[string]string{
-			"accessKey":       AccessKey,
-			"secretAccessKey": SecretAccessKey,
+			"accessKey":       zs3Client.AccessKey,
+			"secretAccessKey": zs3Client.SecretKey,
 			"action":          "getObject",
 			"bucketName":      "system-test",
 			"objectName":      "test-file.txt",
@@ -137,8 +144,8 @@ func TestZs3ServerOperations(testSetup *testing.T) {
 
 	t.RunSequentially("PutObjects should return error when buckcet name does not exist", func(t *test.SystemTest) {
 		queryParams := map[string]string{
-			"accessKey":       AccessKey,
-			"secretAccessKey": SecretAccessKey,
+			"accessKey":       zs3Client.AccessKey,
+			"secretAccessKey": zs3Client.SecretKey,
 			"action":          "putObject",
 			"bucketName":      "This bucket doesnot exist",
 		}
@@ -153,8 +160,8 @@ func TestZs3ServerOperations(testSetup *testing.T) {
 
 	t.RunSequentially("RemoveObject should return 200 all the parameter are correct", func(t *test.SystemTest) {
 		queryParams := map[string]string{
-			"accessKey":       AccessKey,
-			"secretAccessKey": SecretAccessKey,
+			"accessKey":       zs3Client.AccessKey,
+			"secretAccessKey": zs3Client.SecretKey,
 			"action":          "createBucket",
 			"bucketName":      "system-test",
 			"objectName":      "bucket created as a part of " + t.Name(),
@@ -163,8 +170,8 @@ func TestZs3ServerOperations(testSetup *testing.T) {
 		require.Nil(t, err)
 		require.Equal(t, 200, resp.StatusCode())
 		queryParams = map[string]string{
-			"accessKey":       AccessKey,
-			"secretAccessKey": SecretAccessKey,
+			"accessKey":       zs3Client.AccessKey,
+			"secretAccessKey": zs3Client.SecretKey,
 			"action":          "removeObject",
 			"bucketName":      "system-test",
 			"objectName":      "bucket created as a part of " + t.Name(),
@@ -176,8 +183,8 @@ func TestZs3ServerOperations(testSetup *testing.T) {
 
 	t.RunSequentially("RemoveObject should not return error if object doen't exist", func(t *test.SystemTest) {
 		queryParams := map[string]string{
-			"accessKey":       AccessKey,
-			"secretAccessKey": SecretAccessKey,
+			"accessKey":       zs3Client.AccessKey,
+			"secretAccessKey": zs3Client.SecretKey,
 			"action":          "removeObject",
 			"bucketName":      "system-test",
 			"objectName":      "file name created as a part of " + t.Name(),
@@ -190,8 +197,8 @@ func TestZs3ServerOperations(testSetup *testing.T) {
 	// FIXME - this should be 400 not 500
 	t.Run("CreateBucket should return 500 when one of more required parameters are missing", func(t *test.SystemTest) {
 		queryParams := map[string]string{
-			"accessKey":       AccessKey,
-			"secretAccessKey": SecretAccessKey,
+			"accessKey":       zs3Client.AccessKey,
+			"secretAccessKey": zs3Client.SecretKey,
 			"action":          "createBucket",
 		}
 		resp, err := zs3Client.BucketOperation(t, queryParams, map[string]string{})
@@ -201,7 +208,7 @@ func TestZs3ServerOperations(testSetup *testing.T) {
 
 	t.Run("ListBuckets should return 500 when one of more required parameters are missing", func(t *test.SystemTest) {
 		queryParams := map[string]string{
-			"secretAccessKey": SecretAccessKey,
+			"secretAccessKey": zs3Client.SecretKey,
 			"action":          "listBucket",
 		}
 		resp, err := zs3Client.BucketOperation(t, queryParams, map[string]string{})
@@ -211,8 +218,8 @@ func TestZs3ServerOperations(testSetup *testing.T) {
 
 	t.Run("listObjects should return 500 when trying to list objects from un existing bucket", func(t *test.SystemTest) {
 		queryParams := map[string]string{
-			"accessKey":       AccessKey,
-			"secretAccessKey": SecretAccessKey,
+			"accessKey":       zs3Client.AccessKey,
+			"secretAccessKey": zs3Client.SecretKey,
 			"action":          "listObjects",
 			"bucketName":      "random-bucket",
 		}
@@ -221,3 +228,155 @@ func TestZs3ServerOperations(testSetup *testing.T) {
 		require.Equal(t, 500, resp.StatusCode())
 	})
 }
+
+func TestZs3ServerS3API(testSetup *testing.T) {
+	t := test.NewSystemTest(testSetup)
+	t.Parallel()
+
+	bucket := "system-test-s3-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	require.NoError(t, zs3Client.CreateBucket(t, bucket))
+	t.Cleanup(func() {
+		cleanup := test.NewSystemTest(testSetup)
+		objects, _, err := zs3Client.ListObjects(cleanup, bucket, "", 1000)
+		if err == nil && len(objects) > 0 {
+			keys := make([]string, 0, len(objects))
+			for _, object := range objects {
+				keys = append(keys, aws.StringValue(object.Key))
+			}
+			_, _ = zs3Client.DeleteObjects(cleanup, bucket, keys...)
+		}
+		_ = zs3Client.DeleteBucket(cleanup, bucket)
+	})
+
+	content := []byte("0123456789 system test object content")
+
+	t.RunSequentially("PutObject then GetObject should return the same content and metadata", func(t *test.SystemTest) {
+		put, err := zs3Client.PutObject(t, bucket, "objects/plain.txt", content, map[string]string{"Origin": "system-test"})
+		require.NoError(t, err)
+		require.Equal(t, quotedMD5(content), aws.StringValue(put.ETag))
+
+		got, output, err := zs3Client.GetObject(t, bucket, "objects/plain.txt", "")
+		require.NoError(t, err)
+		require.Equal(t, content, got)
+		require.Equal(t, int64(len(content)), aws.Int64Value(output.ContentLength))
+		require.Equal(t, "system-test", aws.StringValue(output.Metadata["Origin"]))
+	})
+
+	t.RunSequentially("GetObject with a range should return only the requested bytes", func(t *test.SystemTest) {
+		got, output, err := zs3Client.GetObject(t, bucket, "objects/plain.txt", "bytes=2-5")
+		require.NoError(t, err)
+		require.Equal(t, content[2:6], got)
+		require.Equal(t, fmt.Sprintf("bytes 2-5/%d", len(content)), aws.StringValue(output.ContentRange))
+	})
+
+	t.RunSequentially("HeadObject should return the size and ETag without the content", func(t *test.SystemTest) {
+		output, err := zs3Client.HeadObject(t, bucket, "objects/plain.txt")
+		require.NoError(t, err)
+		require.Equal(t, int64(len(content)), aws.Int64Value(output.ContentLength))
+		require.Equal(t, quotedMD5(content), aws.StringValue(output.ETag))
+	})
+
+	t.Run("HeadObject and GetObject of a missing object should return 404", func(t *test.SystemTest) {
+		_, err := zs3Client.HeadObject(t, bucket, "objects/missing.txt")
+		_, status := client.S3Error(err)
+		require.Equal(t, http.StatusNotFound, status, err)
+
+		_, _, err = zs3Client.GetObject(t, bucket, "objects/missing.txt", "")
+		code, status := client.S3Error(err)
+		require.Equal(t, "NoSuchKey", code, err)
+		require.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.RunSequentially("ListObjectsV2 should page through all the objects with the prefix", func(t *test.SystemTest) {
+		var keys []string
+		for i := 0; i < 5; i++ {
+			key := fmt.Sprintf("listed/object-%d.txt", i)
+			keys = append(keys, key)
+			_, err := zs3Client.PutObject(t, bucket, key, []byte(key), nil)
+			require.NoError(t, err)
+		}
+
+		objects, pages, err := zs3Client.ListObjects(t, bucket, "listed/", 2)
+		require.NoError(t, err)
+		require.Equal(t, 3, pages)
+		listed := make([]string, 0, len(objects))
+		for _, object := range objects {
+			listed = append(listed, aws.StringValue(object.Key))
+		}
+		require.Equal(t, keys, listed)
+	})
+
+	t.RunSequentially("CopyObject should copy the content to the new key", func(t *test.SystemTest) {
+		_, err := zs3Client.CopyObject(t, bucket, "objects/plain.txt", bucket, "objects/copy of plain.txt")
+		require.NoError(t, err)
+
+		got, _, err := zs3Client.GetObject(t, bucket, "objects/copy of plain.txt", "")
+		require.NoError(t, err)
+		require.Equal(t, content, got)
+	})
+
+	t.RunSequentially("DeleteObjects should delete all the objects in one request", func(t *test.SystemTest) {
+		keys := []string{"listed/object-0.txt", "listed/object-1.txt", "listed/object-2.txt", "listed/object-3.txt", "listed/object-4.txt"}
+		output, err := zs3Client.DeleteObjects(t, bucket, keys...)
+		require.NoError(t, err)
+		require.Empty(t, output.Errors)
+		require.Len(t, output.Deleted, len(keys))
+
+		objects, _, err := zs3Client.ListObjects(t, bucket, "listed/", 2)
+		require.NoError(t, err)
+		require.Empty(t, objects)
+	})
+
+	t.RunSequentially("Multipart upload should join the parts in order", func(t *test.SystemTest) {
+		first := bytes.Repeat([]byte("a"), 5*1024*1024)
+		last := []byte("the last part may be small")
+		output, err := zs3Client.MultipartUpload(t, bucket, "objects/multipart.bin", first, last)
+		require.NoError(t, err)
+		require.True(t, strings.HasSuffix(aws.StringValue(output.ETag), `-2"`), "multipart ETag %s", aws.StringValue(output.ETag))
+
+		got, _, err := zs3Client.GetObject(t, bucket, "objects/multipart.bin", fmt.Sprintf("bytes=%d-", len(first)-1))
+		require.NoError(t, err)
+		require.Equal(t, append([]byte("a"), last...), got)
+	})
+
+	t.RunSequentially("Presigned URLs should allow uploads and downloads without credentials", func(t *test.SystemTest) {
+		uploadURL, err := zs3Client.PresignPutObject(t, bucket, "objects/presigned.txt", 5*time.Minute)
+		require.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPut, uploadURL, bytes.NewReader(content))
+		require.NoError(t, err)
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_ = res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		downloadURL, err := zs3Client.PresignGetObject(t, bucket, "objects/presigned.txt", 5*time.Minute)
+		require.NoError(t, err)
+		res, err = http.Get(downloadURL) //nolint:gosec
+		require.NoError(t, err)
+		got, err := io.ReadAll(res.Body)
+		_ = res.Body.Close()
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode, string(got))
+		require.Equal(t, content, got)
+	})
+
+	t.Run("Requests signed with a wrong secret key should be rejected with 403", func(t *test.SystemTest) {
+		_, err := zs3Client.WithCredentials(zs3Client.AccessKey, "wrong-secret-key").ListObjectsV2(&s3.ListObjectsV2Input{Bucket: aws.String(bucket)})
+		code, status := client.S3Error(err)
+		require.Equal(t, "SignatureDoesNotMatch", code, err)
+		require.Equal(t, http.StatusForbidden, status)
+	})
+
+	t.Run("Requests with an unknown access key should be rejected with 403", func(t *test.SystemTest) {
+		_, err := zs3Client.WithCredentials("unknown-access-key", zs3Client.SecretKey).ListObjectsV2(&s3.ListObjectsV2Input{Bucket: aws.String(bucket)})
+		code, status := client.S3Error(err)
+		require.Equal(t, "InvalidAccessKeyId", code, err)
+		require.Equal(t, http.StatusForbidden, status)
+	})
+}
+
+// quotedMD5 is the ETag S3 returns for an object uploaded in a single part
+func quotedMD5(content []byte) string {
+	sum := md5.Sum(content) //nolint:gosec
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
@@ -1,7 +1,6 @@
 package cli_tests
 
 import (
-	"encoding/json"
 	"math"
 	"strings"
 	"testing"
@@ -9,6 +8,9 @@ import (
 
 	"github.com/0chain/system_test/internal/api/util/test"
 	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
+	cliutils "github.com/0chain/system_test/internal/cli/util"
+	"github.com/0chain/system_test/internal/currency"
 	"github.com/stretchr/testify/require"
 )
 
@@ -59,8 +61,7 @@ func TestStakePool(testSetup *testing.T) {
 		output, err := getBlobberInfo(t, configPath, createParams(map[string]interface{}{"json": "", "blobber_id": minAvailableCapacityBlobber.Id}))
 		require.Nil(t, err, "Error fetching blobber info", strings.Join(output, "\n"))
 
-		err = json.Unmarshal([]byte(output[len(output)-1]), &minAvailableCapacityBlobber)
-		require.Nil(t, err, "error unmarshalling blobber info")
+		minAvailableCapacityBlobber = cliparse.RequireJSON[climodel.BlobberInfo](t, output)
 
 		totalOffersNew := minAvailableCapacityBlobber.TotalOffers
 		require.Greater(t, totalOffersNew, totalOffers, "Total Offers should Increase")
@@ -71,13 +72,13 @@ func TestStakePool(testSetup *testing.T) {
 		lenDelegates = assertNumberOfDelegates(t, minAvailableCapacityBlobber.Id, lenDelegates+1)
 
 		// Unstake tokens from new wallet and check if number of delegates decreases
-		_, err = unstakeTokensForWallet(t, configPath, newStakeWallet, createParams(map[string]interface{}{"blobber_id": minAvailableCapacityBlobber.Id}), true)
+		_, err = unstakeTokensForWallet(t, configPath, newStakeWallet, cliutils.ZboxStakeUnlock{BlobberID: minAvailableCapacityBlobber.Id}, true)
 		require.NoErrorf(t, err, "error unstaking tokens from new wallet for blobber %s", minAvailableCapacityBlobber.Id)
 
 		lenDelegates = assertNumberOfDelegates(t, minAvailableCapacityBlobber.Id, lenDelegates-1)
 
 		// Unstake tokens from old wallet (should return error and number of delegate should not decrease)
-		_, err = unstakeTokens(t, configPath, createParams(map[string]interface{}{"blobber_id": minAvailableCapacityBlobber.Id}), false)
+		_, err = unstakeTokens(t, configPath, cliutils.ZboxStakeUnlock{BlobberID: minAvailableCapacityBlobber.Id}, false)
 		require.Error(t, err, "No error in unstaking tokens from old wallet for blobber %s", minAvailableCapacityBlobber.Id)
 
 		lenDelegates = assertNumberOfDelegates(t, minAvailableCapacityBlobber.Id, lenDelegates)
@@ -87,7 +88,7 @@ func TestStakePool(testSetup *testing.T) {
 		require.Nil(t, err, "error canceling allocation")
 
 		// Unstake tokens from old wallet (should be successful and number of delegate should decrease)
-		_, err = unstakeTokens(t, configPath, createParams(map[string]interface{}{"blobber_id": minAvailableCapacityBlobber.Id}), true)
+		_, err = unstakeTokens(t, configPath, cliutils.ZboxStakeUnlock{BlobberID: minAvailableCapacityBlobber.Id}, true)
 		require.NoErrorf(t, err, "error unstaking tokens from blobber %s", minAvailableCapacityBlobber.Id)
 
 		assertNumberOfDelegates(t, minAvailableCapacityBlobber.Id, lenDelegates-1)
@@ -109,9 +110,7 @@ func getMinStakedCapacityBlobber(t *test.SystemTest) (climodel.BlobberInfo, int6
 		output, err := getBlobberInfo(t, configPath, createParams(map[string]interface{}{"json": "", "blobber_id": blobber.Id}))
 		require.Nil(t, err, "Error fetching blobber info", strings.Join(output, "\n"))
 
-		var blInfo climodel.BlobberInfo
-		err = json.Unmarshal([]byte(output[len(output)-1]), &blInfo)
-		require.Nil(t, err, "error unmarshalling blobber info")
+		blInfo := cliparse.RequireJSON[climodel.BlobberInfo](t, output)
 
 		stakedCapacity := int64(float64(blInfo.TotalStake-blInfo.TotalOffers) * GB / float64(blInfo.Terms.WritePrice))
 
@@ -126,24 +125,19 @@ func getMinStakedCapacityBlobber(t *test.SystemTest) (climodel.BlobberInfo, int6
 	output, err := getBlobberInfo(t, configPath, createParams(map[string]interface{}{"json": "", "blobber_id": minAvailableCapacityBlobber.Id}))
 	require.Nil(t, err, "Error fetching blobber info", strings.Join(output, "\n"))
 
-	var blInfo climodel.BlobberInfo
-	err = json.Unmarshal([]byte(output[len(output)-1]), &blInfo)
-	require.Nil(t, err, "error unmarshalling blobber info")
+	blInfo := cliparse.RequireJSON[climodel.BlobberInfo](t, output)
 
 	return blInfo, minAvailableCapacity, nil
 }
 
 func countDelegates(t *test.SystemTest, blobberId string) (int, error) {
-	output, err := stakePoolInfo(t, configPath, createParams(map[string]interface{}{
-		"blobber_id": blobberId,
-		"json":       "",
-	}))
+	output, err := stakePoolInfo(t, configPath, cliutils.ZboxStakePoolInfo{BlobberID: blobberId, JSON: true})
 	if err != nil {
 		return 0, err
 	}
 
 	stakePool := climodel.StakePoolInfo{}
-	err = json.Unmarshal([]byte(output[len(output)-1]), &stakePool)
+	_, err = cliparse.JSON(output, &stakePool)
 	if err != nil {
 		return 0, err
 	}
@@ -153,28 +147,28 @@ func countDelegates(t *test.SystemTest, blobberId string) (int, error) {
 
 func createAllocationOfMaxSizeBlobbersCanHonour(t *test.SystemTest, minAvailableCapacity int64, numBlobbers int) string {
 	allocSize := minAvailableCapacity - 10*MB
-	output, err := createNewAllocation(t, configPath, createParams(map[string]interface{}{
+	output, err := createNewAllocation(t, configPath, map[string]interface{}{
 		"cost":        "",
 		"data":        1,
 		"parity":      numBlobbers - 1,
 		"size":        allocSize,
 		"read_price":  "0-0.1",
 		"write_price": "0-0.1",
-	}))
+	})
 	require.Nil(t, err, strings.Join(output, "\n"))
 	require.Len(t, output, 1)
 	allocationCost, err := getAllocationCost(output[0])
 	require.Nil(t, err, "could not get allocation cost")
 
 	// Create an allocation of maximum size that all blobbers can honor.
-	output, err = createNewAllocation(t, configPath, createParams(map[string]interface{}{
+	output, err = createNewAllocation(t, configPath, map[string]interface{}{
 		"size":        allocSize,
 		"data":        1,
 		"parity":      numBlobbers - 1,
-		"lock":        allocationCost,
+		"lock":        allocationCost.Decimal(currency.ZCN),
 		"read_price":  "0-0.1",
 		"write_price": "0-0.1",
-	}))
+	})
 	require.Nil(t, err, "Error creating new allocation", err)
 
 	allocationId, err := getAllocationID(output[len(output)-1])
@@ -185,9 +179,9 @@ func createAllocationOfMaxSizeBlobbersCanHonour(t *test.SystemTest, minAvailable
 
 func createWalletAndStakeTokensForWallet(t *test.SystemTest, blobber *climodel.BlobberInfo) {
 	// Stake 1 token from new wallet
-	createWalletForName(newStakeWallet)
+	createWalletForName(t, newStakeWallet)
 
-	_, err := stakeTokensForWallet(t, configPath, newStakeWallet, createParams(map[string]interface{}{"blobber_id": blobber.Id, "tokens": 1}), true)
+	_, err := stakeTokensForWallet(t, configPath, newStakeWallet, map[string]interface{}{"blobber_id": blobber.Id, "tokens": 1}, true)
 	require.Nil(t, err, "Error staking tokens", err)
 }
 
@@ -210,7 +204,7 @@ func stakeTokensToAllBlobbers(t *test.SystemTest, tokens int64) {
 		if blobber.IsKilled || blobber.IsShutdown {
 			continue
 		}
-		_, err := stakeTokens(t, configPath, createParams(map[string]interface{}{"blobber_id": blobber.Id, "tokens": tokens}), true)
+		_, err := stakeTokens(t, configPath, map[string]interface{}{"blobber_id": blobber.Id, "tokens": tokens}, true)
 		require.Nil(t, err, "Error staking tokens", err)
 	}
 }
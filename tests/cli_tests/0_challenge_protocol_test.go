@@ -18,6 +18,7 @@ import (
 	"github.com/0chain/system_test/internal/api/util/test"
 
 	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	"github.com/stretchr/testify/require"
 )
 
@@ -40,9 +41,7 @@ func TestProtocolChallenge(testSetup *testing.T) {
 		require.Greater(t, len(output), 1)
 		require.Equal(t, "MagicBlock Sharders", output[0])
 
-		var sharders map[string]*climodel.Sharder
-		err = json.Unmarshal([]byte(strings.Join(output[1:], "")), &sharders)
-		require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output[1:], "\n"), err)
+		sharders := cliparse.RequireJSON[map[string]*climodel.Sharder](t, output)
 		require.NotEmpty(t, sharders, "No sharders found: %v", strings.Join(output[1:], "\n"))
 
 		// Get base URL for API calls.
@@ -54,8 +53,7 @@ func TestProtocolChallenge(testSetup *testing.T) {
 		require.Nil(t, err, "Error listing blobbers", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		err = json.Unmarshal([]byte(output[0]), &blobberList)
-		require.Nil(t, err, "Error unmarshalling blobber list", strings.Join(output, "\n"))
+		blobberList = cliparse.RequireJSON[[]climodel.BlobberInfo](t, output)
 		require.True(t, len(blobberList) > 0, "No blobbers found in blobber list")
 	})
 
@@ -10,6 +10,7 @@ import (
 
 	"github.com/0chain/system_test/internal/api/util/test"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
+	"github.com/0chain/system_test/internal/currency"
 
 	"github.com/stretchr/testify/require"
 )
@@ -131,10 +132,10 @@ func TestExpiredAllocation(testSetup *testing.T) {
 		createWallet(t)
 
 		// Lock 0.5 token for allocation
-		allocParams := createParams(map[string]interface{}{
+		allocParams := map[string]interface{}{
 			"size": "2048",
 			"lock": "1",
-		})
+		}
 		output, err := createNewAllocation(t, configPath, allocParams)
 		require.Nil(t, err, "Failed to create new allocation", strings.Join(output, "\n"))
 
@@ -161,18 +162,17 @@ func TestExpiredAllocation(testSetup *testing.T) {
 		require.NoError(t, err)
 
 		// assert balance reduced by 1 ZCN and txn fee
-		require.Less(t, balanceAfterLock, balance-1)
+		require.Less(t, balanceAfterLock, balance-zcn(1))
 
 		// Write pool balance should increment by 1
 		allocation := getAllocation(t, allocationID)
-		require.Equal(t, 2.0, intToZCN(allocation.WritePool))
+		requireBalance(t, "2 ZCN", allocation.WritePool)
 
 		allocationCost := 0.0
 		for _, blobber := range allocation.BlobberDetails {
 			allocationCost += sizeInGB(1024) * float64(blobber.Terms.WritePrice)
 		}
-		allocationCancellationCharge := allocationCost * 0.2 // 20% of total allocation cost
-		allocationCancellationChargeInZCN := allocationCancellationCharge / 1e10
+		allocationCancellationCharge := currency.Coin(allocationCost * 0.2) // 20% of total allocation cost
 
 		// get balance before finalize
 		balanceBeforeFinalize, err := getBalanceZCN(t, configPath)
@@ -191,7 +191,7 @@ func TestExpiredAllocation(testSetup *testing.T) {
 		require.NoError(t, err)
 
 		// assert after unlock, balance is greater than before finalize, but need to pay fee
-		require.InEpsilon(t, balanceAfterFinalize, balanceBeforeFinalize+2.0-allocationCancellationChargeInZCN, 0.05)
+		requireBalanceInEpsilon(t, balanceBeforeFinalize+zcn(2)-allocationCancellationCharge, balanceAfterFinalize, 0.05)
 	})
 }
 
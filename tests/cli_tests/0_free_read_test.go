@@ -1,7 +1,6 @@
 package cli_tests
 
 import (
-	"encoding/json"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -10,6 +9,8 @@ import (
 	"time"
 
 	"github.com/0chain/system_test/internal/api/util/test"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
+	"github.com/0chain/system_test/internal/currency"
 
 	climodel "github.com/0chain/system_test/internal/cli/model"
 	"github.com/stretchr/testify/require"
@@ -30,8 +31,7 @@ func TestFreeReads(testSetup *testing.T) {
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.Len(t, output, 1, strings.Join(output, "\n"))
 
-		err = json.Unmarshal([]byte(output[0]), &blobberList)
-		require.Nil(t, err, strings.Join(output, "\n"))
+		blobberList = cliparse.RequireJSON[[]climodel.BlobberDetails](t, output)
 		require.Greater(t, len(blobberList), 0, "blobber list is empty")
 
 		// Set read price to 0 on all blobbers
@@ -47,7 +47,7 @@ func TestFreeReads(testSetup *testing.T) {
 	// revert read prices irrespective of test results
 	t.Cleanup(func() {
 		for _, blobber := range blobberList {
-			output, err := updateBlobberInfo(t, configPath, createParams(map[string]interface{}{"blobber_id": blobber.ID, "read_price": intToZCN(blobber.Terms.ReadPrice)}))
+			output, err := updateBlobberInfo(t, configPath, createParams(map[string]interface{}{"blobber_id": blobber.ID, "read_price": currency.SASToZCN(blobber.Terms.ReadPrice)}))
 			require.Nil(t, err, strings.Join(output, "\n"))
 		}
 	})
@@ -59,7 +59,7 @@ func TestFreeReads(testSetup *testing.T) {
 
 		// Create an allocation
 		options := map[string]interface{}{"size": 1 * MB, "lock": "0.5", "read_price": "0-0"}
-		output, err := createNewAllocation(t, configPath, createParams(options))
+		output, err := createNewAllocation(t, configPath, options)
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.True(t, len(output) > 0, "expected output length be at least 1")
 		require.Regexp(t, regexp.MustCompile("^Allocation created: [0-9a-fA-F]{64}$"), output[0], strings.Join(output, "\n"))
@@ -105,7 +105,7 @@ func TestFreeReads(testSetup *testing.T) {
 
 			// Create an allocation
 			options := map[string]interface{}{"size": 1 * MB, "lock": "0.5", "read_price": "0-0"}
-			output, err := createNewAllocation(t, configPath, createParams(options))
+			output, err := createNewAllocation(t, configPath, options)
 			require.Nil(t, err, strings.Join(output, "\n"))
 			require.True(t, len(output) > 0, "expected output length be at least 1")
 			require.Regexp(t, regexp.MustCompile("^Allocation created: [0-9a-fA-F]{64}$"), output[0], strings.Join(output, "\n"))
@@ -25,6 +25,9 @@ func Test0S3MigrationAlternatePart2(testSetup *testing.T) {
 	if s3SecretKey == "" || s3AccessKey == "" {
 		t.Skip("s3SecretKey or s3AccessKey was missing")
 	}
+	if s3Unavailable != "" {
+		t.Skip(s3Unavailable)
+	}
 
 	fileKey := "OneMinNew" + ".txt"
 	t.TestSetup("Setup s3 bucket with relevant file", func() {
@@ -3,7 +3,6 @@ package cli_tests
 import (
 	"bytes"
 	"crypto/rand"
-	"encoding/json"
 	"fmt"
 	"math"
 	"math/big"
@@ -16,6 +15,7 @@ import (
 
 	"github.com/0chain/system_test/internal/api/util/test"
 	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/stretchr/testify/require"
@@ -35,6 +35,9 @@ func Test0S3MigrationAlternate(testSetup *testing.T) {
 	if s3SecretKey == "" || s3AccessKey == "" {
 		t.Skip("s3SecretKey or s3AccessKey was missing")
 	}
+	if s3Unavailable != "" {
+		t.Skip(s3Unavailable)
+	}
 
 	fileKey := "sdfg" + ".txt"
 	t.TestSetup("Setup s3 bucket with relevant file", func() {
@@ -334,8 +337,7 @@ func checkStats(t *test.SystemTest, remoteFilePath, fname, allocationID string,
 
 	var stats map[string]*climodel.FileStats
 	t.Log(output[0])
-	err = json.Unmarshal([]byte(output[0]), &stats)
-	require.Nil(t, err)
+	stats = cliparse.RequireJSON[map[string]*climodel.FileStats](t, output)
 
 	if len(stats) == 0 {
 		t.Logf("0. zero no files")
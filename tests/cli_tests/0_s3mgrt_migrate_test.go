@@ -11,12 +11,18 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// s3mgrtPath is the s3mgrt binary the migration tests run
+const s3mgrtPath = "./s3mgrt"
+
 func Test0S3Migration(testSetup *testing.T) {
 	t := test.NewSystemTest(testSetup)
 
 	if s3SecretKey == "" || s3AccessKey == "" {
 		t.Skip("s3SecretKey or s3AccessKey was missing")
 	}
+	if s3Unavailable != "" {
+		t.Skip(s3Unavailable)
+	}
 
 	t.SetSmokeTests("Should migrate existing bucket successfully")
 
@@ -245,5 +251,5 @@ func Test0S3Migration(testSetup *testing.T) {
 
 func migrateFromS3(t *test.SystemTest, cliConfigFilename, params string) ([]string, error) {
 	t.Logf("Migrating S3 bucket to Zus...")
-	return cliutils.RunCommand(t, fmt.Sprintf("./s3mgrt migrate --silent --configDir ./config --config %s --network %s %s", cliConfigFilename, cliConfigFilename, params), 1, time.Second*2)
+	return cliutils.RunCommand(t, fmt.Sprintf("%s migrate --silent --configDir ./config --config %s --network %s %s", s3mgrtPath, cliConfigFilename, cliConfigFilename, params), 1, time.Second*2)
 }
@@ -49,7 +49,7 @@ func TestResumeUpload(testSetup *testing.T) {
 			configPath,
 		)
 
-		cmd, _ := cliutils.StartCommandWithoutRetry(command)
+		cmd, _ := cliutils.StartCommandWithoutRetry(t, command)
 		uploaded := waitPartialUploadAndInterrupt(t, cmd)
 		t.Logf("the uploaded is %v ", uploaded)
 
@@ -109,7 +109,7 @@ func TestResumeUpload(testSetup *testing.T) {
 			configPath,
 		)
 
-		cmd, _ := cliutils.StartCommandWithoutRetry(command)
+		cmd, _ := cliutils.StartCommandWithoutRetry(t, command)
 		uploaded := waitPartialUploadAndInterrupt(t, cmd)
 		t.Logf("the uploaded is %v ", uploaded)
 
@@ -163,7 +163,7 @@ func TestResumeUpload(testSetup *testing.T) {
 			configPath,
 		)
 
-		cmd, _ := cliutils.StartCommandWithoutRetry(command)
+		cmd, _ := cliutils.StartCommandWithoutRetry(t, command)
 		uploaded := waitPartialUploadAndInterrupt(t, cmd)
 		t.Logf("the uploaded is %v ", uploaded)
 
@@ -218,7 +218,7 @@ func TestResumeUpload(testSetup *testing.T) {
 			configPath,
 		)
 
-		cmd, _ := cliutils.StartCommandWithoutRetry(command)
+		cmd, _ := cliutils.StartCommandWithoutRetry(t, command)
 		uploaded := waitPartialUploadAndInterrupt(t, cmd)
 		t.Logf("the uploaded is %v ", uploaded)
 
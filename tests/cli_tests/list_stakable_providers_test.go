@@ -1,7 +1,6 @@
 package cli_tests
 
 import (
-	"encoding/json"
 	"log"
 	"regexp"
 	"strings"
@@ -9,6 +8,7 @@ import (
 
 	"github.com/0chain/system_test/internal/api/util/test"
 	"github.com/0chain/system_test/internal/cli/model"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	cliutil "github.com/0chain/system_test/internal/cli/util"
 	"github.com/0chain/system_test/tests/tokenomics_tests/utils"
 	"github.com/stretchr/testify/require"
@@ -26,9 +26,7 @@ func TestGetStakableProviders(testSetup *testing.T) {
 		}), true)
 		require.Nilf(t, err, "error fetching miner info: %v", err)
 		require.Len(t, output, 1)
-		var minerInfo1 model.Node
-		err = json.Unmarshal([]byte(output[0]), &minerInfo1)
-		require.Nilf(t, err, "error unmarshalling miner info: %v", err)
+		minerInfo1 := cliparse.RequireJSON[model.Node](t, output)
 		delegateCnt := len(minerInfo1.Pools)
 		log.Printf("minerInfo: %v", minerInfo1)
 		log.Printf("num delegates: %d", delegateCnt)
@@ -107,9 +105,7 @@ func TestGetStakableProviders(testSetup *testing.T) {
 		}), true)
 		require.Nilf(t, err, "error fetching sharder info: %v", err)
 		require.Len(t, output, 1)
-		var sharderInfo model.Node
-		err = json.Unmarshal([]byte(output[0]), &sharderInfo)
-		require.Nilf(t, err, "error unmarshalling sharder info: %v", err)
+		sharderInfo := cliparse.RequireJSON[model.Node](t, output)
 		delegateCnt := len(sharderInfo.Pools)
 		log.Printf("sharderInfo: %v", sharderInfo)
 		log.Printf("num delegates: %d", delegateCnt)
@@ -190,9 +186,7 @@ func TestGetStakableProviders(testSetup *testing.T) {
 		}))
 		require.Nil(t, err, "Error fetching stake pool info", strings.Join(output, "\n"))
 		require.Len(t, output, 1, "Error fetching stake pool info", strings.Join(output, "\n"))
-		stakePoolInfo := model.StakePoolInfo{}
-		err = json.Unmarshal([]byte(output[0]), &stakePoolInfo)
-		require.Nil(t, err, "Error unmarshalling stake pool info", strings.Join(output, "\n"))
+		stakePoolInfo := cliparse.RequireJSON[model.StakePoolInfo](t, output)
 		delegateCnt := len(stakePoolInfo.Delegate)
 		log.Printf("blobber stakePoolInfo: %v", stakePoolInfo)
 		log.Printf("num delegates: %d", delegateCnt)
@@ -217,18 +211,16 @@ func TestGetStakableProviders(testSetup *testing.T) {
 		})
 
 		// Stake tokens against this blobber
-		output, err = stakeTokens(t, configPath, createParams(map[string]interface{}{
+		output, err = stakeTokens(t, configPath, map[string]interface{}{
 			"blobber_id": blobberNode.Id,
 			"tokens":     1.0,
-		}), true)
+		}, true)
 		require.Nil(t, err, "Error staking tokens", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 		require.Regexp(t, regexp.MustCompile("tokens locked, txn hash: ([a-f0-9]{64})"), output[0])
 		t.Cleanup(func() {
 			// Unstake the tokens
-			output, err = unstakeTokens(t, configPath, createParams(map[string]interface{}{
-				"blobber_id": blobberNode.Id,
-			}), true)
+			output, err = unstakeTokens(t, configPath, cliutil.ZboxStakeUnlock{BlobberID: blobberNode.Id}, true)
 			require.Nilf(t, err, "error in unstake tokens during cleanup: %v", err)
 		})
 
@@ -258,9 +250,7 @@ func TestGetStakableProviders(testSetup *testing.T) {
 		}))
 		require.Nilf(t, err, "error fetching stake pool info: %v", err)
 		require.Len(t, output, 1)
-		stakePoolInfo := model.StakePoolInfo{}
-		err = json.Unmarshal([]byte(output[0]), &stakePoolInfo)
-		require.Nilf(t, err, "error unmarshalling stake pool info: %v", err)
+		stakePoolInfo := cliparse.RequireJSON[model.StakePoolInfo](t, output)
 		delegateCnt := len(stakePoolInfo.Delegate)
 		log.Printf("validator stakePoolInfo: %v", stakePoolInfo)
 		log.Printf("num delegates: %d", delegateCnt)
@@ -282,18 +272,16 @@ func TestGetStakableProviders(testSetup *testing.T) {
 		})
 
 		// Stake tokens against this validator
-		output, err = stakeTokens(t, configPath, createParams(map[string]interface{}{
+		output, err = stakeTokens(t, configPath, map[string]interface{}{
 			"validator_id": validatorNode.ID,
 			"tokens":       1.0,
-		}), true)
+		}, true)
 		require.Nilf(t, err, "error staking tokens: %v", err)
 		require.Len(t, output, 1)
 		require.Regexp(t, regexp.MustCompile("tokens locked, txn hash: ([a-f0-9]{64})"), output[0])
 		t.Cleanup(func() {
 			// Unstake the tokens
-			output, err = unstakeTokens(t, configPath, createParams(map[string]interface{}{
-				"validator_id": validatorNode.ID,
-			}), true)
+			output, err = unstakeTokens(t, configPath, cliutil.ZboxStakeUnlock{ValidatorID: validatorNode.ID}, true)
 			require.Nilf(t, err, "error in unstake tokens during cleanup: %v", err)
 		})
 
@@ -322,8 +310,7 @@ func getStakableMinersList(t *test.SystemTest) *model.NodeList {
 
 	var miners model.NodeList
 	log.Printf("json miners: %s", output[len(output)-1])
-	err = json.Unmarshal([]byte(output[len(output)-1]), &miners)
-	require.Nil(t, err, "Error deserializing JSON string `%s`: %v", output[len(output)-1], err)
+	miners = cliparse.RequireJSON[model.NodeList](t, output)
 	require.NotEmpty(t, miners.Nodes, "No miners found: %v", strings.Join(output, "\n"))
 	return &miners
 }
@@ -334,17 +321,14 @@ func getStakableSharderList(t *test.SystemTest) []model.Node {
 	require.Nil(t, err, "get stakable sharders failed", strings.Join(output, ""))
 	require.Greater(t, len(output), 0, "Expected output to have length of at least 1")
 
-	var sharders []model.Node
-	log.Printf("json sharders: %s", strings.Join(output, ""))
-	err = json.Unmarshal([]byte(strings.Join(output, "")), &sharders)
-	require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output, ""), err)
+	sharders := cliparse.RequireJSON[[]model.Node](t, output)
 	require.NotEmpty(t, sharders, "No sharders found: %v", strings.Join(output, ""))
 	return sharders
 }
 
 func listStakableShardersCommand(t *test.SystemTest, cliConfigFilename string) ([]string, error) {
 	t.Logf("list stakable sharder nodes...")
-	return cliutil.RunCommandWithRawOutput("./zwallet ls-sharders --active --stakable --json --silent --all --wallet " + escapedTestName(t) + "_wallet.json --configDir ./config --config " + cliConfigFilename)
+	return cliutil.RunCommandWithRawOutput(t, "./zwallet ls-sharders --active --stakable --json --silent --all --wallet "+escapedTestName(t)+"_wallet.json --configDir ./config --config "+cliConfigFilename)
 }
 
 func getStakableBlobberList(t *test.SystemTest) []model.BlobberInfo {
@@ -356,9 +340,7 @@ func getStakableBlobberList(t *test.SystemTest) []model.BlobberInfo {
 	require.Nilf(t, err, "error listing blobbers: %v", err)
 	require.Len(t, output, 1)
 
-	blobbers := []model.BlobberInfo{}
-	err = json.Unmarshal([]byte(output[0]), &blobbers)
-	require.Nilf(t, err, "error unmarshalling blobber list: %v", err)
+	blobbers := cliparse.RequireJSON[[]model.BlobberInfo](t, output)
 	require.NotEmpty(t, blobbers, "No blobbers found in blobber list")
 	return blobbers
 }
@@ -371,9 +353,7 @@ func getStakableValidatorList(t *test.SystemTest) []model.Validator {
 	require.Nilf(t, err, "error listing validators: %v", err)
 	require.Len(t, output, 1)
 
-	var validators []model.Validator
-	err = json.Unmarshal([]byte(output[0]), &validators)
-	require.Nilf(t, err, "error unmarshalling validators list: %v", err)
+	validators := cliparse.RequireJSON[[]model.Validator](t, output)
 	require.NotEmpty(t, validators, "No validators found in validators list")
 	return validators
 }
@@ -1,24 +1,28 @@
 package cli_tests
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
-	"strconv"
 	"strings"
-	"sync"
 	"testing"
 	"time"
 
 	"github.com/0chain/system_test/internal/api/util/tenderly"
 
 	"github.com/0chain/system_test/internal/api/util/config"
+	"github.com/0chain/system_test/internal/api/util/keystore"
+	"github.com/0chain/system_test/internal/api/util/redact"
 	"github.com/0chain/system_test/internal/api/util/test"
+	"github.com/0chain/system_test/internal/api/util/topology"
+	"github.com/0chain/system_test/internal/api/util/walletpool"
 
+	"github.com/0chain/system_test/internal/cli/s3stub"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
-	"github.com/spf13/viper"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
@@ -26,57 +30,62 @@ import (
 	"github.com/aws/aws-sdk-go/service/s3"
 )
 
-func setupDefaultConfig() {
-	viper.SetDefault("nodes.miner01ID", "73ad5727612116c025bb4405bf3adb4a4a04867ae508c51cf885395bffc8a949")
-	viper.SetDefault("nodes.miner02ID", "3ec9a42db3355f33c35750ce589ed717c08787997b7f34a7f1f9fb0a03f2b17c")
-	viper.SetDefault("nodes.miner03ID", "c6f4b8ce5da386b278ba8c4e6cf98b24b32d15bc675b4d12c95e082079c91937")
-	viper.SetDefault("nodes.sharder01ID", "ea26431f8adb7061766f1d6bbcc3b292d70dd59960d857f04b8a75e6a5bbe04f")
-	viper.SetDefault("nodes.sharder02ID", "30001a01a888584772b7fee13934021ab8557e0ed471c0a3a454e9164180aef1")
-}
-
-// SetupConfig setups the main configuration system.
-func setupConfig() {
-	setupDefaultConfig()
+// setupConfig loads the configuration of the suite and logs it with the secrets redacted
+func setupConfig() error {
 	path := filepath.Join(".", "config")
-
-	viper.SetConfigName("nodes")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath(path)
-	if err := viper.ReadInConfig(); err != nil {
-		log.Fatalln(fmt.Errorf("fatal error config file: %s", err))
+	cfg, err := config.Load(config.CLI,
+		filepath.Join(path, "config.yaml"),
+		filepath.Join(path, "cli_tests_config.yaml"),
+	)
+	if err != nil {
+		return err
 	}
+	log.Printf("Effective config from %s:\n%s", strings.Join(cfg.Sources, ", "), cfg.Redacted())
+	cfg.Apply()
+	suiteConfig = cfg
+
+	s3AccessKey = cfg.S3AccessKey
+	s3SecretKey = cfg.S3SecretKey
+	s3bucketName = cfg.S3BucketName
+	s3BucketNameAlternate = cfg.S3BucketNameAlternate
+
+	ethereumNodeURL = cfg.EthereumNodeURL
+	tokenAddress = cfg.Bridge.TokenAddress
+	ethereumAddress = cfg.Bridge.EthereumAddress
+	return nil
+}
 
-	miner01ID = viper.GetString("nodes.miner01ID")
-	miner02ID = viper.GetString("nodes.miner02ID")
-	miner03ID = viper.GetString("nodes.miner03ID")
-	sharder01ID = viper.GetString("nodes.sharder01ID")
-	sharder02ID = viper.GetString("nodes.sharder02ID")
-
-	parsedConfig := config.Parse(filepath.Join(".", path, "cli_tests_config.yaml"))
-	defaultTestTimeout, err := time.ParseDuration(parsedConfig.DefaultTestCaseTimeout)
-	s3AccessKey = parsedConfig.S3AccessKey
-	s3SecretKey = parsedConfig.S3SecretKey
-	s3bucketName = parsedConfig.S3BucketName
-	s3BucketNameAlternate = parsedConfig.S3BucketNameAlternate
-
+// discoverNetwork reads the nodes of the network and picks the ones the delegate wallets in config/wallets can manage
+func discoverNetwork() error {
+	wallets, err := topology.LoadWallets(filepath.Join(".", "config"), "wallets/*_wallet.json")
 	if err != nil {
-		log.Printf("Default test case timeout could not be parsed so has defaulted to [%v]", test.DefaultTestTimeout)
+		return fmt.Errorf("reading delegate wallets: %w", err)
+	}
+	network, err = topology.Discover(suiteConfig.BlockWorker, wallets)
+	if err != nil {
+		return fmt.Errorf("discovering the network: %w", err)
+	}
+	log.Printf("Discovered network of %s: %v", network.BlockWorker, network)
+	if report, err := network.WriteReport(test.ArtifactsRoot()); err != nil {
+		log.Println("Error writing network topology report:", err)
 	} else {
-		test.SmokeTestMode, _ = strconv.ParseBool(os.Getenv("SMOKE_TEST_MODE"))
-		test.DefaultTestTimeout = defaultTestTimeout
-		log.Printf("Default test case timeout is [%v]", test.DefaultTestTimeout)
+		log.Printf("Network topology written to [%v]", report)
 	}
 
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath(path)
-	if err := viper.ReadInConfig(); err != nil {
-		log.Fatalln(fmt.Errorf("fatal error config file: %s", err))
+	delegated := func(role topology.Role, wallet string) string {
+		node := network.Delegated(role, wallet)
+		if node == nil {
+			log.Printf("No %s of the network delegates to %s, tests using it will fail", role, wallet)
+			return ""
+		}
+		return node.ID
 	}
-
-	ethereumNodeURL = viper.GetString("ethereum_node_url")
-	tokenAddress = viper.GetString("bridge.token_address")
-	ethereumAddress = viper.GetString("bridge.ethereum_address")
+	miner01ID = delegated(topology.Miner, miner01NodeDelegateWalletName)
+	miner02ID = delegated(topology.Miner, miner02NodeDelegateWalletName)
+	miner03ID = delegated(topology.Miner, miner03NodeDelegateWalletName)
+	sharder01ID = delegated(topology.Sharder, sharder01NodeDelegateWalletName)
+	sharder02ID = delegated(topology.Sharder, sharder02NodeDelegateWalletName)
+	return nil
 }
 
 const (
@@ -93,6 +102,8 @@ const (
 )
 
 var (
+	// the nodes delegating to the wallets of the same name, found by discoverNetwork
+	network     *topology.Network
 	miner01ID   string
 	miner02ID   string
 	miner03ID   string
@@ -107,20 +118,57 @@ var (
 	s3bucketName          string
 	s3BucketNameAlternate string
 	S3Client              *s3.S3
+	// s3Unavailable is why the S3 migration tests are skipped, if they are
+	s3Unavailable string
 )
 
 var (
-	configPath string
-	configDir  string
+	configPath  string
+	configDir   string
+	suiteConfig *config.Config
+
+	wallets    []json.RawMessage
+	walletPool *walletpool.Pool
+)
 
-	wallets     []json.RawMessage
-	walletIdx   int64
-	walletMutex sync.Mutex
+const (
+	// the first wallets of wallets.json are used by the api tests
+	walletPoolOffset           = 500
+	walletPoolMinBalance       = 5 * 1e10
+	walletPoolTopUpAmount      = 10 * 1e10
+	defaultWalletPoolStatePath = "./config/wallet_pool_state.json"
 )
 
 var tenderlyClient *tenderly.Client
 
+// startS3Stub serves the fixture buckets of the migration tests with generated credentials, and points s3mgrt at them
+func startS3Stub() (*s3stub.Server, error) {
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("generating S3 stand-in credentials: %w", err)
+	}
+	secretKey := hex.EncodeToString(secret)
+	redact.AddSecret(secretKey)
+
+	server := s3stub.Start("AKIASYSTEMTESTSTUB", secretKey, s3stub.Fixtures(s3bucketName, s3BucketNameAlternate)...)
+	s3AccessKey, s3SecretKey = server.AccessKey, server.SecretKey
+
+	// s3mgrt reads the endpoint of its S3 client from the environment it inherits
+	if err := os.Setenv(s3stub.EndpointEnv, server.URL); err != nil {
+		server.Close()
+		return nil, fmt.Errorf("pointing s3mgrt at the S3 stand-in: %w", err)
+	}
+	log.Printf("Serving S3 fixture buckets [%v], [%v] and [%v] at [%v]", s3bucketName, s3BucketNameAlternate, s3stub.EmptyBucket, server.URL)
+	return server, nil
+}
+
 func TestMain(m *testing.M) {
+	os.Exit(run(m))
+}
+
+// run sets the suite up and runs the tests, returning the exit code. Once the wallets are decrypted every failure
+// returns instead of exiting, so that the deferred cleanup removes them.
+func run(m *testing.M) int {
 	configPath = os.Getenv("CONFIG_PATH")
 	configDir = os.Getenv("CONFIG_DIR")
 
@@ -148,7 +196,8 @@ func TestMain(m *testing.M) {
 					strings.HasSuffix(f, sharder01NodeDelegateWalletName+"_wallet.json") ||
 					strings.HasSuffix(f, sharder02NodeDelegateWalletName+"_wallet.json") ||
 					strings.HasSuffix(f, stakingWallet+"_wallet.json") ||
-					strings.HasSuffix(f, zboxTeamWallet+"_wallet.json") {
+					strings.HasSuffix(f, zboxTeamWallet+"_wallet.json") ||
+					f == filepath.Clean(defaultWalletPoolStatePath) {
 					continue
 				}
 				_ = os.Remove(f)
@@ -168,43 +217,130 @@ func TestMain(m *testing.M) {
 		}
 	}
 
-	setupConfig()
+	// the CLIs read the wallet files themselves, so encrypted ones are decrypted for the duration of the run
+	decryptedWallets, err := keystore.Materialize("./config/wallets")
+	if err != nil {
+		log.Println("Failed to decrypt wallet files:", err)
+		return 1
+	}
+	defer func() {
+		for _, f := range decryptedWallets {
+			_ = os.Remove(f)
+		}
+	}()
+
+	if err := setupConfig(); err != nil {
+		log.Println("Failed to load the config:", err)
+		return 1
+	}
+	if err := discoverNetwork(); err != nil {
+		log.Println("Failed to discover the network:", err)
+		return 1
+	}
+
+	// tests needing commands or flags missing from older binaries skip with t.RequireCapability
+	capabilities, err := cliutils.ProbeCapabilities("./zbox", "./zwallet")
+	if err != nil {
+		log.Println("Error probing CLI binaries:", err)
+	}
+	test.SetCapabilities(capabilities)
+	for _, binary := range []string{"zbox", "zwallet"} {
+		log.Printf("%s version: [%v]", binary, capabilities.Versions[binary])
+	}
+	if report, err := capabilities.WriteReport(test.ArtifactsRoot()); err != nil {
+		log.Println("Error writing CLI capabilities report:", err)
+	} else {
+		log.Printf("CLI capabilities written to [%v]", report)
+	}
 
 	tenderlyClient = tenderly.NewClient(ethereumNodeURL)
 
+	// uncertain failures of commands are retried only if the sharders show their transaction was not applied
+	cliutils.TransactionVerifier = func(t *test.SystemTest, hash string, sentAt time.Time) (bool, error) {
+		return cliutils.ConfirmTransaction(getSharderUrl(t), hash, sentAt)
+	}
+
+	// the S3 migration tests read the fixture buckets of an in-process S3 instead of AWS if s3_stub is set.
+	// They are skipped if s3mgrt would ignore the stand-in, its requests would reach AWS with generated credentials.
+	var s3Server *s3stub.Server
+	if suiteConfig.S3Stub {
+		honoured, err := s3stub.HonoursEndpointEnv(s3mgrtPath)
+		switch {
+		case err != nil:
+			s3Unavailable = fmt.Sprintf("s3_stub is set but the build of s3mgrt is unknown: %v", err)
+		case !honoured:
+			s3Unavailable = fmt.Sprintf("s3_stub is set but s3mgrt is built with %s older than %s, which ignores %s",
+				s3stub.EndpointModule, s3stub.MinEndpointVersion, s3stub.EndpointEnv)
+		default:
+			if s3Server, err = startS3Stub(); err != nil {
+				log.Println("Failed to start the S3 stand-in:", err)
+				return 1
+			}
+			defer s3Server.Close()
+		}
+		if s3Unavailable != "" {
+			log.Println("Skipping the S3 migration tests:", s3Unavailable)
+		}
+	}
+
 	// Create a session with AWS
-	sess, err := session.NewSession(&aws.Config{
+	awsConfig := &aws.Config{
 		Region:      aws.String("us-east-2"), // Replace with your desired AWS region
 		Credentials: credentials.NewStaticCredentials(s3AccessKey, s3SecretKey, ""),
-	})
+	}
+	if s3Server != nil {
+		awsConfig.Endpoint = aws.String(s3Server.URL)
+		awsConfig.S3ForcePathStyle = aws.Bool(true)
+	}
+	sess, err := session.NewSession(awsConfig)
 	if err != nil {
-		log.Fatalln("Failed to create AWS session:", err)
-		return
+		log.Println("Failed to create AWS session:", err)
+		return 1
 	}
 
 	// Create an S3 client
 	S3Client = s3.New(sess)
 
-	walletMutex.Lock()
 	// Read the content of the file
-	fileContent, err := os.ReadFile("./config/wallets/wallets.json")
+	fileContent, err := keystore.ReadFile("./config/wallets/wallets.json")
 	if err != nil {
 		log.Println("Error reading file:", err)
-		return
+		return 1
 	}
 
 	// Parse the JSON data into a list of strings
 	err = json.Unmarshal(fileContent, &wallets)
 	if err != nil {
 		log.Println("Error decoding JSON:", err)
-		return
+		return 1
 	}
 
-	walletIdx = 500
-
-	walletMutex.Unlock()
+	walletPoolStatePath := os.Getenv(walletpool.StatePathEnv)
+	if walletPoolStatePath == "" {
+		walletPoolStatePath = defaultWalletPoolStatePath
+	}
 
-	exitRun := m.Run()
+	walletPool, err = walletpool.New(wallets, walletpool.Config{
+		StatePath:   walletPoolStatePath,
+		Offset:      walletPoolOffset,
+		MinBalance:  walletPoolMinBalance,
+		TopUpAmount: walletPoolTopUpAmount,
+		Hooks: walletpool.Hooks{
+			Prepare: writePooledWalletFile,
+			Balance: pooledWalletBalance,
+			TopUp:   topUpPooledWallet,
+			Drain:   drainPooledWallet,
+		},
+	})
+	if err != nil {
+		log.Println("Error creating wallet pool:", err)
+		return 1
+	}
+	defer func() {
+		if err := walletPool.Close(); err != nil {
+			log.Println("Error persisting wallet pool state:", err)
+		}
+	}()
 
-	os.Exit(exitRun)
+	return m.Run()
 }
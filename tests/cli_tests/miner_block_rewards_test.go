@@ -2,11 +2,8 @@ package cli_tests
 
 import (
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
-	"math"
 	"net/http"
-	"reflect"
 	"sort"
 	"strconv"
 	"strings"
@@ -16,15 +13,14 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/0chain/system_test/internal/api/util/test"
+	"github.com/0chain/system_test/internal/api/util/topology"
 
 	climodel "github.com/0chain/system_test/internal/cli/model"
 	cliutil "github.com/0chain/system_test/internal/cli/util"
+	"github.com/0chain/system_test/internal/cli/util/rewards"
 )
 
-const (
-	delta          = 1.0
-	restApiRetries = 3
-)
+const restApiRetries = 3
 
 func TestMinerBlockRewards(testSetup *testing.T) { // nolint:gocyclo // team preference is to have codes all within test.
 	t := test.NewSystemTest(testSetup)
@@ -68,227 +64,18 @@ func TestMinerBlockRewards(testSetup *testing.T) { // nolint:gocyclo // team pre
 
 		time.Sleep(time.Second) // give time for last round to be saved
 		history := cliutil.NewHistory(startRound, endRound)
-		history.Read(t, sharderUrl, true)
+		history.ReadFromSharders(t, getSharderUrls(t), true)
+		history.ExportOnFailure(t)
 
-		balanceMinerRewards(
-			t, startRound, endRound, minerIds, beforeMiners.Nodes, afterMiners.Nodes, history,
-		)
+		rewards.New(history, rewards.ConfigFromMap(getMinerScMap(t))).
+			AddMiners(beforeMiners.Nodes, afterMiners.Nodes).
+			Reconcile(t).
+			Require(t)
 	})
 }
 
-func balanceMinerRewards(
-	t *test.SystemTest,
-	startRound, endRound int64,
-	minerIds []string,
-	beforeMiners, afterMiners []climodel.Node,
-	history *cliutil.ChainHistory,
-) {
-	minerScConfig := getMinerScMap(t)
-	require.EqualValues(t, startRound/int64(minerScConfig["epoch"]), endRound/int64(minerScConfig["epoch"]),
-		"epoch changed during test, start %v finish %v",
-		startRound/int64(minerScConfig["epoch"]), endRound/int64(minerScConfig["epoch"]))
-
-	minerBlockReward, _ := blockRewards(startRound, minerScConfig)
-
-	checkMinerBlockRewards(
-		t,
-		minerIds,
-		minerBlockReward,
-		beforeMiners, afterMiners,
-		history,
-	)
-
-	countMinerBlockRewards(
-		t, startRound+1, endRound-1, history,
-	)
-
-	checkMinerDelegatePoolBlockRewards(
-		t,
-		minerIds,
-		int(minerScConfig["num_miner_delegates_rewarded"]),
-		minerBlockReward,
-		beforeMiners, afterMiners,
-		history,
-	)
-}
-
-// checkMinerBlockRewards
-// Each round one miner is chosen to receive a block reward.
-// The winning miner is stored in the block object.
-// The reward payments retrieved from the provider reward table.
-// The amount of the reward is a fraction of the block reward allocated to miners each
-// round. The fraction is the miner's service charge. If the miner has
-// no stake pools then the reward becomes the full block reward.
-//
-// Firstly we confirm the self-consistency of the block and reward tables.
-// We calculate the change in the miner rewards during and confirm that this
-// equals the total of the reward payments read from the provider rewards table.
-func checkMinerBlockRewards(
-	t *test.SystemTest,
-	minerIds []string,
-	minerBlockReward int64,
-	beforeMiners, afterMiners []climodel.Node,
-	history *cliutil.ChainHistory,
-) {
-	for i, id := range minerIds {
-		var feeRewards, blockRewards int64
-		for round := beforeMiners[i].RoundServiceChargeLastUpdated + 1; round <= afterMiners[i].RoundServiceChargeLastUpdated; round++ {
-			roundHistory := history.RoundHistory(t, round)
-			for _, pReward := range roundHistory.ProviderRewards {
-				if pReward.ProviderId != id {
-					continue
-				}
-				switch pReward.RewardType {
-				case climodel.BlockRewardMiner:
-					require.Falsef(t, beforeMiners[i].IsKilled,
-						"killed miners cannot receive rewards, %s is killed", id)
-					var expectedServiceCharge int64
-					if len(beforeMiners[i].StakePool.Pools) > 0 {
-						expectedServiceCharge = int64(float64(minerBlockReward) * beforeMiners[i].Settings.ServiceCharge)
-					} else {
-						expectedServiceCharge = minerBlockReward
-					}
-					require.InDeltaf(t, expectedServiceCharge, pReward.Amount, delta,
-						"incorrect service charge %v for round %d"+
-							" service charge should be block reward %v multiplied by service ratio %v."+
-							"length stake pools %d",
-						pReward.Amount, round,
-						minerBlockReward, beforeMiners[i].Settings.ServiceCharge,
-						len(beforeMiners[i].StakePool.Pools))
-					blockRewards += pReward.Amount
-				case climodel.FeeRewardMiner:
-					feeRewards += pReward.Amount
-				default:
-					require.Failf(t, "", "reward type %s is not available for miners", pReward.RewardType.String())
-				}
-			}
-		}
-		actualReward := afterMiners[i].Reward - beforeMiners[i].Reward
-		require.InDeltaf(t, actualReward, feeRewards+blockRewards, delta,
-			"rewards expected %v, change in miners reward during the test is %v", actualReward, feeRewards+blockRewards)
-	}
-}
-
-// countMinerBlockRewards
-// Each round there should be exactly one block reward payment
-// and this to the blocks' miner.
-func countMinerBlockRewards(
-	t *test.SystemTest,
-	start, end int64,
-	history *cliutil.ChainHistory,
-) {
-	for round := start; round <= end; round++ {
-		roundHistory := history.RoundHistory(t, round)
-		foundBlockRewardPayment := false
-		for _, pReward := range roundHistory.ProviderRewards {
-			if pReward.RewardType == climodel.BlockRewardMiner {
-				require.Falsef(t, foundBlockRewardPayment, "round %d, block reward already paid, only pay miner block rewards once", round)
-				foundBlockRewardPayment = true
-			}
-		}
-		if !foundBlockRewardPayment {
-			t.Log(foundBlockRewardPayment)
-		}
-		require.Truef(t, foundBlockRewardPayment,
-			"rond %d, miner block reward payment not recorded. block rewards should be paid every round.", round)
-	}
-	t.Log("about to test delegate pools")
-}
-
-// checkMinerDelegatePoolBlockRewards
-// Each round confirm payments to delegates or the blocks winning miner.
-// There should be exactly `num_miner_delegates_rewarded` delegates rewarded each round,
-// or all delegates if less.
-//
-// Delegates should be rewarded in proportional to their locked tokens.
-// We check the self-consistency of the reward payments each round using
-// the delegate reward table.
-//
-// Next we compare the actual change in rewards to each miner delegate, with the
-// change expected from the delegate reward table.
-func checkMinerDelegatePoolBlockRewards(
-	t *test.SystemTest,
-	minerIds []string,
-	numMinerDelegatesRewarded int,
-	minerBlockReward int64,
-	beforeMiners, afterMiners []climodel.Node,
-	history *cliutil.ChainHistory,
-) {
-	for i, id := range minerIds {
-		delegateBlockReward := int64(float64(minerBlockReward) * (1 - beforeMiners[i].Settings.ServiceCharge))
-		numPools := len(afterMiners[i].StakePool.Pools)
-		rewards := make(map[string]int64, numPools)
-		for poolId := range afterMiners[i].StakePool.Pools {
-			rewards[poolId] = 0
-		}
-		for round := beforeMiners[i].RoundServiceChargeLastUpdated + 1; round <= afterMiners[i].RoundServiceChargeLastUpdated; round++ {
-			poolsBlockRewarded := make(map[string]int64)
-			roundHistory := history.RoundHistory(t, round)
-			for _, dReward := range roundHistory.DelegateRewards {
-				if dReward.ProviderID != id {
-					continue
-				}
-				_, isMinerPool := rewards[dReward.PoolID]
-				require.Truef(t, isMinerPool, "round %d, invalid pool id, reward %v", round, dReward)
-				switch dReward.RewardType {
-				case climodel.BlockRewardMiner:
-					_, found := poolsBlockRewarded[dReward.PoolID]
-					require.False(t, found, "delegate pool %s paid a block reward more than once on round %d",
-						dReward.PoolID, round)
-					poolsBlockRewarded[dReward.PoolID] = dReward.Amount
-					rewards[dReward.PoolID] += dReward.Amount
-				case climodel.FeeRewardMiner:
-					rewards[dReward.PoolID] += dReward.Amount
-				default:
-					require.Failf(t, "mismatched reward", "round %d, %s not available for miner", round, dReward.RewardType)
-				}
-			}
-			if roundHistory.Block.MinerID != id {
-				require.Len(t, poolsBlockRewarded, 0,
-					"delegate pools should not get a block reward unless their parent miner won the round lottery")
-			}
-			confirmPoolPayments(
-				t, delegateBlockReward, poolsBlockRewarded, afterMiners[i].StakePool.Pools, numMinerDelegatesRewarded,
-			)
-		}
-		for poolId := range afterMiners[i].StakePool.Pools {
-			actualReward := afterMiners[i].StakePool.Pools[poolId].Reward - beforeMiners[i].StakePool.Pools[poolId].Reward
-			require.InDeltaf(t, actualReward, rewards[poolId], delta,
-				"poolID %s, rewards expected %v change in pools reward during test", poolId, rewards[poolId],
-			)
-		}
-	}
-}
-
-func confirmPoolPayments(
-	t *test.SystemTest,
-	totalReward int64,
-	poolsBlockRewarded map[string]int64,
-	pools map[string]*climodel.DelegatePool,
-	numRewards int,
-) {
-	if len(poolsBlockRewarded) == 0 {
-		return
-	}
-	if numRewards > len(pools) {
-		numRewards = len(pools)
-	}
-	require.Equal(t, len(poolsBlockRewarded), numRewards,
-		"expected reward payments %d does not equal actual payment count %d", numRewards, len(poolsBlockRewarded))
-	var total float64
-	for id := range poolsBlockRewarded {
-		total += float64(pools[id].Balance)
-	}
-	for id, reward := range poolsBlockRewarded {
-		expectedReward := (float64(pools[id].Balance) / total) * float64(totalReward)
-		require.InDeltaf(t, expectedReward, float64(reward), 1,
-			"delegate rewards. delegates should be rewarded in proportion to their stake."+
-				"total reward %d stake pools %v", totalReward, pools)
-	}
-}
-
 func initialiseTest(t *test.SystemTest, wallet string, funds bool) string {
-	createWalletForName(wallet)
+	createWalletForName(t, wallet)
 
 	targetWallet, err := getWalletForName(t, configPath, wallet)
 	require.NoError(t, err, "error getting target wallet")
@@ -400,32 +187,15 @@ func getMinerScMap(t *test.SystemTest) map[string]float64 {
 	return floatMap
 }
 
-func blockRewards(round int64, minerScConfig map[string]float64) (minerReward, sharderReward int64) {
-	epoch := round / int64(minerScConfig["epoch"])
-	epochDecline := 1.0 - minerScConfig["reward_decline_rate"]
-	declineRate := math.Pow(epochDecline, float64(epoch))
-	blockReward := (minerScConfig["block_reward"] * float64(TOKEN_UNIT)) * declineRate
-	minerReward = int64(blockReward * minerScConfig["share_ratio"])
-	sharderReward = int64(blockReward) - minerReward
-	return minerReward, sharderReward
-}
-
 func getSharderUrl(t *test.SystemTest) string {
-	t.Logf("getting sharder url...")
-	// Get sharder list.
-	output, err := getSharders(t, configPath)
-	require.Nil(t, err, "get sharders failed", strings.Join(output, "\n"))
-	require.Greater(t, len(output), 1)
-	require.Equal(t, "MagicBlock Sharders", output[0])
-
-	var sharders map[string]climodel.Sharder
-	err = json.Unmarshal([]byte(strings.Join(output[1:], "")), &sharders)
-	require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output[1:], "\n"), err)
-	require.NotEmpty(t, sharders, "No sharders found: %v", strings.Join(output[1:], "\n"))
-
-	sharder := sharders[reflect.ValueOf(sharders).MapKeys()[0].String()]
+	return getSharderUrls(t)[0]
+}
 
-	return getNodeBaseURL(sharder.Host, sharder.Port)
+// getSharderUrls returns the URLs of the sharders that are not killed
+func getSharderUrls(t *test.SystemTest) []string {
+	urls := network.URLs(topology.Sharder)
+	require.NotEmpty(t, urls, "no sharders in the network of %s", network.BlockWorker)
+	return urls
 }
 
 func getNode(t *test.SystemTest, cliConfigFilename, nodeID string) ([]string, error) {
@@ -476,7 +246,7 @@ func getSharders(t *test.SystemTest, cliConfigFilename string) ([]string, error)
 
 func getShardersForWallet(t *test.SystemTest, cliConfigFilename, wallet string) ([]string, error) {
 	t.Logf("list sharder nodes...")
-	return cliutil.RunCommandWithRawOutput("./zwallet ls-sharders --active --json --silent --wallet " + wallet + "_wallet.json --configDir ./config --config " + cliConfigFilename)
+	return cliutil.RunCommandWithRawOutput(t, "./zwallet ls-sharders --active --json --silent --wallet "+wallet+"_wallet.json --configDir ./config --config "+cliConfigFilename)
 }
 
 func getNodeBaseURL(host string, port int) string {
@@ -485,7 +255,7 @@ func getNodeBaseURL(host string, port int) string {
 
 func getMinersForWallet(t *test.SystemTest, cliConfigFilename, wallet string) ([]string, error) {
 	t.Log("list miner nodes...")
-	return cliutil.RunCommandWithRawOutput("./zwallet ls-miners --active --json --silent --wallet " + wallet + "_wallet.json --configDir ./config --config " + cliConfigFilename)
+	return cliutil.RunCommandWithRawOutput(t, "./zwallet ls-miners --active --json --silent --wallet "+wallet+"_wallet.json --configDir ./config --config "+cliConfigFilename)
 }
 
 func apiGetBalance(t *test.SystemTest, sharderBaseURL, clientID string) (*http.Response, error) {
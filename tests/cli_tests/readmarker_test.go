@@ -1,7 +1,6 @@
 package cli_tests
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -11,6 +10,7 @@ import (
 
 	"github.com/0chain/system_test/internal/api/util/test"
 
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
 
 	climodel "github.com/0chain/system_test/internal/cli/model"
@@ -340,10 +340,7 @@ func TestReadMarker(testSetup *testing.T) {
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var stats map[string]climodel.FileStats
-
-		err = json.Unmarshal([]byte(output[0]), &stats)
-		require.Nil(t, err)
+		cliparse.RequireJSON[map[string]climodel.FileStats](t, output)
 
 		startBlock := 1
 		endBlock := 6
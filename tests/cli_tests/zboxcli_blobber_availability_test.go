@@ -37,12 +37,12 @@ func TestBlobberAvailability(testSetup *testing.T) {
 		dataShards := 1
 		parityShards := activeBlobbers - dataShards
 
-		output, err := createNewAllocation(t, configPath, createParams(map[string]interface{}{
+		output, err := createNewAllocation(t, configPath, map[string]interface{}{
 			"data":   strconv.Itoa(dataShards),
 			"parity": strconv.Itoa(parityShards),
 			"lock":   "3.0",
 			"size":   "10000",
-		}))
+		})
 		require.NoError(t, err, strings.Join(output, "\n"))
 		beforeAllocationId, err := getAllocationID(output[0])
 		require.NoError(t, err, "error getting allocation id")
@@ -58,12 +58,12 @@ func TestBlobberAvailability(testSetup *testing.T) {
 			}
 		}
 
-		output, err = createNewAllocation(t, configPath, createParams(map[string]interface{}{
+		output, err = createNewAllocation(t, configPath, map[string]interface{}{
 			"data":   strconv.Itoa(dataShards),
 			"parity": strconv.Itoa(parityShards),
 			"lock":   "3.0",
 			"size":   "10000",
-		}))
+		})
 		require.Error(t, err, "create allocation should fail")
 		require.Len(t, output, 1)
 		require.True(t, strings.Contains(output[0], "not enough blobbers to honor the allocation"))
@@ -87,12 +87,12 @@ func TestBlobberAvailability(testSetup *testing.T) {
 			}
 		}
 
-		output, err = createNewAllocation(t, configPath, createParams(map[string]interface{}{
+		output, err = createNewAllocation(t, configPath, map[string]interface{}{
 			"data":   strconv.Itoa(dataShards),
 			"parity": strconv.Itoa(parityShards),
 			"lock":   "3.0",
 			"size":   "10000",
-		}))
+		})
 		require.NoError(t, err, strings.Join(output, "\n"))
 		afterAllocationId, err := getAllocationID(output[0])
 		require.NoError(t, err, "error getting allocation id")
@@ -1,7 +1,6 @@
 package cli_tests
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
@@ -9,9 +8,12 @@ import (
 	"time"
 
 	"github.com/0chain/system_test/internal/api/util/test"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
+	"github.com/0chain/system_test/internal/currency"
 
 	climodel "github.com/0chain/system_test/internal/cli/model"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
 )
 
@@ -31,9 +33,7 @@ func TestBlobberConfigUpdate(testSetup *testing.T) {
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.Len(t, output, 1, strings.Join(output, "\n"))
 
-		var blobberList []climodel.BlobberDetails
-		err = json.Unmarshal([]byte(output[0]), &blobberList)
-		require.Nil(t, err, strings.Join(output, "\n"))
+		blobberList := cliparse.RequireJSON[[]climodel.BlobberDetails](t, output)
 		require.Greater(t, len(blobberList), 0, "blobber list is empty")
 
 		intialBlobberInfo = blobberList[0]
@@ -54,10 +54,10 @@ func TestBlobberConfigUpdate(testSetup *testing.T) {
 		output, err = updateBlobberInfo(t, configPath, createParams(map[string]interface{}{"blobber_id": intialBlobberInfo.ID, "service_charge": intialBlobberInfo.StakePoolSettings.ServiceCharge}))
 		require.Nil(t, err, strings.Join(output, "\n"))
 
-		output, err = updateBlobberInfo(t, configPath, createParams(map[string]interface{}{"blobber_id": intialBlobberInfo.ID, "read_price": intToZCN(intialBlobberInfo.Terms.ReadPrice)}))
+		output, err = updateBlobberInfo(t, configPath, createParams(map[string]interface{}{"blobber_id": intialBlobberInfo.ID, "read_price": currency.SASToZCN(intialBlobberInfo.Terms.ReadPrice)}))
 		require.Nil(t, err, strings.Join(output, "\n"))
 
-		output, err = updateBlobberInfo(t, configPath, createParams(map[string]interface{}{"blobber_id": intialBlobberInfo.ID, "write_price": intToZCN(intialBlobberInfo.Terms.WritePrice)}))
+		output, err = updateBlobberInfo(t, configPath, createParams(map[string]interface{}{"blobber_id": intialBlobberInfo.ID, "write_price": currency.SASToZCN(intialBlobberInfo.Terms.WritePrice)}))
 		require.Nil(t, err, strings.Join(output, "\n"))
 
 		output, err = updateBlobberInfo(t, configPath, createParams(map[string]interface{}{"blobber_id": intialBlobberInfo.ID, "url": intialBlobberInfo.BaseURL}))
@@ -78,9 +78,7 @@ func TestBlobberConfigUpdate(testSetup *testing.T) {
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var finalBlobberInfo climodel.BlobberDetails
-		err = json.Unmarshal([]byte(output[0]), &finalBlobberInfo)
-		require.Nil(t, err, strings.Join(output, "\n"))
+		finalBlobberInfo := cliparse.RequireJSON[climodel.BlobberDetails](t, output)
 
 		require.Equal(t, int64(newCapacity), finalBlobberInfo.Capacity)
 	})
@@ -98,9 +96,7 @@ func TestBlobberConfigUpdate(testSetup *testing.T) {
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var finalBlobberInfo climodel.BlobberDetails
-		err = json.Unmarshal([]byte(output[0]), &finalBlobberInfo)
-		require.Nil(t, err, strings.Join(output, "\n"))
+		finalBlobberInfo := cliparse.RequireJSON[climodel.BlobberDetails](t, output)
 
 		require.Equal(t, newNumberOfDelegates, finalBlobberInfo.StakePoolSettings.MaxNumDelegates)
 	})
@@ -118,9 +114,7 @@ func TestBlobberConfigUpdate(testSetup *testing.T) {
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var finalBlobberInfo climodel.BlobberDetails
-		err = json.Unmarshal([]byte(output[0]), &finalBlobberInfo)
-		require.Nil(t, err, strings.Join(output, "\n"))
+		finalBlobberInfo := cliparse.RequireJSON[climodel.BlobberDetails](t, output)
 
 		require.Equal(t, newServiceCharge, finalBlobberInfo.StakePoolSettings.ServiceCharge)
 	})
@@ -168,7 +162,7 @@ func TestBlobberConfigUpdate(testSetup *testing.T) {
 		createWallet(t)
 
 		oldReadPrice := intialBlobberInfo.Terms.ReadPrice
-		newReadPrice := intToZCN(oldReadPrice) + 1
+		newReadPrice := currency.SASToZCN(oldReadPrice).Add(decimal.NewFromInt(1))
 
 		output, err := updateBlobberInfo(t, configPath, createParams(map[string]interface{}{"blobber_id": intialBlobberInfo.ID, "read_price": newReadPrice}))
 		require.Nil(t, err, strings.Join(output, "\n"))
@@ -179,18 +173,16 @@ func TestBlobberConfigUpdate(testSetup *testing.T) {
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var finalBlobberInfo climodel.BlobberDetails
-		err = json.Unmarshal([]byte(output[0]), &finalBlobberInfo)
-		require.Nil(t, err, strings.Join(output, "\n"))
+		finalBlobberInfo := cliparse.RequireJSON[climodel.BlobberDetails](t, output)
 
-		require.Equal(t, newReadPrice, intToZCN(finalBlobberInfo.Terms.ReadPrice))
+		require.Equal(t, newReadPrice.String(), currency.SASToZCN(finalBlobberInfo.Terms.ReadPrice).String())
 	})
 
 	t.RunSequentially("update blobber write price should work", func(t *test.SystemTest) {
 		createWallet(t)
 
 		oldWritePrice := intialBlobberInfo.Terms.WritePrice
-		newWritePrice := intToZCN(oldWritePrice) + 0.01
+		newWritePrice := currency.SASToZCN(oldWritePrice).Add(decimal.RequireFromString("0.01"))
 
 		output, err := updateBlobberInfo(t, configPath, createParams(map[string]interface{}{"blobber_id": intialBlobberInfo.ID, "write_price": newWritePrice}))
 		require.Nil(t, err, strings.Join(output, "\n"))
@@ -201,19 +193,17 @@ func TestBlobberConfigUpdate(testSetup *testing.T) {
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var finalBlobberInfo climodel.BlobberDetails
-		err = json.Unmarshal([]byte(output[0]), &finalBlobberInfo)
-		require.Nil(t, err, strings.Join(output, "\n"))
+		finalBlobberInfo := cliparse.RequireJSON[climodel.BlobberDetails](t, output)
 
-		require.Equal(t, newWritePrice, intToZCN(finalBlobberInfo.Terms.WritePrice))
+		require.Equal(t, newWritePrice.String(), currency.SASToZCN(finalBlobberInfo.Terms.WritePrice).String())
 	})
 
 	t.RunSequentially("update all params at once should work", func(t *test.SystemTest) {
 		createWallet(t)
 
-		newWritePrice := intToZCN(intialBlobberInfo.Terms.WritePrice) + 0.01
+		newWritePrice := currency.SASToZCN(intialBlobberInfo.Terms.WritePrice).Add(decimal.RequireFromString("0.01"))
 		newServiceCharge := intialBlobberInfo.StakePoolSettings.ServiceCharge + 0.1
-		newReadPrice := intToZCN(intialBlobberInfo.Terms.ReadPrice) + 1
+		newReadPrice := currency.SASToZCN(intialBlobberInfo.Terms.ReadPrice).Add(decimal.NewFromInt(1))
 		newNumberOfDelegates := intialBlobberInfo.StakePoolSettings.MaxNumDelegates + 1
 		newCapacity := intialBlobberInfo.Capacity + 1
 		newNotAvailable := !intialBlobberInfo.NotAvailable
@@ -241,13 +231,11 @@ func TestBlobberConfigUpdate(testSetup *testing.T) {
 			t.Cleanup(func() { setNotAvailability(t, intialBlobberInfo.ID, false) })
 		}
 
-		var finalBlobberInfo climodel.BlobberDetails
-		err = json.Unmarshal([]byte(output[0]), &finalBlobberInfo)
-		require.Nil(t, err, strings.Join(output, "\n"))
+		finalBlobberInfo := cliparse.RequireJSON[climodel.BlobberDetails](t, output)
 
-		require.Equal(t, newWritePrice, intToZCN(finalBlobberInfo.Terms.WritePrice))
+		require.Equal(t, newWritePrice.String(), currency.SASToZCN(finalBlobberInfo.Terms.WritePrice).String())
 		require.Equal(t, newServiceCharge, finalBlobberInfo.StakePoolSettings.ServiceCharge)
-		require.Equal(t, newReadPrice, intToZCN(finalBlobberInfo.Terms.ReadPrice))
+		require.Equal(t, newReadPrice.String(), currency.SASToZCN(finalBlobberInfo.Terms.ReadPrice).String())
 		require.Equal(t, newNumberOfDelegates, finalBlobberInfo.StakePoolSettings.MaxNumDelegates)
 		require.Equal(t, newCapacity, finalBlobberInfo.Capacity)
 		require.Equal(t, newNotAvailable, finalBlobberInfo.NotAvailable)
@@ -270,9 +258,7 @@ func TestBlobberConfigUpdate(testSetup *testing.T) {
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var finalBlobberInfo climodel.BlobberDetails
-		err = json.Unmarshal([]byte(output[0]), &finalBlobberInfo)
-		require.Nil(t, err, strings.Join(output, "\n"))
+		finalBlobberInfo := cliparse.RequireJSON[climodel.BlobberDetails](t, output)
 
 		require.Equal(t, url, finalBlobberInfo.BaseURL)
 	})
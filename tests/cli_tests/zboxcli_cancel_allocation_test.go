@@ -67,7 +67,7 @@ func TestCancelAllocation(testSetup *testing.T) {
 			configPath,
 		)
 
-		output, err := cliutils.RunCommandWithoutRetry(cmd)
+		output, err := cliutils.RunCommandWithoutRetry(t, cmd)
 		require.Error(t, err, "expected error canceling allocation", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 		require.Equal(t, "Error: allocation flag is missing", output[0])
@@ -110,6 +110,6 @@ func cancelAllocation(t *test.SystemTest, cliConfigFilename, allocationID string
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
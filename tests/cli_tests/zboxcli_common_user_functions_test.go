@@ -1,7 +1,6 @@
 package cli_tests
 
 import (
-	"encoding/json"
 	"fmt"
 	"path/filepath"
 	"regexp"
@@ -12,15 +11,15 @@ import (
 	"github.com/0chain/system_test/internal/api/util/test"
 
 	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
 	"github.com/stretchr/testify/require"
 )
 
 const (
-	KB               = 1024      // kilobyte
-	MB               = 1024 * KB // megabyte
-	GB               = 1024 * MB // gigabyte
-	TOKEN_UNIT int64 = 1e10
+	KB = 1024      // kilobyte
+	MB = 1024 * KB // megabyte
+	GB = 1024 * MB // gigabyte
 )
 
 func TestCommonUserFunctions(testSetup *testing.T) {
@@ -36,10 +35,10 @@ func TestCommonUserFunctions(testSetup *testing.T) {
 		require.NoError(t, err)
 
 		// Lock tokens for allocation
-		allocParams := createParams(map[string]interface{}{
+		allocParams := map[string]interface{}{
 			"lock": "5",
 			"size": 1 * MB,
-		})
+		}
 		output, err := createNewAllocation(t, configPath, allocParams)
 		require.Nil(t, err, "Failed to create new allocation", strings.Join(output, "\n"))
 
@@ -50,7 +49,7 @@ func TestCommonUserFunctions(testSetup *testing.T) {
 		// Wallet balance should decrease by locked amount
 		balanceAfter, err := getBalanceZCN(t, configPath)
 		require.NoError(t, err)
-		require.Equal(t, balanceBefore-5.01, balanceAfter) // lock - fee
+		require.Equal(t, balanceBefore-zcn(5.01), balanceAfter) // lock - fee
 
 		createAllocationTestTeardown(t, allocationID)
 	})
@@ -63,10 +62,10 @@ func TestCommonUserFunctions(testSetup *testing.T) {
 		require.NoError(t, err)
 
 		// Lock 5 token for allocation
-		allocParams := createParams(map[string]interface{}{
+		allocParams := map[string]interface{}{
 			"lock": "5",
 			"size": 1 * MB,
-		})
+		}
 		output, err := createNewAllocation(t, configPath, allocParams)
 		require.Nil(t, err, "Failed to create new allocation", strings.Join(output, "\n"))
 
@@ -79,7 +78,7 @@ func TestCommonUserFunctions(testSetup *testing.T) {
 		require.NoError(t, err)
 
 		// Wallet balance should decrease by locked amount and txn fee
-		require.Less(t, balanceAfterAllocation, balance-0.5)
+		require.Less(t, balanceAfterAllocation, balance-zcn(0.5))
 
 		params := createParams(map[string]interface{}{
 			"allocation": allocationID,
@@ -95,7 +94,7 @@ func TestCommonUserFunctions(testSetup *testing.T) {
 		require.NoError(t, err)
 
 		// Wallet balance should decrease by locked amount and txn fee
-		require.Less(t, balanceAfterUpdate, balanceAfterAllocation-0.2)
+		require.Less(t, balanceAfterUpdate, balanceAfterAllocation-zcn(0.2))
 
 		createAllocationTestTeardown(t, allocationID)
 	})
@@ -187,7 +186,7 @@ func renameFile(t *test.SystemTest, cliConfigFilename string, param map[string]i
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*20)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
@@ -209,17 +208,14 @@ func updateFileWithWallet(t *test.SystemTest, walletName, cliConfigFilename stri
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*20)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
 func getAllocation(t *test.SystemTest, allocationID string) (allocation climodel.Allocation) {
 	output, err := getAllocationWithRetry(t, configPath, allocationID, 1)
 	require.Nil(t, err, "error fetching allocation")
-	require.Greater(t, len(output), 0, "gettting allocation - output is empty unexpectedly")
-	err = json.Unmarshal([]byte(output[0]), &allocation)
-	require.Nil(t, err, "error unmarshalling allocation json")
-	return
+	return cliparse.RequireJSON[climodel.Allocation](t, output)
 }
 
 func getAllocationWithRetry(t *test.SystemTest, cliConfigFilename, allocationID string, retry int) ([]string, error) {
@@ -231,13 +227,3 @@ func getAllocationWithRetry(t *test.SystemTest, cliConfigFilename, allocationID
 		cliConfigFilename), retry, time.Second*5)
 	return output, err
 }
-
-// ConvertToToken converts the value to ZCN tokens
-func ConvertToToken(value int64) float64 {
-	return float64(value) / float64(TOKEN_UNIT)
-}
-
-// ConvertToValue converts ZCN tokens to value
-func ConvertToValue(token float64) int64 {
-	return int64(token * float64(TOKEN_UNIT))
-}
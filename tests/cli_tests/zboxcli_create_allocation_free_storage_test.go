@@ -7,17 +7,19 @@ import (
 	"io"
 	"os"
 	"regexp"
-	"strconv"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/0chain/system_test/internal/api/util/test"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
+	"github.com/0chain/system_test/internal/currency"
 
 	"github.com/0chain/system_test/internal/api/util/crypto"
 
 	"github.com/herumi/bls-go-binary/bls"
+	"github.com/shopspring/decimal"
 
 	"github.com/stretchr/testify/require"
 
@@ -61,7 +63,7 @@ func TestCreateAllocationFreeStorage(testSetup *testing.T) {
 		assigner := escapedTestName(t) + "_ASSIGNER"
 
 		// create assigner wallet
-		createWalletForName(assigner)
+		createWalletForName(t, assigner)
 
 		assignerWallet = readWalletFile(t, "./config/"+assigner+"_wallet.json")
 
@@ -79,9 +81,7 @@ func TestCreateAllocationFreeStorage(testSetup *testing.T) {
 		require.Nil(t, err, "get miners failed", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var miners climodel.NodeList
-		err = json.Unmarshal([]byte(output[0]), &miners)
-		require.Nil(t, err, "Error deserializing JSON string `%s`: %v", output[0], err)
+		miners := cliparse.RequireJSON[climodel.NodeList](t, output)
 		require.NotEmpty(t, miners.Nodes, "No miners found: %v", strings.Join(output, "\n"))
 
 		input := map[string]interface{}{
@@ -99,7 +99,7 @@ func TestCreateAllocationFreeStorage(testSetup *testing.T) {
 		recipient := escapedTestName(t)
 
 		// create recipient wallet
-		createWalletForName(recipient)
+		createWalletForName(t, recipient)
 
 		recipientWallet, err := getWalletForName(t, configPath, recipient)
 		require.Nil(t, err, "Error occurred when retrieving new owner wallet")
@@ -122,27 +122,30 @@ func TestCreateAllocationFreeStorage(testSetup *testing.T) {
 		err = os.WriteFile(markerFile, forFileBytes, 0600)
 		require.Nil(t, err, "Could not write file marker")
 
-		output, err := createNewAllocationForWallet(t, recipient, configPath, createParams(map[string]interface{}{
+		output, err := createNewAllocationForWallet(t, recipient, configPath, map[string]interface{}{
 			"free_storage": markerFile,
-		}))
+		})
 		require.Nil(t, err, "Failed to create new allocation", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 		matcher := regexp.MustCompile("Allocation created: ([a-f0-9]{64})")
 		require.Regexp(t, matcher, output[0], "Allocation creation output did not match expected")
 		allocationID := strings.Fields(output[0])[2]
 
-		readPoolFraction, err := strconv.ParseFloat(cfg[configKeyReadPoolFraction], 64)
-		require.Nil(t, err, "Read pool fraction config is not float: %s", cfg[configKeyReadPoolFraction])
+		readPoolFraction, err := decimal.NewFromString(cfg[configKeyReadPoolFraction])
+		require.Nil(t, err, "Read pool fraction config is not a number: %s", cfg[configKeyReadPoolFraction])
 
-		wantReadPoolFraction := marker.FreeTokens * readPoolFraction
-		wantWritePoolToken := marker.FreeTokens - wantReadPoolFraction
+		freeTokens, err := currency.ParseZCN(marker.FreeTokens)
+		require.Nil(t, err)
+		wantReadPoolFraction, err := freeTokens.MulDecimal(readPoolFraction)
+		require.Nil(t, err)
+		wantWritePoolToken := freeTokens - wantReadPoolFraction
 
 		allocation := getAllocation(t, allocationID)
-		require.Equal(t, ConvertToValue(wantWritePoolToken), allocation.WritePool, "Expected write pool amount not met", strings.Join(output, "\n"))
+		require.Equal(t, int64(wantWritePoolToken), allocation.WritePool, "Expected write pool amount not met", strings.Join(output, "\n"))
 		require.Equal(t, uint16(63), allocation.FileOptions, "Expected file_options to be 63 (all allowed) by default", strings.Join(output, "\n"))
 
 		readPool := getReadPoolInfo(t)
-		require.Equal(t, ConvertToValue(wantReadPoolFraction), readPool.Balance, "Read Pool balance must be equal to locked amount")
+		require.Equal(t, int64(wantReadPoolFraction), readPool.Balance, "Read Pool balance must be equal to locked amount")
 	})
 
 	t.Run("Create free storage with malformed marker should fail", func(t *test.SystemTest) {
@@ -154,9 +157,9 @@ func TestCreateAllocationFreeStorage(testSetup *testing.T) {
 		err := os.WriteFile(markerFile, []byte("bad marker json"), 0600)
 		require.Nil(t, err, "Could not write file marker")
 
-		output, err := createNewAllocationWithoutRetry(t, configPath, createParams(map[string]interface{}{
+		output, err := createNewAllocationWithoutRetry(t, configPath, map[string]interface{}{
 			"free_storage": markerFile,
-		}))
+		})
 		require.NotNil(t, err, "Failed to create new allocation", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 		require.Equal(t, "unmarshalling markerinvalid character 'b' looking for beginning of value", output[0])
@@ -171,9 +174,9 @@ func TestCreateAllocationFreeStorage(testSetup *testing.T) {
 		err := os.WriteFile(markerFile, []byte(`{"invalid_marker":true}`), 0600)
 		require.Nil(t, err, "Could not write file marker")
 
-		output, err := createNewAllocationWithoutRetry(t, configPath, createParams(map[string]interface{}{
+		output, err := createNewAllocationWithoutRetry(t, configPath, map[string]interface{}{
 			"free_storage": markerFile,
-		}))
+		})
 		require.NotNil(t, err, "Failed to create new allocation", strings.Join(output, "\n"))
 		require.Len(t, output, 1, strings.Join(output, "\n"))
 		require.Equal(t, "Error creating free allocation: free_allocation_failed: marker can be used only by its recipient", output[0])
@@ -183,7 +186,7 @@ func TestCreateAllocationFreeStorage(testSetup *testing.T) {
 		recipient := escapedTestName(t)
 
 		// create recipient wallet
-		createWalletForName(recipient)
+		createWalletForName(t, recipient)
 
 		recipientWallet, err := getWalletForName(t, configPath, recipient)
 		require.Nil(t, err, "Error occurred when retrieving new owner wallet")
@@ -206,9 +209,9 @@ func TestCreateAllocationFreeStorage(testSetup *testing.T) {
 		err = os.WriteFile(markerFile, forFileBytes, 0600)
 		require.Nil(t, err, "Could not write file marker")
 
-		output, err := createNewAllocationWithoutRetry(t, configPath, createParams(map[string]interface{}{
+		output, err := createNewAllocationWithoutRetry(t, configPath, map[string]interface{}{
 			"free_storage": markerFile,
-		}))
+		})
 		require.NotNil(t, err, "Failed to create new allocation", strings.Join(output, "\n"))
 		require.Equal(t, len(output), 1)
 		require.Equal(t, "Error creating free allocation: free_allocation_failed: marker verification failed: encoding/hex: invalid byte: U+0073 's'", output[0])
@@ -218,7 +221,7 @@ func TestCreateAllocationFreeStorage(testSetup *testing.T) {
 		recipientCorrect := escapedTestName(t) + "_RECIPIENT"
 
 		// create correct recipient wallet
-		createWalletForName(recipientCorrect)
+		createWalletForName(t, recipientCorrect)
 
 		recipientWallet, err := getWalletForName(t, configPath, recipientCorrect)
 		require.Nil(t, err, "Error occurred when retrieving new owner wallet")
@@ -252,9 +255,9 @@ func TestCreateAllocationFreeStorage(testSetup *testing.T) {
 		err = os.WriteFile(markerFile, forFileBytes, 0600)
 		require.Nil(t, err, "Could not write file marker")
 
-		output, err := createNewAllocationWithoutRetry(t, configPath, createParams(map[string]interface{}{
+		output, err := createNewAllocationWithoutRetry(t, configPath, map[string]interface{}{
 			"free_storage": markerFile,
-		}))
+		})
 		require.NotNil(t, err, "Failed to create new allocation", strings.Join(output, "\n"))
 		require.Equal(t, 1, len(output), strings.Join(output, "\n"))
 		require.Equal(t, "Error creating free allocation: free_allocation_failed: marker can be used only by its recipient", output[0])
@@ -264,7 +267,7 @@ func TestCreateAllocationFreeStorage(testSetup *testing.T) {
 		recipient := escapedTestName(t)
 
 		// create recipient wallet
-		createWalletForName(recipient)
+		createWalletForName(t, recipient)
 
 		recipientWallet, err := getWalletForName(t, configPath, recipient)
 		require.Nil(t, err, "Error occurred when retrieving new owner wallet")
@@ -287,9 +290,9 @@ func TestCreateAllocationFreeStorage(testSetup *testing.T) {
 		err = os.WriteFile(markerFile, forFileBytes, 0600)
 		require.Nil(t, err, "Could not write file marker")
 
-		output, err := createNewAllocationWithoutRetry(t, configPath, createParams(map[string]interface{}{
+		output, err := createNewAllocationWithoutRetry(t, configPath, map[string]interface{}{
 			"free_storage": markerFile,
-		}))
+		})
 		require.NotNil(t, err, "Failed to create new allocation", strings.Join(output, "\n"))
 		require.Equal(t, len(output), 1)
 		require.Equal(t, "Error creating free allocation: free_allocation_failed: marker verification failed: 1010000000000 exceeded permitted free storage  1000000000000", output[0])
@@ -1,8 +1,6 @@
 package cli_tests
 
 import (
-	"encoding/json"
-	"fmt"
 	"regexp"
 	"strings"
 	"testing"
@@ -11,7 +9,10 @@ import (
 	"github.com/0chain/system_test/internal/api/util/test"
 
 	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
+	"github.com/0chain/system_test/internal/currency"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
 )
 
@@ -30,7 +31,7 @@ func TestCreateAllocation(testSetup *testing.T) {
 			"read_price":  "0-1",
 			"write_price": "0-1",
 		}
-		output, err := createNewAllocation(t, configPath, createParams(options))
+		output, err := createNewAllocation(t, configPath, options)
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
@@ -38,12 +39,12 @@ func TestCreateAllocation(testSetup *testing.T) {
 		require.Nil(t, err, "could not get allocation cost", strings.Join(output, "\n"))
 
 		options = map[string]interface{}{
-			"lock":        allocationCost,
+			"lock":        allocationCost.Decimal(currency.ZCN),
 			"size":        "10000",
 			"read_price":  "0-1",
 			"write_price": "0-1",
 		}
-		output, err = createNewAllocation(t, configPath, createParams(options))
+		output, err = createNewAllocation(t, configPath, options)
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.True(t, len(output) > 0, "expected output length be at least 1")
 		require.Regexp(t, regexp.MustCompile("^Allocation created: [0-9a-fA-F]{64}$"), output[0], strings.Join(output, "\n"))
@@ -63,16 +64,17 @@ func TestCreateAllocation(testSetup *testing.T) {
 			"write_price": "0-1",
 			"size":        10000,
 		}
-		output, err := createNewAllocation(t, configPath, createParams(options))
+		output, err := createNewAllocation(t, configPath, options)
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.True(t, len(output) > 0, "expected output length be at least 1")
 
 		allocationCost, err := getAllocationCost(output[0])
 		require.Nil(t, err, "could not get allocation cost", strings.Join(output, "\n"))
 
-		mustFailCost := allocationCost * 0.8
-		options = map[string]interface{}{"lock": mustFailCost}
-		output, err = createNewAllocationWithoutRetry(t, configPath, createParams(options))
+		mustFailCost, err := allocationCost.MulDecimal(decimal.NewFromFloat(0.8))
+		require.Nil(t, err)
+		options = map[string]interface{}{"lock": mustFailCost.Decimal(currency.ZCN)}
+		output, err = createNewAllocationWithoutRetry(t, configPath, options)
 		require.NotNil(t, err, strings.Join(output, "\n"))
 		require.Contains(t, output[len(output)-1], "not enough tokens to honor the allocation")
 	})
@@ -88,7 +90,7 @@ func TestCreateAllocation(testSetup *testing.T) {
 		}
 		mustFailCost := -1
 		options = map[string]interface{}{"lock": mustFailCost}
-		output, err := createNewAllocationWithoutRetry(t, configPath, createParams(options))
+		output, err := createNewAllocationWithoutRetry(t, configPath, options)
 		require.NotNil(t, err, strings.Join(output, "\n"))
 	})
 
@@ -96,7 +98,7 @@ func TestCreateAllocation(testSetup *testing.T) {
 		_ = setupWallet(t, configPath)
 
 		options := map[string]interface{}{"size": "1024", "lock": "0.5"}
-		output, err := createNewAllocation(t, configPath, createParams(options))
+		output, err := createNewAllocation(t, configPath, options)
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.True(t, len(output) > 0, "expected output length be at least 1")
 		require.Regexp(t, regexp.MustCompile("^Allocation created: [0-9a-fA-F]{64}$"), output[0], strings.Join(output, "\n"))
@@ -110,7 +112,7 @@ func TestCreateAllocation(testSetup *testing.T) {
 	t.Run("Create allocation for another owner should Work", func(t *test.SystemTest) {
 		_ = setupWallet(t, configPath)
 		targetWalletName := escapedTestName(t) + "_TARGET"
-		createWalletForName(targetWalletName)
+		createWalletForName(t, targetWalletName)
 
 		targetWallet, err := getWalletForName(t, configPath, targetWalletName)
 		require.Nil(t, err, "could not get target wallet")
@@ -120,7 +122,7 @@ func TestCreateAllocation(testSetup *testing.T) {
 			"owner":            targetWallet.ClientID,
 			"owner_public_key": targetWallet.ClientPublicKey,
 		}
-		output, err := createNewAllocation(t, configPath, createParams(options))
+		output, err := createNewAllocation(t, configPath, options)
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.True(t, len(output) > 0, "expected output length be at least 1")
 		require.Regexp(t, regexp.MustCompile("^Allocation created: [0-9a-fA-F]{64}$"), output[0], strings.Join(output, "\n"))
@@ -155,7 +157,7 @@ func TestCreateAllocation(testSetup *testing.T) {
 		_ = setupWallet(t, configPath)
 
 		options := map[string]interface{}{"size": "1024", "parity": "1", "lock": "0.5"}
-		output, err := createNewAllocation(t, configPath, createParams(options))
+		output, err := createNewAllocation(t, configPath, options)
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.True(t, len(output) > 0, "expected output length be at least 1")
 		require.Regexp(t, regexp.MustCompile("^Allocation created: [0-9a-fA-F]{64}$"), output[0], strings.Join(output, "\n"))
@@ -170,7 +172,7 @@ func TestCreateAllocation(testSetup *testing.T) {
 		_ = setupWallet(t, configPath)
 
 		options := map[string]interface{}{"size": "1024", "data": "1", "lock": "0.5"}
-		output, err := createNewAllocation(t, configPath, createParams(options))
+		output, err := createNewAllocation(t, configPath, options)
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.True(t, len(output) > 0, "expected output length be at least 1")
 		require.Regexp(t, regexp.MustCompile("^Allocation created: [0-9a-fA-F]{64}$"), output[0], strings.Join(output, "\n"))
@@ -185,7 +187,7 @@ func TestCreateAllocation(testSetup *testing.T) {
 		_ = setupWallet(t, configPath)
 
 		options := map[string]interface{}{"size": "1024", "read_price": "0-9999", "lock": "0.5"}
-		output, err := createNewAllocation(t, configPath, createParams(options))
+		output, err := createNewAllocation(t, configPath, options)
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.True(t, len(output) > 0, "expected output length be at least 1")
 		require.Regexp(t, regexp.MustCompile("^Allocation created: [0-9a-fA-F]{64}$"), output[0], strings.Join(output, "\n"))
@@ -200,7 +202,7 @@ func TestCreateAllocation(testSetup *testing.T) {
 		_ = setupWallet(t, configPath)
 
 		options := map[string]interface{}{"size": "1024", "write_price": "0-9999", "lock": "0.5"}
-		output, err := createNewAllocation(t, configPath, createParams(options))
+		output, err := createNewAllocation(t, configPath, options)
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.True(t, len(output) > 0, "expected output length be at least 1")
 		require.Regexp(t, regexp.MustCompile("^Allocation created: [0-9a-fA-F]{64}$"), output[0], strings.Join(output, "\n"))
@@ -214,7 +216,7 @@ func TestCreateAllocation(testSetup *testing.T) {
 		_ = setupWallet(t, configPath)
 
 		options := map[string]interface{}{"parity": "99", "lock": "0.5", "size": 1024}
-		output, err := createNewAllocationWithoutRetry(t, configPath, createParams(options))
+		output, err := createNewAllocationWithoutRetry(t, configPath, options)
 		require.NotNil(t, err, strings.Join(output, "\n"))
 		require.True(t, len(output) > 0, "expected output length be at least 1")
 		require.Contains(t, output[0], "Too many blobbers selected")
@@ -224,7 +226,7 @@ func TestCreateAllocation(testSetup *testing.T) {
 		_ = setupWallet(t, configPath)
 
 		options := map[string]interface{}{"data": "99", "lock": "0.5", "size": 1024}
-		output, err := createNewAllocationWithoutRetry(t, configPath, createParams(options))
+		output, err := createNewAllocationWithoutRetry(t, configPath, options)
 		require.NotNil(t, err, strings.Join(output, "\n"))
 		require.True(t, len(output) > 0, "expected output length be at least 1")
 		require.Contains(t, output[0], "Too many blobbers selected")
@@ -234,7 +236,7 @@ func TestCreateAllocation(testSetup *testing.T) {
 		_ = setupWallet(t, configPath)
 
 		options := map[string]interface{}{"data": "30", "parity": "20", "lock": "0.5", "size": 1024}
-		output, err := createNewAllocationWithoutRetry(t, configPath, createParams(options))
+		output, err := createNewAllocationWithoutRetry(t, configPath, options)
 		require.NotNil(t, err, strings.Join(output, "\n"))
 		require.True(t, len(output) > 0, "expected output length be at least 1")
 		require.Contains(t, output[0], "Too many blobbers selected")
@@ -244,7 +246,7 @@ func TestCreateAllocation(testSetup *testing.T) {
 		_ = setupWallet(t, configPath)
 
 		options := map[string]interface{}{"read_price": "0-0", "lock": "0.5", "size": 1024}
-		output, err := createNewAllocationWithoutRetry(t, configPath, createParams(options))
+		output, err := createNewAllocationWithoutRetry(t, configPath, options)
 		require.NotNil(t, err, strings.Join(output, "\n"))
 		require.True(t, len(output) > 0, "expected output length be at least 1")
 		require.Contains(t, output[0], "Error creating allocation: failed_get_allocation_blobbers: failed to get blobbers for allocation: not enough blobbers to honor the allocation", strings.Join(output, "\n"))
@@ -254,7 +256,7 @@ func TestCreateAllocation(testSetup *testing.T) {
 		_ = setupWallet(t, configPath)
 
 		options := map[string]interface{}{"size": 256, "lock": "0.5"}
-		output, err := createNewAllocationWithoutRetry(t, configPath, createParams(options))
+		output, err := createNewAllocationWithoutRetry(t, configPath, options)
 		require.NotNil(t, err, strings.Join(output, "\n"))
 		require.True(t, len(output) > 0, "expected output length be at least 1")
 		require.Equal(t, "Error creating allocation: allocation_creation_failed: invalid request: insufficient allocation size", output[0], strings.Join(output, "\n"))
@@ -264,7 +266,7 @@ func TestCreateAllocation(testSetup *testing.T) {
 		_ = setupWallet(t, configPath)
 
 		options := map[string]interface{}{}
-		output, err := createNewAllocationWithoutRetry(t, configPath, createParams(options))
+		output, err := createNewAllocationWithoutRetry(t, configPath, options)
 		require.NotNil(t, err)
 		require.True(t, len(output) > 0, "expected output length be at least 1", strings.Join(output, "\n"))
 		require.Equal(t, "missing required 'lock' argument", output[len(output)-1])
@@ -274,7 +276,7 @@ func TestCreateAllocation(testSetup *testing.T) {
 		_ = setupWallet(t, configPath)
 
 		options := map[string]interface{}{"lock": "0.5", "size": 1024}
-		output, err := createNewAllocationWithoutRetry(t, configPath, createParams(options))
+		output, err := createNewAllocationWithoutRetry(t, configPath, options)
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.True(t, len(output) > 0, "expected output length be at least 1")
 		require.Contains(t, output[0], "Allocation created", strings.Join(output, "\n"))
@@ -288,8 +290,7 @@ func TestCreateAllocation(testSetup *testing.T) {
 		output, err = getAllocationWithRetry(t, configPath, allocationID, 10)
 		require.Nil(t, err, "error fetching allocation")
 		require.Greater(t, len(output), 0, "gettting allocation - output is empty unexpectedly")
-		err = json.Unmarshal([]byte(output[0]), &alloc)
-		require.Nil(t, err, "error unmarshalling allocation json")
+		alloc = cliparse.RequireJSON[*climodel.Allocation](t, output)
 		require.Equal(t, uint16(63), alloc.FileOptions)
 		createAllocationTestTeardown(t, allocationID)
 	})
@@ -299,7 +300,7 @@ func TestCreateAllocation(testSetup *testing.T) {
 
 		// Forbid upload
 		options := map[string]interface{}{"lock": "0.5", "size": 1024, "forbid_upload": nil}
-		output, err := createNewAllocationWithoutRetry(t, configPath, createParams(options))
+		output, err := createNewAllocationWithoutRetry(t, configPath, options)
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.True(t, len(output) > 0, "expected output length be at least 1")
 		require.Contains(t, output[0], "Allocation created", strings.Join(output, "\n"))
@@ -313,7 +314,7 @@ func TestCreateAllocation(testSetup *testing.T) {
 
 		// Forbid delete
 		options = map[string]interface{}{"lock": "0.5", "size": 1024, "forbid_delete": nil}
-		output, err = createNewAllocationWithoutRetry(t, configPath, createParams(options))
+		output, err = createNewAllocationWithoutRetry(t, configPath, options)
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.True(t, len(output) > 0, "expected output length be at least 1")
 		require.Contains(t, output[0], "Allocation created", strings.Join(output, "\n"))
@@ -327,7 +328,7 @@ func TestCreateAllocation(testSetup *testing.T) {
 
 		// Forbid update
 		options = map[string]interface{}{"lock": "0.5", "size": 1024, "forbid_update": nil}
-		output, err = createNewAllocationWithoutRetry(t, configPath, createParams(options))
+		output, err = createNewAllocationWithoutRetry(t, configPath, options)
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.True(t, len(output) > 0, "expected output length be at least 1")
 		require.Contains(t, output[0], "Allocation created", strings.Join(output, "\n"))
@@ -341,7 +342,7 @@ func TestCreateAllocation(testSetup *testing.T) {
 
 		// Forbid move
 		options = map[string]interface{}{"lock": "0.5", "size": 1024, "forbid_move": nil}
-		output, err = createNewAllocationWithoutRetry(t, configPath, createParams(options))
+		output, err = createNewAllocationWithoutRetry(t, configPath, options)
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.True(t, len(output) > 0, "expected output length be at least 1")
 		require.Contains(t, output[0], "Allocation created", strings.Join(output, "\n"))
@@ -355,7 +356,7 @@ func TestCreateAllocation(testSetup *testing.T) {
 
 		// Forbid copy
 		options = map[string]interface{}{"lock": "0.5", "size": 1024, "forbid_copy": nil}
-		output, err = createNewAllocationWithoutRetry(t, configPath, createParams(options))
+		output, err = createNewAllocationWithoutRetry(t, configPath, options)
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.True(t, len(output) > 0, "expected output length be at least 1")
 		require.Contains(t, output[0], "Allocation created", strings.Join(output, "\n"))
@@ -369,7 +370,7 @@ func TestCreateAllocation(testSetup *testing.T) {
 
 		// Forbid rename
 		options = map[string]interface{}{"lock": "0.5", "size": 1024, "forbid_rename": nil}
-		output, err = createNewAllocationWithoutRetry(t, configPath, createParams(options))
+		output, err = createNewAllocationWithoutRetry(t, configPath, options)
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.True(t, len(output) > 0, "expected output length be at least 1")
 		require.Contains(t, output[0], "Allocation created", strings.Join(output, "\n"))
@@ -387,7 +388,7 @@ func TestCreateAllocation(testSetup *testing.T) {
 
 		// Forbid update, rename and delete
 		options := map[string]interface{}{"lock": "0.5", "size": 1024}
-		output, err := createNewAllocationWithoutRetry(t, configPath, createParams(options))
+		output, err := createNewAllocationWithoutRetry(t, configPath, options)
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.True(t, len(output) > 0, "expected output length be at least 1")
 		require.Contains(t, output[0], "Allocation created", strings.Join(output, "\n"))
@@ -401,14 +402,13 @@ func TestCreateAllocation(testSetup *testing.T) {
 		output, err = getAllocationWithRetry(t, configPath, allocationID, 10)
 		require.Nil(t, err, "error fetching allocation")
 		require.Greater(t, len(output), 0, "gettting allocation - output is empty unexpectedly")
-		err = json.Unmarshal([]byte(output[0]), &alloc)
-		require.Nil(t, err, "error unmarshalling allocation json")
+		alloc = cliparse.RequireJSON[*climodel.Allocation](t, output)
 		require.Equal(t, false, alloc.ThirdPartyExtendable) // 63 - (2 + 4 + 32) = 25 (update mask = 2, rename = 32, delete = 4)
 		createAllocationTestTeardown(t, allocationID)
 
 		// Forbid upload, move and copy
 		options = map[string]interface{}{"lock": "0.5", "size": 1024, "third_party_extendable": nil}
-		output, err = createNewAllocationWithoutRetry(t, configPath, createParams(options))
+		output, err = createNewAllocationWithoutRetry(t, configPath, options)
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.True(t, len(output) > 0, "expected output length be at least 1")
 		require.Contains(t, output[0], "Allocation created", strings.Join(output, "\n"))
@@ -420,8 +420,7 @@ func TestCreateAllocation(testSetup *testing.T) {
 		output, err = getAllocationWithRetry(t, configPath, allocationID, 10)
 		require.Nil(t, err, "error fetching allocation")
 		require.Greater(t, len(output), 0, "gettting allocation - output is empty unexpectedly")
-		err = json.Unmarshal([]byte(output[0]), &alloc)
-		require.Nil(t, err, "error unmarshalling allocation json")
+		alloc = cliparse.RequireJSON[*climodel.Allocation](t, output)
 		require.Equal(t, true, alloc.ThirdPartyExtendable) // 63 - (1 + 8 + 16) = 38 (upload mask = 1, move = 8, copy = 16)
 		createAllocationTestTeardown(t, allocationID)
 	})
@@ -436,27 +435,29 @@ func setupWallet(t *test.SystemTest, configPath string) []string {
 	return output
 }
 
-func createNewAllocation(t *test.SystemTest, cliConfigFilename, params string) ([]string, error) {
+func createNewAllocation(t *test.SystemTest, cliConfigFilename string, params map[string]interface{}) ([]string, error) {
 	return createNewAllocationForWallet(t, escapedTestName(t), cliConfigFilename, params)
 }
 
-func createNewAllocationForWallet(t *test.SystemTest, wallet, cliConfigFilename, params string) ([]string, error) {
+func createNewAllocationForWallet(t *test.SystemTest, wallet, cliConfigFilename string, params map[string]interface{}) ([]string, error) {
 	t.Logf("Creating new allocation...")
-	return cliutils.RunCommand(t, fmt.Sprintf(
-		"./zbox newallocation %s --silent --wallet %s --configDir ./config --config %s --allocationFileName %s",
-		params,
-		wallet+"_wallet.json",
-		cliConfigFilename,
-		wallet+"_allocation.txt"), 3, time.Second*5)
+	return newAllocationCommand(wallet, cliConfigFilename, params).Run(t, 3, time.Second*5)
+}
+
+func createNewAllocationWithoutRetry(t *test.SystemTest, cliConfigFilename string, params map[string]interface{}) ([]string, error) {
+	return newAllocationCommand(escapedTestName(t), cliConfigFilename, params).RunWithoutRetry(t)
+}
+
+func newAllocationCommand(wallet, cliConfigFilename string, params map[string]interface{}) cliutils.Command {
+	var flags cliutils.ZboxNewAllocation
+	cliutils.DecodeFlags(params, &flags)
+	flags.AllocationFileName = wallet + "_allocation.txt"
+	return cliutils.Zbox("newallocation", flags, zboxFlags(wallet, cliConfigFilename))
 }
 
-func createNewAllocationWithoutRetry(t *test.SystemTest, cliConfigFilename, params string) ([]string, error) {
-	return cliutils.RunCommandWithoutRetry(fmt.Sprintf(
-		"./zbox newallocation %s --silent --wallet %s --configDir ./config --config %s --allocationFileName %s",
-		params,
-		escapedTestName(t)+"_wallet.json",
-		cliConfigFilename,
-		escapedTestName(t)+"_allocation.txt"))
+// zboxFlags are the global flags of the commands run by wallet
+func zboxFlags(wallet, cliConfigFilename string) cliutils.GlobalFlags {
+	return cliutils.GlobalFlags{Wallet: wallet + "_wallet.json", ConfigDir: "./config", Config: cliConfigFilename, Silent: true}
 }
 
 func createAllocationTestTeardown(t *test.SystemTest, allocationID string) {
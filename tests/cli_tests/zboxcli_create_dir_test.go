@@ -1,7 +1,6 @@
 package cli_tests
 
 import (
-	"encoding/json"
 	"strings"
 	"testing"
 	"time"
@@ -9,6 +8,7 @@ import (
 	"github.com/0chain/system_test/internal/api/util/test"
 
 	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
 	"github.com/stretchr/testify/require"
 )
@@ -32,9 +32,7 @@ func TestCreateDir(testSetup *testing.T) {
 		require.Nil(t, err, "Unexpected list all failure %s", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var files []climodel.AllocationFile
-		err = json.Unmarshal([]byte(output[0]), &files)
-		require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output, "\n"), err)
+		files := cliparse.RequireJSON[[]climodel.AllocationFile](t, output)
 
 		wantFile := climodel.AllocationFile{Name: "rootdir", Path: "/rootdir", Type: "d"}
 		require.Len(t, files, 1, "Expecting directories created. Possibly `createdir` failed to create on blobbers (error suppressed) or unable to `list-all` from 3/4 blobbers")
@@ -60,9 +58,7 @@ func TestCreateDir(testSetup *testing.T) {
 		require.Nil(t, err, "Unexpected list all failure %s", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var files []climodel.AllocationFile
-		err = json.Unmarshal([]byte(output[0]), &files)
-		require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output, "\n"), err)
+		files := cliparse.RequireJSON[[]climodel.AllocationFile](t, output)
 
 		require.Len(t, files, 2, "Expecting directories created. Possibly `createdir` failed to create on blobbers (error suppressed) or unable to `list-all` from 3/4 blobbers")
 		require.Contains(t, files, climodel.AllocationFile{Name: "parent", Path: "/parent", Type: "d"})
@@ -86,9 +82,7 @@ func TestCreateDir(testSetup *testing.T) {
 		require.Nil(t, err, "Unexpected list all failure %s", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var files []climodel.AllocationFile
-		err = json.Unmarshal([]byte(output[0]), &files)
-		require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output, "\n"), err)
+		files := cliparse.RequireJSON[[]climodel.AllocationFile](t, output)
 
 		wantFile := climodel.AllocationFile{Name: longDirName[1:], Path: longDirName, Type: "d"}
 		require.Len(t, files, 1, "Expecting directories created. Possibly `createdir` failed to create on blobbers (error suppressed) or unable to `list-all` from 3/4 blobbers")
@@ -115,9 +109,7 @@ func TestCreateDir(testSetup *testing.T) {
 		require.Len(t, output, 1, "unexpected output"+strings.Join(output, ", "))
 		require.Equal(t, "[]", output[0], "unexpected output"+strings.Join(output, ", "))
 
-		var files []climodel.AllocationFile
-		err = json.Unmarshal([]byte(output[0]), &files)
-		require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output, "\n"), err)
+		files := cliparse.RequireJSON[[]climodel.AllocationFile](t, output)
 
 		require.Len(t, files, 0)
 	})
@@ -166,9 +158,7 @@ func TestCreateDir(testSetup *testing.T) {
 		require.Nil(t, err, "Unexpected list all failure %s", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var files []climodel.AllocationFile
-		err = json.Unmarshal([]byte(output[0]), &files)
-		require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output, "\n"), err)
+		files := cliparse.RequireJSON[[]climodel.AllocationFile](t, output)
 
 		require.Len(t, files, 2, "Expecting directories created. Possibly `createdir` failed to create on blobbers (error suppressed) or unable to `list-all` from 3/4 blobbers")
 		require.Contains(t, files, climodel.AllocationFile{Name: "existingdir", Path: "/existingdir", Type: "d"})
@@ -188,9 +178,7 @@ func TestCreateDir(testSetup *testing.T) {
 		require.Nil(t, err, "Unexpected list all failure %s", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var files []climodel.AllocationFile
-		err = json.Unmarshal([]byte(output[0]), &files)
-		require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output, "\n"), err)
+		files := cliparse.RequireJSON[[]climodel.AllocationFile](t, output)
 
 		require.Len(t, files, 2, "Expecting directories created. Possibly `createdir` failed to create on blobbers (error suppressed) or unable to `list-all` from 3/4 blobbers")
 		require.Contains(t, files, climodel.AllocationFile{Name: "nonexistent", Path: "/nonexistent", Type: "d"})
@@ -210,9 +198,7 @@ func TestCreateDir(testSetup *testing.T) {
 		require.Nil(t, err, "Unexpected list all failure %s", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var files []climodel.AllocationFile
-		err = json.Unmarshal([]byte(output[0]), &files)
-		require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output, "\n"), err)
+		files := cliparse.RequireJSON[[]climodel.AllocationFile](t, output)
 
 		wantFile := climodel.AllocationFile{Name: dirname[1:], Path: dirname, Type: "d"}
 		require.Len(t, files, 1, "Expecting directories created. Possibly `createdir` failed to create on blobbers (error suppressed) or unable to `list-all` from 3/4 blobbers")
@@ -279,7 +265,7 @@ func TestCreateDir(testSetup *testing.T) {
 
 		allocID := setupAllocation(t, configPath)
 
-		createWalletForName(nonAllocOwnerWallet)
+		createWalletForName(t, nonAllocOwnerWallet)
 
 		output, err := createDirForWallet(t, configPath, nonAllocOwnerWallet, true, allocID, true, "/mydir", false)
 		require.NotNil(t, err, "Expected create dir failure but got output: ", strings.Join(output, "\n"))
@@ -305,7 +291,7 @@ func createDirForWallet(t *test.SystemTest, cliConfigFilename, wallet string, wi
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
@@ -322,6 +308,6 @@ func listAllWithWallet(t *test.SystemTest, wallet, cliConfigFilename, allocation
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
@@ -3,7 +3,6 @@ package cli_tests
 import (
 	"crypto/md5" //nolint:gosec
 	"encoding/hex"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -20,6 +19,7 @@ import (
 	"github.com/0chain/system_test/internal/api/util/test"
 
 	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
 	"github.com/stretchr/testify/require"
 )
@@ -43,10 +43,10 @@ func TestLivestreamDownload(testSetup *testing.T) { // nolint cyclomatic complex
 
 		_ = initialiseTest(t, walletOwner, true)
 
-		output, err := createNewAllocation(t, configPath, createParams(map[string]interface{}{
+		output, err := createNewAllocation(t, configPath, map[string]interface{}{
 			"lock": 5,
 			"size": "10000",
-		}))
+		})
 
 		require.Nil(t, err, "error creating allocation", strings.Join(output, "\n"))
 
@@ -176,9 +176,7 @@ func TestLivestreamDownload(testSetup *testing.T) { // nolint cyclomatic complex
 		require.Nil(t, err, "error listing files in remotepath")
 		require.Len(t, output, 1, "listing files should return an output")
 
-		files := []climodel.ListFileResult{}
-		err = json.Unmarshal([]byte(output[0]), &files)
-		require.Nil(t, err, "error in unmarshalling the response from the list files")
+		files := cliparse.RequireJSON[[]climodel.ListFileResult](t, output)
 
 		for _, file := range files {
 			require.Regexp(t, regexp.MustCompile(`up(\d+).ts`), file.Name, "files created locally must be found uploaded to allocation")
@@ -191,10 +189,10 @@ func TestLivestreamDownload(testSetup *testing.T) { // nolint cyclomatic complex
 
 		_ = initialiseTest(t, walletOwner, true)
 
-		output, err := createNewAllocation(t, configPath, createParams(map[string]interface{}{
+		output, err := createNewAllocation(t, configPath, map[string]interface{}{
 			"lock": 5,
 			"size": "10000",
-		}))
+		})
 		t.Log(output)
 		require.Nil(t, err, "error creating allocation", strings.Join(output, "\n"))
 
@@ -325,9 +323,7 @@ func TestLivestreamDownload(testSetup *testing.T) { // nolint cyclomatic complex
 		require.Nil(t, err, "error listing files in remotepath")
 		require.Len(t, output, 1, "listing files should return an output")
 
-		files := []climodel.ListFileResult{}
-		err = json.Unmarshal([]byte(output[0]), &files)
-		require.Nil(t, err, "error in unmarshalling the response from the list files")
+		files := cliparse.RequireJSON[[]climodel.ListFileResult](t, output)
 
 		for _, file := range files {
 			require.Regexp(t, regexp.MustCompile(`up(\d+).ts`), file.Name, "files created locally must be found uploaded to allocation")
@@ -345,10 +341,10 @@ func TestLivestreamDownload(testSetup *testing.T) { // nolint cyclomatic complex
 
 		_ = initialiseTest(t, walletOwner, true)
 
-		output, err := createNewAllocation(t, configPath, createParams(map[string]interface{}{
+		output, err := createNewAllocation(t, configPath, map[string]interface{}{
 			"lock": 5,
 			"size": "10000",
-		}))
+		})
 		t.Log(output)
 		require.Nil(t, err, "error creating allocation", strings.Join(output, "\n"))
 
@@ -479,9 +475,7 @@ func TestLivestreamDownload(testSetup *testing.T) { // nolint cyclomatic complex
 		require.Nil(t, err, "error listing files in remotepath")
 		require.Len(t, output, 1, "listing files should return an output")
 
-		files := []climodel.ListFileResult{}
-		err = json.Unmarshal([]byte(output[0]), &files)
-		require.Nil(t, err, "error in unmarshalling the response from the list files")
+		files := cliparse.RequireJSON[[]climodel.ListFileResult](t, output)
 
 		for _, file := range files {
 			require.Regexp(t, regexp.MustCompile(`up(\d+).ts`), file.Name, "files created locally must be found uploaded to allocation")
@@ -1,16 +1,16 @@
 package cli_tests
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
-	"strconv"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/0chain/system_test/internal/api/util/test"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
+	"github.com/0chain/system_test/internal/currency"
 
 	climodel "github.com/0chain/system_test/internal/cli/model"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
@@ -53,9 +53,7 @@ func TestFileDownloadTokenMovement(testSetup *testing.T) {
 		require.Nil(t, err, "Error fetching read pool", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		initialReadPool := climodel.ReadPoolInfo{}
-		err = json.Unmarshal([]byte(output[0]), &initialReadPool)
-		require.Nil(t, err, "Error unmarshalling read pool", strings.Join(output, "\n"))
+		initialReadPool := cliparse.RequireJSON[climodel.ReadPoolInfo](t, output)
 		require.NotEmpty(t, initialReadPool)
 
 		// staked a total of 1 ZCN in readpool
@@ -68,12 +66,9 @@ func TestFileDownloadTokenMovement(testSetup *testing.T) {
 		require.Nil(t, err, "Could not get download cost", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		expectedDownloadCost, err := strconv.ParseFloat(strings.Fields(output[0])[0], 64)
-		require.Nil(t, err, "Cost couldn't be parsed to float", strings.Join(output, "\n"))
-
-		unit := strings.Fields(output[0])[1]
-		expectedDownloadCostInSas := unitToZCN(expectedDownloadCost, unit) * 1e10
-		t.Logf("Download cost: %v sas", expectedDownloadCostInSas)
+		expectedDownloadCost, err := parseCost(output[0])
+		require.Nil(t, err, "Cost couldn't be parsed", strings.Join(output, "\n"))
+		t.Logf("Download cost: %v", expectedDownloadCost)
 
 		// Download the file (delete local copy first)
 		os.Remove(file)
@@ -99,21 +94,19 @@ func TestFileDownloadTokenMovement(testSetup *testing.T) {
 		require.Nil(t, err, "Error fetching read pool", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		finalReadPool := climodel.ReadPoolInfo{}
-		err = json.Unmarshal([]byte(output[0]), &finalReadPool)
-		require.Nil(t, err, "Error unmarshalling read pool", strings.Join(output, "\n"))
+		finalReadPool := cliparse.RequireJSON[climodel.ReadPoolInfo](t, output)
 		require.NotEmpty(t, finalReadPool)
 
-		expectedRPBalance := float64(initialReadPool.Balance) - expectedDownloadCostInSas - 10 // because download cost is till 3 decimal point only and missing the 4th decimal digit
+		expectedRPBalance := initialReadPool.Balance - int64(expectedDownloadCost) - 10 // because download cost is till 3 decimal point only and missing the 4th decimal digit
 		require.Nil(t, err, "Error fetching read pool", strings.Join(output, "\n"))
 
 		// getDownloadCost returns download cost when all the associated blobbers of an allocation are required
 		// In current enhancement/verify-download PR, it gets data from minimum blobbers possible.
 		// So the download cost will be in between initial balance and expected balance.
-		t.Logf("FinalReadPool.Balance:%d\nInitialReadPool.Balance:%d\nExpectedReadPool.Balance:%d\n", finalReadPool.Balance, initialReadPool.Balance, int64(expectedRPBalance))
+		t.Logf("FinalReadPool.Balance:%d\nInitialReadPool.Balance:%d\nExpectedReadPool.Balance:%d\n", finalReadPool.Balance, initialReadPool.Balance, expectedRPBalance)
 		require.Equal(t, true,
 			finalReadPool.Balance < initialReadPool.Balance &&
-				finalReadPool.Balance >= int64(expectedRPBalance))
+				finalReadPool.Balance >= expectedRPBalance)
 	})
 }
 
@@ -137,7 +130,7 @@ func readPoolLockWithWallet(t *test.SystemTest, wallet, cliConfigFilename, param
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
@@ -151,21 +144,15 @@ func getDownloadCostWithWallet(t *test.SystemTest, wallet, cliConfigFilename, pa
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
-func unitToZCN(unitCost float64, unit string) float64 {
-	switch unit {
-	case "SAS", "sas":
-		unitCost /= 1e10
-		return unitCost
-	case "uZCN", "uzcn":
-		unitCost /= 1e6
-		return unitCost
-	case "mZCN", "mzcn":
-		unitCost /= 1e3
-		return unitCost
+// parseCost parses a cost printed by the CLIs, an amount followed by its unit, e.g. "0.25 mZCN"
+func parseCost(output string) (currency.Coin, error) {
+	fields := strings.Fields(output)
+	if len(fields) < 2 {
+		return 0, fmt.Errorf("%w: %q", currency.ErrInvalidCoin, output)
 	}
-	return unitCost
+	return currency.ParseCoin(fields[0] + " " + fields[1])
 }
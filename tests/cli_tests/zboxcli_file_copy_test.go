@@ -6,7 +6,6 @@ import (
 	"math"
 	"path/filepath"
 	"regexp"
-	"strconv"
 	"strings"
 	"sync"
 	"testing"
@@ -16,6 +15,7 @@ import (
 
 	climodel "github.com/0chain/system_test/internal/cli/model"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
+	"github.com/0chain/system_test/internal/currency"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -755,7 +755,7 @@ func TestFileCopy(testSetup *testing.T) { // nolint:gocyclo // team preference i
 	t.Run("copy file from someone else's allocation should fail", func(t *test.SystemTest) {
 		nonAllocOwnerWallet := escapedTestName(t) + "_NON_OWNER"
 
-		createWalletForName(nonAllocOwnerWallet)
+		createWalletForName(t, nonAllocOwnerWallet)
 
 		allocSize := int64(2048)
 		fileSize := int64(256)
@@ -913,10 +913,10 @@ func TestFileCopy(testSetup *testing.T) { // nolint:gocyclo // team preference i
 		createWallet(t)
 
 		// Lock 0.5 token for allocation
-		allocParams := createParams(map[string]interface{}{
+		allocParams := map[string]interface{}{
 			"lock": "0.5",
 			"size": 4 * MB,
-		})
+		}
 		output, err := createNewAllocation(t, configPath, allocParams)
 		require.Nil(t, err, "Failed to create new allocation", strings.Join(output, "\n"))
 		t.Logf("Allocation created: %s", output[0])
@@ -934,19 +934,15 @@ func TestFileCopy(testSetup *testing.T) { // nolint:gocyclo // team preference i
 		// Upload 1 MB file
 		localpath := uploadRandomlyGeneratedFile(t, allocationID, "/", fileSize)
 		output, _ = getUploadCostInUnit(t, configPath, allocationID, localpath)
-		expectedUploadCostInZCN, err := strconv.ParseFloat(strings.Fields(output[0])[0], 64)
-		require.Nil(t, err, "Cost couldn't be parsed to float", strings.Join(output, "\n"))
-		t.Logf("Upload cost: %v", expectedUploadCostInZCN)
-		unit := strings.Fields(output[0])[1]
-		t.Logf("Upload cost unit: %v", unit)
-		expectedUploadCostInZCN = unitToZCN(expectedUploadCostInZCN, unit)
-		t.Logf("Upload cost in ZCN: %v", expectedUploadCostInZCN)
+		expectedUploadCost, err := parseCost(output[0])
+		require.Nil(t, err, "Cost couldn't be parsed", strings.Join(output, "\n"))
+		t.Logf("Upload cost: %v", expectedUploadCost)
 
 		time.Sleep(30 * time.Second)
 
 		allocAfterUpload := getAllocation(t, allocationID)
 		require.Equal(t, initialAllocation.WritePool-allocAfterUpload.WritePool, allocAfterUpload.MovedToChallenge)
-		require.InEpsilon(t, expectedUploadCostInZCN, intToZCN(allocAfterUpload.MovedToChallenge), 0.05, "Upload cost is not as expected %v != %v", expectedUploadCostInZCN, intToZCN(allocAfterUpload.MovedToChallenge))
+		requireCostInEpsilon(t, expectedUploadCost, allocAfterUpload.MovedToChallenge, 0.05, "Upload cost is not as expected")
 
 		remotepath := "/" + filepath.Base(localpath)
 		// copy file
@@ -969,9 +965,9 @@ func TestFileCopy(testSetup *testing.T) { // nolint:gocyclo // team preference i
 		actualCost := finalAllocation.MovedToChallenge - allocAfterUpload.MovedToChallenge
 
 		t.Logf("Actual cost: %v", actualCost)
-		t.Log("expectedUploadCostInZCN : ", expectedUploadCostInZCN, " actualCost : ", intToZCN(actualCost))
+		t.Log("expectedUploadCost : ", expectedUploadCost, " actualCost : ", currency.Coin(actualCost))
 
-		require.InEpsilon(t, expectedUploadCostInZCN, intToZCN(actualCost), 0.05, "Copy file cost is not as expected")
+		requireCostInEpsilon(t, expectedUploadCost, actualCost, 0.05, "Copy file cost is not as expected")
 
 		createAllocationTestTeardown(t, allocationID)
 	})
@@ -994,6 +990,6 @@ func copyFileForWallet(t *test.SystemTest, cliConfigFilename, wallet string, par
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*20)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
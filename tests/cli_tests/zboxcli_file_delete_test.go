@@ -337,7 +337,7 @@ func TestFileDelete(testSetup *testing.T) {
 
 		balanceAfter, err := getBalanceZCN(t, configPath)
 		require.NoError(t, err)
-		require.Equal(t, balanceBefore-5.01, balanceAfter)
+		require.Equal(t, balanceBefore-zcn(5.01), balanceAfter)
 		balanceBefore = balanceAfter
 
 		output, err := deleteFile(t, escapedTestName(t), createParams(map[string]interface{}{
@@ -360,7 +360,7 @@ func TestFileDelete(testSetup *testing.T) {
 		balanceAfter, err = getBalanceZCN(t, configPath)
 		require.NoError(t, err)
 
-		require.InEpsilon(t, balanceBefore-0.01, balanceAfter, 0.01)
+		requireBalanceInEpsilon(t, balanceBefore-zcn(0.01), balanceAfter, 0.01)
 	})
 
 	t.Run("delete existing file in someone else's allocation should fail", func(t *test.SystemTest) {
@@ -494,6 +494,6 @@ func deleteFile(t *test.SystemTest, walletName, params string, retry bool) ([]st
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*20)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
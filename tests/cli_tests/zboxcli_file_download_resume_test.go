@@ -127,7 +127,7 @@ func startDownloadFileForWallet(t *test.SystemTest, wallet, cliConfigFilename, p
 	if retry {
 		return cliutils.StartCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.StartCommandWithoutRetry(cmd)
+		return cliutils.StartCommandWithoutRetry(t, cmd)
 	}
 }
 
@@ -1,7 +1,6 @@
 package cli_tests
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -16,6 +15,7 @@ import (
 	"golang.org/x/crypto/sha3"
 
 	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
 	"github.com/stretchr/testify/require"
 )
@@ -799,10 +799,7 @@ func TestDownload(testSetup *testing.T) {
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var stats map[string]climodel.FileStats
-
-		err = json.Unmarshal([]byte(output[0]), &stats)
-		require.Nil(t, err)
+		stats := cliparse.RequireJSON[map[string]climodel.FileStats](t, output)
 		var data climodel.FileStats
 		for _, data = range stats {
 			break
@@ -883,10 +880,7 @@ func TestDownload(testSetup *testing.T) {
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var stats map[string]climodel.FileStats
-
-		err = json.Unmarshal([]byte(output[0]), &stats)
-		require.Nil(t, err)
+		stats := cliparse.RequireJSON[map[string]climodel.FileStats](t, output)
 		var data climodel.FileStats
 		for _, data = range stats {
 			break
@@ -1321,14 +1315,14 @@ func downloadFileForWallet(t *test.SystemTest, wallet, cliConfigFilename, param
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
 func generateChecksum(t *test.SystemTest, filePath string) string {
 	t.Logf("Generating checksum for file [%v]...", filePath)
 
-	output, err := cliutils.RunCommandWithoutRetry("shasum -a 256 " + filePath)
+	output, err := cliutils.RunCommandWithoutRetry(t, "shasum -a 256 "+filePath)
 	require.Nil(t, err, "Checksum generation for file %v failed", filePath, strings.Join(output, "\n"))
 	require.Greater(t, len(output), 0)
 
@@ -402,6 +402,6 @@ func getFileMetaWithWallet(t *test.SystemTest, walletName, cliConfigFilename, pa
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
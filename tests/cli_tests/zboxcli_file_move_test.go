@@ -6,7 +6,6 @@ import (
 	"math"
 	"path/filepath"
 	"regexp"
-	"strconv"
 	"strings"
 	"sync"
 	"testing"
@@ -17,6 +16,8 @@ import (
 	climodel "github.com/0chain/system_test/internal/cli/model"
 
 	cliutils "github.com/0chain/system_test/internal/cli/util"
+	"github.com/0chain/system_test/internal/currency"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
 )
 
@@ -526,10 +527,10 @@ func TestFileMove(testSetup *testing.T) { // nolint:gocyclo // team preference i
 		createWallet(t)
 
 		// Lock 0.5 token for allocation
-		allocParams := createParams(map[string]interface{}{
+		allocParams := map[string]interface{}{
 			"lock": "5",
 			"size": 4 * MB,
-		})
+		}
 		output, err := createNewAllocation(t, configPath, allocParams)
 		require.Nil(t, err, "Failed to create new allocation", strings.Join(output, "\n"))
 
@@ -560,19 +561,16 @@ func TestFileMove(testSetup *testing.T) { // nolint:gocyclo // team preference i
 		// Get expected upload cost
 		output, _ = getUploadCostInUnit(t, configPath, allocationID, localpath)
 
-		expectedUploadCostInZCN, err := strconv.ParseFloat(strings.Fields(output[0])[0], 64)
-		require.Nil(t, err, "Cost couldn't be parsed to float", strings.Join(output, "\n"))
-
-		unit := strings.Fields(output[0])[1]
-		expectedUploadCostInZCN = unitToZCN(expectedUploadCostInZCN, unit)
+		expectedUploadCost, err := parseCost(output[0])
+		require.Nil(t, err, "Cost couldn't be parsed", strings.Join(output, "\n"))
 
 		// Expected cost is given in "per 720 hours", we need 1 hour
-		actualExpectedUploadCostInZCN := expectedUploadCostInZCN / 720
+		actualExpectedUploadCost := expectedUploadCost.Decimal(currency.SAS).Div(decimal.NewFromInt(720))
 
 		finalAllocation := getAllocation(t, allocationID)
 
 		actualCost := initialAllocation.WritePool - finalAllocation.WritePool
-		require.True(t, actualCost == 0 || intToZCN(actualCost) == (actualExpectedUploadCostInZCN))
+		require.True(t, actualCost == 0 || decimal.NewFromInt(actualCost).Equal(actualExpectedUploadCost))
 
 		createAllocationTestTeardown(t, allocationID)
 	})
@@ -753,7 +751,7 @@ func TestFileMove(testSetup *testing.T) { // nolint:gocyclo // team preference i
 	t.Run("move file from someone else's allocation should fail", func(t *test.SystemTest) {
 		nonAllocOwnerWallet := escapedTestName(t) + "_NON_OWNER"
 
-		createWalletForName(nonAllocOwnerWallet)
+		createWalletForName(t, nonAllocOwnerWallet)
 
 		allocSize := int64(2048)
 		fileSize := int64(256)
@@ -930,6 +928,6 @@ func moveFileWithWallet(t *test.SystemTest, wallet, cliConfigFilename string, pa
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*20)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
@@ -7,7 +7,6 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
-	"strconv"
 	"strings"
 	"sync"
 	"testing"
@@ -17,6 +16,8 @@ import (
 
 	climodel "github.com/0chain/system_test/internal/cli/model"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
+	"github.com/0chain/system_test/internal/currency"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
 )
 
@@ -499,10 +500,10 @@ func TestFileRename(testSetup *testing.T) { // nolint:gocyclo // team preference
 		createWallet(t)
 
 		// Lock 0.5 token for allocation
-		allocParams := createParams(map[string]interface{}{
+		allocParams := map[string]interface{}{
 			"lock": "5",
 			"size": 4 * MB,
-		})
+		}
 		output, err := createNewAllocation(t, configPath, allocParams)
 		require.Nil(t, err, "Failed to create new allocation", strings.Join(output, "\n"))
 
@@ -526,19 +527,16 @@ func TestFileRename(testSetup *testing.T) { // nolint:gocyclo // team preference
 		// Get expected upload cost
 		output, _ = getUploadCostInUnit(t, configPath, allocationID, localpath)
 
-		expectedUploadCostInZCN, err := strconv.ParseFloat(strings.Fields(output[0])[0], 64)
-		require.Nil(t, err, "Cost couldn't be parsed to float", strings.Join(output, "\n"))
-
-		unit := strings.Fields(output[0])[1]
-		expectedUploadCostInZCN = unitToZCN(expectedUploadCostInZCN, unit)
+		expectedUploadCost, err := parseCost(output[0])
+		require.Nil(t, err, "Cost couldn't be parsed", strings.Join(output, "\n"))
 
 		// Expected cost is given in "per 720 hours", we need 1 hour
-		actualExpectedUploadCostInZCN := expectedUploadCostInZCN / 720
+		actualExpectedUploadCost := expectedUploadCost.Decimal(currency.SAS).Div(decimal.NewFromInt(720))
 
 		finalAllocation := getAllocation(t, allocationID)
 
 		actualCost := initialAllocation.WritePool - finalAllocation.WritePool
-		require.True(t, actualCost == 0 || intToZCN(actualCost) == actualExpectedUploadCostInZCN)
+		require.True(t, actualCost == 0 || decimal.NewFromInt(actualCost).Equal(actualExpectedUploadCost))
 		createAllocationTestTeardown(t, allocationID)
 	})
 
@@ -583,7 +581,7 @@ func TestFileRename(testSetup *testing.T) { // nolint:gocyclo // team preference
 	t.Run("rename file from someone else's allocation should fail", func(t *test.SystemTest) {
 		nonAllocOwnerWallet := escapedTestName(t) + "_NON_OWNER"
 
-		createWalletForName(nonAllocOwnerWallet)
+		createWalletForName(t, nonAllocOwnerWallet)
 
 		allocSize := int64(2048)
 		fileSize := int64(256)
@@ -697,10 +695,10 @@ func TestFileRename(testSetup *testing.T) { // nolint:gocyclo // team preference
 		createWallet(t)
 
 		// Lock 0.5 token for allocation
-		allocParams := createParams(map[string]interface{}{
+		allocParams := map[string]interface{}{
 			"lock": "5",
 			"size": 4 * MB,
-		})
+		}
 		output, err := createNewAllocation(t, configPath, allocParams)
 		require.Nil(t, err, "Failed to create new allocation", strings.Join(output, "\n"))
 
@@ -723,20 +721,17 @@ func TestFileRename(testSetup *testing.T) { // nolint:gocyclo // team preference
 		// Get expected upload cost
 		output, _ = getUploadCostInUnit(t, configPath, allocationID, localpath)
 
-		expectedUploadCostInZCN, err := strconv.ParseFloat(strings.Fields(output[0])[0], 64)
-		require.Nil(t, err, "Cost couldn't be parsed to float", strings.Join(output, "\n"))
-
-		unit := strings.Fields(output[0])[1]
-		expectedUploadCostInZCN = unitToZCN(expectedUploadCostInZCN, unit)
+		expectedUploadCost, err := parseCost(output[0])
+		require.Nil(t, err, "Cost couldn't be parsed", strings.Join(output, "\n"))
 
 		// Expected cost is given in "per 720 hours", we need 1 hour
 		// Expected cost takes into account data+parity, so we divide by that
-		actualExpectedUploadCostInZCN := expectedUploadCostInZCN / ((2 + 2) * 720)
+		actualExpectedUploadCost := expectedUploadCost.Decimal(currency.SAS).Div(decimal.NewFromInt((2 + 2) * 720))
 
 		finalAllocation := getAllocation(t, allocationID)
 
 		actualCost := initialAllocation.WritePool - finalAllocation.WritePool
-		require.True(t, actualCost == 0 || intToZCN(actualCost) == actualExpectedUploadCostInZCN)
+		require.True(t, actualCost == 0 || decimal.NewFromInt(actualCost).Equal(actualExpectedUploadCost))
 		createAllocationTestTeardown(t, allocationID)
 	})
 
@@ -1,7 +1,6 @@
 package cli_tests
 
 import (
-	"encoding/json"
 	"fmt"
 	"math"
 	"os"
@@ -14,6 +13,7 @@ import (
 	"github.com/0chain/system_test/internal/api/util/test"
 
 	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/sha3"
@@ -50,10 +50,7 @@ func TestFileStats(testSetup *testing.T) {
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var stats map[string]climodel.FileStats
-
-		err = json.Unmarshal([]byte(output[0]), &stats)
-		require.Nil(t, err)
+		stats := cliparse.RequireJSON[map[string]climodel.FileStats](t, output)
 
 		for _, data := range stats {
 			require.Equal(t, fname, data.Name)
@@ -90,10 +87,7 @@ func TestFileStats(testSetup *testing.T) {
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var stats map[string]climodel.FileStats
-
-		err = json.Unmarshal([]byte(output[0]), &stats)
-		require.Nil(t, err)
+		stats := cliparse.RequireJSON[map[string]climodel.FileStats](t, output)
 
 		for _, data := range stats {
 			require.Equal(t, fname, data.Name)
@@ -130,10 +124,7 @@ func TestFileStats(testSetup *testing.T) {
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var stats map[string]climodel.FileStats
-
-		err = json.Unmarshal([]byte(output[0]), &stats)
-		require.Nil(t, err)
+		stats := cliparse.RequireJSON[map[string]climodel.FileStats](t, output)
 
 		for _, data := range stats {
 			require.Equal(t, fname, data.Name)
@@ -166,10 +157,7 @@ func TestFileStats(testSetup *testing.T) {
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var stats map[string]climodel.FileStats
-
-		err = json.Unmarshal([]byte(output[0]), &stats)
-		require.Nil(t, err)
+		stats := cliparse.RequireJSON[map[string]climodel.FileStats](t, output)
 
 		for _, data := range stats {
 			require.Equal(t, "", data.Name)
@@ -202,10 +190,7 @@ func TestFileStats(testSetup *testing.T) {
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var stats map[string]climodel.FileStats
-
-		err = json.Unmarshal([]byte(output[0]), &stats)
-		require.Nil(t, err)
+		stats := cliparse.RequireJSON[map[string]climodel.FileStats](t, output)
 
 		for _, data := range stats {
 			require.Equal(t, "", data.Name)
@@ -245,9 +230,7 @@ func TestFileStats(testSetup *testing.T) {
 			}), true)
 			require.Nil(t, err, strings.Join(output, "\n"))
 			require.Len(t, output, 1)
-			var stats map[string]climodel.FileStats
-			err = json.Unmarshal([]byte(output[0]), &stats)
-			require.Nil(t, err)
+			stats := cliparse.RequireJSON[map[string]climodel.FileStats](t, output)
 
 			for _, data := range stats {
 				require.Equal(t, fname, data.Name)
@@ -277,9 +260,7 @@ func TestFileStats(testSetup *testing.T) {
 
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.Len(t, output, 1)
-		var stats map[string]climodel.FileStats
-		err = json.Unmarshal([]byte(output[0]), &stats)
-		require.Nil(t, err)
+		stats := cliparse.RequireJSON[map[string]climodel.FileStats](t, output)
 		require.Len(t, stats, 0)
 	})
 
@@ -339,10 +320,7 @@ func TestFileStats(testSetup *testing.T) {
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var stats map[string]climodel.FileStats
-
-		err = json.Unmarshal([]byte(output[0]), &stats)
-		require.Nil(t, err)
+		stats := cliparse.RequireJSON[map[string]climodel.FileStats](t, output)
 
 		for _, data := range stats {
 			require.Equal(t, fname, data.Name)
@@ -375,8 +353,7 @@ func TestFileStats(testSetup *testing.T) {
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		err = json.Unmarshal([]byte(output[0]), &stats)
-		require.Nil(t, err)
+		stats = cliparse.RequireJSON[map[string]climodel.FileStats](t, output)
 
 		for _, data := range stats {
 			require.Equal(t, fname, data.Name)
@@ -419,10 +396,7 @@ func TestFileStats(testSetup *testing.T) {
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var stats map[string]climodel.FileStats
-
-		err = json.Unmarshal([]byte(output[0]), &stats)
-		require.Nil(t, err)
+		stats := cliparse.RequireJSON[map[string]climodel.FileStats](t, output)
 
 		for _, data := range stats {
 			require.Equal(t, fname, data.Name)
@@ -463,8 +437,7 @@ func TestFileStats(testSetup *testing.T) {
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		err = json.Unmarshal([]byte(output[0]), &stats)
-		require.Nil(t, err)
+		stats = cliparse.RequireJSON[map[string]climodel.FileStats](t, output)
 
 		var skippedBlobber int
 		for _, data := range stats {
@@ -499,6 +472,6 @@ func getFileStats(t *test.SystemTest, cliConfigFilename, param string, retry boo
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
@@ -3,12 +3,10 @@ package cli_tests
 import (
 	"encoding/base64"
 	"encoding/json"
-	"fmt"
 	"math"
 	"os"
 	"path/filepath"
 	"regexp"
-	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -18,6 +16,8 @@ import (
 	"github.com/0chain/system_test/internal/api/util/test"
 
 	cliutils "github.com/0chain/system_test/internal/cli/util"
+	"github.com/0chain/system_test/internal/currency"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
 )
 
@@ -72,10 +72,10 @@ func TestFileUpdate(testSetup *testing.T) {
 		createWallet(t)
 
 		// Lock 0.5 token for allocation
-		allocParams := createParams(map[string]interface{}{
+		allocParams := map[string]interface{}{
 			"lock": "5",
 			"size": 4 * MB,
-		})
+		}
 		output, err := createNewAllocation(t, configPath, allocParams)
 		require.Nil(t, err, "Failed to create new allocation", strings.Join(output, "\n"))
 
@@ -99,20 +99,17 @@ func TestFileUpdate(testSetup *testing.T) {
 		// Get expected upload cost
 		output, _ = getUploadCostInUnit(t, configPath, allocationID, localpath)
 
-		expectedUploadCostInZCN, err := strconv.ParseFloat(strings.Fields(output[0])[0], 64)
-		require.Nil(t, err, "Cost couldn't be parsed to float", strings.Join(output, "\n"))
-
-		unit := strings.Fields(output[0])[1]
-		expectedUploadCostInZCN = unitToZCN(expectedUploadCostInZCN, unit)
+		expectedUploadCost, err := parseCost(output[0])
+		require.Nil(t, err, "Cost couldn't be parsed", strings.Join(output, "\n"))
 
 		// Expected cost is given in "per 720 hours", we need 1 hour
 		// Expected cost takes into account data+parity, so we divide by that
-		actualExpectedUploadCostInZCN := expectedUploadCostInZCN / ((2 + 2) * 720)
+		actualExpectedUploadCost := expectedUploadCost.Decimal(currency.SAS).Div(decimal.NewFromInt((2 + 2) * 720))
 
 		finalAllocation := getAllocation(t, allocationID)
 
 		actualCost := initialAllocation.WritePool - finalAllocation.WritePool
-		require.True(t, actualCost == 0 || intToZCN(actualCost) == actualExpectedUploadCostInZCN)
+		require.True(t, actualCost == 0 || decimal.NewFromInt(actualCost).Equal(actualExpectedUploadCost))
 
 		createAllocationTestTeardown(t, allocationID)
 	})
@@ -464,10 +461,10 @@ func TestFileUpdate(testSetup *testing.T) {
 		createWallet(t)
 
 		// Lock 0.5 token for allocation
-		allocParams := createParams(map[string]interface{}{
+		allocParams := map[string]interface{}{
 			"lock": "1",
 			"size": 10 * MB,
-		})
+		}
 		output, err := createNewAllocation(t, configPath, allocParams)
 		require.Nil(t, err, "Failed to create new allocation", strings.Join(output, "\n"))
 
@@ -480,19 +477,17 @@ func TestFileUpdate(testSetup *testing.T) {
 		// Get expected upload cost for 0.5 MB
 		localpath := uploadRandomlyGeneratedFile(t, allocationID, "/", fileSize)
 		output, _ = getUploadCostInUnit(t, configPath, allocationID, localpath)
-		expectedUploadCostInZCN, err := strconv.ParseFloat(strings.Fields(output[0])[0], 64)
-		require.Nil(t, err, "Cost couldn't be parsed to float", strings.Join(output, "\n"))
-		unit := strings.Fields(output[0])[1]
-		expectedUploadCostInZCN = unitToZCN(expectedUploadCostInZCN, unit)
+		expectedUploadCost, err := parseCost(output[0])
+		require.Nil(t, err, "Cost couldn't be parsed", strings.Join(output, "\n"))
 
-		fmt.Println("expectedUploadCostInZCN", expectedUploadCostInZCN)
+		t.Logf("Upload cost: %v", expectedUploadCost)
 
 		// Wait for write pool balance to be deduced for initial 0.5 MB
 		cliutils.Wait(t, 20*time.Second)
 
 		initialAllocation := getAllocation(t, allocationID)
 
-		require.InEpsilon(t, expectedUploadCostInZCN, intToZCN(initialAllocation.MovedToChallenge), 0.05)
+		requireCostInEpsilon(t, expectedUploadCost, initialAllocation.MovedToChallenge, 0.05)
 
 		remotepath := "/" + filepath.Base(localpath)
 		updateFileWithRandomlyGeneratedData(t, allocationID, remotepath, int64(1*MB))
@@ -502,7 +497,7 @@ func TestFileUpdate(testSetup *testing.T) {
 
 		finalAllocation := getAllocation(t, allocationID)
 
-		require.InEpsilon(t, expectedUploadCostInZCN*2, intToZCN(finalAllocation.MovedToChallenge), 0.2)
+		requireCostInEpsilon(t, expectedUploadCost*2, finalAllocation.MovedToChallenge, 0.2)
 
 		createAllocationTestTeardown(t, allocationID)
 	})
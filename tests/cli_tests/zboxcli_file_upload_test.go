@@ -3,27 +3,25 @@ package cli_tests
 import (
 	"context"
 	"encoding/base64"
-	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path"
 	"path/filepath"
 	"regexp"
-	"strconv"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
-	"github.com/0chain/system_test/tests/tokenomics_tests/utils"
-
 	"github.com/0chain/system_test/internal/api/util/test"
 
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	"github.com/stretchr/testify/require"
 
 	climodel "github.com/0chain/system_test/internal/cli/model"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
+	"github.com/0chain/system_test/internal/currency"
 )
 
 func TestUpload(testSetup *testing.T) {
@@ -229,9 +227,7 @@ func TestUpload(testSetup *testing.T) {
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var listResults []climodel.ListFileResult
-		err = json.Unmarshal([]byte(output[0]), &listResults)
-		require.Nil(t, err, "Decoding list results failed\n", strings.Join(output, "\n"))
+		listResults := cliparse.RequireJSON[[]climodel.ListFileResult](t, output)
 
 		require.Len(t, listResults, 1)
 		result := listResults[0]
@@ -305,10 +301,10 @@ func TestUpload(testSetup *testing.T) {
 		t.Skip("Need improvements in performance")
 		for _, blobber := range blobbersList {
 			// stake tokens
-			_, err := stakeTokens(t, configPath, utils.CreateParams(map[string]interface{}{
+			_, err := stakeTokens(t, configPath, map[string]interface{}{
 				"blobber_id": blobber.Id,
 				"tokens":     10,
-			}), true)
+			}, true)
 			require.Nil(t, err, "Error staking tokens")
 		}
 
@@ -742,10 +738,10 @@ func TestUpload(testSetup *testing.T) {
 	t.RunWithTimeout("Tokens should move from write pool balance to challenge pool acc. to expected upload cost", 10*time.Minute, func(t *test.SystemTest) {
 		createWallet(t)
 
-		allocParam := createParams(map[string]interface{}{
+		allocParam := map[string]interface{}{
 			"lock": 0.8,
 			"size": 10485760,
-		})
+		}
 		output, err := createNewAllocation(t, configPath, allocParam)
 		require.Nil(t, err, "Failed to create new allocation", strings.Join(output, "\n"))
 
@@ -757,15 +753,13 @@ func TestUpload(testSetup *testing.T) {
 
 		// Write pool balance should increment to 1
 		initialAllocation := getAllocation(t, allocationID)
-		require.Equal(t, 0.8, intToZCN(initialAllocation.WritePool))
+		requireBalance(t, "0.8 ZCN", initialAllocation.WritePool)
 
 		// Get Challenge-Pool info after upload
 		output, err = challengePoolInfo(t, configPath, allocationID)
 		require.Nil(t, err, "Could not fetch challenge pool", strings.Join(output, "\n"))
 
-		challengePool := climodel.ChallengePoolInfo{}
-		err = json.Unmarshal([]byte(output[0]), &challengePool)
-		require.Nil(t, err, "Error unmarshalling challenge pool info", strings.Join(output, "\n"))
+		challengePool := cliparse.RequireJSON[climodel.ChallengePoolInfo](t, output)
 
 		filename := generateRandomTestFileName(t)
 		err = createFileWithSize(filename, 1024*1024*0.5)
@@ -779,10 +773,8 @@ func TestUpload(testSetup *testing.T) {
 		})
 
 		output, _ = getUploadCostInUnit(t, configPath, allocationID, filename)
-		expectedUploadCostInZCN, err := strconv.ParseFloat(strings.Fields(output[0])[0], 64)
-		require.Nil(t, err, "Cost couldn't be parsed to float", strings.Join(output, "\n"))
-		unit := strings.Fields(output[0])[1]
-		expectedUploadCostInZCN = unitToZCN(expectedUploadCostInZCN, unit)
+		expectedUploadCost, err := parseCost(output[0])
+		require.Nil(t, err, "Cost couldn't be parsed", strings.Join(output, "\n"))
 
 		cliutils.Wait(t, 30*time.Second)
 
@@ -792,9 +784,7 @@ func TestUpload(testSetup *testing.T) {
 		output, err = challengePoolInfo(t, configPath, allocationID)
 		require.Nil(t, err, "Could not fetch challenge pool", strings.Join(output, "\n"))
 
-		challengePool = climodel.ChallengePoolInfo{}
-		err = json.Unmarshal([]byte(output[0]), &challengePool)
-		require.Nil(t, err, "Error unmarshalling challenge pool info", strings.Join(output, "\n"))
+		challengePool = cliparse.RequireJSON[climodel.ChallengePoolInfo](t, output)
 
 		require.Regexp(t, regexp.MustCompile(fmt.Sprintf("([a-f0-9]{64}):challengepool:%s", allocationID)), challengePool.Id)
 		require.IsType(t, int64(1), challengePool.StartTime)
@@ -802,10 +792,10 @@ func TestUpload(testSetup *testing.T) {
 		require.IsType(t, int64(1), challengePool.Balance)
 		require.False(t, challengePool.Finalized)
 
-		totalChangeInWritePool := intToZCN(initialAllocation.WritePool - finalAllocation.WritePool)
+		totalChangeInWritePool := initialAllocation.WritePool - finalAllocation.WritePool
 
-		require.InEpsilon(t, expectedUploadCostInZCN, totalChangeInWritePool, 0.05, "expected write pool balance to decrease by [%v] but has actually decreased by [%v]", expectedUploadCostInZCN, totalChangeInWritePool)
-		require.InEpsilon(t, totalChangeInWritePool, intToZCN(challengePool.Balance), 0.05, "expected challenge pool balance to match deducted amount from write pool [%v] but balance was actually [%v]", totalChangeInWritePool, intToZCN(challengePool.Balance))
+		requireCostInEpsilon(t, expectedUploadCost, totalChangeInWritePool, 0.05, "expected write pool balance to decrease by [%v] but has actually decreased by [%v]", expectedUploadCost, currency.Coin(totalChangeInWritePool))
+		requireCostInEpsilon(t, currency.Coin(totalChangeInWritePool), challengePool.Balance, 0.05, "expected challenge pool balance to match deducted amount from write pool [%v] but balance was actually [%v]", currency.Coin(totalChangeInWritePool), currency.Coin(challengePool.Balance))
 	})
 
 	t.RunSequentiallyWithTimeout("stream tests for different formats", 20*time.Minute, func(t *test.SystemTest) {
@@ -984,32 +974,23 @@ func uploadFile(t *test.SystemTest, cliConfigFilename string, param map[string]i
 func uploadFileForWallet(t *test.SystemTest, wallet, cliConfigFilename string, param map[string]interface{}, retry bool) ([]string, error) {
 	t.Logf("Uploading file...")
 
-	p := createParams(param)
-	cmd := fmt.Sprintf(
-		"./zbox upload %s --silent --wallet %s_wallet.json --configDir ./config --config %s",
-		p,
-		wallet,
-		cliConfigFilename,
-	)
-
+	cmd := uploadCommand(wallet, cliConfigFilename, param)
 	if retry {
-		return cliutils.RunCommand(t, cmd, 3, time.Second*40)
+		return cmd.Run(t, 3, time.Second*40)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cmd.RunWithoutRetry(t)
 	}
 }
 
 func uploadFileWithoutRetry(t *test.SystemTest, cliConfigFilename string, param map[string]interface{}) ([]string, error) {
 	t.Logf("Uploading file...")
-	p := createParams(param)
-	cmd := fmt.Sprintf(
-		"./zbox upload %s --silent --wallet %s --configDir ./config --config %s",
-		p,
-		escapedTestName(t)+"_wallet.json",
-		cliConfigFilename,
-	)
-
-	return cliutils.RunCommandWithoutRetry(cmd)
+	return uploadCommand(escapedTestName(t), cliConfigFilename, param).RunWithoutRetry(t)
+}
+
+func uploadCommand(wallet, cliConfigFilename string, param map[string]interface{}) cliutils.Command {
+	var flags cliutils.ZboxUpload
+	cliutils.DecodeFlags(param, &flags)
+	return cliutils.Zbox("upload", flags, zboxFlags(wallet, cliConfigFilename))
 }
 
 func generateFileAndUpload(t *test.SystemTest, allocationID, remotepath string, size int64) string {
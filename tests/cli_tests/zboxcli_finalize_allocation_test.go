@@ -50,7 +50,7 @@ func TestFinalizeAllocation(testSetup *testing.T) {
 			configPath,
 		)
 
-		output, err := cliutils.RunCommandWithoutRetry(cmd)
+		output, err := cliutils.RunCommandWithoutRetry(t, cmd)
 		require.Error(t, err, "expected error finalizing allocation", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 		require.Equal(t, "Error: allocation flag is missing", output[len(output)-1])
@@ -69,6 +69,6 @@ func finalizeAllocation(t *test.SystemTest, cliConfigFilename, allocationID stri
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
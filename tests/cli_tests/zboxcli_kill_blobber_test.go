@@ -1,7 +1,6 @@
 package cli_tests
 
 import (
-	"encoding/json"
 	"fmt"
 	"log"
 	"regexp"
@@ -11,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
 
 	"github.com/0chain/system_test/internal/cli/model"
@@ -46,17 +46,17 @@ func TestKillBlobber(testSetup *testing.T) {
 
 		t.Log("blobberToKill", blobberToKill)
 
-		_, err := stakeTokens(t, configPath, createParams(map[string]interface{}{"blobber_id": blobberToKill, "tokens": 100}), true)
+		_, err := stakeTokens(t, configPath, map[string]interface{}{"blobber_id": blobberToKill, "tokens": 100}, true)
 		require.NoErrorf(t, err, "error unstaking tokens from blobber %s", blobberToKill)
 
 		time.Sleep(2 * time.Minute)
 
-		output, err := createNewAllocation(t, configPath, createParams(map[string]interface{}{
+		output, err := createNewAllocation(t, configPath, map[string]interface{}{
 			"data":   strconv.Itoa(dataShards),
 			"parity": strconv.Itoa(parityShards),
 			"lock":   5.0,
 			"size":   "10000",
-		}))
+		})
 		require.NoError(t, err, "Failed to create new allocation", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 		require.Regexp(t, regexp.MustCompile("Allocation created: ([a-f0-9]{64})"),
@@ -105,7 +105,7 @@ func TestKillBlobber(testSetup *testing.T) {
 		balanceAfter := getBalanceFromSharders(t, stakingWalletModel.ClientID) + 1500000000 // Txn fee
 		require.GreaterOrEqual(t, balanceAfter, balanceBefore, "should have collected rewards")
 
-		output, err = unstakeTokens(t, configPath, createParams(map[string]interface{}{"blobber_id": blobberToKill}), true)
+		output, err = unstakeTokens(t, configPath, cliutils.ZboxStakeUnlock{BlobberID: blobberToKill}, true)
 		require.NoError(t, err, "should be able to unstake tokens from a killed blobber")
 		t.Log(strings.Join(output, "\n"))
 
@@ -124,12 +124,12 @@ func TestKillBlobber(testSetup *testing.T) {
 		balanceAfter = getBalanceFromSharders(t, blobberDelegateWallet.ClientID) + 1500000000 // Txn fee
 		require.Greater(t, balanceAfter, balanceBefore, "should have collected rewards")
 
-		output, err = createNewAllocation(t, configPath, createParams(map[string]interface{}{
+		output, err = createNewAllocation(t, configPath, map[string]interface{}{
 			"data":   strconv.Itoa(dataShards),
 			"parity": strconv.Itoa(parityShards),
 			"lock":   4.0,
 			"size":   "10000",
-		}))
+		})
 		require.Error(t, err, "should fail to create allocation")
 		require.Len(t, output, 1)
 		require.True(t, strings.Contains(output[0], "not enough blobbers to honor the allocation"),
@@ -175,17 +175,17 @@ func TestKillBlobber(testSetup *testing.T) {
 
 		t.Log("blobberToShutdown", blobberToShutdown)
 
-		_, err := stakeTokens(t, configPath, createParams(map[string]interface{}{"blobber_id": blobberToShutdown, "tokens": 100}), true)
+		_, err := stakeTokens(t, configPath, map[string]interface{}{"blobber_id": blobberToShutdown, "tokens": 100}, true)
 		require.NoErrorf(t, err, "error unstaking tokens from blobber %s", blobberToShutdown)
 
 		time.Sleep(2 * time.Minute)
 
-		output, err := createNewAllocation(t, configPath, createParams(map[string]interface{}{
+		output, err := createNewAllocation(t, configPath, map[string]interface{}{
 			"data":   strconv.Itoa(dataShards),
 			"parity": strconv.Itoa(parityShards),
 			"lock":   5.0,
 			"size":   "10000",
-		}))
+		})
 		require.NoError(t, err, "Failed to create new allocation", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 		require.Regexp(t, regexp.MustCompile("Allocation created: ([a-f0-9]{64})"),
@@ -197,7 +197,7 @@ func TestKillBlobber(testSetup *testing.T) {
 		_, err = executeFaucetWithTokens(t, configPath, 100.0)
 		require.NoError(t, err, "faucet execution failed", strings.Join(output, "\n"))
 
-		_, err = stakeTokens(t, configPath, createParams(map[string]interface{}{"blobber_id": blobberToShutdown, "tokens": 100}), true)
+		_, err = stakeTokens(t, configPath, map[string]interface{}{"blobber_id": blobberToShutdown, "tokens": 100}, true)
 		require.NoErrorf(t, err, "error unstaking tokens from blobber %s", blobberToShutdown)
 
 		spBefore := getStakePoolInfo(t, blobberToShutdown)
@@ -240,7 +240,7 @@ func TestKillBlobber(testSetup *testing.T) {
 		balanceAfter := getBalanceFromSharders(t, stakingWalletModel.ClientID) + 1500000000 // Txn fee
 		require.GreaterOrEqual(t, balanceAfter, balanceBefore, "should have collected rewards")
 
-		output, err = unstakeTokens(t, configPath, createParams(map[string]interface{}{"blobber_id": blobberToShutdown}), true)
+		output, err = unstakeTokens(t, configPath, cliutils.ZboxStakeUnlock{BlobberID: blobberToShutdown}, true)
 		require.NoError(t, err, "should be able to unstake tokens from a shutdowned blobber")
 		t.Log(strings.Join(output, "\n"))
 
@@ -259,12 +259,12 @@ func TestKillBlobber(testSetup *testing.T) {
 		balanceAfter = getBalanceFromSharders(t, blobberDelegateWallet.ClientID) + 1500000000 // Txn fee
 		require.Greater(t, balanceAfter, balanceBefore, "should have collected rewards")
 
-		output, err = createNewAllocation(t, configPath, createParams(map[string]interface{}{
+		output, err = createNewAllocation(t, configPath, map[string]interface{}{
 			"data":   strconv.Itoa(dataShards),
 			"parity": strconv.Itoa(parityShards),
 			"lock":   4.0,
 			"size":   "10000",
-		}))
+		})
 		require.Error(t, err, "should fail to create allocation")
 		require.Len(t, output, 1)
 		require.True(t, strings.Contains(output[0], "not enough blobbers to honor the allocation"),
@@ -299,7 +299,7 @@ func killBlobber(t *test.SystemTest, wallet, cliConfigFilename, params string, r
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
@@ -308,8 +308,7 @@ func getBlobbers(t *test.SystemTest) []model.BlobberDetails {
 	output, err := listBlobbers(t, configPath, "--json")
 	require.NoError(t, err, "Error listing blobbers", strings.Join(output, "\n"))
 	require.True(t, len(output) > 0, "no output to ls-blobbers")
-	err = json.Unmarshal([]byte(output[len(output)-1]), &blobbers)
-	require.NoError(t, err, "Error unmarshalling blobber list", strings.Join(output, "\n"))
+	blobbers = cliparse.RequireJSON[[]model.BlobberDetails](t, output)
 	require.True(t, len(blobbers) > 0, "No blobbers found in blobber list")
 	return blobbers
 }
@@ -319,25 +318,18 @@ func getBlobber(t *test.SystemTest, id string) model.BlobberDetails {
 	require.NoError(t, err, strings.Join(output, "\n"))
 	require.Len(t, output, 1)
 
-	var blobberInfo model.BlobberDetails
-	err = json.Unmarshal([]byte(output[0]), &blobberInfo)
-	require.Nil(t, err, strings.Join(output, "\n"))
+	blobberInfo := cliparse.RequireJSON[model.BlobberDetails](t, output)
 
 	return blobberInfo
 }
 
 func getStakePoolInfo(t *test.SystemTest, blobberId string) model.StakePoolInfo {
 	// Use sp-info to check the staked tokens in blobber's stake pool
-	output, err := stakePoolInfo(t, configPath, createParams(map[string]interface{}{
-		"blobber_id": blobberId,
-		"json":       "",
-	}))
+	output, err := stakePoolInfo(t, configPath, cliutils.ZboxStakePoolInfo{BlobberID: blobberId, JSON: true})
 	require.Nil(t, err, "Error fetching stake pool info", strings.Join(output, "\n"))
 	require.Len(t, output, 1)
 
-	stakePool := model.StakePoolInfo{}
-	err = json.Unmarshal([]byte(output[0]), &stakePool)
-	require.Nil(t, err, "Error unmarshalling stake pool info", strings.Join(output, "\n"))
+	stakePool := cliparse.RequireJSON[model.StakePoolInfo](t, output)
 	require.NotEmpty(t, stakePool)
 
 	sort.Slice(stakePool.Delegate, func(i, j int) bool {
@@ -357,7 +349,7 @@ func shutdownBlobber(t *test.SystemTest, wallet, cliConfigFilename, params strin
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
@@ -372,6 +364,6 @@ func collectRewardsForWallet(t *test.SystemTest, cliConfigFilename, params, wall
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
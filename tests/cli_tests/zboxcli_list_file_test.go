@@ -14,6 +14,7 @@ import (
 
 	"github.com/0chain/system_test/internal/api/util/test"
 
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/sha3"
 
@@ -580,10 +581,7 @@ func TestListFileSystem(testSetup *testing.T) {
 		require.Nil(t, err, "list files failed", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var listResults []climodel.ListFileResult
-
-		err = json.Unmarshal([]byte(output[0]), &listResults)
-		require.Nil(t, err, "list files failed", strings.Join(output, "\n"))
+		listResults := cliparse.RequireJSON[[]climodel.ListFileResult](t, output)
 		require.Empty(t, listResults)
 	})
 }
@@ -643,7 +641,7 @@ func listFilesInAllocationForWallet(t *test.SystemTest, wallet, cliConfigFilenam
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
@@ -659,6 +657,6 @@ func listAllFilesInAllocation(t *test.SystemTest, cliConfigFilename, param strin
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
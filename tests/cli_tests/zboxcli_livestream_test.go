@@ -2,7 +2,6 @@ package cli_tests
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io/fs"
@@ -21,6 +20,7 @@ import (
 	"github.com/0chain/system_test/internal/api/util/test"
 
 	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
 	"github.com/stretchr/testify/require"
 )
@@ -42,10 +42,10 @@ func TestStreamUploadDownload(testSetup *testing.T) {
 
 		createWallet(t)
 
-		output, err := createNewAllocation(t, configPath, createParams(map[string]interface{}{
+		output, err := createNewAllocation(t, configPath, map[string]interface{}{
 			"lock": 5,
 			"size": "10000",
-		}))
+		})
 		require.Nil(t, err, "error creating allocation", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 		require.Regexp(t, regexp.MustCompile("Allocation created: ([a-f0-9]{64})"), output[0], "Allocation creation output did not match expected")
@@ -94,9 +94,7 @@ func TestStreamUploadDownload(testSetup *testing.T) {
 		require.Nil(t, err, "error listing files in remotepath")
 		require.Len(t, output, 1, "listing files should return output")
 
-		files := []climodel.ListFileResult{}
-		err = json.Unmarshal([]byte(output[0]), &files)
-		require.Nil(t, err, "error unmarshalling the response from list files")
+		files := cliparse.RequireJSON[[]climodel.ListFileResult](t, output)
 
 		// remotepath must have numbered .ts files
 		for _, file := range files {
@@ -113,10 +111,10 @@ func TestStreamUploadDownload(testSetup *testing.T) {
 
 		createWallet(t)
 
-		output, err := createNewAllocation(t, configPath, createParams(map[string]interface{}{
+		output, err := createNewAllocation(t, configPath, map[string]interface{}{
 			"lock": 5,
 			"size": "10000",
-		}))
+		})
 		require.Nil(t, err, "error creating allocation", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 		require.Regexp(t, regexp.MustCompile("Allocation created: ([a-f0-9]{64})"), output[0], "Allocation creation output did not match expected")
@@ -166,9 +164,7 @@ func TestStreamUploadDownload(testSetup *testing.T) {
 		require.Nil(t, err, "error listing files in remotepath")
 		require.Len(t, output, 1, "listing files should return output")
 
-		files := []climodel.ListFileResult{}
-		err = json.Unmarshal([]byte(output[0]), &files)
-		require.Nil(t, err, "error unmarshalling the response from list files")
+		files := cliparse.RequireJSON[[]climodel.ListFileResult](t, output)
 
 		for _, file := range files {
 			require.Regexp(t, regexp.MustCompile(`up(\d+).ts`), file.Name, "files created locally must be found uploaded to allocation")
@@ -184,10 +180,10 @@ func TestStreamUploadDownload(testSetup *testing.T) {
 
 		createWallet(t)
 
-		output, err := createNewAllocation(t, configPath, createParams(map[string]interface{}{
+		output, err := createNewAllocation(t, configPath, map[string]interface{}{
 			"lock": 5,
 			"size": "10000",
-		}))
+		})
 		require.Nil(t, err, "error creating allocation", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 		require.Regexp(t, regexp.MustCompile("Allocation created: ([a-f0-9]{64})"), output[0], "Allocation creation output did not match expected")
@@ -237,9 +233,7 @@ func TestStreamUploadDownload(testSetup *testing.T) {
 		require.Nil(t, err, "error listing files in remotepath")
 		require.Len(t, output, 1, "listing files should return output")
 
-		files := []climodel.ListFileResult{}
-		err = json.Unmarshal([]byte(output[0]), &files)
-		require.Nil(t, err, "error unmarshalling the response from list files")
+		files := cliparse.RequireJSON[[]climodel.ListFileResult](t, output)
 
 		for _, file := range files {
 			require.Regexp(t, regexp.MustCompile(`up(\d+).ts`), file.Name, "files created locally must be found uploaded to allocation")
@@ -253,10 +247,10 @@ func TestStreamUploadDownload(testSetup *testing.T) {
 
 		createWallet(t)
 
-		output, err := createNewAllocation(t, configPath, createParams(map[string]interface{}{
+		output, err := createNewAllocation(t, configPath, map[string]interface{}{
 			"lock": 5,
 			"size": "10000",
-		}))
+		})
 		require.Nil(t, err, "error creating allocation", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 		require.Regexp(t, regexp.MustCompile("Allocation created: ([a-f0-9]{64})"), output[0], "Allocation creation output did not match expected")
@@ -304,9 +298,7 @@ func TestStreamUploadDownload(testSetup *testing.T) {
 		require.Nil(t, err, "error listing files in remotepath")
 		require.Len(t, output, 1, "listing files should return output")
 
-		files := []climodel.ListFileResult{}
-		err = json.Unmarshal([]byte(output[0]), &files)
-		require.Nil(t, err, "error unmarshalling the response from list files")
+		files := cliparse.RequireJSON[[]climodel.ListFileResult](t, output)
 
 		for _, file := range files {
 			require.Regexp(t, regexp.MustCompile(`up(\d+).ts`), file.Name, "files created locally must be found uploaded to allocation")
@@ -318,10 +310,10 @@ func TestStreamUploadDownload(testSetup *testing.T) {
 
 		createWallet(t)
 
-		output, err := createNewAllocation(t, configPath, createParams(map[string]interface{}{
+		output, err := createNewAllocation(t, configPath, map[string]interface{}{
 			"lock": 5,
 			"size": "10000",
-		}))
+		})
 		require.Nil(t, err, "error creating allocation", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 		require.Regexp(t, regexp.MustCompile("Allocation created: ([a-f0-9]{64})"), output[0], "Allocation creation output did not match expected")
@@ -370,9 +362,7 @@ func TestStreamUploadDownload(testSetup *testing.T) {
 		require.Nil(t, err, "error listing files in remotepath")
 		require.Len(t, output, 1, "listing files should return output")
 
-		files := []climodel.ListFileResult{}
-		err = json.Unmarshal([]byte(output[0]), &files)
-		require.Nil(t, err, "error unmarshalling the response from list files")
+		files := cliparse.RequireJSON[[]climodel.ListFileResult](t, output)
 
 		for _, file := range files {
 			require.Regexp(t, regexp.MustCompile(`up(\d+).ts`), file.Name, "files created locally must be found uploaded to allocation")
@@ -384,10 +374,10 @@ func TestStreamUploadDownload(testSetup *testing.T) {
 
 		createWallet(t)
 
-		output, err := createNewAllocation(t, configPath, createParams(map[string]interface{}{
+		output, err := createNewAllocation(t, configPath, map[string]interface{}{
 			"lock": 5,
 			"size": "10000",
-		}))
+		})
 		require.Nil(t, err, "error creating allocation", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 		require.Regexp(t, regexp.MustCompile("Allocation created: ([a-f0-9]{64})"), output[0], "Allocation creation output did not match expected")
@@ -436,9 +426,7 @@ func TestStreamUploadDownload(testSetup *testing.T) {
 		require.Nil(t, err, "error listing files in remotepath")
 		require.Len(t, output, 1, "listing files should return output")
 
-		files := []climodel.ListFileResult{}
-		err = json.Unmarshal([]byte(output[0]), &files)
-		require.Nil(t, err, "error unmarshalling the response from list files")
+		files := cliparse.RequireJSON[[]climodel.ListFileResult](t, output)
 
 		for _, file := range files {
 			require.Regexp(t, regexp.MustCompile(`up(\d+).ts`), file.Name, "files created locally must be found uploaded to allocation")
@@ -1,7 +1,6 @@
 package cli_tests
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -12,6 +11,7 @@ import (
 	"github.com/0chain/system_test/internal/api/util/test"
 
 	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
 
 	"github.com/stretchr/testify/require"
@@ -19,6 +19,7 @@ import (
 
 func TestRecentlyAddedRefs(testSetup *testing.T) {
 	t := test.NewSystemTest(testSetup)
+	t.RequireCapability("zbox recent-refs")
 	t.SetSmokeTests("Recently Added Refs Should be listed")
 
 	t.Parallel()
@@ -69,10 +70,7 @@ func TestRecentlyAddedRefs(testSetup *testing.T) {
 		require.Nil(t, err, "List recent files failed", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		result := climodel.RecentlyAddedRefResult{}
-
-		err = json.Unmarshal([]byte(output[len(output)-1]), &result)
-		require.Nil(t, err)
+		result := cliparse.RequireJSON[climodel.RecentlyAddedRefResult](t, output)
 
 		paths, err := cliutils.GetSubPaths(remotePath)
 
@@ -122,9 +120,7 @@ func TestRecentlyAddedRefs(testSetup *testing.T) {
 		require.Nil(t, err, "List recent files failed", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		result := climodel.RecentlyAddedRefResult{}
-		err = json.Unmarshal([]byte(output[len(output)-1]), &result)
-		require.Nil(t, err)
+		result := cliparse.RequireJSON[climodel.RecentlyAddedRefResult](t, output)
 
 		require.Equal(t, 0, result.Offset)
 		require.Len(t, result.Refs, 0)
@@ -154,7 +150,7 @@ func TestRecentlyAddedRefs(testSetup *testing.T) {
 
 		nonAllocOwnerWallet := escapedTestName(t) + "_NON_OWNER"
 
-		createWalletForName(nonAllocOwnerWallet)
+		createWalletForName(t, nonAllocOwnerWallet)
 
 		t1 := time.Now()
 		time.Sleep(time.Second * 30)
@@ -169,9 +165,7 @@ func TestRecentlyAddedRefs(testSetup *testing.T) {
 			}), true)
 
 		require.Nil(t, err)
-		result := climodel.RecentlyAddedRefResult{}
-		err = json.Unmarshal([]byte(output[len(output)-1]), &result)
-		require.Nil(t, err)
+		result := cliparse.RequireJSON[climodel.RecentlyAddedRefResult](t, output)
 
 		require.Len(t, result.Refs, 0)
 	})
@@ -221,5 +215,5 @@ func listRecentlyAddedRefsForWallet(t *test.SystemTest, wallet, cliConfigFilenam
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	}
-	return cliutils.RunCommandWithoutRetry(cmd)
+	return cliutils.RunCommandWithoutRetry(t, cmd)
 }
@@ -84,7 +84,7 @@ func TestRestrictedBlobbers(testSetup *testing.T) {
 		_ = setupWallet(t, configPath)
 
 		options := map[string]interface{}{"size": "1024", "data": "3", "parity": "3", "lock": "0.5", "force": "true"}
-		output, err = createNewAllocationWithoutRetry(t, configPath, createParams(options))
+		output, err = createNewAllocationWithoutRetry(t, configPath, options)
 		require.NotNil(t, err)
 		require.True(t, len(output) > 0, "expected output length be at least 1", strings.Join(output, "\n"))
 
@@ -115,7 +115,7 @@ func TestRestrictedBlobbers(testSetup *testing.T) {
 		}
 
 		options = map[string]interface{}{"size": "1024", "data": "3", "parity": "3", "lock": "0.5", "preferred_blobbers": preferredBlobbers, "blobber_auth_tickets": blobberAuthTickets, "force": "true"}
-		output, err = createNewAllocation(t, configPath, createParams(options))
+		output, err = createNewAllocation(t, configPath, options)
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.True(t, len(output) > 0, "expected output length be at least 1")
 		require.Regexp(t, regexp.MustCompile("^Allocation created: [0-9a-fA-F]{64}$"), output[0], strings.Join(output, "\n"))
@@ -147,7 +147,7 @@ func TestRestrictedBlobbers(testSetup *testing.T) {
 		_ = setupWallet(t, configPath)
 
 		options := map[string]interface{}{"size": "1024", "data": "3", "parity": "3", "lock": "0.5"}
-		output, err = createNewAllocationWithoutRetry(t, configPath, createParams(options))
+		output, err = createNewAllocationWithoutRetry(t, configPath, options)
 		require.NotNil(t, err)
 		require.True(t, len(output) > 0, "expected output length be at least 1", strings.Join(output, "\n"))
 		require.Contains(t, output[len(output)-1], "not enough blobbers to honor the allocation")
@@ -168,7 +168,7 @@ func TestRestrictedBlobbers(testSetup *testing.T) {
 		}
 
 		options = map[string]interface{}{"size": "1024", "data": "3", "parity": "3", "lock": "0.5", "preferred_blobbers": preferredBlobbers, "blobber_auth_tickets": blobberAuthTickets}
-		output, err = createNewAllocationWithoutRetry(t, configPath, createParams(options))
+		output, err = createNewAllocationWithoutRetry(t, configPath, options)
 		require.NotNil(t, err)
 		require.True(t, len(output) > 0, "expected output length be at least 1", strings.Join(output, "\n"))
 		require.Contains(t, output[len(output)-1], "Not enough blobbers to honor the allocation")
@@ -18,6 +18,7 @@ import (
 
 func TestRollbackAllocation(testSetup *testing.T) {
 	t := test.NewSystemTest(testSetup)
+	t.RequireCapability("zbox rollback")
 	err := os.MkdirAll("tmp", os.ModePerm)
 	require.Nil(t, err)
 
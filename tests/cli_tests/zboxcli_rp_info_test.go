@@ -1,7 +1,6 @@
 package cli_tests
 
 import (
-	"encoding/json"
 	"regexp"
 	"strings"
 	"testing"
@@ -9,6 +8,7 @@ import (
 
 	"github.com/0chain/system_test/internal/api/util/test"
 	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
 	"github.com/stretchr/testify/require"
 )
@@ -39,9 +39,7 @@ func TestReadPoolInfo(testSetup *testing.T) {
 		require.Nil(t, err, "Error fetching read pool info", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		rpInfo := climodel.ReadPoolInfo{}
-		err = json.Unmarshal([]byte(output[0]), &rpInfo)
-		require.Nil(t, err, "Error unmarshalling read pool", strings.Join(output, "\n"))
+		rpInfo := cliparse.RequireJSON[climodel.ReadPoolInfo](t, output)
 		require.NotEmpty(t, rpInfo)
 	})
 
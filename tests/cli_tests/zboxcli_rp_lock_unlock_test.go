@@ -1,7 +1,6 @@
 package cli_tests
 
 import (
-	"encoding/json"
 	"fmt"
 	"strings"
 	"testing"
@@ -10,7 +9,9 @@ import (
 	"github.com/0chain/system_test/internal/api/util/test"
 
 	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
+	"github.com/0chain/system_test/internal/currency"
 	"github.com/stretchr/testify/require"
 )
 
@@ -39,12 +40,12 @@ func TestReadPoolLockUnlock(testSetup *testing.T) {
 		// Wallet balance should decrement from 5 to 3.9 (0.01 is fees) ZCN
 		balanceAfter, err := getBalanceZCN(t, configPath)
 		require.NoError(t, err)
-		require.InEpsilon(t, balanceBefore-1.01, balanceAfter, 0.01)
+		requireBalanceInEpsilon(t, balanceBefore-zcn(1.01), balanceAfter, 0.01)
 		balanceBefore = balanceAfter
 
 		// Read pool balance should increment to 1
 		readPool := getReadPoolInfo(t)
-		require.Equal(t, ConvertToValue(lockAmount), readPool.Balance, "Read Pool balance must be equal to locked amount")
+		require.Equal(t, currency.ZCNToSAS(lockAmount), readPool.Balance, "Read Pool balance must be equal to locked amount")
 
 		output, err = readPoolUnlock(t, configPath, "", true)
 		require.Nil(t, err, "Unable to unlock tokens", strings.Join(output, "\n"))
@@ -57,7 +58,7 @@ func TestReadPoolLockUnlock(testSetup *testing.T) {
 		require.NoError(t, err)
 
 		t.Log("balanceBefore : ", balanceBefore, " balanceAfter : ", balanceAfter)
-		require.InEpsilon(t, balanceBefore+0.99, balanceAfter, 0.01)
+		requireBalanceInEpsilon(t, balanceBefore+zcn(0.99), balanceAfter, 0.01)
 	})
 
 	t.Run("Should not be able to lock more read tokens than wallet balance", func(t *test.SystemTest) {
@@ -78,7 +79,7 @@ func TestReadPoolLockUnlock(testSetup *testing.T) {
 		// Wallet balance reduced due to chargeable error (0.1 fees)
 		balanceAfter, err := getBalanceZCN(t, configPath)
 		require.NoError(t, err)
-		require.InEpsilon(t, balanceBefore-0.01, balanceAfter, 0.01)
+		requireBalanceInEpsilon(t, balanceBefore-zcn(0.01), balanceAfter, 0.01)
 	})
 
 	t.Run("Should not be able to lock negative read tokens", func(t *test.SystemTest) {
@@ -120,7 +121,7 @@ func TestReadPoolLockUnlock(testSetup *testing.T) {
 		// Wallet balance gets reduced due to chargeable error (0.1 fees)
 		balanceAfter, err := getBalanceZCN(t, configPath)
 		require.NoError(t, err)
-		require.InEpsilon(t, balanceBefore-0.01, balanceAfter, 0.01)
+		requireBalanceInEpsilon(t, balanceBefore-zcn(0.01), balanceAfter, 0.01)
 	})
 
 	t.Run("Missing tokens flag in rp-lock should result in error", func(t *test.SystemTest) {
@@ -149,17 +150,13 @@ func readPoolUnlock(t *test.SystemTest, cliConfigFilename, params string, retry
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
 func getReadPoolInfo(t *test.SystemTest) climodel.ReadPoolInfo {
 	output, err := readPoolInfo(t, configPath)
 	require.Nil(t, err, "Error fetching read pool", strings.Join(output, "\n"))
-	require.Lenf(t, output, 1, "ouptut: %v", output)
 
-	var readPool climodel.ReadPoolInfo
-	err = json.Unmarshal([]byte(output[0]), &readPool)
-	require.Nil(t, err, "Error unmarshalling read pool %s", strings.Join(output, "\n"))
-	return readPool
+	return cliparse.RequireJSON[climodel.ReadPoolInfo](t, output)
 }
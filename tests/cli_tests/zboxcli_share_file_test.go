@@ -2,12 +2,10 @@ package cli_tests
 
 import (
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
-	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -15,6 +13,7 @@ import (
 	"github.com/0chain/system_test/internal/api/util/test"
 
 	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/sha3"
@@ -819,7 +818,7 @@ func TestShareFile(testSetup *testing.T) {
 		// receiver wallet operations
 		receiverWallet := escapedTestName(t) + "_second"
 
-		createWalletForName(receiverWallet)
+		createWalletForName(t, receiverWallet)
 
 		shareParams := map[string]interface{}{
 			"allocation": allocationID,
@@ -881,7 +880,7 @@ func TestShareFile(testSetup *testing.T) {
 		// receiver wallet operations
 		receiverWallet := escapedTestName(t) + "_second"
 
-		createWalletForName(receiverWallet)
+		createWalletForName(t, receiverWallet)
 
 		shareParams := map[string]interface{}{
 			"allocation":         allocationID,
@@ -1029,14 +1028,14 @@ func TestShareFile(testSetup *testing.T) {
 	t.RunWithTimeout("Share encrypted huge file using auth ticket - proxy re-encryption", 5*time.Minute, func(t *test.SystemTest) {
 		walletOwner := escapedTestName(t)
 
-		createWalletForName(walletOwner)
+		createWalletForName(t, walletOwner)
 
-		allocParam := createParams(map[string]interface{}{
+		allocParam := map[string]interface{}{
 			"lock":   24,
 			"size":   1024000,
 			"parity": 1,
 			"data":   1,
-		})
+		}
 
 		output, err := createNewAllocationForWallet(t, walletOwner, configPath, allocParam)
 
@@ -1156,7 +1155,7 @@ func TestShareFile(testSetup *testing.T) {
 		// receiver wallet operations
 		receiverWallet := escapedTestName(t) + "_second"
 
-		createWalletForName(receiverWallet)
+		createWalletForName(t, receiverWallet)
 
 		walletReceiver, err := getWalletForName(t, configPath, receiverWallet)
 		require.Nil(t, err)
@@ -1232,7 +1231,7 @@ func TestShareFile(testSetup *testing.T) {
 		// receiver wallet operations
 		receiverWallet := escapedTestName(t) + "_second"
 
-		createWalletForName(receiverWallet)
+		createWalletForName(t, receiverWallet)
 
 		walletReceiver, err := getWalletForName(t, configPath, receiverWallet)
 		require.Nil(t, err)
@@ -1503,7 +1502,7 @@ func TestShareFile(testSetup *testing.T) {
 		// receiver wallet operations
 		receiverWallet := escapedTestName(t) + "_second"
 
-		createWalletForName(receiverWallet)
+		createWalletForName(t, receiverWallet)
 
 		walletReceiver, err := getWalletForName(t, configPath, receiverWallet)
 		require.Nil(t, err)
@@ -1546,7 +1545,7 @@ func TestShareFile(testSetup *testing.T) {
 		// receiver wallet operations
 		receiverWallet := escapedTestName(t) + "_second"
 
-		createWalletForName(receiverWallet)
+		createWalletForName(t, receiverWallet)
 
 		walletReceiver, err := getWalletForName(t, configPath, receiverWallet)
 		require.Nil(t, err)
@@ -1574,12 +1573,12 @@ func TestShareFile(testSetup *testing.T) {
 		// sharer wallet operations
 		sharerWallet := escapedTestName(t) + "_sharer"
 
-		createWalletForName(sharerWallet)
+		createWalletForName(t, sharerWallet)
 
 		// receiver wallet operations
 		receiverWallet := escapedTestName(t) + "_receiver"
 
-		createWalletForName(receiverWallet)
+		createWalletForName(t, receiverWallet)
 
 		walletReceiver, err := getWalletForName(t, configPath, receiverWallet)
 		require.Nil(t, err)
@@ -1681,9 +1680,7 @@ func TestShareFile(testSetup *testing.T) {
 		require.Nil(t, err, "Error fetching read pool", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		initialReadPool := climodel.ReadPoolInfo{}
-		err = json.Unmarshal([]byte(output[0]), &initialReadPool)
-		require.Nil(t, err, "Error unmarshalling read pool", strings.Join(output, "\n"))
+		initialReadPool := cliparse.RequireJSON[climodel.ReadPoolInfo](t, output)
 		require.NotEmpty(t, initialReadPool)
 
 		// instead of 0.4*1e10 we need to use 0.1*1e11 as we are staking single token via receiver wallet
@@ -1697,12 +1694,9 @@ func TestShareFile(testSetup *testing.T) {
 		require.Nil(t, err, "Could not get download cost", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		expectedDownloadCost, err := strconv.ParseFloat(strings.Fields(output[0])[0], 64)
-		require.Nil(t, err, "Cost couldn't be parsed to float", strings.Join(output, "\n"))
-
-		unit := strings.Fields(output[0])[1]
-		expectedDownloadCostInSas := unitToZCN(expectedDownloadCost, unit) * 1e10
-		t.Logf("Download cost: %v sas", expectedDownloadCostInSas)
+		expectedDownloadCost, err := parseCost(output[0])
+		require.Nil(t, err, "Cost couldn't be parsed", strings.Join(output, "\n"))
+		t.Logf("Download cost: %v", expectedDownloadCost)
 
 		// Download the file (delete local copy first)
 		os.Remove(file)
@@ -1727,13 +1721,11 @@ func TestShareFile(testSetup *testing.T) {
 		require.Nil(t, err, "Error fetching read pool", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		finalReadPool := climodel.ReadPoolInfo{}
-		err = json.Unmarshal([]byte(output[0]), &finalReadPool)
-		require.Nil(t, err, "Error unmarshalling read pool", strings.Join(output, "\n"))
+		finalReadPool := cliparse.RequireJSON[climodel.ReadPoolInfo](t, output)
 		require.NotEmpty(t, finalReadPool)
 
 		require.Nil(t, err, "Error fetching read pool", strings.Join(output, "\n"))
-		expectedRPBalance := initialReadPool.Balance - int64(expectedDownloadCostInSas)
+		expectedRPBalance := initialReadPool.Balance - int64(expectedDownloadCost)
 
 		expectedRPBalance = int64(expectedRPBalance*95) / 100 // reducing it to 5% to deal with the rounding off issue
 
@@ -1764,14 +1756,14 @@ func shareFileWithWallet(t *test.SystemTest, wallet, cliConfigFilename string, p
 }
 
 func createWalletAndAllocation(t *test.SystemTest, configPath, wallet string) (string, *climodel.Wallet) {
-	createWalletForName(wallet)
+	createWalletForName(t, wallet)
 
-	allocParam := createParams(map[string]interface{}{
+	allocParam := map[string]interface{}{
 		"lock":   2,
 		"size":   1024 * 1024 * 1024,
 		"parity": 1,
 		"data":   1,
-	})
+	}
 
 	output, err := createNewAllocationForWallet(t, wallet, configPath, allocParam)
 	require.Nil(t, err, "Failed to create new allocation", strings.Join(output, "\n"))
@@ -1827,6 +1819,6 @@ func listAllFilesFromBlobber(t *test.SystemTest, wallet, cliConfigFilename, para
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
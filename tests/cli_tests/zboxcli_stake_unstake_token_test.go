@@ -1,7 +1,6 @@
 package cli_tests
 
 import (
-	"encoding/json"
 	"fmt"
 	"regexp"
 	"strconv"
@@ -12,6 +11,7 @@ import (
 	"github.com/0chain/system_test/internal/api/util/test"
 
 	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
 	"github.com/stretchr/testify/require"
 )
@@ -33,8 +33,7 @@ func TestStakeUnstakeTokens(testSetup *testing.T) {
 		require.Nil(t, err, "Error listing blobbers", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		err = json.Unmarshal([]byte(output[0]), &blobbers)
-		require.Nil(t, err, "Error unmarshalling blobber list", strings.Join(output, "\n"))
+		blobbers = cliparse.RequireJSON[[]climodel.BlobberInfo](t, output)
 		require.True(t, len(blobbers) > 0, "No blobbers found in blobber list")
 
 		// Pick a random blobber
@@ -44,10 +43,10 @@ func TestStakeUnstakeTokens(testSetup *testing.T) {
 		require.NoError(t, err)
 
 		// Stake tokens against this blobber
-		output, err = stakeTokens(t, configPath, createParams(map[string]interface{}{
+		output, err = stakeTokens(t, configPath, map[string]interface{}{
 			"blobber_id": blobber.Id,
 			"tokens":     1.0,
-		}), true)
+		}, true)
 		require.Nil(t, err, "Error staking tokens", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 		require.Regexp(t, regexp.MustCompile("tokens locked, txn hash: ([a-f0-9]{64})"), output[0])
@@ -58,19 +57,14 @@ func TestStakeUnstakeTokens(testSetup *testing.T) {
 		require.NoError(t, err)
 
 		// less than balanceBefore - 1 due to txn fee
-		require.Less(t, balanceAfter, balanceBefore-1)
+		require.Less(t, balanceAfter, balanceBefore-zcn(1))
 
 		// Use sp-info to check the staked tokens in blobber's stake pool
-		output, err = stakePoolInfo(t, configPath, createParams(map[string]interface{}{
-			"blobber_id": blobber.Id,
-			"json":       "",
-		}))
+		output, err = stakePoolInfo(t, configPath, cliutils.ZboxStakePoolInfo{BlobberID: blobber.Id, JSON: true})
 		require.Nil(t, err, "Error fetching stake pool info", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		stakePool := climodel.StakePoolInfo{}
-		err = json.Unmarshal([]byte(output[0]), &stakePool)
-		require.Nil(t, err, "Error unmarshalling stake pool info", strings.Join(output, "\n"))
+		stakePool := cliparse.RequireJSON[climodel.StakePoolInfo](t, output)
 		require.NotEmpty(t, stakePool)
 
 		delegates := stakePool.Delegate
@@ -90,9 +84,7 @@ func TestStakeUnstakeTokens(testSetup *testing.T) {
 			strings.Join(output, "\n"), wallet.ClientID))
 
 		// Unstake the tokens
-		output, err = unstakeTokens(t, configPath, createParams(map[string]interface{}{
-			"blobber_id": blobber.Id,
-		}), true)
+		output, err = unstakeTokens(t, configPath, cliutils.ZboxStakeUnlock{BlobberID: blobber.Id}, true)
 		require.Nil(t, err, "Error unstaking tokens from stake pool", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 		require.Equal(t, "tokens unlocked: 10000000000, pool deleted", output[0])
@@ -108,15 +100,11 @@ func TestStakeUnstakeTokens(testSetup *testing.T) {
 		require.GreaterOrEqual(t, newBalanceValue, float64(1.0))
 
 		// Pool Id must be deleted from stake pool now
-		output, err = stakePoolInfo(t, configPath, createParams(map[string]interface{}{
-			"blobber_id": blobber.Id,
-			"json":       "",
-		}))
+		output, err = stakePoolInfo(t, configPath, cliutils.ZboxStakePoolInfo{BlobberID: blobber.Id, JSON: true})
 		require.Nil(t, err, "Error fetching stake pool info", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		err = json.Unmarshal([]byte(output[0]), &stakePool)
-		require.Nil(t, err, "Error unmarshalling stake pool info", strings.Join(output, "\n"))
+		stakePool = cliparse.RequireJSON[climodel.StakePoolInfo](t, output)
 		require.NotEmpty(t, stakePool)
 
 		delegates = stakePool.Delegate
@@ -135,9 +123,9 @@ func TestStakeUnstakeTokens(testSetup *testing.T) {
 	t.Run("Staking tokens without specifying amount of tokens to lock should fail", func(t *test.SystemTest) {
 		createWallet(t)
 
-		output, err := stakeTokens(t, configPath, createParams(map[string]interface{}{
+		output, err := stakeTokens(t, configPath, map[string]interface{}{
 			"blobber_id": "-",
-		}), false)
+		}, false)
 		require.NotNil(t, err, "Expected error when amount of tokens to stake is not specified", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 		require.Equal(t, "missing required 'tokens' flag", output[0])
@@ -146,9 +134,9 @@ func TestStakeUnstakeTokens(testSetup *testing.T) {
 	t.Run("Staking tokens without specifying provider should generate corresponding error", func(t *test.SystemTest) {
 		createWallet(t)
 
-		output, err := stakeTokens(t, configPath, createParams(map[string]interface{}{
+		output, err := stakeTokens(t, configPath, map[string]interface{}{
 			"tokens": 1.0,
-		}), false)
+		}, false)
 		require.NotNil(t, err, "Expected error when blobber to stake tokens to is not specified", strings.Join(output, "\n"))
 		require.GreaterOrEqual(t, len(output), 1)
 		require.Equal(t, "missing flag: one of 'miner_id', 'sharder_id', 'blobber_id', 'validator_id', 'authorizer_id' is required", output[0])
@@ -167,18 +155,17 @@ func TestStakeUnstakeTokens(testSetup *testing.T) {
 		require.Nil(t, err, "Error listing blobbers", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		err = json.Unmarshal([]byte(output[0]), &blobbers)
-		require.Nil(t, err, "Error unmarshalling blobber list", strings.Join(output, "\n"))
+		blobbers = cliparse.RequireJSON[[]climodel.BlobberInfo](t, output)
 		require.True(t, len(blobbers) > 0, "No blobbers found in blobber list")
 
 		// Pick a random blobber
 		blobber := blobbers[time.Now().Unix()%int64(len(blobbers))]
 
 		// Stake tokens against this blobber
-		output, err = stakeTokens(t, configPath, createParams(map[string]interface{}{
+		output, err = stakeTokens(t, configPath, map[string]interface{}{
 			"blobber_id": blobber.Id,
 			"tokens":     10,
-		}), false)
+		}, false)
 		require.NotNil(t, err, "Expected error when staking more tokens than in wallet", strings.Join(output, "\n"))
 		require.GreaterOrEqual(t, len(output), 1)
 		require.Equal(t, "Failed to lock tokens in stake pool: stake_pool_lock_failed: stake pool digging error: lock amount is greater than balance", output[0])
@@ -201,18 +188,17 @@ func TestStakeUnstakeTokens(testSetup *testing.T) {
 		require.Nil(t, err, "Error listing blobbers", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		err = json.Unmarshal([]byte(output[0]), &blobbers)
-		require.Nil(t, err, "Error unmarshalling blobber list", strings.Join(output, "\n"))
+		blobbers = cliparse.RequireJSON[[]climodel.BlobberInfo](t, output)
 		require.True(t, len(blobbers) > 0, "No blobbers found in blobber list")
 
 		// Pick a random blobber
 		blobber := blobbers[time.Now().Unix()%int64(len(blobbers))]
 
 		// Stake tokens against this blobber
-		output, err = stakeTokens(t, configPath, createParams(map[string]interface{}{
+		output, err = stakeTokens(t, configPath, map[string]interface{}{
 			"blobber_id": blobber.Id,
 			"tokens":     0.0,
-		}), false)
+		}, false)
 		require.NotNil(t, err, "Expected error when staking 0 tokens than in stake pool", strings.Join(output, "\n"))
 		require.GreaterOrEqual(t, len(output), 1)
 		require.Equal(t, "Failed to lock tokens in stake pool: stake_pool_lock_failed: no stake to lock: 0", output[0])
@@ -235,18 +221,17 @@ func TestStakeUnstakeTokens(testSetup *testing.T) {
 		require.Nil(t, err, "Error listing blobbers", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		err = json.Unmarshal([]byte(output[0]), &blobbers)
-		require.Nil(t, err, "Error unmarshalling blobber list", strings.Join(output, "\n"))
+		blobbers = cliparse.RequireJSON[[]climodel.BlobberInfo](t, output)
 		require.True(t, len(blobbers) > 0, "No blobbers found in blobber list")
 
 		// Pick a random blobber
 		blobber := blobbers[time.Now().Unix()%int64(len(blobbers))]
 
 		// Stake tokens against this blobber
-		output, err = stakeTokens(t, configPath, createParams(map[string]interface{}{
+		output, err = stakeTokens(t, configPath, map[string]interface{}{
 			"blobber_id": blobber.Id,
 			"tokens":     -1.0,
-		}), false)
+		}, false)
 		require.NotNil(t, err, "Expected error when staking negative tokens than in stake pool", strings.Join(output, "\n"))
 		require.GreaterOrEqual(t, len(output), 1)
 		require.Equal(t, "invalid token amount: negative", output[0])
@@ -269,36 +254,38 @@ func getTransaction(t *test.SystemTest, cliConfigFilename, params string) ([]str
 	return cliutils.RunCommand(t, fmt.Sprintf("./zwallet verify %s --silent --configDir ./config --config %s", params, cliConfigFilename), 3, time.Second*2)
 }
 
-func stakeTokens(t *test.SystemTest, cliConfigFilename, params string, retry bool) ([]string, error) {
+func stakeTokens(t *test.SystemTest, cliConfigFilename string, params map[string]interface{}, retry bool) ([]string, error) {
 	return stakeTokensForWallet(t, cliConfigFilename, escapedTestName(t), params, retry)
 }
 
-func stakeTokensForWallet(t *test.SystemTest, cliConfigFilename, wallet, params string, retry bool) ([]string, error) {
+func stakeTokensForWallet(t *test.SystemTest, cliConfigFilename, wallet string, params map[string]interface{}, retry bool) ([]string, error) {
 	t.Log("Staking tokens...")
-	cmd := fmt.Sprintf("./zbox sp-lock %s --silent --wallet %s_wallet.json --configDir ./config --config %s", params, wallet, cliConfigFilename)
+	var flags cliutils.ZboxStakeLock
+	cliutils.DecodeFlags(params, &flags)
+	cmd := cliutils.Zbox("sp-lock", flags, zboxFlags(wallet, cliConfigFilename))
 	if retry {
-		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
+		return cmd.Run(t, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cmd.RunWithoutRetry(t)
 	}
 }
 
-func stakePoolInfo(t *test.SystemTest, cliConfigFilename, params string) ([]string, error) {
+func stakePoolInfo(t *test.SystemTest, cliConfigFilename string, flags cliutils.ZboxStakePoolInfo) ([]string, error) {
 	t.Log("Fetching stake pool info...")
-	return cliutils.RunCommand(t, fmt.Sprintf("./zbox sp-info %s --silent --wallet %s_wallet.json --configDir ./config --config %s", params, escapedTestName(t), cliConfigFilename), 3, time.Second*2)
+	return cliutils.Zbox("sp-info", flags, zboxFlags(escapedTestName(t), cliConfigFilename)).Run(t, 3, time.Second*2)
 }
 
-func unstakeTokens(t *test.SystemTest, cliConfigFilename, params string, retry bool) ([]string, error) {
-	return unstakeTokensForWallet(t, cliConfigFilename, escapedTestName(t), params, retry)
+func unstakeTokens(t *test.SystemTest, cliConfigFilename string, flags cliutils.ZboxStakeUnlock, retry bool) ([]string, error) {
+	return unstakeTokensForWallet(t, cliConfigFilename, escapedTestName(t), flags, retry)
 }
 
-func unstakeTokensForWallet(t *test.SystemTest, cliConfigFilename, wallet, params string, retry bool) ([]string, error) {
+func unstakeTokensForWallet(t *test.SystemTest, cliConfigFilename, wallet string, flags cliutils.ZboxStakeUnlock, retry bool) ([]string, error) {
 	t.Log("Unlocking tokens from stake pool...")
-	cmd := fmt.Sprintf("./zbox sp-unlock %s --silent --wallet %s_wallet.json --configDir ./config --config %s", params, wallet, cliConfigFilename)
+	cmd := cliutils.Zbox("sp-unlock", flags, zboxFlags(wallet, cliConfigFilename))
 	if retry {
-		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
+		return cmd.Run(t, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cmd.RunWithoutRetry(t)
 	}
 }
 
@@ -308,8 +295,7 @@ func getBlobbersList(t *test.SystemTest) []climodel.BlobberInfo {
 	require.Nil(t, err, "Error listing blobbers", strings.Join(output, "\n"))
 	require.Len(t, output, 1)
 
-	err = json.Unmarshal([]byte(output[0]), &blobbers)
-	require.Nil(t, err, "Error unmarshalling blobber list", strings.Join(output, "\n"))
+	blobbers = cliparse.RequireJSON[[]climodel.BlobberInfo](t, output)
 	require.True(t, len(blobbers) > 0, "No blobbers found in blobber list")
 
 	return blobbers
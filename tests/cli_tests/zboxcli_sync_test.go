@@ -14,6 +14,7 @@ import (
 	"github.com/0chain/system_test/internal/api/util/test"
 
 	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
 	"github.com/stretchr/testify/require"
 )
@@ -127,9 +128,7 @@ func TestSyncWithBlobbers(testSetup *testing.T) {
 		require.Nil(t, err, "Error in listing the allocation files: ", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var files []climodel.AllocationFile
-		err = json.Unmarshal([]byte(output[0]), &files)
-		require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output, "\n"), err)
+		files := cliparse.RequireJSON[[]climodel.AllocationFile](t, output)
 
 		require.Len(t, files, 1, "1 file must be uploaded", files)
 		file := files[0]
@@ -198,9 +197,7 @@ func TestSyncWithBlobbers(testSetup *testing.T) {
 		require.Nil(t, err, "Error in listing the allocation files: ", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var files []climodel.AllocationFile
-		err = json.Unmarshal([]byte(output[0]), &files)
-		require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output, "\n"), err)
+		files := cliparse.RequireJSON[[]climodel.AllocationFile](t, output)
 
 		require.Len(t, files, 1, "1 file must be uploaded", files)
 		file := files[0]
@@ -268,9 +265,7 @@ func TestSyncWithBlobbers(testSetup *testing.T) {
 		require.Nil(t, err, "Error in listing the allocation files: ", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var files []climodel.AllocationFile
-		err = json.Unmarshal([]byte(output[0]), &files)
-		require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output, "\n"), err)
+		files := cliparse.RequireJSON[[]climodel.AllocationFile](t, output)
 
 		// This will traverse the tree and asserts the existent of the files
 		assertFileExistenceRecursively(t, mockFolderStructure, files)
@@ -308,9 +303,7 @@ func TestSyncWithBlobbers(testSetup *testing.T) {
 		require.Nil(t, err, "Error in listing the allocation files: ", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var files []climodel.AllocationFile
-		err = json.Unmarshal([]byte(output[0]), &files)
-		require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output, "\n"), err)
+		files := cliparse.RequireJSON[[]climodel.AllocationFile](t, output)
 
 		// This will traverse the tree and asserts the existent of the files
 		assertFileExistenceRecursively(t, mockFolderStructure, files)
@@ -354,9 +347,7 @@ func TestSyncWithBlobbers(testSetup *testing.T) {
 		require.Nil(t, err, "Error in listing the allocation files: ", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var files []climodel.AllocationFile
-		err = json.Unmarshal([]byte(output[0]), &files)
-		require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output, "\n"), err)
+		files := cliparse.RequireJSON[[]climodel.AllocationFile](t, output)
 
 		// This will traverse the tree and asserts the existent of the files
 		assertFileExistenceRecursively(t, mockFolderStructure, files)
@@ -421,9 +412,7 @@ func TestSyncWithBlobbers(testSetup *testing.T) {
 		require.Nil(t, err, "Error in listing the allocation files: ", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var files []climodel.AllocationFile
-		err = json.Unmarshal([]byte(output[0]), &files)
-		require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output, "\n"), err)
+		files := cliparse.RequireJSON[[]climodel.AllocationFile](t, output)
 
 		// This will traverse the tree and asserts the existent of the files
 		assertFileExistenceRecursively(t, mockFolderStructure, files)
@@ -498,9 +487,7 @@ func TestSyncWithBlobbers(testSetup *testing.T) {
 		require.Nil(t, err, "Error in listing the allocation files: ", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var files []climodel.AllocationFile
-		err = json.Unmarshal([]byte(output[0]), &files)
-		require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output, "\n"), err)
+		files := cliparse.RequireJSON[[]climodel.AllocationFile](t, output)
 
 		// This will traverse the tree and asserts the existent of the files
 		assertFileExistenceRecursively(t, mockFolderStructure, files)
@@ -556,9 +543,7 @@ func TestSyncWithBlobbers(testSetup *testing.T) {
 		require.Nil(t, err, "Error in listing the allocation files: ", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var files []climodel.AllocationFile
-		err = json.Unmarshal([]byte(output[0]), &files)
-		require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output, "\n"), err)
+		files := cliparse.RequireJSON[[]climodel.AllocationFile](t, output)
 
 		// This will traverse the tree and asserts the existent of the files
 		assertFileExistenceRecursively(t, mockFolderStructure, files)
@@ -607,9 +592,7 @@ func TestSyncWithBlobbers(testSetup *testing.T) {
 		require.Nil(t, err, "Error in listing the allocation files: ", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var files []climodel.AllocationFile
-		err = json.Unmarshal([]byte(output[0]), &files)
-		require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output, "\n"), err)
+		files := cliparse.RequireJSON[[]climodel.AllocationFile](t, output)
 
 		// This will traverse the tree and asserts the existent of the files in th allocation
 		assertFileExistenceRecursively(t, mockFolderStructure, files)
@@ -664,9 +647,7 @@ func TestSyncWithBlobbers(testSetup *testing.T) {
 		require.Nil(t, err, "Error in listing the allocation files: ", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var files []climodel.AllocationFile
-		err = json.Unmarshal([]byte(output[0]), &files)
-		require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output, "\n"), err)
+		files := cliparse.RequireJSON[[]climodel.AllocationFile](t, output)
 
 		// This will traverse the tree and asserts the existent of the files
 		assertFileExistenceRecursively(t, mockFolderStructure, files)
@@ -677,7 +658,7 @@ func TestSyncWithBlobbers(testSetup *testing.T) {
 		createAllocationTestTeardown(t, allocationID)
 
 		notOwnerWalletName := escapedTestName(t) + "_NOT_OWNER_WALLET"
-		createWalletForName(notOwnerWalletName)
+		createWalletForName(t, notOwnerWalletName)
 
 		// The folder structure tree
 		// Integer values will be consider as files with that size
@@ -763,9 +744,7 @@ func TestSyncWithBlobbers(testSetup *testing.T) {
 		require.Nil(t, err, "Error in listing the allocation files: ", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var files []climodel.AllocationFile
-		err = json.Unmarshal([]byte(output[0]), &files)
-		require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output, "\n"), err)
+		files := cliparse.RequireJSON[[]climodel.AllocationFile](t, output)
 
 		var file_initial climodel.AllocationFile
 		for _, item := range files {
@@ -786,9 +765,7 @@ func TestSyncWithBlobbers(testSetup *testing.T) {
 		require.Nil(t, err, "Error in syncing the folder: ", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var differences []climodel.FileDiff
-		err = json.Unmarshal([]byte(output[0]), &differences)
-		require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output, "\n"), err)
+		differences := cliparse.RequireJSON[[]climodel.FileDiff](t, output)
 		require.Len(t, differences, 1, "we updated a file, we except 1 change but we got %v", len(differences), differences)
 
 		output, err = syncFolder(t, configPath, map[string]interface{}{
@@ -803,9 +780,7 @@ func TestSyncWithBlobbers(testSetup *testing.T) {
 		require.Nil(t, err, "Error in listing the allocation files: ", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var files2 []climodel.AllocationFile
-		err = json.Unmarshal([]byte(output[0]), &files2)
-		require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output, "\n"), err)
+		files2 := cliparse.RequireJSON[[]climodel.AllocationFile](t, output)
 
 		var file climodel.AllocationFile
 		for _, item := range files2 {
@@ -851,9 +826,7 @@ func TestSyncWithBlobbers(testSetup *testing.T) {
 		require.Nil(t, err, "Error in listing the allocation files: ", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var files []climodel.AllocationFile
-		err = json.Unmarshal([]byte(output[0]), &files)
-		require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output, "\n"), err)
+		files := cliparse.RequireJSON[[]climodel.AllocationFile](t, output)
 
 		// This will traverse the tree and asserts the existent of the files
 		assertFileExistenceRecursively(t, mockFolderStructure, files)
@@ -881,9 +854,7 @@ func TestSyncWithBlobbers(testSetup *testing.T) {
 		require.Nil(t, err, "Error in syncing the folder: ", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var differences []climodel.FileDiff
-		err = json.Unmarshal([]byte(output[0]), &differences)
-		require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output, "\n"), err)
+		differences := cliparse.RequireJSON[[]climodel.FileDiff](t, output)
 		require.Len(t, differences, 2, "Since we updated 2 files we expect 2 differences but we got %v", len(differences), differences)
 
 		output, err = syncFolder(t, configPath, map[string]interface{}{
@@ -899,9 +870,7 @@ func TestSyncWithBlobbers(testSetup *testing.T) {
 		require.Nil(t, err, "Error in listing the allocation files: ", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var files2 []climodel.AllocationFile
-		err = json.Unmarshal([]byte(output[0]), &files2)
-		require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output, "\n"), err)
+		files2 := cliparse.RequireJSON[[]climodel.AllocationFile](t, output)
 
 		var file1 climodel.AllocationFile
 		var file2 climodel.AllocationFile
@@ -955,9 +924,7 @@ func TestSyncWithBlobbers(testSetup *testing.T) {
 		require.Nil(t, err, "Error in syncing the folder: ", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var differences []climodel.FileDiff
-		err = json.Unmarshal([]byte(output[0]), &differences)
-		require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output, "\n"), err)
+		differences := cliparse.RequireJSON[[]climodel.FileDiff](t, output)
 
 		require.Len(t, differences, 2, "Since we added a file and we updated 2 files (1 excluded) we expect 2 differences but we got %v", len(differences))
 
@@ -973,9 +940,7 @@ func TestSyncWithBlobbers(testSetup *testing.T) {
 		require.Nil(t, err, "Error in listing the allocation files: ", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var files []climodel.AllocationFile
-		err = json.Unmarshal([]byte(output[0]), &files)
-		require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output, "\n"), err)
+		files := cliparse.RequireJSON[[]climodel.AllocationFile](t, output)
 
 		var includedFile_initial climodel.AllocationFile
 		var excludedFile_initial climodel.AllocationFile
@@ -1006,8 +971,7 @@ func TestSyncWithBlobbers(testSetup *testing.T) {
 		require.Nil(t, err, "Error in syncing the folder: ", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		err = json.Unmarshal([]byte(output[0]), &differences)
-		require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output, "\n"), err)
+		differences = cliparse.RequireJSON[[]climodel.FileDiff](t, output)
 
 		require.Len(t, differences, 2, "Since we added a file and we updated 2 files (1 excluded) we expect 2 differences but we got %v", len(differences))
 
@@ -1024,9 +988,7 @@ func TestSyncWithBlobbers(testSetup *testing.T) {
 		require.Nil(t, err, "Error in listing the allocation files: ", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var files2 []climodel.AllocationFile
-		err = json.Unmarshal([]byte(output[0]), &files2)
-		require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output, "\n"), err)
+		files2 := cliparse.RequireJSON[[]climodel.AllocationFile](t, output)
 
 		var includedFile_final climodel.AllocationFile
 		var excludedFile_final climodel.AllocationFile
@@ -1082,7 +1044,7 @@ func syncFolderWithWallet(t *test.SystemTest, wallet, cliConfigFilename string,
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*40)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
@@ -1104,7 +1066,7 @@ func getDifferencesWithWallet(t *test.SystemTest, wallet, cliConfigFilename stri
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*40)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
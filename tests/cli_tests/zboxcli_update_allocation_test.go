@@ -8,7 +8,6 @@ import (
 	"path/filepath"
 	"reflect"
 	"regexp"
-	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -18,7 +17,9 @@ import (
 	"github.com/stretchr/testify/require"
 
 	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
+	"github.com/0chain/system_test/internal/currency"
 )
 
 var (
@@ -167,7 +168,7 @@ func TestUpdateAllocation(testSetup *testing.T) {
 		myAllocationID := setupAllocation(t, configPath)
 
 		targetWalletName := escapedTestName(t) + "_TARGET"
-		createWalletForName(targetWalletName)
+		createWalletForName(t, targetWalletName)
 
 		size := int64(2048)
 
@@ -536,7 +537,7 @@ func TestUpdateAllocation(testSetup *testing.T) {
 
 		nonAllocOwnerWallet := escapedTestName(t) + "_NON_OWNER"
 
-		createWalletForName(nonAllocOwnerWallet)
+		createWalletForName(t, nonAllocOwnerWallet)
 
 		// expand allocation
 		params = createParams(map[string]interface{}{
@@ -572,7 +573,7 @@ func TestUpdateAllocation(testSetup *testing.T) {
 
 		nonAllocOwnerWallet := escapedTestName(t) + "_NON_OWNER"
 
-		createWalletForName(nonAllocOwnerWallet)
+		createWalletForName(t, nonAllocOwnerWallet)
 
 		// expand allocation
 		params = createParams(map[string]interface{}{
@@ -611,7 +612,7 @@ func TestUpdateAllocation(testSetup *testing.T) {
 
 		nonAllocOwnerWallet := escapedTestName(t) + "_NON_OWNER"
 
-		createWalletForName(nonAllocOwnerWallet)
+		createWalletForName(t, nonAllocOwnerWallet)
 
 		// reduce allocation should fail
 		params = createParams(map[string]interface{}{
@@ -811,9 +812,9 @@ func setupAllocationWithWallet(t *test.SystemTest, walletName, cliConfigFilename
 		}
 	}
 	// First create a wallet and run faucet command
-	createWalletForName(walletName)
+	createWalletForName(t, walletName)
 
-	output, err := createNewAllocationForWallet(t, walletName, cliConfigFilename, createParams(options))
+	output, err := createNewAllocationForWallet(t, walletName, cliConfigFilename, options)
 	require.NoError(t, err, "create new allocation failed", strings.Join(output, "\n"))
 	require.Len(t, output, 1)
 
@@ -825,28 +826,25 @@ func setupAllocationWithWallet(t *test.SystemTest, walletName, cliConfigFilename
 }
 
 func assertOutputMatchesAllocationRegex(t *test.SystemTest, re *regexp.Regexp, str string) {
-	match := re.FindStringSubmatch(str)
-	require.True(t, len(match) > 0, "expected allocation to match regex", re, str)
+	_, err := cliparse.Regex([]string{str}, re)
+	require.NoError(t, err, "expected allocation to match regex: %s", str)
 }
 
+// getAllocationID reads the id from the output of newallocation, which has no JSON output
 func getAllocationID(str string) (string, error) {
-	match := createAllocationRegex.FindStringSubmatch(str)
-	if len(match) < 2 {
+	match, err := cliparse.Regex([]string{str}, createAllocationRegex)
+	if err != nil {
 		return "", errors.New("allocation match not found")
 	}
 	return match[1], nil
 }
 
-func getAllocationCost(str string) (float64, error) {
-	allocationCostInOutput, err := strconv.ParseFloat(strings.Fields(str)[5], 64)
-	if err != nil {
-		return 0.0, err
+func getAllocationCost(str string) (currency.Coin, error) {
+	fields := strings.Fields(str)
+	if len(fields) < 7 {
+		return 0, fmt.Errorf("%w: %q", currency.ErrInvalidCoin, str)
 	}
-
-	unit := strings.Fields(str)[6]
-	allocationCostInZCN := unitToZCN(allocationCostInOutput, unit)
-
-	return allocationCostInZCN, nil
+	return parseCost(strings.Join(fields[5:7], " "))
 }
 
 func createParams(params map[string]interface{}) string {
@@ -899,7 +897,7 @@ func updateAllocationWithWallet(t *test.SystemTest, wallet, cliConfigFilename, p
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
@@ -1,7 +1,6 @@
 package cli_tests
 
 import (
-	"encoding/json"
 	"fmt"
 	"regexp"
 	"strings"
@@ -9,14 +8,15 @@ import (
 	"time"
 
 	"github.com/0chain/system_test/internal/api/util/test"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
+	"github.com/0chain/system_test/internal/currency"
 
 	climodel "github.com/0chain/system_test/internal/cli/model"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
 )
 
-const tokenUnit float64 = 1e+10
-
 func TestFileUploadTokenMovement(testSetup *testing.T) {
 	t := test.NewSystemTest(testSetup)
 	t.SetSmokeTests("Challenge pool should be 0 before any write")
@@ -27,10 +27,10 @@ func TestFileUploadTokenMovement(testSetup *testing.T) {
 	t.Run("Challenge pool should be 0 before any write", func(t *test.SystemTest) {
 		createWallet(t)
 
-		allocParam := createParams(map[string]interface{}{
+		allocParam := map[string]interface{}{
 			"lock": balance,
 			"size": 10000,
-		})
+		}
 		output, err := createNewAllocation(t, configPath, allocParam)
 		require.Nil(t, err, "Failed to create new allocation", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
@@ -41,9 +41,7 @@ func TestFileUploadTokenMovement(testSetup *testing.T) {
 		require.Nil(t, err, "Could not fetch challenge pool", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		challengePool := climodel.ChallengePoolInfo{}
-		err = json.Unmarshal([]byte(output[0]), &challengePool)
-		require.Nil(t, err, "Error unmarshalling challenge pool info", strings.Join(output, "\n"))
+		challengePool := cliparse.RequireJSON[climodel.ChallengePoolInfo](t, output)
 		require.NotEmpty(t, challengePool)
 
 		require.Regexp(t, regexp.MustCompile(fmt.Sprintf("([a-f0-9]{64}):challengepool:%s", allocationID)), challengePool.Id)
@@ -56,10 +54,10 @@ func TestFileUploadTokenMovement(testSetup *testing.T) {
 	t.Run("Total balance in blobber pool equals locked tokens", func(t *test.SystemTest) {
 		createWallet(t)
 
-		allocParam := createParams(map[string]interface{}{
+		allocParam := map[string]interface{}{
 			"lock": balance,
 			"size": 10000,
-		})
+		}
 		output, err := createNewAllocation(t, configPath, allocParam)
 		require.Nil(t, err, "Failed to create new allocation", strings.Join(output, "\n"))
 
@@ -69,7 +67,7 @@ func TestFileUploadTokenMovement(testSetup *testing.T) {
 		allocationID := strings.Fields(output[0])[2]
 
 		allocation := getAllocation(t, allocationID)
-		require.Equal(t, 0.8, intToZCN(allocation.WritePool))
+		requireBalance(t, "0.8 ZCN", allocation.WritePool)
 	})
 }
 
@@ -86,6 +84,19 @@ func challengePoolInfo(t *test.SystemTest, cliConfigFilename, allocationID strin
 	return cliutils.RunCommand(t, "./zbox cp-info --allocation "+allocationID+" --json --silent --wallet "+escapedTestName(t)+"_wallet.json"+" --configDir ./config --config "+cliConfigFilename, 3, time.Second*2)
 }
 
-func intToZCN(balance int64) float64 {
-	return float64(balance) / tokenUnit
+// requireBalance checks that balance in SAS equals expected, e.g. "0.8 ZCN"
+func requireBalance(t *test.SystemTest, expected string, balance int64, msgAndArgs ...interface{}) {
+	want, err := currency.ParseCoin(expected)
+	require.NoError(t, err, "invalid expected balance %q", expected)
+	require.GreaterOrEqual(t, balance, int64(0), msgAndArgs...)
+	require.Equal(t, want.String(), currency.Coin(balance).String(), msgAndArgs...)
+}
+
+// requireCostInEpsilon checks that actual, in SAS, is within the relative error epsilon of the expected cost
+func requireCostInEpsilon(t *test.SystemTest, expected currency.Coin, actual int64, epsilon float64, msgAndArgs ...interface{}) {
+	require.GreaterOrEqual(t, actual, int64(0), msgAndArgs...)
+	diff := currency.Diff(currency.Coin(actual), expected).Abs()
+	if diff.GreaterThan(expected.Decimal(currency.SAS).Mul(decimal.NewFromFloat(epsilon))) {
+		require.Fail(t, fmt.Sprintf("cost %s is not within %v of the expected %s", currency.Coin(actual), epsilon, expected), msgAndArgs...)
+	}
 }
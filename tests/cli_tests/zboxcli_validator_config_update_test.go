@@ -1,7 +1,6 @@
 package cli_tests
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
@@ -11,6 +10,7 @@ import (
 	"github.com/0chain/system_test/internal/api/util/test"
 
 	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
 	"github.com/stretchr/testify/require"
 )
@@ -32,9 +32,7 @@ func TestValidatorConfigUpdate(testSetup *testing.T) {
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.Len(t, output, 1, strings.Join(output, "\n"))
 
-		var validatorList []climodel.Validator
-		err = json.Unmarshal([]byte(output[0]), &validatorList)
-		require.Nil(t, err, strings.Join(output, "\n"))
+		validatorList := cliparse.RequireJSON[[]climodel.Validator](t, output)
 		require.Greater(t, len(validatorList), 0, "validator list is empty")
 
 		intialValidatorInfo = validatorList[0]
@@ -63,9 +61,7 @@ func TestValidatorConfigUpdate(testSetup *testing.T) {
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var finalValidatorInfo climodel.Validator
-		err = json.Unmarshal([]byte(output[0]), &finalValidatorInfo)
-		require.Nil(t, err, strings.Join(output, "\n"))
+		finalValidatorInfo := cliparse.RequireJSON[climodel.Validator](t, output)
 
 		require.Equal(t, newNumberOfDelegates, finalValidatorInfo.NumDelegates)
 	})
@@ -83,9 +79,7 @@ func TestValidatorConfigUpdate(testSetup *testing.T) {
 		require.Nil(t, err, strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		var finalValidatorInfo climodel.Validator
-		err = json.Unmarshal([]byte(output[0]), &finalValidatorInfo)
-		require.Nil(t, err, strings.Join(output, "\n"))
+		finalValidatorInfo := cliparse.RequireJSON[climodel.Validator](t, output)
 
 		require.Equal(t, newServiceCharge, finalValidatorInfo.ServiceCharge)
 	})
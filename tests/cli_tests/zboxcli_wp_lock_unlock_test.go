@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/0chain/system_test/internal/api/util/test"
+	"github.com/0chain/system_test/internal/currency"
 
 	cliutils "github.com/0chain/system_test/internal/cli/util"
 	"github.com/stretchr/testify/require"
@@ -27,10 +28,10 @@ func TestWritePoolLock(testSetup *testing.T) {
 		require.NoError(t, err)
 
 		// Lock 0.5 token for allocation
-		allocParams := createParams(map[string]interface{}{
+		allocParams := map[string]interface{}{
 			"size": "2048",
 			"lock": "1",
-		})
+		}
 		output, err := createNewAllocation(t, configPath, allocParams)
 		require.Nil(t, err, "Failed to create new allocation", strings.Join(output, "\n"))
 		t.Log("new allocation:", output)
@@ -41,7 +42,7 @@ func TestWritePoolLock(testSetup *testing.T) {
 
 		balanceAfterAlloc, err := getBalanceZCN(t, configPath)
 		require.NoError(t, err)
-		require.Less(t, balanceAfterAlloc, balance-0.5)
+		require.Less(t, balanceAfterAlloc, balance-zcn(0.5))
 
 		// Lock 1 token in Write pool amongst all blobbers
 		params := createParams(map[string]interface{}{
@@ -55,18 +56,17 @@ func TestWritePoolLock(testSetup *testing.T) {
 
 		balanceAfterLock, err := getBalanceZCN(t, configPath)
 		require.NoError(t, err)
-		require.Less(t, balanceAfterLock, balanceAfterAlloc-1)
+		require.Less(t, balanceAfterLock, balanceAfterAlloc-zcn(1))
 
 		// Write pool balance should increment by 1
 		allocation := getAllocation(t, allocationID)
-		require.Equal(t, 2.0, intToZCN(allocation.WritePool))
+		requireBalance(t, "2 ZCN", allocation.WritePool)
 
 		allocationCost := 0.0
 		for _, blobber := range allocation.BlobberDetails {
 			allocationCost += sizeInGB(1024) * float64(blobber.Terms.WritePrice)
 		}
-		allocationCancellationCharge := allocationCost * 0.2
-		allocationCancellationChargeInZCN := allocationCancellationCharge / 1e10
+		allocationCancellationCharge := currency.Coin(allocationCost * 0.2)
 
 		// get balance before cancel
 		balanceBeforeCancel, err := getBalanceZCN(t, configPath)
@@ -79,7 +79,7 @@ func TestWritePoolLock(testSetup *testing.T) {
 
 		balanceAfterCancel, err := getBalanceZCN(t, configPath)
 		require.NoError(t, err)
-		require.InEpsilon(t, balanceAfterCancel, balanceBeforeCancel+2.0-allocationCancellationChargeInZCN, 0.05)
+		requireBalanceInEpsilon(t, balanceBeforeCancel+zcn(2)-allocationCancellationCharge, balanceAfterCancel, 0.05)
 	})
 
 	t.Run("Should not be able to lock more write tokens than wallet balance", func(t *test.SystemTest) {
@@ -90,10 +90,10 @@ func TestWritePoolLock(testSetup *testing.T) {
 		require.NoError(t, err)
 
 		// Lock 0.5 token for allocation
-		allocParams := createParams(map[string]interface{}{
+		allocParams := map[string]interface{}{
 			"size": "1024",
 			"lock": "0.5",
-		})
+		}
 		output, err := createNewAllocation(t, configPath, allocParams)
 		require.Nil(t, err, "Failed to create new allocation", strings.Join(output, "\n"))
 
@@ -104,7 +104,7 @@ func TestWritePoolLock(testSetup *testing.T) {
 		// Wallet balance before lock should be 4.5 ZCN
 		balanceAfter, err := getBalanceZCN(t, configPath)
 		require.NoError(t, err)
-		require.Equal(t, balanceBefore-0.5-0.01, balanceAfter)
+		require.Equal(t, balanceBefore-zcn(0.51), balanceAfter)
 		balanceBefore = balanceAfter
 
 		// Lock 10 token in write pool should fail
@@ -120,7 +120,7 @@ func TestWritePoolLock(testSetup *testing.T) {
 		// Wallet balance should remain same (- fee)
 		balanceAfter, err = getBalanceZCN(t, configPath)
 		require.NoError(t, err)
-		require.Equal(t, balanceBefore-0.01, balanceAfter)
+		require.Equal(t, balanceBefore-zcn(0.01), balanceAfter)
 	})
 
 	t.Run("Should not be able to lock negative write tokens", func(t *test.SystemTest) {
@@ -130,10 +130,10 @@ func TestWritePoolLock(testSetup *testing.T) {
 		require.NoError(t, err)
 
 		// Lock 0.5 token for allocation
-		allocParams := createParams(map[string]interface{}{
+		allocParams := map[string]interface{}{
 			"size": "1024",
 			"lock": "0.5",
-		})
+		}
 		output, err := createNewAllocation(t, configPath, allocParams)
 		require.Nil(t, err, "Failed to create new allocation", strings.Join(output, "\n"))
 
@@ -143,7 +143,7 @@ func TestWritePoolLock(testSetup *testing.T) {
 
 		balanceAfter, err := getBalanceZCN(t, configPath)
 		require.NoError(t, err)
-		require.Equal(t, balanceBefore-0.5-0.01, balanceAfter)
+		require.Equal(t, balanceBefore-zcn(0.51), balanceAfter)
 		balanceBefore = balanceAfter
 
 		// Locking -1 token in write pool should not succeed
@@ -169,10 +169,10 @@ func TestWritePoolLock(testSetup *testing.T) {
 		require.NoError(t, err)
 
 		// Lock 0.5 token for allocation
-		allocParams := createParams(map[string]interface{}{
+		allocParams := map[string]interface{}{
 			"size": "1024",
 			"lock": "0.5",
-		})
+		}
 		output, err := createNewAllocation(t, configPath, allocParams)
 		require.Nil(t, err, "Failed to create new allocation", strings.Join(output, "\n"))
 
@@ -183,7 +183,7 @@ func TestWritePoolLock(testSetup *testing.T) {
 		balanceAfter, err := getBalanceZCN(t, configPath)
 		require.NoError(t, err)
 
-		require.InEpsilon(t, balanceBefore-0.51, balanceAfter, 0.01)
+		requireBalanceInEpsilon(t, balanceBefore-zcn(0.51), balanceAfter, 0.01)
 		balanceBefore = balanceAfter
 
 		// Locking 0 token in write pool should not succeed
@@ -199,17 +199,17 @@ func TestWritePoolLock(testSetup *testing.T) {
 		// Wallet balance should remain same (-fee)
 		balanceAfter, err = getBalanceZCN(t, configPath)
 		require.NoError(t, err)
-		require.InEpsilon(t, balanceBefore-0.01, balanceAfter, 0.01)
+		requireBalanceInEpsilon(t, balanceBefore-zcn(0.01), balanceAfter, 0.01)
 	})
 
 	t.Run("Missing tokens flag should result in error", func(t *test.SystemTest) {
 		createWallet(t)
 
 		// Lock 0.5 token for allocation
-		allocParams := createParams(map[string]interface{}{
+		allocParams := map[string]interface{}{
 			"size": "1024",
 			"lock": "0.5",
-		})
+		}
 		output, err := createNewAllocation(t, configPath, allocParams)
 		require.Nil(t, err, "Failed to create new allocation", strings.Join(output, "\n"))
 
@@ -238,6 +238,6 @@ func writePoolLockWithWallet(t *test.SystemTest, wallet, cliConfigFilename, para
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
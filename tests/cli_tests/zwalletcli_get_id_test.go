@@ -64,6 +64,6 @@ func getId(t *test.SystemTest, cliConfigFilename, url string, retry bool) ([]str
 	if retry {
 		return cliutils.RunCommand(t, fmt.Sprintf("./zwallet getid --silent --configDir ./config --url %s --config %s", url, cliConfigFilename), 3, time.Second)
 	} else {
-		return cliutils.RunCommandWithoutRetry(fmt.Sprintf("./zwallet getid --silent --configDir ./config --url %s --config %s", url, cliConfigFilename))
+		return cliutils.RunCommandWithoutRetry(t, fmt.Sprintf("./zwallet getid --silent --configDir ./config --url %s --config %s", url, cliConfigFilename))
 	}
 }
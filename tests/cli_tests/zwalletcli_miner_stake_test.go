@@ -16,6 +16,7 @@ import (
 	"github.com/0chain/system_test/internal/api/util/test"
 
 	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
 	"github.com/stretchr/testify/require"
 )
@@ -37,8 +38,7 @@ func TestMinerStake(testSetup *testing.T) {
 		require.NoError(t, err, "error listing miners")
 		require.Len(t, output, 1)
 
-		err = json.Unmarshal([]byte(output[0]), &miners)
-		require.Nil(t, err, "error unmarshalling ls-miners json output")
+		miners = cliparse.RequireJSON[climodel.MinerSCNodes](t, output)
 
 		for _, miner = range miners.Nodes {
 			if miner.ID == miner01ID {
@@ -62,7 +62,7 @@ func TestMinerStake(testSetup *testing.T) {
 
 		poolsInfo, err := pollForPoolInfo(t, miner.ID)
 		require.Nil(t, err)
-		require.Equal(t, float64(2.0), intToZCN(poolsInfo.Balance))
+		requireBalance(t, "2 ZCN", poolsInfo.Balance)
 
 		// Unlock should work
 		output, err = minerOrSharderUnlock(t, configPath, createParams(map[string]interface{}{
@@ -84,12 +84,10 @@ func TestMinerStake(testSetup *testing.T) {
 	t.RunWithTimeout("Multiple stakes against a miner should add balance to client's stake pool", 5*time.Minute, func(t *test.SystemTest) {
 		createWallet(t)
 
-		var poolsInfoBefore climodel.MinerSCUserPoolsInfo
 		output, err := stakePoolsInMinerSCInfo(t, configPath, "", true)
 		require.Nil(t, err, "error fetching Miner SC User pools")
 		require.Len(t, output, 1)
-		err = json.Unmarshal([]byte(output[0]), &poolsInfoBefore)
-		require.Nil(t, err, "error unmarshalling Miner SC user pool info")
+		cliparse.RequireJSON[climodel.MinerSCUserPoolsInfo](t, output)
 
 		output, err = minerOrSharderLock(t, configPath, createParams(map[string]interface{}{
 			"miner_id": miner.ID,
@@ -116,8 +114,7 @@ func TestMinerStake(testSetup *testing.T) {
 		require.Nil(t, err, "error fetching Miner SC User pools")
 		require.Len(t, output, 1)
 
-		err = json.Unmarshal([]byte(output[0]), &poolsInfo)
-		require.NoError(t, err)
+		poolsInfo = cliparse.RequireJSON[climodel.MinerSCUserPoolsInfo](t, output)
 		require.Len(t, poolsInfo.Pools[miner.ID], 1)
 	})
 
@@ -213,7 +210,7 @@ func TestMinerStake(testSetup *testing.T) {
 				defer wg.Done()
 
 				walletName := escapedTestName(t) + fmt.Sprintf("%d", i)
-				createWalletForName(walletName)
+				createWalletForName(t, walletName)
 
 				output, err = minerOrSharderLockForWallet(t, configPath, createParams(map[string]interface{}{
 					"miner_id": newMiner.ID,
@@ -288,8 +285,7 @@ func pollForPoolInfo(t *test.SystemTest, minerID string) (climodel.DelegatePool,
 		require.Nil(t, err, "error fetching Miner Sharder pools")
 		require.Len(t, output, 1)
 
-		err = json.Unmarshal([]byte(output[0]), &poolsInfo)
-		require.Nil(t, err, "error unmarshalling Miner Sharder pools")
+		poolsInfo = cliparse.RequireJSON[climodel.DelegatePool](t, output)
 		require.NotEmpty(t, poolsInfo)
 
 		if poolsInfo.Status == int(climodel.Active) {
@@ -313,7 +309,7 @@ func minerSharderPoolInfoForWallet(t *test.SystemTest, cliConfigFilename, params
 	if retry {
 		return cliutils.RunCommand(t, fmt.Sprintf("./zwallet mn-pool-info %s --silent --wallet %s_wallet.json --configDir ./config --config %s", params, wallet, cliConfigFilename), 3, time.Second)
 	} else {
-		return cliutils.RunCommandWithoutRetry(fmt.Sprintf("./zwallet mn-pool-info %s --silent --wallet %s_wallet.json --configDir ./config --config %s", params, wallet, cliConfigFilename))
+		return cliutils.RunCommandWithoutRetry(t, fmt.Sprintf("./zwallet mn-pool-info %s --silent --wallet %s_wallet.json --configDir ./config --config %s", params, wallet, cliConfigFilename))
 	}
 }
 
@@ -323,9 +319,7 @@ func getBalanceFromSharders(t *test.SystemTest, clientId string) int64 {
 	require.Greater(t, len(output), 1)
 	require.Equal(t, "MagicBlock Sharders", output[0])
 
-	var sharders map[string]*climodel.Sharder
-	err = json.Unmarshal([]byte(strings.Join(output[1:], "")), &sharders)
-	require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output[1:], "\n"), err)
+	sharders := cliparse.RequireJSON[map[string]*climodel.Sharder](t, output)
 	require.NotEmpty(t, sharders, "No sharders found: %v", strings.Join(output[1:], "\n"))
 
 	// Get base URL for API calls.
@@ -358,7 +352,7 @@ func minerOrSharderLockForWallet(t *test.SystemTest, cliConfigFilename, params,
 	if retry {
 		return cliutils.RunCommand(t, fmt.Sprintf("./zwallet mn-lock %s --silent --wallet %s_wallet.json --configDir ./config --config %s", params, wallet, cliConfigFilename), 3, time.Second)
 	} else {
-		return cliutils.RunCommandWithoutRetry(fmt.Sprintf("./zwallet mn-lock %s --silent --wallet %s_wallet.json --configDir ./config --config %s", params, wallet, cliConfigFilename))
+		return cliutils.RunCommandWithoutRetry(t, fmt.Sprintf("./zwallet mn-lock %s --silent --wallet %s_wallet.json --configDir ./config --config %s", params, wallet, cliConfigFilename))
 	}
 }
 
@@ -371,6 +365,6 @@ func minerOrSharderUnlockForWallet(t *test.SystemTest, cliConfigFilename, params
 	if retry {
 		return cliutils.RunCommand(t, fmt.Sprintf("./zwallet mn-unlock %s --silent --wallet %s_wallet.json --configDir ./config --config %s", params, wallet, cliConfigFilename), 3, time.Second)
 	} else {
-		return cliutils.RunCommandWithoutRetry(fmt.Sprintf("./zwallet mn-unlock %s --silent --wallet %s_wallet.json --configDir ./config --config %s", params, wallet, cliConfigFilename))
+		return cliutils.RunCommandWithoutRetry(t, fmt.Sprintf("./zwallet mn-unlock %s --silent --wallet %s_wallet.json --configDir ./config --config %s", params, wallet, cliConfigFilename))
 	}
 }
@@ -494,7 +494,7 @@ func getMinerSCConfig(t *test.SystemTest, cliConfigFilename string, retry bool)
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
@@ -511,7 +511,7 @@ func updateMinerSCConfig(t *test.SystemTest, walletName string, param map[string
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*5)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
@@ -539,7 +539,7 @@ func isUpdateSuccess(output []string) bool {
 // 	if retry {
 // 		return cliutils.RunCommand(t, cmd, 3, time.Second*5)
 // 	} else {
-// 		return cliutils.RunCommandWithoutRetry(cmd)
+// 		return cliutils.RunCommandWithoutRetry(t, cmd)
 // 	}
 // }
 
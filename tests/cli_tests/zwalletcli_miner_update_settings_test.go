@@ -1,7 +1,6 @@
 package cli_tests
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"regexp"
@@ -12,6 +11,7 @@ import (
 	"github.com/0chain/system_test/internal/api/util/test"
 
 	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
 	"github.com/stretchr/testify/require"
 )
@@ -32,15 +32,14 @@ func TestMinerUpdateSettings(testSetup *testing.T) { // nolint cyclomatic comple
 
 		createWallet(t)
 
-		createWalletForName(miner01NodeDelegateWalletName)
+		createWalletForName(t, miner01NodeDelegateWalletName)
 
 		mnConfig = getMinerSCConfiguration(t)
 		output, err := listMiners(t, configPath, "--json")
 		require.Nil(t, err, "error listing miners")
 		require.Len(t, output, 1)
 
-		err = json.Unmarshal([]byte(output[0]), &miners)
-		require.Nil(t, err, "error unmarshalling ls-miners json output")
+		miners = cliparse.RequireJSON[climodel.MinerSCNodes](t, output)
 
 		found := false
 		for _, miner = range miners.Nodes {
@@ -98,9 +97,7 @@ func TestMinerUpdateSettings(testSetup *testing.T) { // nolint cyclomatic comple
 		require.Nil(t, err, "error fetching miner info")
 		require.Len(t, output, 1)
 
-		var minerInfo climodel.Node
-		err = json.Unmarshal([]byte(output[0]), &minerInfo)
-		require.Nil(t, err, "error unmarshalling miner info")
+		minerInfo := cliparse.RequireJSON[climodel.Node](t, output)
 		require.Equal(t, 5, minerInfo.Settings.MaxNumDelegates)
 	})
 
@@ -1,7 +1,6 @@
 package cli_tests
 
 import (
-	"encoding/json"
 	"fmt"
 	"regexp"
 	"testing"
@@ -10,6 +9,7 @@ import (
 	"github.com/0chain/system_test/internal/api/util/test"
 
 	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
 	"github.com/stretchr/testify/require"
 )
@@ -29,9 +29,7 @@ func TestMinerSCUserPoolInfo(testSetup *testing.T) {
 		require.Nil(t, err, "error fetching stake pools")
 		require.Len(t, output, 1)
 
-		var poolsInfo climodel.MinerSCUserPoolsInfo
-		err = json.Unmarshal([]byte(output[0]), &poolsInfo)
-		require.Nil(t, err, "error unmarshalling Miner SC User Pool")
+		poolsInfo := cliparse.RequireJSON[climodel.MinerSCUserPoolsInfo](t, output)
 		require.Empty(t, poolsInfo.Pools)
 
 		output, err = minerOrSharderLock(t, configPath, createParams(map[string]interface{}{
@@ -47,11 +45,10 @@ func TestMinerSCUserPoolInfo(testSetup *testing.T) {
 		require.Nil(t, err, "error fetching Miner SC User pools")
 		require.Len(t, output, 1)
 
-		err = json.Unmarshal([]byte(output[0]), &poolsInfo)
-		require.Nil(t, err, "error unmarshalling Miner SC User Pool")
+		poolsInfo = cliparse.RequireJSON[climodel.MinerSCUserPoolsInfo](t, output)
 		require.Len(t, poolsInfo.Pools[miner01ID], 1)
 		require.Equal(t, w.ClientID, poolsInfo.Pools[miner01ID][0].ID)
-		require.Equal(t, float64(5), intToZCN(poolsInfo.Pools[miner01ID][0].Balance))
+		requireBalance(t, "5 ZCN", poolsInfo.Pools[miner01ID][0].Balance)
 
 		// teardown
 		_, err = minerOrSharderUnlock(t, configPath, createParams(map[string]interface{}{
@@ -70,9 +67,7 @@ func TestMinerSCUserPoolInfo(testSetup *testing.T) {
 		require.Nil(t, err, "error fetching stake pools")
 		require.Len(t, output, 1)
 
-		var poolsInfo climodel.MinerSCUserPoolsInfo
-		err = json.Unmarshal([]byte(output[0]), &poolsInfo)
-		require.Nil(t, err, "error unmarshalling Miner SC User Pool")
+		poolsInfo := cliparse.RequireJSON[climodel.MinerSCUserPoolsInfo](t, output)
 		require.Empty(t, poolsInfo.Pools)
 
 		output, err = minerOrSharderLock(t, configPath, createParams(map[string]interface{}{
@@ -90,11 +85,10 @@ func TestMinerSCUserPoolInfo(testSetup *testing.T) {
 		require.Nil(t, err, "error fetching Miner SC User pools")
 		require.Len(t, output, 1)
 
-		err = json.Unmarshal([]byte(output[0]), &poolsInfo)
-		require.Nil(t, err, "error unmarshalling Miner SC User Pool")
+		poolsInfo = cliparse.RequireJSON[climodel.MinerSCUserPoolsInfo](t, output)
 		require.Len(t, poolsInfo.Pools[sharder01ID], 1)
 		require.Equal(t, w.ClientID, poolsInfo.Pools[sharder01ID][0].ID)
-		require.Equal(t, float64(5), intToZCN(poolsInfo.Pools[sharder01ID][0].Balance))
+		requireBalance(t, "5 ZCN", poolsInfo.Pools[sharder01ID][0].Balance)
 
 		// teardown
 		_, err = minerOrSharderUnlock(t, configPath, createParams(map[string]interface{}{
@@ -112,7 +106,7 @@ func TestMinerSCUserPoolInfo(testSetup *testing.T) {
 		require.Nil(t, err, "error fetching wallet")
 
 		targetWalletName := escapedTestName(t) + "_target"
-		createWalletForName(targetWalletName)
+		createWalletForName(t, targetWalletName)
 		require.Nil(t, err, "error creating wallet")
 
 		output, err := minerOrSharderLock(t, configPath, createParams(map[string]interface{}{
@@ -138,20 +132,18 @@ func TestMinerSCUserPoolInfo(testSetup *testing.T) {
 		require.Nil(t, err, "error fetching Miner SC User Pools")
 		require.Len(t, output, 1)
 
-		var poolsInfo climodel.MinerSCUserPoolsInfo
-		err = json.Unmarshal([]byte(output[0]), &poolsInfo)
-		require.Nil(t, err, "error unmarshalling Miner SC User Pools")
+		poolsInfo := cliparse.RequireJSON[climodel.MinerSCUserPoolsInfo](t, output)
 
 		w, err := getWallet(t, configPath)
 		require.NoError(t, err)
 
 		require.Len(t, poolsInfo.Pools[miner01ID], 1)
 		require.Equal(t, w.ClientID, poolsInfo.Pools[miner01ID][0].ID)
-		require.Equal(t, float64(4), intToZCN(poolsInfo.Pools[miner01ID][0].Balance))
+		requireBalance(t, "4 ZCN", poolsInfo.Pools[miner01ID][0].Balance)
 
 		require.Len(t, poolsInfo.Pools[sharder01ID], 1)
 		require.Equal(t, w.ClientID, poolsInfo.Pools[sharder01ID][0].ID)
-		require.Equal(t, float64(4), intToZCN(poolsInfo.Pools[sharder01ID][0].Balance))
+		requireBalance(t, "4 ZCN", poolsInfo.Pools[sharder01ID][0].Balance)
 
 		// teardown
 		_, err = minerOrSharderUnlock(t, configPath, createParams(map[string]interface{}{
@@ -179,6 +171,6 @@ func stakePoolsInMinerSCInfoForWallet(t *test.SystemTest, cliConfigFilename, par
 	if retry {
 		return cliutils.RunCommand(t, fmt.Sprintf("./zwallet mn-user-info %s --json --silent --wallet %s_wallet.json --configDir ./config --config %s", params, wallet, cliConfigFilename), 3, time.Second)
 	} else {
-		return cliutils.RunCommandWithoutRetry(fmt.Sprintf("./zwallet mn-user-info %s --json --silent --wallet %s_wallet.json --configDir ./config --config %s", params, wallet, cliConfigFilename))
+		return cliutils.RunCommandWithoutRetry(t, fmt.Sprintf("./zwallet mn-user-info %s --json --silent --wallet %s_wallet.json --configDir ./config --config %s", params, wallet, cliConfigFilename))
 	}
 }
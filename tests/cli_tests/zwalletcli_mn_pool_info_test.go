@@ -1,7 +1,6 @@
 package cli_tests
 
 import (
-	"encoding/json"
 	"os"
 	"regexp"
 	"testing"
@@ -9,6 +8,7 @@ import (
 	"github.com/0chain/system_test/internal/api/util/test"
 
 	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	"github.com/stretchr/testify/require"
 )
 
@@ -64,15 +64,13 @@ func TestMinerSharderPoolInfo(testSetup *testing.T) {
 		require.Len(t, output, 1)
 		require.Regexp(t, lockOutputRegex, output[0])
 
-		var poolsInfo climodel.DelegatePool
 		output, err = minerSharderPoolInfo(t, configPath, createParams(map[string]interface{}{
 			"id": miner.ID,
 		}), true)
 		require.Nil(t, err, "error fetching Miner Sharder pools")
 		require.Len(t, output, 1)
 
-		err = json.Unmarshal([]byte(output[0]), &poolsInfo)
-		require.Nil(t, err, "error unmarshalling Miner Sharder pools")
+		cliparse.RequireJSON[climodel.DelegatePool](t, output)
 	})
 
 	t.Run("Miner pool info after locking against sharder should work", func(t *test.SystemTest) {
@@ -86,16 +84,13 @@ func TestMinerSharderPoolInfo(testSetup *testing.T) {
 		require.Len(t, output, 1)
 		require.Regexp(t, lockOutputRegex, output[0])
 
-		var poolsInfo climodel.DelegatePool
-
 		output, err = minerSharderPoolInfo(t, configPath, createParams(map[string]interface{}{
 			"id": sharder.ID,
 		}), true)
 		require.Nil(t, err, "error fetching Miner Sharder pools")
 		require.Len(t, output, 1)
 
-		err = json.Unmarshal([]byte(output[0]), &poolsInfo)
-		require.Nil(t, err, "error unmarshalling Miner Sharder pools")
+		cliparse.RequireJSON[climodel.DelegatePool](t, output)
 	})
 
 	t.Run("Miner/Sharder pool info for invalid node id should fail", func(t *test.SystemTest) {
@@ -45,9 +45,9 @@ func TestRecoverWallet(testSetup *testing.T) {
 	})
 
 	t.Run("Recover wallet no mnemonic", func(t *test.SystemTest) {
-		output, err := cliutils.RunCommandWithoutRetry("./zwallet recoverwallet --silent " +
-			"--wallet " + escapedTestName(t) + "_wallet.json" + " " +
-			"--configDir ./config --config " + configPath)
+		output, err := cliutils.RunCommandWithoutRetry(t, "./zwallet recoverwallet --silent "+
+			"--wallet "+escapedTestName(t)+"_wallet.json"+" "+
+			"--configDir ./config --config "+configPath)
 
 		require.NotNil(t, err, "expected error to occur recovering a wallet", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
@@ -64,6 +64,6 @@ func recoverWalletFromMnemonic(t *test.SystemTest, configPath, mnemonic string,
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
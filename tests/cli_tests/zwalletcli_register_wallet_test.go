@@ -1,24 +1,27 @@
 package cli_tests
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/0chain/system_test/internal/api/util/test"
+	"github.com/0chain/system_test/internal/api/util/walletpool"
+	"github.com/0chain/system_test/internal/currency"
 
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
 
 	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
 )
 
 func createWalletAndLockReadTokens(t *test.SystemTest, cliConfigFilename string) error {
-	createWalletForName(escapedTestName(t))
+	createWalletForName(t, escapedTestName(t))
 
 	// Lock half the tokens for read pool
 	readPoolParams := createParams(map[string]interface{}{
@@ -33,10 +36,11 @@ func createWalletAndLockReadTokens(t *test.SystemTest, cliConfigFilename string)
 }
 
 func createWallet(t *test.SystemTest) {
-	createWalletForName(escapedTestName(t))
+	createWalletForName(t, escapedTestName(t))
 }
 
-func createWalletForName(name string) {
+// createWalletForName leases a pooled wallet to name for the lifetime of t, unless name already has a wallet file
+func createWalletForName(t *test.SystemTest, name string) {
 	walletPath := fmt.Sprintf("./config/%s_wallet.json", name)
 
 	// check if wallet already exists
@@ -44,23 +48,104 @@ func createWalletForName(name string) {
 		return
 	}
 
-	walletMutex.Lock()
-	wallet := wallets[walletIdx]
-	walletIdx++
-	log.Println("Creating wallet for", name, "with walletIdx", walletIdx)
-	walletMutex.Unlock()
+	// registered before leasing so that the wallet is released while its file still exists
+	t.Cleanup(func() {
+		_ = os.Remove(walletPath)
+	})
+
+	wallet := walletPool.Lease(t, name)
+	log.Println("Created wallet for", name, "with walletIdx", wallet.Index)
+}
+
+func writePooledWalletFile(wallet *walletpool.Wallet) error {
+	walletPath := fmt.Sprintf("./config/%s_wallet.json", wallet.Holder)
+	if err := os.WriteFile(walletPath, wallet.Raw, 0600); err != nil {
+		return fmt.Errorf("writing file %s: %w", walletPath, err)
+	}
+	return nil
+}
+
+func pooledWalletBalance(t *test.SystemTest, wallet *walletpool.Wallet) (currency.Coin, error) {
+	output, err := getBalanceForWalletJSON(t, configPath, wallet.Holder)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", err, strings.Join(output, "\n"))
+	}
+	return parseBalance(output)
+}
 
-	err := os.WriteFile(walletPath, wallet, 0600)
+func topUpPooledWallet(t *test.SystemTest, wallet *walletpool.Wallet, amount currency.Coin) error {
+	output, err := executeFaucetWithTokensForWallet(t, wallet.Holder, configPath, amount.Decimal(currency.ZCN).InexactFloat64())
 	if err != nil {
-		fmt.Printf("Error writing file %s: %v\n", walletPath, err)
+		return fmt.Errorf("%w: %s", err, strings.Join(output, "\n"))
+	}
+	return nil
+}
+
+// drainPooledWallet unlocks the read pool and the stake pools of the wallet. Write pools stay locked with their
+// allocation until it is finalized or cancelled, which is up to the test owning it, so they are only reported.
+func drainPooledWallet(t *test.SystemTest, wallet *walletpool.Wallet) error {
+	global := zboxFlags(wallet.Holder, configPath)
+	var errs []error
+
+	// the read pool may be empty, so the failure is not an error
+	_, _ = cliutils.Zbox("rp-unlock", nil, global).RunWithoutRetry(t)
+
+	output, err := cliutils.Zbox("sp-user-info", cliutils.Flags{"json": nil}, global).Run(t, 3, time.Second*2)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("listing stake pools: %w: %s", err, strings.Join(output, "\n")))
 	} else {
-		fmt.Printf("File %s written successfully.\n", walletPath)
+		var stakePools climodel.StakePoolUserInfo
+		if _, err := cliparse.JSON(output, &stakePools); err != nil {
+			errs = append(errs, fmt.Errorf("decoding stake pools: %w", err))
+		}
+		for providerID, delegates := range stakePools.Pools {
+			if len(delegates) == 0 {
+				continue
+			}
+			var unlock cliutils.Command
+			switch delegates[0].ProviderType {
+			case climodel.ProviderBlobber:
+				unlock = cliutils.Zbox("sp-unlock", cliutils.ZboxStakeUnlock{BlobberID: providerID}, global)
+			case climodel.ProviderValidator:
+				unlock = cliutils.Zbox("sp-unlock", cliutils.ZboxStakeUnlock{ValidatorID: providerID}, global)
+			case climodel.ProviderMiner:
+				unlock = cliutils.Zwallet("mn-unlock", cliutils.ZwalletMinerUnlock{MinerID: providerID}, global)
+			case climodel.ProviderSharder:
+				unlock = cliutils.Zwallet("mn-unlock", cliutils.ZwalletMinerUnlock{SharderID: providerID}, global)
+			default:
+				errs = append(errs, fmt.Errorf("stake pool of %s %s cannot be unlocked", delegates[0].ProviderType, providerID))
+				continue
+			}
+			if output, err := unlock.Run(t, 3, time.Second*2); err != nil {
+				errs = append(errs, fmt.Errorf("unlocking stake pool of %s: %w: %s", providerID, err, strings.Join(output, "\n")))
+			}
+		}
 	}
+
+	output, err = cliutils.Zbox("listallocations", cliutils.Flags{"json": nil}, global).Run(t, 3, time.Second*2)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("listing allocations: %w: %s", err, strings.Join(output, "\n")))
+		return errors.Join(errs...)
+	}
+	var allocations []climodel.Allocation
+	if _, err := cliparse.JSON(output, &allocations); err != nil {
+		return errors.Join(append(errs, fmt.Errorf("decoding allocations: %w", err))...)
+	}
+	var locked currency.Coin
+	for i := range allocations {
+		if !allocations[i].Finalized && !allocations[i].Canceled {
+			locked += currency.Coin(allocations[i].WritePool)
+		}
+	}
+	if locked > 0 {
+		t.Logf("Wallet [%s] is released with [%s] locked in the write pools of its allocations", wallet.ClientID, locked)
+	}
+	return errors.Join(errs...)
 }
 
 func createWalletForNameAndLockReadTokens(t *test.SystemTest, cliConfigFilename, name string) {
 	var tokens = 2.0
-	createWalletForName(name)
+	createWalletForName(t, name)
 	readPoolParams := createParams(map[string]interface{}{
 		"tokens": tokens / 2,
 	})
@@ -73,7 +158,8 @@ func getBalance(t *test.SystemTest, cliConfigFilename string) ([]string, error)
 	return getBalanceForWallet(t, cliConfigFilename, escapedTestName(t))
 }
 
-func getBalanceZCN(t *test.SystemTest, cliConfigFilename string, walletName ...string) (float64, error) {
+// getBalanceZCN returns the balance of the wallet of the test, or of walletName if given
+func getBalanceZCN(t *test.SystemTest, cliConfigFilename string, walletName ...string) (currency.Coin, error) {
 	cliutils.Wait(t, 5*time.Second)
 	var (
 		output []string
@@ -91,21 +177,31 @@ func getBalanceZCN(t *test.SystemTest, cliConfigFilename string, walletName ...s
 		}
 	}
 
+	return parseBalance(output)
+}
+
+// parseBalance reads the balance printed by zwallet getbalance --json
+func parseBalance(output []string) (currency.Coin, error) {
 	var balance = struct {
 		ZCN string `json:"zcn"`
 	}{}
-
-	if err := json.Unmarshal([]byte(output[0]), &balance); err != nil {
+	if _, err := cliparse.JSON(output, &balance); err != nil {
 		return 0, err
 	}
+	return currency.ParseCoin(balance.ZCN)
+}
 
-	balanceFloat, err := strconv.ParseFloat(balance.ZCN, 64)
-	if err != nil {
-		return 0, err
-	}
+// zcn converts an amount of ZCN, as written in the tests, to a Coin
+func zcn(tokens float64) currency.Coin {
+	return currency.Coin(currency.ZCNToSAS(tokens))
+}
 
-	// round up to 2 decimal places
-	return float64(int(balanceFloat*100)) / 100, nil
+// requireBalanceInEpsilon checks that the balance actual is within the relative error epsilon of expected
+func requireBalanceInEpsilon(t *test.SystemTest, expected, actual currency.Coin, epsilon float64, msgAndArgs ...interface{}) {
+	diff := currency.Diff(actual, expected).Abs()
+	if diff.GreaterThan(expected.Decimal(currency.SAS).Mul(decimal.NewFromFloat(epsilon))) {
+		require.Fail(t, fmt.Sprintf("balance %s is not within %v of the expected %s", actual, epsilon, expected), msgAndArgs...)
+	}
 }
 
 func getBalanceForWallet(t *test.SystemTest, cliConfigFilename, wallet string) ([]string, error) {
@@ -131,25 +227,13 @@ func getWalletForName(t *test.SystemTest, cliConfigFilename, name string) (*clim
 		return nil, err
 	}
 
-	require.True(t, len(output) == 1 || len(output) == 2)
-
 	var wallet *climodel.Wallet
-	if len(output) == 1 {
-		err = json.Unmarshal([]byte(output[0]), &wallet)
-		if err != nil {
-			t.Errorf("failed to unmarshal the result into wallet")
-			return nil, err
-		}
-	} else {
-		require.EqualValues(t, "ZCN wallet created", output[0])
-		err = json.Unmarshal([]byte(output[1]), &wallet)
-		if err != nil {
-			t.Errorf("failed to unmarshal the result into wallet")
-			return nil, err
-		}
+	if _, err := cliparse.JSON(output, &wallet); err != nil {
+		t.Errorf("failed to unmarshal the result into wallet: %v", err)
+		return nil, err
 	}
 
-	return wallet, err
+	return wallet, nil
 }
 
 func escapedTestName(t *test.SystemTest) string {
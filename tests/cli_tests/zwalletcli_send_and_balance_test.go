@@ -1,7 +1,6 @@
 package cli_tests
 
 import (
-	"encoding/json"
 	"fmt"
 	"regexp"
 	"strconv"
@@ -10,10 +9,12 @@ import (
 	"time"
 
 	"github.com/0chain/system_test/internal/api/util/test"
+	"github.com/0chain/system_test/internal/currency"
 
 	"github.com/stretchr/testify/require"
 
 	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
 )
 
@@ -31,7 +32,7 @@ func TestSendAndBalance(testSetup *testing.T) {
 
 		createWallet(t)
 
-		createWalletForName(targetWallet)
+		createWalletForName(t, targetWallet)
 
 		target, err := getWalletForName(t, configPath, targetWallet)
 		require.Nil(t, err, "Error occurred when retrieving target wallet")
@@ -49,7 +50,7 @@ func TestSendAndBalance(testSetup *testing.T) {
 
 		createWallet(t)
 
-		createWalletForName(targetWallet)
+		createWalletForName(t, targetWallet)
 
 		target, err := getWalletForName(t, configPath, targetWallet)
 		require.Nil(t, err, "Error occurred when retrieving target wallet")
@@ -60,9 +61,7 @@ func TestSendAndBalance(testSetup *testing.T) {
 		require.Nil(t, err, "Unexpected send failure", strings.Join(output, "\n"))
 		require.Len(t, output, 1)
 
-		sendTxnOutput := &climodel.SendTransaction{}
-		err = json.Unmarshal([]byte(output[0]), sendTxnOutput)
-		require.Nil(t, err, "error unmarshalling send txn json response")
+		sendTxnOutput := cliparse.RequireJSON[climodel.SendTransaction](t, output)
 		require.Equal(t, "success", sendTxnOutput.Status)
 		require.NotEmpty(t, sendTxnOutput.Txn)
 		require.NotEmpty(t, sendTxnOutput.Nonce)
@@ -73,7 +72,7 @@ func TestSendAndBalance(testSetup *testing.T) {
 
 		createWallet(t)
 
-		createWalletForName(targetWallet)
+		createWalletForName(t, targetWallet)
 
 		target, err := getWalletForName(t, configPath, targetWallet)
 		require.Nil(t, err, "Error occurred when retrieving target wallet")
@@ -98,19 +97,19 @@ func TestSendAndBalance(testSetup *testing.T) {
 		// After send balance checks
 		srcBalanceAfter, err := getBalanceZCN(t, configPath)
 		require.NoError(t, err)
-		require.Equal(t, srcBalanceBefore-1.01, srcBalanceAfter)
+		require.Equal(t, srcBalanceBefore-zcn(1.01), srcBalanceAfter)
 
 		targetBalanceAfter, err := getBalanceZCN(t, configPath, targetWallet)
 		require.Nil(t, err, "Unexpected balance check failure for wallet", targetWallet, strings.Join(output, "\n"))
-		require.Equal(t, targetBalanceBefore+1, targetBalanceAfter)
+		require.Equal(t, targetBalanceBefore+zcn(1), targetBalanceAfter)
 	})
 
 	t.Run("Send without description should fail", func(t *test.SystemTest) {
 		createWallet(t)
 
-		output, err := cliutils.RunCommandWithoutRetry("./zwallet send --silent --tokens 1" +
-			" --to_client_id 7ec733204418d72b68e3579bdf55881b1528c676850976920de3f73e45d4fafa" +
-			" --wallet " + escapedTestName(t) + "_wallet.json --configDir ./config --config " + configPath,
+		output, err := cliutils.RunCommandWithoutRetry(t, "./zwallet send --silent --tokens 1"+
+			" --to_client_id 7ec733204418d72b68e3579bdf55881b1528c676850976920de3f73e45d4fafa"+
+			" --wallet "+escapedTestName(t)+"_wallet.json --configDir ./config --config "+configPath,
 		)
 		require.NotNil(t, err, "Expected send to fail", strings.Join(output, "\n"))
 
@@ -125,7 +124,7 @@ func TestSendAndBalance(testSetup *testing.T) {
 		_, err := executeFaucetWithTokens(t, configPath, 0.1)
 		require.Nil(t, err, "Error occurred when executing faucet")
 
-		createWalletForName(targetWallet)
+		createWalletForName(t, targetWallet)
 
 		target, err := getWalletForName(t, configPath, targetWallet)
 		require.Nil(t, err, "Error occurred when retrieving target wallet")
@@ -157,7 +156,7 @@ func TestSendAndBalance(testSetup *testing.T) {
 
 		createWallet(t)
 
-		createWalletForName(targetWallet)
+		createWalletForName(t, targetWallet)
 
 		target, err := getWalletForName(t, configPath, targetWallet)
 		require.Nil(t, err, "Error occurred when retrieving target wallet")
@@ -178,13 +177,13 @@ func TestSendAndBalance(testSetup *testing.T) {
 		balance, err := getBalanceZCN(t, configPath)
 		require.NoError(t, err)
 
-		createWalletForName(targetWallet)
+		createWalletForName(t, targetWallet)
 
 		target, err := getWalletForName(t, configPath, targetWallet)
 		require.Nil(t, err, "Error occurred when retrieving target wallet")
 
 		wantFailureMsg := `Send tokens failed. submit transaction failed. {"error":"insufficient balance to send"}`
-		tokens := strconv.Itoa(int(balance) + 1)
+		tokens := strconv.FormatInt(balance.Decimal(currency.ZCN).IntPart()+1, 10)
 
 		output, err := sendZCN(t, configPath, target.ClientID, tokens, "", createParams(map[string]interface{}{}), false)
 		require.NotNil(t, err, "Expected send to fail", strings.Join(output, "\n"))
@@ -198,7 +197,7 @@ func TestSendAndBalance(testSetup *testing.T) {
 
 		createWallet(t)
 
-		createWalletForName(targetWallet)
+		createWalletForName(t, targetWallet)
 
 		target, err := getWalletForName(t, configPath, targetWallet)
 		require.Nil(t, err, "Error occurred when retrieving target wallet")
@@ -217,7 +216,7 @@ func TestSendAndBalance(testSetup *testing.T) {
 
 		createWallet(t)
 
-		createWalletForName(targetWallet)
+		createWalletForName(t, targetWallet)
 
 		target, err := getWalletForName(t, configPath, targetWallet)
 		require.Nil(t, err, "Error occurred when retrieving target wallet")
@@ -264,7 +263,7 @@ func sendZCN(t *test.SystemTest, cliConfigFilename, toClientID, tokens, desc, pa
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
@@ -304,9 +303,7 @@ func getShardersListForWallet(t *test.SystemTest, wallet string) map[string]clim
 	require.Greater(t, len(output), 0)
 	require.Equal(t, "MagicBlock Sharders", output[0])
 
-	var sharders map[string]climodel.Sharder
-	err = json.Unmarshal([]byte(strings.Join(output[1:], "")), &sharders)
-	require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output[1:], "\n"), err)
+	sharders := cliparse.RequireJSON[map[string]climodel.Sharder](t, output)
 	require.NotEmpty(t, sharders, "No sharders found: %v", strings.Join(output[1:], "\n"))
 
 	return sharders
@@ -316,11 +313,8 @@ func getMinersListForWallet(t *test.SystemTest, wallet string) climodel.NodeList
 	// Get miner list.
 	output, err := getMinersForWallet(t, configPath, wallet)
 	require.Nil(t, err, "get miners failed", strings.Join(output, "\n"))
-	require.Greater(t, len(output), 0, "Expected output to have length of at least 1")
 
-	var miners climodel.NodeList
-	err = json.Unmarshal([]byte(output[0]), &miners)
-	require.Nil(t, err, "Error deserializing JSON string `%s`: %v", output[0], err)
+	miners := cliparse.RequireJSON[climodel.NodeList](t, output)
 	require.NotEmpty(t, miners.Nodes, "No miners found: %v", strings.Join(output, "\n"))
 
 	return miners
@@ -330,11 +324,8 @@ func getMinersDetail(t *test.SystemTest, miner_id string) *climodel.Node {
 	// Get miner's node details (this has the total_stake and pools populated).
 	output, err := getNode(t, configPath, miner_id)
 	require.Nil(t, err, "get node %s failed", miner_id, strings.Join(output, "\n"))
-	require.Len(t, output, 1)
 
-	var nodeRes climodel.Node
-	err = json.Unmarshal([]byte(strings.Join(output, "")), &nodeRes)
-	require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output, "\n"), err)
+	nodeRes := cliparse.RequireJSON[climodel.Node](t, output)
 	require.NotEmpty(t, nodeRes, "No node found: %v", strings.Join(output, "\n"))
 	return &nodeRes
 }
@@ -343,11 +334,8 @@ func getMinersList(t *test.SystemTest) *climodel.NodeList {
 	// Get miner list.
 	output, err := getMiners(t, configPath)
 	require.Nil(t, err, "get miners failed", strings.Join(output, "\n"))
-	require.Greater(t, len(output), 0, "Expected output to have length of at least 1")
 
-	var miners climodel.NodeList
-	err = json.Unmarshal([]byte(output[len(output)-1]), &miners)
-	require.Nil(t, err, "Error deserializing JSON string `%s`: %v", output[len(output)-1], err)
+	miners := cliparse.RequireJSON[climodel.NodeList](t, output)
 	require.NotEmpty(t, miners.Nodes, "No miners found: %v", strings.Join(output, "\n"))
 	return &miners
 }
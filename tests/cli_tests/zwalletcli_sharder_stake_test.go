@@ -1,7 +1,6 @@
 package cli_tests
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"regexp"
@@ -13,6 +12,7 @@ import (
 	"github.com/0chain/system_test/internal/api/util/test"
 
 	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
 	"github.com/stretchr/testify/require"
 )
@@ -29,7 +29,7 @@ func TestSharderStake(testSetup *testing.T) {
 
 		createWallet(t)
 
-		createWalletForName(sharder01NodeDelegateWalletName)
+		createWalletForName(t, sharder01NodeDelegateWalletName)
 
 		sharders := getShardersListForWallet(t, sharder01NodeDelegateWalletName)
 
@@ -61,7 +61,7 @@ func TestSharderStake(testSetup *testing.T) {
 
 		poolsInfo, err := pollForPoolInfo(t, sharder.ID)
 		require.Nil(t, err)
-		require.Equal(t, float64(1), intToZCN(poolsInfo.Balance))
+		requireBalance(t, "1 ZCN", poolsInfo.Balance)
 
 		// unlock should work
 		output, err = minerOrSharderUnlock(t, configPath, createParams(map[string]interface{}{
@@ -103,9 +103,7 @@ func TestSharderStake(testSetup *testing.T) {
 		require.Nil(t, err, "error fetching Miner SC User pools")
 		require.Len(t, output, 1)
 
-		var poolsInfo climodel.MinerSCUserPoolsInfo
-		err = json.Unmarshal([]byte(output[0]), &poolsInfo)
-		require.NoError(t, err, "error unmarshalling Miner SC User Pool")
+		poolsInfo := cliparse.RequireJSON[climodel.MinerSCUserPoolsInfo](t, output)
 		require.Len(t, poolsInfo.Pools[sharder.ID], 1)
 
 		require.Equal(t, poolsInfo.Pools[sharder.ID][0].Balance, int64(4e10))
@@ -1,7 +1,6 @@
 package cli_tests
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"regexp"
@@ -12,6 +11,7 @@ import (
 	"github.com/0chain/system_test/internal/api/util/test"
 
 	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
 	"github.com/stretchr/testify/require"
 )
@@ -40,9 +40,7 @@ func TestSharderUpdateSettings(testSetup *testing.T) { //nolint cyclomatic compl
 		require.Nil(t, err, "error fetching sharder settings")
 		require.Len(t, output, 1)
 
-		var oldSharderInfo climodel.Node
-		err = json.Unmarshal([]byte(output[0]), &oldSharderInfo)
-		require.Nil(t, err, "error unmarhsalling mn-info json output")
+		oldSharderInfo := cliparse.RequireJSON[climodel.Node](t, output)
 		require.NotEmpty(t, oldSharderInfo)
 
 		sharders := getShardersListForWallet(t, sharder01NodeDelegateWalletName)
@@ -111,9 +109,7 @@ func TestSharderUpdateSettings(testSetup *testing.T) { //nolint cyclomatic compl
 		require.Nil(t, err, "error fetching sharder info")
 		require.Len(t, output, 1)
 
-		var sharderInfo climodel.Node
-		err = json.Unmarshal([]byte(output[0]), &sharderInfo)
-		require.Nil(t, err, "error unmarhsalling sharder node info")
+		sharderInfo := cliparse.RequireJSON[climodel.Node](t, output)
 		require.Equal(t, 5, sharderInfo.Settings.MaxNumDelegates)
 	})
 
@@ -228,6 +224,6 @@ func minerSharderUpdateSettingsForWallet(t *test.SystemTest, cliConfigFilename,
 	if retry {
 		return cliutils.RunCommand(t, fmt.Sprintf("./zwallet mn-update-settings %s --silent --wallet %s_wallet.json --configDir ./config --config %s", params, wallet, cliConfigFilename), 3, time.Second)
 	} else {
-		return cliutils.RunCommandWithoutRetry(fmt.Sprintf("./zwallet mn-update-settings %s --silent --wallet %s_wallet.json --configDir ./config --config %s", params, wallet, cliConfigFilename))
+		return cliutils.RunCommandWithoutRetry(t, fmt.Sprintf("./zwallet mn-update-settings %s --silent --wallet %s_wallet.json --configDir ./config --config %s", params, wallet, cliConfigFilename))
 	}
 }
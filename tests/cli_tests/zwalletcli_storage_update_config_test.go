@@ -236,7 +236,7 @@ func updateStorageSCConfig(t *test.SystemTest, walletName string, param map[stri
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*5)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
@@ -257,6 +257,6 @@ func getStorageSCConfig(t *test.SystemTest, cliConfigFilename string, retry bool
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
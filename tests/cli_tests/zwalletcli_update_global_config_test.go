@@ -271,7 +271,7 @@ func getGlobalConfigWithWallet(t *test.SystemTest, walletName string, retry bool
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
@@ -288,6 +288,6 @@ func updateGlobalConfigWithWallet(t *test.SystemTest, walletName string, param m
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*5)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
@@ -13,6 +13,7 @@ import (
 
 func TestZCNBridgeAuthorizerRegisterAndDelete(testSetup *testing.T) { // nolint:gocyclo // team preference is to have codes all within test.
 	t := test.NewSystemTest(testSetup)
+	t.RequireCapability("zwallet auth-sc-register")
 	createWallet(t)
 
 	t.RunSequentially("Register authorizer to DEX smartcontract", func(t *test.SystemTest) {
@@ -30,6 +31,7 @@ func TestZCNBridgeAuthorizerRegisterAndDelete(testSetup *testing.T) { // nolint:
 
 func TestZCNAuthorizerRegisterAndDelete(testSetup *testing.T) {
 	t := test.NewSystemTest(testSetup)
+	t.RequireCapability("zwallet bridge-auth-delete")
 	createWallet(t)
 
 	w, err := getWallet(t, configPath)
@@ -71,7 +73,7 @@ func scRegisterAuthorizer(t *test.SystemTest, authAddress string, retry bool) ([
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
@@ -91,7 +93,7 @@ func scRemoveAuthorizer(t *test.SystemTest, authAddress string, retry bool) ([]s
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
@@ -115,7 +117,7 @@ func registerAuthorizer(t *test.SystemTest, clientID, publicKey, authURL string,
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
@@ -131,6 +133,6 @@ func removeAuthorizer(t *test.SystemTest, clientID string, retry bool) ([]string
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
@@ -14,6 +14,7 @@ import (
 
 func TestReplaceAuthorizerBurnZCNAndMintWZCN(testSetup *testing.T) {
 	t := test.NewSystemTest(testSetup)
+	t.RequireCapability("zwallet bridge-mint-wzcn")
 
 	err := tenderlyClient.InitBalance(ethereumAddress)
 	require.NoError(t, err)
@@ -121,7 +122,7 @@ func burnZCN(t *test.SystemTest, amount string, retry bool) ([]string, error) {
 	if retry {
 		return cliutils.RunCommand(t, cmd, 6, time.Second*10)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
@@ -138,7 +139,7 @@ func mintWZCN(t *test.SystemTest, retry bool) ([]string, error) {
 	if retry {
 		return cliutils.RunCommand(t, cmd, 6, time.Second*10)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
@@ -190,6 +191,6 @@ func getAuthorizersCmd(t *test.SystemTest, retry bool) ([]string, error) {
 	if retry {
 		return cliutils.RunCommand(t, cmd, 6, time.Second*10)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
@@ -9,8 +9,7 @@ import (
 	"testing"
 	"time"
 
-	"github.com/0chain/system_test/internal/api/util/tokenomics"
-
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
 
 	"github.com/0chain/system_test/internal/api/util/test"
@@ -20,6 +19,7 @@ import (
 
 func TestBridgeBurn(testSetup *testing.T) {
 	t := test.NewSystemTest(testSetup)
+	t.RequireCapability("zwallet bridge-burn-zcn")
 
 	t.RunSequentiallyWithTimeout("Burning WZCN tokens on balance, should work", time.Minute*10, func(t *test.SystemTest) {
 		err := tenderlyClient.InitBalance(ethereumAddress)
@@ -96,10 +96,9 @@ func TestBridgeBurn(testSetup *testing.T) {
 		require.Nil(t, err)
 
 		amount := strings.TrimSpace(strings.Split(output[len(output)-2], ":")[1])
-		var amountFloat float64
-		amountFloat, err = strconv.ParseFloat(amount, 32)
+		amountSAS, err := decimal.NewFromString(amount)
 		require.Nil(t, err)
-		require.Equal(t, int64(1), tokenomics.ZcnToInt(amountFloat))
+		requireBalance(t, "1 ZCN", amountSAS.IntPart(), "burn ticket amount")
 
 		nonce := strings.TrimSpace(strings.Split(output[len(output)-3], ":")[1])
 		var nonceInt int
@@ -140,7 +139,7 @@ func burnZcn(t *test.SystemTest, amount string, retry bool) ([]string, error) {
 	if retry {
 		return cliutils.RunCommand(t, cmd, 6, time.Second*10)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
@@ -157,7 +156,7 @@ func burnEth(t *test.SystemTest, amount string, retry bool) ([]string, error) {
 	if retry {
 		return cliutils.RunCommand(t, cmd, 6, time.Second*10)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
@@ -175,7 +174,7 @@ func getZcnBurnTicket(t *test.SystemTest, hash string, retry bool) ([]string, er
 	if retry {
 		return cliutils.RunCommand(t, cmd, 6, time.Second*10)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
@@ -193,6 +192,6 @@ func getWrappedZcnBurnTicket(t *test.SystemTest, hash string, retry bool) ([]str
 	if retry {
 		return cliutils.RunCommand(t, cmd, 6, time.Second*10)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
@@ -29,6 +29,7 @@ const (
 
 func TestEthRegisterAccount(testSetup *testing.T) {
 	t := test.NewSystemTest(testSetup)
+	t.RequireCapability("zwallet bridge-import-account")
 	t.SetSmokeTests("Register ethereum account in local key storage")
 
 	t.RunSequentially("Register ethereum account in local key storage", func(t *test.SystemTest) {
@@ -72,7 +73,7 @@ func importAccount(t *test.SystemTest, password, mnemonic string, retry bool) ([
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
@@ -84,7 +85,7 @@ func listAccounts(t *test.SystemTest, retry bool) ([]string, error) {
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
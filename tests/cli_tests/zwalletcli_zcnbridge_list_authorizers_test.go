@@ -14,6 +14,7 @@ import (
 
 func TestListAuthorizers(testSetup *testing.T) {
 	t := test.NewSystemTest(testSetup)
+	t.RequireCapability("zwallet bridge-list-auth")
 	t.SetSmokeTests("List authorizers should work")
 
 	t.Parallel()
@@ -36,6 +37,6 @@ func getAuthorizersList(t *test.SystemTest, retry bool) ([]string, error) {
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
@@ -14,6 +14,7 @@ import (
 
 func TestBridgeMint(testSetup *testing.T) {
 	t := test.NewSystemTest(testSetup)
+	t.RequireCapability("zwallet bridge-mint-zcn")
 
 	t.RunSequentiallyWithTimeout("Mint WZCN tokens", time.Minute*10, func(t *test.SystemTest) {
 		err := tenderlyClient.InitBalance(ethereumAddress)
@@ -73,7 +74,7 @@ func mintZcnTokens(t *test.SystemTest, retry bool) ([]string, error) {
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
@@ -90,7 +91,7 @@ func mintWrappedZcnTokens(t *test.SystemTest, retry bool) ([]string, error) {
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
@@ -108,6 +109,6 @@ func resetUserNonce(t *test.SystemTest, retry bool) ([]string, error) {
 	if retry {
 		return cliutils.RunCommand(t, cmd, 6, time.Second*10)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
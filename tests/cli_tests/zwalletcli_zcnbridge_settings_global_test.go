@@ -8,7 +8,7 @@ import (
 	"time"
 
 	"github.com/0chain/system_test/internal/api/util/test"
-	"github.com/0chain/system_test/internal/api/util/tokenomics"
+	"github.com/0chain/system_test/internal/currency"
 
 	"github.com/stretchr/testify/require"
 
@@ -32,13 +32,14 @@ type BridgeConfig struct {
 
 func TestZCNBridgeGlobalSettings(testSetup *testing.T) {
 	t := test.NewSystemTest(testSetup)
+	t.RequireCapability("zwallet bridge-config-update")
 	t.SetSmokeTests("should allow update of min_mint_amount")
 
 	defaultParams := getDefaultConfig(t)
 
 	t.RunSequentially("should allow update of min_mint_amount", func(t *test.SystemTest) {
 		t.Cleanup(func() {
-			_ = updateAndVerify(t, "min_mint", fmt.Sprintf("%v", tokenomics.ZcnToInt(defaultParams["min_mint"])))
+			_ = updateAndVerify(t, "min_mint", currency.Coin(defaultParams["min_mint"]).Decimal(currency.ZCN).String())
 		})
 		cfgAfter := updateAndVerify(t, "min_mint", "1")
 
@@ -50,7 +51,7 @@ func TestZCNBridgeGlobalSettings(testSetup *testing.T) {
 
 	t.RunSequentially("should allow update of min_burn_amount", func(t *test.SystemTest) {
 		t.Cleanup(func() {
-			_ = updateAndVerify(t, "min_burn", fmt.Sprintf("%v", tokenomics.ZcnToInt(defaultParams["min_burn"])))
+			_ = updateAndVerify(t, "min_burn", currency.Coin(defaultParams["min_burn"]).Decimal(currency.ZCN).String())
 		})
 		cfgAfter := updateAndVerify(t, "min_burn", "2")
 
@@ -62,7 +63,7 @@ func TestZCNBridgeGlobalSettings(testSetup *testing.T) {
 
 	t.RunSequentially("should allow update of min_stake_amount", func(t *test.SystemTest) {
 		t.Cleanup(func() {
-			_ = updateAndVerify(t, "min_stake", fmt.Sprintf("%v", tokenomics.ZcnToInt(defaultParams["min_stake"])))
+			_ = updateAndVerify(t, "min_stake", currency.Coin(defaultParams["min_stake"]).Decimal(currency.ZCN).String())
 		})
 		cfgAfter := updateAndVerify(t, "min_stake", "3")
 
@@ -111,7 +112,7 @@ func TestZCNBridgeGlobalSettings(testSetup *testing.T) {
 	t.RunSequentially("should allow update of owner_id", func(t *test.SystemTest) {
 		newOwner := escapedTestName(t)
 
-		createWalletForName(newOwner)
+		createWalletForName(t, newOwner)
 
 		newOwnerWallet, err := getWalletForName(t, configPath, newOwner)
 		t.Cleanup(func() {
@@ -193,7 +194,7 @@ func getZCNBridgeGlobalSCConfig(t *test.SystemTest, cliConfigFilename string, re
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
@@ -210,6 +211,6 @@ func updateZCNBridgeSCConfig(t *test.SystemTest, walletName string, param map[st
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
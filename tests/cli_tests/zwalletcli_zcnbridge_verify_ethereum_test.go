@@ -49,6 +49,6 @@ func verifyBridgeTransaction(t *test.SystemTest, address string, retry bool) ([]
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
@@ -56,6 +56,8 @@ func TestKillSharder(testSetup *testing.T) { // nolint:gocyclo // team preferenc
 		}), true)
 		require.NoError(t, err, strings.Join(output, "\n"))
 		require.Len(t, output, 1)
+		// the killed sharder is no longer asked, by the helpers and to confirm transactions
+		network.Kill(sharderToKill)
 
 		cliutil.Wait(t, time.Second)
 
@@ -79,6 +81,6 @@ func killSharder(t *test.SystemTest, wallet, cliConfigFilename, params string, r
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
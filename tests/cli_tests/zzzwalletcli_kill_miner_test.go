@@ -57,6 +57,7 @@ func TestKillMiner(testSetup *testing.T) { // nolint:gocyclo // team preference
 		}), true)
 		require.NoError(t, err, strings.Join(output, "\n"))
 		require.Len(t, output, 1)
+		network.Kill(minerToKill)
 
 		cliutil.Wait(t, time.Second)
 
@@ -80,6 +81,6 @@ func killMiner(t *test.SystemTest, wallet, cliConfigFilename, params string, ret
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
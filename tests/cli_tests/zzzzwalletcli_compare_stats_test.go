@@ -11,7 +11,6 @@ import (
 	"github.com/0chain/system_test/internal/api/util/test"
 
 	"github.com/0chain/system_test/internal/api/util/client"
-	"github.com/0chain/system_test/internal/api/util/config"
 	"github.com/stretchr/testify/require"
 )
 
@@ -24,9 +23,7 @@ type customDataMap map[string]interface{}
 func TestMonitoringCompareMPTAndEventsDBData(testSetup *testing.T) {
 	t := setUpTest(testSetup)
 	createWallet(t)
-	t.Log("Default Config File ", configPath)
-	parsedConfig := config.Parse("./config/" + configPath)
-	apiClient = client.NewAPIClient(parsedConfig.BlockWorker)
+	apiClient = client.NewAPIClient(suiteConfig.BlockWorker)
 
 	compareBlobbersData(t)
 	compareShardersData(t)
@@ -14,6 +14,8 @@ import (
 
 	"github.com/0chain/system_test/internal/api/util/test"
 	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
+	"github.com/0chain/system_test/internal/currency"
 	"github.com/0chain/system_test/tests/tokenomics_tests/utils"
 	"github.com/stretchr/testify/require"
 )
@@ -44,12 +46,10 @@ func TestAllocationRewards(testSetup *testing.T) {
 	require.Nil(t, err, "Error listing blobbers", strings.Join(output, "\n"))
 	require.Len(t, output, 1)
 
-	err = json.Unmarshal([]byte(output[0]), &blobberList)
-	require.Nil(t, err, "Error unmarshalling blobber list", strings.Join(output, "\n"))
+	blobberList = cliparse.RequireJSON[[]climodel.BlobberInfo](t, output)
 	require.True(t, len(blobberList) > 0, "No blobbers found in blobber list")
 
-	err = json.Unmarshal([]byte(output[0]), &blobberDetailList)
-	require.Nil(t, err, "Error unmarshalling blobber list", strings.Join(output, "\n"))
+	blobberDetailList = cliparse.RequireJSON[[]climodel.BlobberDetails](t, output)
 	require.True(t, len(blobberList) > 0, "No blobbers found in blobber list")
 
 	var blobberListString []string
@@ -62,8 +62,7 @@ func TestAllocationRewards(testSetup *testing.T) {
 	require.Nil(t, err, "Error listing validators", strings.Join(output, "\n"))
 	require.Len(t, output, 1)
 
-	err = json.Unmarshal([]byte(output[0]), &validatorList)
-	require.Nil(t, err, "Error unmarshalling validator list", strings.Join(output, "\n"))
+	validatorList = cliparse.RequireJSON[[]climodel.Validator](t, output)
 	require.True(t, len(validatorList) > 0, "No validators found in validator list")
 
 	var validatorListString []string
@@ -122,10 +121,10 @@ func TestAllocationRewards(testSetup *testing.T) {
 			_, err := utils.ExecuteFaucetWithTokensForWallet(t, "wallets/blobber_owner", configPath, 99)
 			require.Nil(t, err, "Error executing faucet", strings.Join(output, "\n"))
 
-			output, err = utils.UpdateBlobberInfoForWallet(t, configPath, "wallets/blobber_owner", utils.CreateParams(map[string]interface{}{"blobber_id": intialBlobberInfo.ID, "read_price": utils.IntToZCN(intialBlobberInfo.Terms.ReadPrice * 10)}))
+			output, err = utils.UpdateBlobberInfoForWallet(t, configPath, "wallets/blobber_owner", utils.CreateParams(map[string]interface{}{"blobber_id": intialBlobberInfo.ID, "read_price": currency.SASToZCN(intialBlobberInfo.Terms.ReadPrice * 10)}))
 			require.Nil(t, err, strings.Join(output, "\n"))
 
-			output, err = utils.UpdateBlobberInfoForWallet(t, configPath, "wallets/blobber_owner", utils.CreateParams(map[string]interface{}{"blobber_id": intialBlobberInfo.ID, "write_price": utils.IntToZCN(intialBlobberInfo.Terms.WritePrice * 10)}))
+			output, err = utils.UpdateBlobberInfoForWallet(t, configPath, "wallets/blobber_owner", utils.CreateParams(map[string]interface{}{"blobber_id": intialBlobberInfo.ID, "write_price": currency.SASToZCN(intialBlobberInfo.Terms.WritePrice * 10)}))
 			require.Nil(t, err, strings.Join(output, "\n"))
 		}
 
@@ -367,17 +366,14 @@ func TestAddOrReplaceBlobberAllocationRewards(testSetup *testing.T) {
 	require.Nil(t, err, "Error registering wallet", strings.Join(output, "\n"))
 
 	var blobberList []climodel.BlobberInfo
-	var blobberDetailList []climodel.BlobberDetails
 	output, err = utils.ListBlobbers(t, configPath, "--json")
 	require.Nil(t, err, "Error listing blobbers", strings.Join(output, "\n"))
 	require.Len(t, output, 1)
 
-	err = json.Unmarshal([]byte(output[0]), &blobberList)
-	require.Nil(t, err, "Error unmarshalling blobber list", strings.Join(output, "\n"))
+	blobberList = cliparse.RequireJSON[[]climodel.BlobberInfo](t, output)
 	require.True(t, len(blobberList) > 0, "No blobbers found in blobber list")
 
-	err = json.Unmarshal([]byte(output[0]), &blobberDetailList)
-	require.Nil(t, err, "Error unmarshalling blobber list", strings.Join(output, "\n"))
+	cliparse.RequireJSON[[]climodel.BlobberDetails](t, output)
 	require.True(t, len(blobberList) > 0, "No blobbers found in blobber list")
 
 	var blobberListString []string
@@ -390,8 +386,7 @@ func TestAddOrReplaceBlobberAllocationRewards(testSetup *testing.T) {
 	require.Nil(t, err, "Error listing validators", strings.Join(output, "\n"))
 	require.Len(t, output, 1)
 
-	err = json.Unmarshal([]byte(output[0]), &validatorList)
-	require.Nil(t, err, "Error unmarshalling validator list", strings.Join(output, "\n"))
+	validatorList = cliparse.RequireJSON[[]climodel.Validator](t, output)
 	require.True(t, len(validatorList) > 0, "No validators found in validator list")
 
 	var validatorListString []string
@@ -765,9 +760,7 @@ func collectAndVerifyRewardsForWallet(t *test.SystemTest, blobberID, wallet stri
 	}))
 	require.Nil(t, err, "error getting stake pool info")
 	require.Len(t, output, 1)
-	stakePoolAfter := climodel.StakePoolInfo{}
-	err = json.Unmarshal([]byte(output[0]), &stakePoolAfter)
-	require.Nil(t, err, "Error unmarshalling stake pool info", strings.Join(output, "\n"))
+	stakePoolAfter := cliparse.RequireJSON[climodel.StakePoolInfo](t, output)
 	require.NotEmpty(t, stakePoolAfter)
 
 	rewards := int64(0)
@@ -12,6 +12,7 @@ import (
 
 	"github.com/0chain/system_test/internal/api/util/test"
 	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	"github.com/0chain/system_test/tests/tokenomics_tests/utils"
 	"github.com/stretchr/testify/require"
 )
@@ -38,8 +39,7 @@ func TestBlobberChallengeRewards(testSetup *testing.T) {
 	require.Nil(t, err, "Error listing blobbers", strings.Join(output, "\n"))
 	require.Len(t, output, 2)
 
-	err = json.Unmarshal([]byte(output[1]), &blobberList)
-	require.Nil(t, err, "Error unmarshalling blobber list", strings.Join(output, "\n"))
+	blobberList = cliparse.RequireJSON[[]climodel.BlobberInfo](t, output)
 	require.True(t, len(blobberList) > 0, "No blobbers found in blobber list")
 
 	var blobberListString []string
@@ -52,8 +52,7 @@ func TestBlobberChallengeRewards(testSetup *testing.T) {
 	require.Nil(t, err, "Error listing validators", strings.Join(output, "\n"))
 	require.Len(t, output, 1)
 
-	err = json.Unmarshal([]byte(output[0]), &validatorList)
-	require.Nil(t, err, "Error unmarshalling validator list", strings.Join(output, "\n"))
+	validatorList = cliparse.RequireJSON[[]climodel.Validator](t, output)
 	require.True(t, len(validatorList) > 0, "No validators found in validator list")
 
 	var validatorListString []string
@@ -1,7 +1,6 @@
 package tokenomics_tests
 
 import (
-	"encoding/json"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -10,6 +9,7 @@ import (
 
 	"github.com/0chain/system_test/internal/api/util/test"
 	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
 	"github.com/0chain/system_test/tests/tokenomics_tests/utils"
 	"github.com/stretchr/testify/require"
@@ -44,8 +44,7 @@ func TestBlobberSlashPenalty(testSetup *testing.T) {
 	require.Nil(t, err, "Error listing blobbers", strings.Join(output, "\n"))
 	require.Len(t, output, 1)
 
-	err = json.Unmarshal([]byte(output[0]), &blobberList)
-	require.Nil(t, err, "Error unmarshalling blobber list", strings.Join(output, "\n"))
+	blobberList = cliparse.RequireJSON[[]climodel.BlobberInfo](t, output)
 	require.True(t, len(blobberList) > 0, "No blobbers found in blobber list")
 
 	var blobberListString []string
@@ -58,8 +57,7 @@ func TestBlobberSlashPenalty(testSetup *testing.T) {
 	require.Nil(t, err, "Error listing validators", strings.Join(output, "\n"))
 	require.Len(t, output, 1)
 
-	err = json.Unmarshal([]byte(output[0]), &validatorList)
-	require.Nil(t, err, "Error unmarshalling validator list", strings.Join(output, "\n"))
+	validatorList = cliparse.RequireJSON[[]climodel.Validator](t, output)
 	require.True(t, len(validatorList) > 0, "No validators found in validator list")
 
 	var validatorListString []string
@@ -139,6 +137,6 @@ func killBlobber(t *test.SystemTest, cliConfigFilename, params string, retry boo
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
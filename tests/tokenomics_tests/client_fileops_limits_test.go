@@ -1,7 +1,6 @@
 package tokenomics_tests
 
 import (
-	"encoding/json"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -9,6 +8,7 @@ import (
 
 	"github.com/0chain/system_test/internal/api/util/test"
 	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	"github.com/0chain/system_test/tests/tokenomics_tests/utils"
 	"github.com/stretchr/testify/require"
 )
@@ -20,17 +20,14 @@ func TestClientThrottling(testSetup *testing.T) {
 	require.Nil(t, err, "Error registering wallet", strings.Join(output, "\n"))
 
 	var blobberList []climodel.BlobberInfo
-	var blobberDetailList []climodel.BlobberDetails
 	output, err = utils.ListBlobbers(t, configPath, "--json")
 	require.Nil(t, err, "Error listing blobbers", strings.Join(output, "\n"))
 	require.Len(t, output, 1)
 
-	err = json.Unmarshal([]byte(output[0]), &blobberList)
-	require.Nil(t, err, "Error unmarshalling blobber list", strings.Join(output, "\n"))
+	blobberList = cliparse.RequireJSON[[]climodel.BlobberInfo](t, output)
 	require.True(t, len(blobberList) > 0, "No blobbers found in blobber list")
 
-	err = json.Unmarshal([]byte(output[0]), &blobberDetailList)
-	require.Nil(t, err, "Error unmarshalling blobber list", strings.Join(output, "\n"))
+	cliparse.RequireJSON[[]climodel.BlobberDetails](t, output)
 	require.True(t, len(blobberList) > 0, "No blobbers found in blobber list")
 
 	var blobberListString []string
@@ -43,8 +40,7 @@ func TestClientThrottling(testSetup *testing.T) {
 	require.Nil(t, err, "Error listing validators", strings.Join(output, "\n"))
 	require.Len(t, output, 1)
 
-	err = json.Unmarshal([]byte(output[0]), &validatorList)
-	require.Nil(t, err, "Error unmarshalling validator list", strings.Join(output, "\n"))
+	validatorList = cliparse.RequireJSON[[]climodel.Validator](t, output)
 	require.True(t, len(validatorList) > 0, "No validators found in validator list")
 
 	var validatorListString []string
@@ -1,19 +1,16 @@
 package tokenomics_tests
 
 import (
-	"fmt"
 	"log"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
-	"time"
 
 	"github.com/0chain/system_test/internal/api/util/config"
-	"github.com/0chain/system_test/internal/api/util/test"
+	"github.com/0chain/system_test/internal/api/util/keystore"
 
 	cliutils "github.com/0chain/system_test/internal/cli/util"
-	"github.com/spf13/viper"
 )
 
 const (
@@ -27,41 +24,19 @@ const (
 	GB = 1024 * MB // gigabyte
 )
 
-func setupDefaultConfig() {
-	viper.SetDefault("nodes.miner01ID", "73ad5727612116c025bb4405bf3adb4a4a04867ae508c51cf885395bffc8a949")
-	viper.SetDefault("nodes.miner02ID", "3ec9a42db3355f33c35750ce589ed717c08787997b7f34a7f1f9fb0a03f2b17c")
-	viper.SetDefault("nodes.miner03ID", "c6f4b8ce5da386b278ba8c4e6cf98b24b32d15bc675b4d12c95e082079c91937")
-	viper.SetDefault("nodes.sharder01ID", "ea26431f8adb7061766f1d6bbcc3b292d70dd59960d857f04b8a75e6a5bbe04f")
-	viper.SetDefault("nodes.sharder02ID", "30001a01a888584772b7fee13934021ab8557e0ed471c0a3a454e9164180aef1")
-}
-
-// SetupConfig setups the main configuration system.
-func setupConfig() {
-	setupDefaultConfig()
+// setupConfig loads the configuration of the suite and logs it with the secrets redacted
+func setupConfig() error {
 	path := filepath.Join(".", "config")
-
-	viper.SetConfigName("nodes")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath(path)
-	if err := viper.ReadInConfig(); err != nil {
-		log.Fatalln(fmt.Errorf("fatal error config file: %s", err))
-	}
-
-	parsedConfig := config.Parse(filepath.Join(".", path, "tokenomics_tests_config.yaml"))
-	defaultTestTimeout, err := time.ParseDuration(parsedConfig.DefaultTestCaseTimeout)
+	cfg, err := config.Load(config.Tokenomics,
+		filepath.Join(path, "config.yaml"),
+		filepath.Join(path, "tokenomics_tests_config.yaml"),
+	)
 	if err != nil {
-		log.Printf("Default test case timeout could not be parsed so has defaulted to [%v]", test.DefaultTestTimeout)
-	} else {
-		test.DefaultTestTimeout = defaultTestTimeout
-		log.Printf("Default test case timeout is [%v]", test.DefaultTestTimeout)
-	}
-
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath(path)
-	if err := viper.ReadInConfig(); err != nil {
-		log.Fatalln(fmt.Errorf("fatal error config file: %s", err))
+		return err
 	}
+	log.Printf("Effective config from %s:\n%s", strings.Join(cfg.Sources, ", "), cfg.Redacted())
+	cfg.Apply()
+	return nil
 }
 
 const (
@@ -91,6 +66,12 @@ var (
 )
 
 func TestMain(m *testing.M) {
+	os.Exit(run(m))
+}
+
+// run sets the suite up and runs the tests, returning the exit code. Once the wallets are decrypted every failure
+// returns instead of exiting, so that the deferred cleanup removes them.
+func run(m *testing.M) int {
 	configPath = os.Getenv("CONFIG_PATH")
 	configDir = os.Getenv("CONFIG_DIR")
 	bridgeClientConfigFile = os.Getenv("BRIDGE_CONFIG_FILE")
@@ -146,11 +127,24 @@ func TestMain(m *testing.M) {
 		}
 	}
 
-	setupConfig()
+	// the CLIs read the wallet files themselves, so encrypted ones are decrypted for the duration of the run
+	decryptedWallets, err := keystore.Materialize("./config/wallets")
+	if err != nil {
+		log.Println("Failed to decrypt wallet files:", err)
+		return 1
+	}
+	defer func() {
+		for _, f := range decryptedWallets {
+			_ = os.Remove(f)
+		}
+	}()
 
-	exitRun := m.Run()
+	if err := setupConfig(); err != nil {
+		log.Println("Failed to load the config:", err)
+		return 1
+	}
 
-	os.Exit(exitRun)
+	return m.Run()
 }
 
 func getConfigDir() string {
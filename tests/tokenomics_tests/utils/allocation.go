@@ -2,7 +2,6 @@ package utils
 
 import (
 	"crypto/rand"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -13,6 +12,7 @@ import (
 
 	"github.com/0chain/system_test/internal/api/util/test"
 	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
 	"github.com/stretchr/testify/require"
 )
@@ -53,7 +53,7 @@ func readPoolLockWithWallet(t *test.SystemTest, wallet, cliConfigFilename, param
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
@@ -122,7 +122,7 @@ func downloadFileForWallet(t *test.SystemTest, wallet, cliConfigFilename, param
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
@@ -153,7 +153,7 @@ func CancelAllocation(t *test.SystemTest, cliConfigFilename, allocationID string
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
@@ -200,7 +200,7 @@ func uploadFileForWallet(t *test.SystemTest, wallet, cliConfigFilename string, p
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*40)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
@@ -208,8 +208,7 @@ func GetAllocation(t *test.SystemTest, allocationID string) (allocation climodel
 	output, err := getAllocationWithRetry(t, configPath, allocationID, 1)
 	require.Nil(t, err, "error fetching allocation")
 	require.Greater(t, len(output), 0, "gettting allocation - output is empty unexpectedly")
-	err = json.Unmarshal([]byte(output[0]), &allocation)
-	require.Nil(t, err, "error unmarshalling allocation json")
+	allocation = cliparse.RequireJSON[climodel.Allocation](t, output)
 	return
 }
 
@@ -239,7 +238,7 @@ func UpdateAllocationWithWallet(t *test.SystemTest, wallet, cliConfigFilename, p
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
@@ -255,6 +254,6 @@ func DeleteFile(t *test.SystemTest, walletName, params string, retry bool) ([]st
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*20)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
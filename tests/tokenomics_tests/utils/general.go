@@ -14,8 +14,6 @@ const (
 	KB = 1024      // kilobyte
 	MB = 1024 * KB // megabyte
 	GB = 1024 * MB // gigabyte
-
-	tokenUnit float64 = 1e+10
 )
 
 func EscapedTestName(t *test.SystemTest) string {
@@ -39,7 +37,3 @@ func CreateParams(params map[string]interface{}) string {
 	}
 	return strings.TrimSpace(builder.String())
 }
-
-func IntToZCN(balance int64) float64 {
-	return float64(balance) / tokenUnit
-}
@@ -1,12 +1,12 @@
 package utils
 
 import (
-	"encoding/json"
 	"reflect"
 	"strings"
 
 	"github.com/0chain/system_test/internal/api/util/test"
 	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	cliutil "github.com/0chain/system_test/internal/cli/util"
 	"github.com/stretchr/testify/require"
 )
@@ -30,9 +30,7 @@ func getShardersListForWallet(t *test.SystemTest, wallet string) map[string]clim
 	require.Greater(t, len(output), 0)
 	require.Equal(t, "MagicBlock Sharders", output[0])
 
-	var sharders map[string]climodel.Sharder
-	err = json.Unmarshal([]byte(strings.Join(output[1:], "")), &sharders)
-	require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output[1:], "\n"), err)
+	sharders := cliparse.RequireJSON[map[string]climodel.Sharder](t, output)
 	require.NotEmpty(t, sharders, "No sharders found: %v", strings.Join(output[1:], "\n"))
 
 	return sharders
@@ -46,9 +44,7 @@ func GetSharderUrl(t *test.SystemTest) string {
 	require.Greater(t, len(output), 1)
 	require.Equal(t, "MagicBlock Sharders", output[0])
 
-	var sharders map[string]climodel.Sharder
-	err = json.Unmarshal([]byte(strings.Join(output[1:], "")), &sharders)
-	require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output[1:], "\n"), err)
+	sharders := cliparse.RequireJSON[map[string]climodel.Sharder](t, output)
 	require.NotEmpty(t, sharders, "No sharders found: %v", strings.Join(output[1:], "\n"))
 
 	sharder := sharders[reflect.ValueOf(sharders).MapKeys()[0].String()]
@@ -62,7 +58,7 @@ func getSharders(t *test.SystemTest, cliConfigFilename string) ([]string, error)
 
 func getShardersForWallet(t *test.SystemTest, cliConfigFilename, wallet string) ([]string, error) {
 	t.Logf("list sharder nodes...")
-	return cliutil.RunCommandWithRawOutput("./zwallet ls-sharders --active --json --silent --wallet " + wallet + "_wallet.json --configDir ./config --config " + cliConfigFilename)
+	return cliutil.RunCommandWithRawOutput(t, "./zwallet ls-sharders --active --json --silent --wallet "+wallet+"_wallet.json --configDir ./config --config "+cliConfigFilename)
 }
 
 func GetSortedSharderIds(t *test.SystemTest, sharderBaseURL string) []string {
@@ -5,13 +5,14 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/0chain/system_test/internal/api/util/test"
 	climodel "github.com/0chain/system_test/internal/cli/model"
+	cliparse "github.com/0chain/system_test/internal/cli/parse"
 	cliutils "github.com/0chain/system_test/internal/cli/util"
+	"github.com/0chain/system_test/internal/currency"
 	"github.com/stretchr/testify/require"
 )
 
@@ -79,7 +80,7 @@ func GetWalletForName(t *test.SystemTest, cliConfigFilename, name string) (*clim
 
 	var wallet *climodel.Wallet
 
-	err = json.Unmarshal([]byte(output[0]), &wallet)
+	_, err = cliparse.JSON(output, &wallet)
 	if err != nil {
 		t.Errorf("failed to unmarshal the result into wallet")
 		return nil, err
@@ -104,7 +105,7 @@ func StakeTokensForWallet(t *test.SystemTest, cliConfigFilename, wallet, params
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
@@ -125,7 +126,7 @@ func UpdateStorageSCConfig(t *test.SystemTest, walletName string, param map[stri
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*5)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
@@ -158,11 +159,11 @@ func CollectRewardsForWallet(t *test.SystemTest, cliConfigFilename, params, wall
 	if retry {
 		return cliutils.RunCommand(t, cmd, 3, time.Second*2)
 	} else {
-		return cliutils.RunCommandWithoutRetry(cmd)
+		return cliutils.RunCommandWithoutRetry(t, cmd)
 	}
 }
 
-func GetBalanceZCN(t *test.SystemTest, cliConfigFilename string, walletName ...string) (float64, error) {
+func GetBalanceZCN(t *test.SystemTest, cliConfigFilename string, walletName ...string) (currency.Coin, error) {
 	cliutils.Wait(t, 5*time.Second)
 	var (
 		output []string
@@ -184,17 +185,11 @@ func GetBalanceZCN(t *test.SystemTest, cliConfigFilename string, walletName ...s
 		ZCN string `json:"zcn"`
 	}{}
 
-	if err := json.Unmarshal([]byte(output[0]), &balance); err != nil {
+	if _, err := cliparse.JSON(output, &balance); err != nil {
 		return 0, err
 	}
 
-	balanceFloat, err := strconv.ParseFloat(balance.ZCN, 64)
-	if err != nil {
-		return 0, err
-	}
-
-	// round up to 2 decimal places
-	return float64(int(balanceFloat*100)) / 100, nil
+	return currency.ParseCoin(balance.ZCN)
 }
 
 func GetBalanceForWallet(t *test.SystemTest, cliConfigFilename, wallet string) ([]string, error) {
@@ -213,9 +208,7 @@ func GetBalanceFromSharders(t *test.SystemTest, clientId string) int64 {
 	require.Greater(t, len(output), 1)
 	require.Equal(t, "MagicBlock Sharders", output[0])
 
-	var sharders map[string]*climodel.Sharder
-	err = json.Unmarshal([]byte(strings.Join(output[1:], "")), &sharders)
-	require.Nil(t, err, "Error deserializing JSON string `%s`: %v", strings.Join(output[1:], "\n"), err)
+	sharders := cliparse.RequireJSON[map[string]*climodel.Sharder](t, output)
 	require.NotEmpty(t, sharders, "No sharders found: %v", strings.Join(output[1:], "\n"))
 
 	// Get base URL for API calls.